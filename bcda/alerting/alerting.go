@@ -0,0 +1,145 @@
+// Package alerting evaluates rules against the state of exports and imports and notifies the team when
+// one of them fires. Rules are evaluated periodically by the worker; each firing alert is sent to every
+// configured notifier unless it was already sent within the repeat interval or is silenced.
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CMSgov/bcda-app/log"
+)
+
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert is a single firing instance of a rule.
+type Alert struct {
+	Rule string `json:"rule"`
+	// Key identifies what the alert is about within the rule, e.g. a CMS ID or model. It is empty for
+	// rules that only fire once.
+	Key      string    `json:"key,omitempty"`
+	Severity Severity  `json:"severity"`
+	Summary  string    `json:"summary"`
+	FiredAt  time.Time `json:"fired_at"`
+}
+
+// ID is used to dedupe notifications for the alert.
+func (a Alert) ID() string {
+	if a.Key == "" {
+		return a.Rule
+	}
+	return a.Rule + "/" + a.Key
+}
+
+// Rule checks one condition and returns an alert for each instance of it that is firing at now.
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, now time.Time) ([]Alert, error)
+}
+
+// Notifier delivers an alert to the team.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// State remembers when each alert was last sent so it isn't repeated on every evaluation.
+type State interface {
+	// LastNotified returns when the alert with id was last sent, or the zero time if it never was.
+	LastNotified(ctx context.Context, id string) (time.Time, error)
+	RecordNotified(ctx context.Context, id string, at time.Time) error
+}
+
+type Engine struct {
+	rules          []Rule
+	notifiers      []Notifier
+	state          State
+	silences       []Silence
+	repeatInterval time.Duration
+}
+
+func NewEngine(rules []Rule, notifiers []Notifier, state State, silences []Silence, repeatInterval time.Duration) *Engine {
+	return &Engine{
+		rules:          rules,
+		notifiers:      notifiers,
+		state:          state,
+		silences:       silences,
+		repeatInterval: repeatInterval,
+	}
+}
+
+// Run evaluates every rule and sends the alerts that fire. A rule or notifier that fails doesn't stop the
+// others; their errors are returned together once every rule has run.
+func (e *Engine) Run(ctx context.Context, now time.Time) error {
+	var errs []error
+	for _, rule := range e.rules {
+		alerts, err := rule.Evaluate(ctx, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to evaluate rule %s: %w", rule.Name(), err))
+			continue
+		}
+		for _, alert := range alerts {
+			if err := e.send(ctx, alert, now); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Engine) send(ctx context.Context, alert Alert, now time.Time) error {
+	logger := log.Worker.WithFields(logrus.Fields{"alert": alert.ID(), "severity": alert.Severity})
+
+	if s, ok := e.silenced(alert, now); ok {
+		logger.Infof("Alert silenced until %s: %s", s.Ends, s.Reason)
+		return nil
+	}
+
+	last, err := e.state.LastNotified(ctx, alert.ID())
+	if err != nil {
+		return fmt.Errorf("failed to get last notification for alert %s: %w", alert.ID(), err)
+	}
+	if !last.IsZero() && now.Sub(last) < e.repeatInterval {
+		logger.Debugf("Alert already sent at %s", last)
+		return nil
+	}
+
+	logger.Warn(alert.Summary)
+
+	var (
+		errs []error
+		sent bool
+	)
+	for _, n := range e.notifiers {
+		if err := n.Notify(ctx, alert); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send alert %s to %s: %w", alert.ID(), n.Name(), err))
+			continue
+		}
+		sent = true
+	}
+	// Only wait out the repeat interval once someone has been told
+	if sent {
+		if err := e.state.RecordNotified(ctx, alert.ID(), now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to record notification for alert %s: %w", alert.ID(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Engine) silenced(alert Alert, now time.Time) (Silence, bool) {
+	for _, s := range e.silences {
+		if s.matches(alert, now) {
+			return s, true
+		}
+	}
+	return Silence{}, false
+}
@@ -0,0 +1,124 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRule struct {
+	name   string
+	alerts []Alert
+	err    error
+}
+
+func (r fakeRule) Name() string { return r.name }
+
+func (r fakeRule) Evaluate(context.Context, time.Time) ([]Alert, error) {
+	return r.alerts, r.err
+}
+
+type fakeNotifier struct {
+	err  error
+	sent []Alert
+}
+
+func (n *fakeNotifier) Name() string { return "fake" }
+
+func (n *fakeNotifier) Notify(_ context.Context, alert Alert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, alert)
+	return nil
+}
+
+type fakeState map[string]time.Time
+
+func (s fakeState) LastNotified(_ context.Context, id string) (time.Time, error) {
+	return s[id], nil
+}
+
+func (s fakeState) RecordNotified(_ context.Context, id string, at time.Time) error {
+	s[id] = at
+	return nil
+}
+
+func TestEngineRun(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+	a9994 := Alert{Rule: RuleJobFailureRate, Key: "A9994", Severity: SeverityCritical, Summary: "A9994 failing"}
+	a9995 := Alert{Rule: RuleJobFailureRate, Key: "A9995", Severity: SeverityCritical, Summary: "A9995 failing"}
+	queue := Alert{Rule: RuleQueueAge, Severity: SeverityCritical, Summary: "queue backed up"}
+
+	silences := []Silence{
+		{Rule: RuleJobFailureRate, Key: "A9995", ends: now.Add(5 * time.Hour)},
+		{Rule: RuleQueueAge, ends: now.Add(-time.Hour)}, // expired
+	}
+	notifier := &fakeNotifier{}
+	state := fakeState{}
+	engine := NewEngine([]Rule{
+		fakeRule{name: RuleJobFailureRate, alerts: []Alert{a9994, a9995}},
+		fakeRule{name: RuleCCLFImport, err: errors.New("connection refused")},
+		fakeRule{name: RuleQueueAge, alerts: []Alert{queue}},
+	}, []Notifier{notifier}, state, silences, 4*time.Hour)
+
+	err := engine.Run(ctx, now)
+	assert.ErrorContains(t, err, "failed to evaluate rule cclf_import: connection refused")
+	assert.Equal(t, []Alert{a9994, queue}, notifier.sent, "silenced alert is not sent, other rules still run")
+	assert.Equal(t, fakeState{"job_failure_rate/A9994": now, "queue_age": now}, state)
+
+	notifier.sent = nil
+	_ = engine.Run(ctx, now.Add(time.Hour))
+	assert.Empty(t, notifier.sent, "alerts are not repeated within the repeat interval")
+
+	_ = engine.Run(ctx, now.Add(4*time.Hour))
+	assert.Equal(t, []Alert{a9994, queue}, notifier.sent)
+}
+
+func TestEngineRunNotifierError(t *testing.T) {
+	now := time.Now()
+	alert := Alert{Rule: RuleQueueAge, Summary: "queue backed up"}
+	failing := &fakeNotifier{err: errors.New("timeout")}
+	working := &fakeNotifier{}
+	state := fakeState{}
+
+	engine := NewEngine([]Rule{fakeRule{name: RuleQueueAge, alerts: []Alert{alert}}}, []Notifier{failing, working}, state, nil, time.Hour)
+	err := engine.Run(context.Background(), now)
+	assert.ErrorContains(t, err, "failed to send alert queue_age to fake: timeout")
+	assert.Equal(t, []Alert{alert}, working.sent)
+	assert.Equal(t, now, state["queue_age"], "sent once any notifier succeeds")
+
+	// Keep trying when nobody was told
+	state = fakeState{}
+	engine = NewEngine([]Rule{fakeRule{name: RuleQueueAge, alerts: []Alert{alert}}}, []Notifier{failing}, state, nil, time.Hour)
+	require.Error(t, engine.Run(context.Background(), now))
+	assert.Empty(t, state)
+}
+
+func TestSilenceMatches(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+	alert := Alert{Rule: RuleJobFailureRate, Key: "A9994"}
+	tests := []struct {
+		name    string
+		silence Silence
+		matches bool
+	}{
+		{"whole rule", Silence{Rule: RuleJobFailureRate}, true},
+		{"matching key", Silence{Rule: RuleJobFailureRate, Key: "A9994"}, true},
+		{"other key", Silence{Rule: RuleJobFailureRate, Key: "A9995"}, false},
+		{"other rule", Silence{Rule: RuleQueueAge}, false},
+		{"in window", Silence{Rule: RuleJobFailureRate, starts: now.Add(-time.Hour), ends: now.Add(time.Hour)}, true},
+		{"not started", Silence{Rule: RuleJobFailureRate, starts: now.Add(time.Minute)}, false},
+		{"ended", Silence{Rule: RuleJobFailureRate, ends: now}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, tt.silence.matches(alert, now))
+		})
+	}
+}
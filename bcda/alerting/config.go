@@ -0,0 +1,131 @@
+package alerting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+// Config is the alerting section of the application config.
+type Config struct {
+	// Schedule is the cron schedule the worker evaluates the rules on
+	Schedule string `conf:"schedule" conf_default:"*/15 * * * *"`
+	// RepeatInterval is how long to wait before sending an alert that is still firing again
+	RepeatInterval time.Duration `conf:"repeat_interval" conf_default:"4h"`
+
+	JobFailureRate JobFailureRateConfig `conf:"job_failure_rate"`
+	CCLFImport     CCLFImportConfig     `conf:"cclf_import"`
+	BenePrefsFiles BenePrefsFilesConfig `conf:"bene_prefs_files"`
+	QueueAge       QueueAgeConfig       `conf:"queue_age"`
+
+	Silences  []Silence       `conf:"silences"`
+	Notifiers NotifiersConfig `conf:"notifiers"`
+}
+
+type JobFailureRateConfig struct {
+	Enabled bool          `conf:"enabled"`
+	Window  time.Duration `conf:"window" conf_default:"1h"`
+	// Threshold is the share of an ACO's finished jobs, between 0 and 1, that must fail to fire
+	Threshold float64 `conf:"threshold" conf_default:"0.5"`
+	// MinJobs keeps an ACO's single failed job from firing
+	MinJobs int `conf:"min_jobs" conf_default:"4"`
+}
+
+type CCLFImportConfig struct {
+	Enabled bool `conf:"enabled"`
+	// Days is how long after a model's performance year transition its first CCLF file is expected
+	Days int `conf:"days" conf_default:"14"`
+}
+
+type BenePrefsFilesConfig struct {
+	Enabled bool          `conf:"enabled"`
+	Window  time.Duration `conf:"window" conf_default:"168h"`
+	// Drop is the decrease, between 0 and 1, from the previous window's file count that fires
+	Drop float64 `conf:"drop" conf_default:"0.5"`
+}
+
+type QueueAgeConfig struct {
+	Enabled bool `conf:"enabled"`
+	// Threshold is how long the oldest available River job may wait to be worked
+	Threshold time.Duration `conf:"threshold" conf_default:"30m"`
+}
+
+// Silence suppresses notifications for a rule, or one key of a rule, between two times. Either time may
+// be left out to leave that end open.
+type Silence struct {
+	Rule   string `conf:"rule"`
+	Key    string `conf:"key"`
+	Starts string `conf:"starts"` // RFC 3339
+	Ends   string `conf:"ends"`   // RFC 3339
+	Reason string `conf:"reason"`
+
+	// Un-exported fields that are computed using the exported ones above
+	starts, ends time.Time
+}
+
+func (s Silence) matches(alert Alert, now time.Time) bool {
+	if s.Rule != alert.Rule || (s.Key != "" && s.Key != alert.Key) {
+		return false
+	}
+	return (s.starts.IsZero() || !now.Before(s.starts)) && (s.ends.IsZero() || now.Before(s.ends))
+}
+
+type NotifiersConfig struct {
+	// SlackChannel receives alerts when SLACK_TOKEN is set
+	SlackChannel string `conf:"slack_channel"`
+	// Alerts are emailed through SMTPAddr (host:port) when it is set. Credentials come from the
+	// ALERT_SMTP_USERNAME and ALERT_SMTP_PASSWORD env vars.
+	SMTPAddr  string   `conf:"smtp_addr"`
+	EmailFrom string   `conf:"email_from"`
+	EmailTo   []string `conf:"email_to"`
+	// Alerts are also posted as JSON to the URL in the ALERT_WEBHOOK_URL env var when it is set
+}
+
+func LoadConfig() (*Config, error) {
+	var c struct {
+		Alerting Config `conf:"alerting"`
+	}
+	if err := conf.Checkout(&c); err != nil {
+		return nil, err
+	}
+
+	cfg := &c.Alerting
+	if err := cfg.ComputeFields(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Parse un-exported fields using the fields loaded via the config
+func (cfg *Config) ComputeFields() (err error) {
+	if _, err = cron.ParseStandard(cfg.Schedule); err != nil {
+		return fmt.Errorf("invalid alerting schedule %q: %w", cfg.Schedule, err)
+	}
+
+	for idx := range cfg.Silences {
+		s := &cfg.Silences[idx]
+		if s.Rule == "" {
+			return fmt.Errorf("alerting silence %d has no rule", idx)
+		}
+		if s.Starts != "" {
+			if s.starts, err = time.Parse(time.RFC3339, s.Starts); err != nil {
+				return fmt.Errorf("failed to parse start of %s silence: %w", s.Rule, err)
+			}
+		}
+		if s.Ends != "" {
+			if s.ends, err = time.Parse(time.RFC3339, s.Ends); err != nil {
+				return fmt.Errorf("failed to parse end of %s silence: %w", s.Rule, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Enabled reports whether any rule is turned on.
+func (cfg *Config) Enabled() bool {
+	return cfg.JobFailureRate.Enabled || cfg.CCLFImport.Enabled || cfg.BenePrefsFiles.Enabled || cfg.QueueAge.Enabled
+}
@@ -0,0 +1,52 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, "*/5 * * * *", cfg.Schedule)
+	assert.Equal(t, 2*time.Hour, cfg.RepeatInterval)
+	assert.Equal(t, JobFailureRateConfig{Enabled: true, Window: time.Hour, Threshold: 0.25, MinJobs: 2}, cfg.JobFailureRate)
+	assert.Equal(t, CCLFImportConfig{Enabled: true, Days: 14}, cfg.CCLFImport)
+	assert.Equal(t, BenePrefsFilesConfig{Enabled: true, Window: 168 * time.Hour, Drop: 0.5}, cfg.BenePrefsFiles)
+	assert.Equal(t, QueueAgeConfig{Enabled: true, Threshold: 30 * time.Minute}, cfg.QueueAge)
+	assert.Equal(t, []string{"bcda-alerts@example.com"}, cfg.Notifiers.EmailTo)
+	assert.True(t, cfg.Enabled())
+
+	require.Len(t, cfg.Silences, 1)
+	assert.Equal(t, "A9994", cfg.Silences[0].Key)
+	assert.True(t, cfg.Silences[0].starts.IsZero())
+	assert.Equal(t, time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC), cfg.Silences[0].ends)
+}
+
+func TestComputeFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    Config
+		errMsg string
+	}{
+		{"valid", Config{Schedule: "0 * * * *", Silences: []Silence{{Rule: RuleQueueAge, Starts: "2026-01-01T00:00:00Z"}}}, ""},
+		{"invalid schedule", Config{Schedule: "every hour"}, "invalid alerting schedule"},
+		{"silence without rule", Config{Schedule: "0 * * * *", Silences: []Silence{{Key: "A9994"}}}, "has no rule"},
+		{"invalid silence start", Config{Schedule: "0 * * * *", Silences: []Silence{{Rule: RuleQueueAge, Starts: "2026-01-01"}}}, "failed to parse start"},
+		{"invalid silence end", Config{Schedule: "0 * * * *", Silences: []Silence{{Rule: RuleQueueAge, Ends: "tomorrow"}}}, "failed to parse end"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.ComputeFields()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
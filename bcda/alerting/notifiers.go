@@ -0,0 +1,141 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	msgr "github.com/CMSgov/bcda-app/bcda/slackmessenger"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+// NewNotifiers returns a notifier for each destination configured in cfg or the environment.
+func NewNotifiers(cfg NotifiersConfig) []Notifier {
+	var notifiers []Notifier
+	if token := conf.GetEnv("SLACK_TOKEN"); token != "" {
+		channel := cfg.SlackChannel
+		if channel == "" {
+			channel = msgr.AlertsChannel
+		}
+		notifiers = append(notifiers, NewSlackNotifier(slack.New(token), channel))
+	}
+	if cfg.SMTPAddr != "" && len(cfg.EmailTo) > 0 {
+		var auth smtp.Auth
+		if user := conf.GetEnv("ALERT_SMTP_USERNAME"); user != "" {
+			host, _, _ := net.SplitHostPort(cfg.SMTPAddr)
+			auth = smtp.PlainAuth("", user, conf.GetEnv("ALERT_SMTP_PASSWORD"), host)
+		}
+		notifiers = append(notifiers, NewEmailNotifier(cfg.SMTPAddr, auth, cfg.EmailFrom, cfg.EmailTo))
+	}
+	if url := conf.GetEnv("ALERT_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(url, &http.Client{Timeout: 10 * time.Second}))
+	}
+	return notifiers
+}
+
+func environment() string {
+	return conf.GetEnv("DEPLOYMENT_TARGET")
+}
+
+// SlackClient is the part of slack.Client used to post alerts.
+type SlackClient interface {
+	PostMessageContext(context.Context, string, ...slack.MsgOption) (string, string, error)
+}
+
+type slackNotifier struct {
+	client  SlackClient
+	channel string
+}
+
+func NewSlackNotifier(client SlackClient, channel string) Notifier {
+	return slackNotifier{client, channel}
+}
+
+func (n slackNotifier) Name() string { return "slack" }
+
+func (n slackNotifier) Notify(ctx context.Context, alert Alert) error {
+	color := "warning"
+	if alert.Severity == SeverityCritical {
+		color = msgr.Danger
+	}
+	a := slack.Attachment{
+		Color: color,
+		Title: fmt.Sprintf("%s: %s in %s env.", strings.ToUpper(string(alert.Severity)), alert.Rule, environment()),
+		Text:  alert.Summary,
+	}
+	_, _, err := n.client.PostMessageContext(ctx, n.channel, slack.MsgOptionAttachments(a))
+	return err
+}
+
+type emailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	// sendMail is swapped out in tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmailNotifier(addr string, auth smtp.Auth, from string, to []string) Notifier {
+	return emailNotifier{addr, auth, from, to, smtp.SendMail}
+}
+
+func (n emailNotifier) Name() string { return "email" }
+
+func (n emailNotifier) Notify(_ context.Context, alert Alert) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [BCDA %s] %s alert: %s\r\n", environment(), alert.Severity, alert.Rule)
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.FiredAt.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(alert.Summary + "\r\n")
+	return n.sendMail(n.addr, n.auth, n.from, n.to, []byte(msg.String()))
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, client *http.Client) Notifier {
+	return webhookNotifier{url, client}
+}
+
+func (n webhookNotifier) Name() string { return "webhook" }
+
+// Notify posts the alert as JSON, along with the environment it fired in.
+func (n webhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(struct {
+		Alert
+		Environment string `json:"environment"`
+	}{alert, environment()})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(constants.ContentType, constants.JsonContentType)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
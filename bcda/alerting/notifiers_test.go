@@ -0,0 +1,101 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+var testAlert = Alert{Rule: RuleQueueAge, Severity: SeverityCritical, Summary: "queue backed up", FiredAt: now}
+
+type mockSlackClient struct {
+	mock.Mock
+}
+
+func (m *mockSlackClient) PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (string, string, error) {
+	args := m.Called(ctx, channel, options)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func TestNewNotifiers(t *testing.T) {
+	for _, key := range []string{"SLACK_TOKEN", "ALERT_WEBHOOK_URL"} {
+		old := conf.GetEnv(key)
+		t.Cleanup(func() { conf.SetEnv(t, key, old) })
+	}
+
+	conf.SetEnv(t, "SLACK_TOKEN", "")
+	conf.SetEnv(t, "ALERT_WEBHOOK_URL", "")
+	assert.Empty(t, NewNotifiers(NotifiersConfig{}))
+
+	conf.SetEnv(t, "SLACK_TOKEN", "xoxb-token")
+	conf.SetEnv(t, "ALERT_WEBHOOK_URL", "https://example.com/hook")
+	var names []string
+	for _, n := range NewNotifiers(NotifiersConfig{SMTPAddr: "smtp.example.com:587", EmailTo: []string{"ops@example.com"}}) {
+		names = append(names, n.Name())
+	}
+	assert.Equal(t, []string{"slack", "email", "webhook"}, names)
+}
+
+func TestSlackNotifier(t *testing.T) {
+	client := &mockSlackClient{}
+	client.On("PostMessageContext", mock.Anything, "C123", mock.Anything).Return("", "", nil).Once()
+	require.NoError(t, NewSlackNotifier(client, "C123").Notify(context.Background(), testAlert))
+	client.AssertExpectations(t)
+
+	client.On("PostMessageContext", mock.Anything, "C123", mock.Anything).Return("", "", assert.AnError)
+	assert.ErrorIs(t, NewSlackNotifier(client, "C123").Notify(context.Background(), testAlert), assert.AnError)
+}
+
+func TestEmailNotifier(t *testing.T) {
+	var (
+		addr, from string
+		to         []string
+		msg        []byte
+	)
+	n := NewEmailNotifier("smtp.example.com:587", nil, "bcda@example.com", []string{"ops@example.com", "oncall@example.com"}).(emailNotifier)
+	n.sendMail = func(a string, _ smtp.Auth, f string, t []string, m []byte) error {
+		addr, from, to, msg = a, f, t, m
+		return nil
+	}
+
+	require.NoError(t, n.Notify(context.Background(), testAlert))
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Equal(t, "bcda@example.com", from)
+	assert.Equal(t, []string{"ops@example.com", "oncall@example.com"}, to)
+	assert.Contains(t, string(msg), "To: ops@example.com, oncall@example.com\r\n")
+	assert.Contains(t, string(msg), "critical alert: queue_age\r\n")
+	assert.Contains(t, string(msg), "\r\n\r\nqueue backed up\r\n")
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received["severity"] == string(SeverityWarning) {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, server.Client())
+	require.NoError(t, n.Notify(context.Background(), testAlert))
+	assert.Equal(t, "queue_age", received["rule"])
+	assert.Equal(t, "queue backed up", received["summary"])
+	assert.Contains(t, received, "environment")
+
+	warning := testAlert
+	warning.Severity = SeverityWarning
+	assert.ErrorContains(t, n.Notify(context.Background(), warning), "webhook returned 502 Bad Gateway")
+}
@@ -0,0 +1,223 @@
+package alerting
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+const (
+	RuleJobFailureRate = "job_failure_rate"
+	RuleCCLFImport     = "cclf_import"
+	RuleBenePrefsFiles = "bene_prefs_files"
+	RuleQueueAge       = "queue_age"
+)
+
+// NewRules returns the rules turned on in cfg. Models come from acoConfigs.
+func NewRules(cfg *Config, src Source, acoConfigs []service.ACOConfig) ([]Rule, error) {
+	var rules []Rule
+	if cfg.JobFailureRate.Enabled {
+		rules = append(rules, jobFailureRate{src, cfg.JobFailureRate})
+	}
+	if cfg.CCLFImport.Enabled {
+		r, err := newCCLFImport(src, cfg.CCLFImport, acoConfigs)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	if cfg.BenePrefsFiles.Enabled {
+		rules = append(rules, benePrefsFiles{src, cfg.BenePrefsFiles})
+	}
+	if cfg.QueueAge.Enabled {
+		rules = append(rules, queueAge{src, cfg.QueueAge})
+	}
+	return rules, nil
+}
+
+// jobFailureRate fires for each ACO whose recent jobs fail too often.
+type jobFailureRate struct {
+	src Source
+	cfg JobFailureRateConfig
+}
+
+func (r jobFailureRate) Name() string { return RuleJobFailureRate }
+
+func (r jobFailureRate) Evaluate(ctx context.Context, now time.Time) ([]Alert, error) {
+	counts, err := r.src.JobCounts(ctx, now.Add(-r.cfg.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []Alert
+	for cmsID, c := range counts {
+		finished := c.Completed + c.Failed
+		if finished == 0 || finished < r.cfg.MinJobs {
+			continue
+		}
+		rate := float64(c.Failed) / float64(finished)
+		if rate <= r.cfg.Threshold {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Rule:     RuleJobFailureRate,
+			Key:      cmsID,
+			Severity: SeverityCritical,
+			Summary: fmt.Sprintf("%d of %d jobs (%.0f%%) for ACO %s failed in the last %s",
+				c.Failed, finished, rate*100, cmsID, r.cfg.Window),
+			FiredAt: now,
+		})
+	}
+	// Map order is random; keep notifications in a stable order
+	slices.SortFunc(alerts, func(a, b Alert) int { return cmp.Compare(a.Key, b.Key) })
+	return alerts, nil
+}
+
+type cclfModel struct {
+	model      string
+	pattern    *regexp.Regexp
+	transition time.Time // only the month and day are used
+}
+
+// cclfImport fires for each model that imported CCLF files during its last performance year but has not
+// imported any since the current one began.
+type cclfImport struct {
+	src    Source
+	cfg    CCLFImportConfig
+	models []cclfModel
+}
+
+func newCCLFImport(src Source, cfg CCLFImportConfig, acoConfigs []service.ACOConfig) (cclfImport, error) {
+	r := cclfImport{src: src, cfg: cfg}
+	for _, aco := range acoConfigs {
+		if aco.Disabled || aco.PerfYearTransition == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(aco.Pattern)
+		if err != nil {
+			return cclfImport{}, fmt.Errorf("failed to parse ACO model %s pattern: %w", aco.Model, err)
+		}
+		// MM/DD
+		transition, err := time.Parse("01/02", aco.PerfYearTransition)
+		if err != nil {
+			return cclfImport{}, fmt.Errorf("failed to parse ACO model %s perf year: %w", aco.Model, err)
+		}
+		r.models = append(r.models, cclfModel{aco.Model, pattern, transition})
+	}
+	return r, nil
+}
+
+func (r cclfImport) Name() string { return RuleCCLFImport }
+
+func (r cclfImport) Evaluate(ctx context.Context, now time.Time) ([]Alert, error) {
+	var alerts []Alert
+	for _, m := range r.models {
+		transition := lastTransition(m.transition, now)
+		if now.Sub(transition) < time.Duration(r.cfg.Days)*24*time.Hour {
+			continue
+		}
+
+		latest, err := r.src.LatestCCLFFiles(ctx, transition.AddDate(-1, 0, 0))
+		if err != nil {
+			return nil, err
+		}
+		var before, since bool
+		for cmsID, ts := range latest {
+			if !m.pattern.MatchString(cmsID) {
+				continue
+			}
+			if ts.Before(transition) {
+				before = true
+			} else {
+				since = true
+			}
+		}
+		// Models that weren't importing files last year aren't expected to start now
+		if !before || since {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			Rule:     RuleCCLFImport,
+			Key:      m.model,
+			Severity: SeverityWarning,
+			Summary: fmt.Sprintf("No CCLF files have been imported for %s ACOs since their performance year began on %s",
+				m.model, transition.Format("2006-01-02")),
+			FiredAt: now,
+		})
+	}
+	return alerts, nil
+}
+
+// lastTransition returns the most recent performance year transition on or before now.
+func lastTransition(transition, now time.Time) time.Time {
+	now = now.UTC()
+	t := time.Date(now.Year(), transition.Month(), transition.Day(), 0, 0, 0, 0, time.UTC)
+	if t.After(now) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t
+}
+
+// benePrefsFiles fires when fewer bene-prefs files were imported in the latest window than in the one before.
+type benePrefsFiles struct {
+	src Source
+	cfg BenePrefsFilesConfig
+}
+
+func (r benePrefsFiles) Name() string { return RuleBenePrefsFiles }
+
+func (r benePrefsFiles) Evaluate(ctx context.Context, now time.Time) ([]Alert, error) {
+	start := now.Add(-r.cfg.Window)
+	current, err := r.src.BenePrefsFileCount(ctx, start, now)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := r.src.BenePrefsFileCount(ctx, start.Add(-r.cfg.Window), start)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous == 0 || float64(previous-current)/float64(previous) < r.cfg.Drop {
+		return nil, nil
+	}
+	return []Alert{{
+		Rule:     RuleBenePrefsFiles,
+		Severity: SeverityWarning,
+		Summary: fmt.Sprintf("%d bene-prefs files were imported in the last %s, down from %d in the %s before",
+			current, r.cfg.Window, previous, r.cfg.Window),
+		FiredAt: now,
+	}}, nil
+}
+
+// queueAge fires when River jobs wait too long to be worked.
+type queueAge struct {
+	src Source
+	cfg QueueAgeConfig
+}
+
+func (r queueAge) Name() string { return RuleQueueAge }
+
+func (r queueAge) Evaluate(ctx context.Context, now time.Time) ([]Alert, error) {
+	oldest, err := r.src.OldestAvailableJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if oldest.IsZero() {
+		return nil, nil
+	}
+	age := now.Sub(oldest)
+	if age <= r.cfg.Threshold {
+		return nil, nil
+	}
+	return []Alert{{
+		Rule:     RuleQueueAge,
+		Severity: SeverityCritical,
+		Summary:  fmt.Sprintf("The oldest available River job has waited %s to be worked", age.Round(time.Second)),
+		FiredAt:  now,
+	}}, nil
+}
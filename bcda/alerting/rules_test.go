@@ -0,0 +1,172 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+type fakeSource struct {
+	jobCounts      map[string]JobCounts
+	jobsSince      time.Time
+	cclfFiles      map[string]time.Time
+	benePrefsFiles func(start, end time.Time) int
+	oldestJob      time.Time
+}
+
+func (s *fakeSource) JobCounts(_ context.Context, since time.Time) (map[string]JobCounts, error) {
+	s.jobsSince = since
+	return s.jobCounts, nil
+}
+
+func (s *fakeSource) LatestCCLFFiles(_ context.Context, since time.Time) (map[string]time.Time, error) {
+	latest := make(map[string]time.Time)
+	for cmsID, ts := range s.cclfFiles {
+		if !ts.Before(since) {
+			latest[cmsID] = ts
+		}
+	}
+	return latest, nil
+}
+
+func (s *fakeSource) BenePrefsFileCount(_ context.Context, start, end time.Time) (int, error) {
+	return s.benePrefsFiles(start, end), nil
+}
+
+func (s *fakeSource) OldestAvailableJob(context.Context) (time.Time, error) {
+	return s.oldestJob, nil
+}
+
+var now = time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+func TestNewRules(t *testing.T) {
+	rules, err := NewRules(&Config{QueueAge: QueueAgeConfig{Enabled: true}, JobFailureRate: JobFailureRateConfig{Enabled: true}}, &fakeSource{}, nil)
+	require.NoError(t, err)
+	var names []string
+	for _, r := range rules {
+		names = append(names, r.Name())
+	}
+	assert.Equal(t, []string{RuleJobFailureRate, RuleQueueAge}, names)
+
+	_, err = NewRules(&Config{CCLFImport: CCLFImportConfig{Enabled: true}}, &fakeSource{},
+		[]service.ACOConfig{{Model: "SSP", Pattern: `^A\d{4}$`, PerfYearTransition: "13/01"}})
+	assert.ErrorContains(t, err, "failed to parse ACO model SSP perf year")
+}
+
+func TestJobFailureRate(t *testing.T) {
+	src := &fakeSource{jobCounts: map[string]JobCounts{
+		"A9994": {Completed: 1, Failed: 3},
+		"A9995": {Completed: 3, Failed: 1}, // at the threshold
+		"A9996": {Failed: 2},               // too few jobs
+		"A9997": {Completed: 10},
+	}}
+	rule := jobFailureRate{src, JobFailureRateConfig{Window: time.Hour, Threshold: 0.25, MinJobs: 3}}
+
+	alerts, err := rule.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), src.jobsSince)
+	assert.Equal(t, []Alert{{
+		Rule:     RuleJobFailureRate,
+		Key:      "A9994",
+		Severity: SeverityCritical,
+		Summary:  "3 of 4 jobs (75%) for ACO A9994 failed in the last 1h0m0s",
+		FiredAt:  now,
+	}}, alerts)
+}
+
+func TestCCLFImport(t *testing.T) {
+	acoConfigs := []service.ACOConfig{
+		{Model: "SSP", Pattern: `^A\d{4}$`, PerfYearTransition: "01/01"},
+		{Model: "DC", Pattern: `^D\d{4}$`, PerfYearTransition: "03/10"},
+		{Model: "KCF", Pattern: `^K\d{4}$`, PerfYearTransition: "02/01"},
+		{Model: "CEC", Pattern: `^E\d{4}$`, PerfYearTransition: "01/01", Disabled: true},
+		{Model: "GUIDE", Pattern: `^GUIDE-\d{4}$`},
+	}
+	src := &fakeSource{cclfFiles: map[string]time.Time{
+		"A0001": time.Date(2025, time.December, 20, 0, 0, 0, 0, time.UTC), // nothing this year
+		"D0001": time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),  // within the days after transition
+		"K0001": time.Date(2026, time.February, 3, 0, 0, 0, 0, time.UTC),  // imported this year
+		"E0001": time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),  // disabled model
+	}}
+	rule, err := newCCLFImport(src, CCLFImportConfig{Days: 14}, acoConfigs)
+	require.NoError(t, err)
+
+	alerts, err := rule.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, []Alert{{
+		Rule:     RuleCCLFImport,
+		Key:      "SSP",
+		Severity: SeverityWarning,
+		Summary:  "No CCLF files have been imported for SSP ACOs since their performance year began on 2026-01-01",
+		FiredAt:  now,
+	}}, alerts)
+}
+
+func TestLastTransition(t *testing.T) {
+	transition := time.Date(0, time.April, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC), lastTransition(transition, now))
+	assert.Equal(t, time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), lastTransition(transition, time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestBenePrefsFiles(t *testing.T) {
+	weekAgo := now.Add(-168 * time.Hour)
+	tests := []struct {
+		name              string
+		current, previous int
+		fires             bool
+	}{
+		{"dropped", 2, 7, true},
+		{"dropped by threshold", 3, 6, true},
+		{"steady", 7, 7, false},
+		{"small drop", 5, 7, false},
+		{"none previously", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &fakeSource{benePrefsFiles: func(start, end time.Time) int {
+				if start.Equal(weekAgo) && end.Equal(now) {
+					return tt.current
+				}
+				return tt.previous
+			}}
+			rule := benePrefsFiles{src, BenePrefsFilesConfig{Window: 168 * time.Hour, Drop: 0.5}}
+
+			alerts, err := rule.Evaluate(context.Background(), now)
+			require.NoError(t, err)
+			if !tt.fires {
+				assert.Empty(t, alerts)
+				return
+			}
+			require.Len(t, alerts, 1)
+			assert.Equal(t, RuleBenePrefsFiles, alerts[0].Rule)
+			assert.Contains(t, alerts[0].Summary, "imported in the last 168h0m0s")
+		})
+	}
+}
+
+func TestQueueAge(t *testing.T) {
+	rule := queueAge{&fakeSource{}, QueueAgeConfig{Threshold: 30 * time.Minute}}
+	alerts, err := rule.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	assert.Empty(t, alerts, "empty queue")
+
+	rule.src = &fakeSource{oldestJob: now.Add(-10 * time.Minute)}
+	alerts, err = rule.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	rule.src = &fakeSource{oldestJob: now.Add(-45 * time.Minute)}
+	alerts, err = rule.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, []Alert{{
+		Rule:     RuleQueueAge,
+		Severity: SeverityCritical,
+		Summary:  "The oldest available River job has waited 45m0s to be worked",
+		FiredAt:  now,
+	}}, alerts)
+}
@@ -0,0 +1,132 @@
+package alerting
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
+)
+
+// JobCounts are the jobs an ACO created that have finished.
+type JobCounts struct {
+	Completed int
+	Failed    int
+}
+
+// Source provides the data the rules are evaluated against.
+type Source interface {
+	// JobCounts returns the finished jobs created at or after since, by CMS ID.
+	JobCounts(ctx context.Context, since time.Time) (map[string]JobCounts, error)
+	// LatestCCLFFiles returns the timestamp of the latest imported CCLF file at or after since, by CMS ID.
+	LatestCCLFFiles(ctx context.Context, since time.Time) (map[string]time.Time, error)
+	// BenePrefsFileCount returns the number of imported bene-prefs files timestamped at or after start and
+	// before end.
+	BenePrefsFileCount(ctx context.Context, start, end time.Time) (int, error)
+	// OldestAvailableJob returns when the longest waiting River job that is ready to be worked was
+	// scheduled, or the zero time if there isn't one.
+	OldestAvailableJob(ctx context.Context) (time.Time, error)
+}
+
+type dbSource struct {
+	db *sql.DB
+}
+
+func NewSource(db *sql.DB) Source {
+	return dbSource{db}
+}
+
+func (s dbSource) JobCounts(ctx context.Context, since time.Time) (map[string]JobCounts, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT a.cms_id, j.status, COUNT(*) FROM jobs j JOIN acos a ON a.uuid = j.aco_id
+		WHERE j.created_at >= $1 AND a.cms_id IS NOT NULL GROUP BY a.cms_id, j.status`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]JobCounts)
+	for rows.Next() {
+		var (
+			cmsID  string
+			status models.JobStatus
+			count  int
+		)
+		if err := rows.Scan(&cmsID, &status, &count); err != nil {
+			return nil, err
+		}
+		c := counts[cmsID]
+		switch status {
+		case models.JobStatusCompleted, models.JobStatusArchived, models.JobStatusExpired:
+			c.Completed += count
+		case models.JobStatusFailed, models.JobStatusFailedExpired:
+			c.Failed += count
+		default:
+			continue
+		}
+		counts[cmsID] = c
+	}
+	return counts, rows.Err()
+}
+
+func (s dbSource) LatestCCLFFiles(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT aco_cms_id, MAX("timestamp") FROM cclf_files
+		WHERE import_status = $1 AND "timestamp" >= $2 AND aco_cms_id IS NOT NULL GROUP BY aco_cms_id`,
+		constants.ImportComplete, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	latest := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			cmsID string
+			ts    time.Time
+		)
+		if err := rows.Scan(&cmsID, &ts); err != nil {
+			return nil, err
+		}
+		latest[cmsID] = ts
+	}
+	return latest, rows.Err()
+}
+
+func (s dbSource) BenePrefsFileCount(ctx context.Context, start, end time.Time) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM suppression_files
+		WHERE import_status = $1 AND "timestamp" >= $2 AND "timestamp" < $3`,
+		constants.ImportComplete, start, end).Scan(&count)
+	return count, err
+}
+
+func (s dbSource) OldestAvailableJob(ctx context.Context) (time.Time, error) {
+	var oldest sql.NullTime
+	err := s.db.QueryRowContext(ctx, `SELECT MIN(scheduled_at) FROM river_job
+		WHERE state = 'available' AND scheduled_at <= now()`).Scan(&oldest)
+	return oldest.Time, err
+}
+
+type dbState struct {
+	db *sql.DB
+}
+
+// NewState keeps track of sent alerts in the database, so every worker sees them.
+func NewState(db *sql.DB) State {
+	return dbState{db}
+}
+
+func (s dbState) LastNotified(ctx context.Context, id string) (time.Time, error) {
+	var at time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT notified_at FROM alert_notifications WHERE alert_id = $1`, id).Scan(&at)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return at, err
+}
+
+func (s dbState) RecordNotified(ctx context.Context, id string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO alert_notifications (alert_id, notified_at) VALUES ($1, $2)
+		ON CONFLICT (alert_id) DO UPDATE SET notified_at = EXCLUDED.notified_at`, id, at)
+	return err
+}
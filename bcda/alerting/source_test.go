@@ -0,0 +1,72 @@
+package alerting
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	src := NewSource(db)
+
+	mock.ExpectQuery("FROM jobs").WithArgs(now).WillReturnRows(sqlmock.NewRows([]string{"cms_id", "status", "count"}).
+		AddRow("A9994", "Completed", 2).
+		AddRow("A9994", "Archived", 1).
+		AddRow("A9994", "Failed", 1).
+		AddRow("A9994", "In Progress", 4).
+		AddRow("A9995", "FailedExpired", 3))
+	counts, err := src.JobCounts(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]JobCounts{"A9994": {Completed: 3, Failed: 1}, "A9995": {Failed: 3}}, counts)
+
+	mock.ExpectQuery("FROM cclf_files").WithArgs("Completed", now).WillReturnRows(sqlmock.NewRows([]string{"aco_cms_id", "max"}).
+		AddRow("A9994", now))
+	latest, err := src.LatestCCLFFiles(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"A9994": now}, latest)
+
+	mock.ExpectQuery("FROM suppression_files").WithArgs("Completed", now.Add(-time.Hour), now).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	count, err := src.BenePrefsFileCount(ctx, now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	mock.ExpectQuery("FROM river_job").WillReturnRows(sqlmock.NewRows([]string{"min"}).AddRow(nil))
+	oldest, err := src.OldestAvailableJob(ctx)
+	require.NoError(t, err)
+	assert.True(t, oldest.IsZero())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestState(t *testing.T) {
+	ctx := context.Background()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	state := NewState(db)
+
+	mock.ExpectQuery("FROM alert_notifications").WithArgs("queue_age").WillReturnError(sql.ErrNoRows)
+	last, err := state.LastNotified(ctx, "queue_age")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	mock.ExpectExec("INSERT INTO alert_notifications").WithArgs("queue_age", now).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, state.RecordNotified(ctx, "queue_age", now))
+
+	mock.ExpectQuery("FROM alert_notifications").WithArgs("queue_age").WillReturnRows(sqlmock.NewRows([]string{"notified_at"}).AddRow(now))
+	last, err = state.LastNotified(ctx, "queue_age")
+	require.NoError(t, err)
+	assert.Equal(t, now, last)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/CMSgov/bcda-app/bcda/auth"
 	"github.com/CMSgov/bcda-app/bcda/client/fhir"
 	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
 	responseutils "github.com/CMSgov/bcda-app/bcda/responseutils"
@@ -30,6 +31,7 @@ import (
 	responseutilsv3 "github.com/CMSgov/bcda-app/bcda/responseutils/v3"
 	"github.com/CMSgov/bcda-app/bcda/service"
 	"github.com/CMSgov/bcda-app/bcda/servicemux"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/bcda/utils"
 	"github.com/CMSgov/bcda-app/bcda/web/middleware"
 	"github.com/CMSgov/bcda-app/bcdaworker/queueing"
@@ -330,10 +332,24 @@ func (h *Handler) JobStatus(w http.ResponseWriter, r *http.Request) {
 			scheme = "https"
 		}
 
+		// When signed URLs are enabled, each file URL carries its own expiring signature, which never outlives the job.
+		signURLs := auth.SignedURLsEnabled()
+		signedExpiry := time.Now().Add(auth.SignedURLTTL())
+		if jobExpiry := job.UpdatedAt.Add(h.JobTimeout); jobExpiry.Before(signedExpiry) {
+			signedExpiry = jobExpiry
+		}
+		dataURL := func(fileName string) string {
+			u := fmt.Sprintf("%s://%s/data/%d/%s", scheme, r.Host, jobID, fileName)
+			if signURLs {
+				u = fmt.Sprintf("%s?%s", u, auth.SignDataURL(uint(jobID), fileName, signedExpiry).Encode())
+			}
+			return u
+		}
+
 		rb := BulkResponseBody{
 			TransactionTime:     job.TransactionTime,
 			RequestURL:          job.RequestURL,
-			RequiresAccessToken: true,
+			RequiresAccessToken: !signURLs,
 			Files:               []FileItem{},
 			Errors:              []FileItem{},
 			JobID:               job.ID,
@@ -344,7 +360,7 @@ func (h *Handler) JobStatus(w http.ResponseWriter, r *http.Request) {
 		if _, err := os.Stat(filePath); !os.IsNotExist(err) { // #nosec G703
 			rb.Errors = append(rb.Errors, FileItem{
 				Type: "OperationOutcome",
-				URL:  fmt.Sprintf("%s://%s/data/%d/%s", scheme, r.Host, jobID, constants.WarningsAndInfoFileName),
+				URL:  dataURL(constants.WarningsAndInfoFileName),
 			})
 		}
 
@@ -352,7 +368,7 @@ func (h *Handler) JobStatus(w http.ResponseWriter, r *http.Request) {
 			// data files
 			fi := FileItem{
 				Type: jobKey.ResourceType,
-				URL:  fmt.Sprintf("%s://%s/data/%d/%s", scheme, r.Host, jobID, strings.TrimSpace(jobKey.FileName)),
+				URL:  dataURL(strings.TrimSpace(jobKey.FileName)),
 			}
 
 			// Check if "error" is not in the filename
@@ -368,7 +384,7 @@ func (h *Handler) JobStatus(w http.ResponseWriter, r *http.Request) {
 			if _, err := os.Stat(errFilePath); !os.IsNotExist(err) { // #nosec G703
 				errFI := FileItem{
 					Type: "OperationOutcome",
-					URL:  fmt.Sprintf("%s://%s/data/%d/%s-error.ndjson", scheme, r.Host, jobID, errFileName),
+					URL:  dataURL(fmt.Sprintf("%s-error.ndjson", errFileName)),
 				}
 				rb.Errors = append(rb.Errors, errFI)
 			}
@@ -454,6 +470,8 @@ func (h *Handler) DeleteJob(w http.ResponseWriter, r *http.Request) {
 type AttributionFileStatus struct {
 	Timestamp time.Time `json:"timestamp"`
 	Type      string    `json:"type"`
+	// Status is only set for attribution files that were withdrawn after being ingested
+	Status string `json:"status,omitempty"`
 }
 
 type AttributionFileStatusResponse struct {
@@ -494,6 +512,7 @@ func (h *Handler) AttributionStatus(w http.ResponseWriter, r *http.Request) {
 	if asd != nil {
 		resp.Data = append(resp.Data, *asd)
 	}
+	resp.Data = append(resp.Data, h.getWithdrawnAttributionFiles(ctx, ad.CMSID, models.FileTypeDefault, asd)...)
 
 	// Retrieve the most recent cclf 8 runout file we have successfully ingested
 	asr, err := h.getAttributionFileStatus(ctx, ad.CMSID, models.FileTypeRunout)
@@ -509,6 +528,7 @@ func (h *Handler) AttributionStatus(w http.ResponseWriter, r *http.Request) {
 	if asr != nil {
 		resp.Data = append(resp.Data, *asr)
 	}
+	resp.Data = append(resp.Data, h.getWithdrawnAttributionFiles(ctx, ad.CMSID, models.FileTypeRunout, asr)...)
 
 	if resp.Data == nil {
 		ctx, _ = log.WriteWarnWithFields(
@@ -562,6 +582,32 @@ func (h *Handler) getAttributionFileStatus(ctx context.Context, CMSID string, fi
 	return status, nil
 }
 
+// getWithdrawnAttributionFiles lists the attribution files that were superseded or invalidated after being
+// ingested and are newer than current, so clients can tell why their attribution has not been updated.
+func (h *Handler) getWithdrawnAttributionFiles(ctx context.Context, CMSID string, fileType models.CCLFFileType, current *AttributionFileStatus) []AttributionFileStatus {
+	var after time.Time
+	if current != nil {
+		after = current.Timestamp
+	}
+
+	files, err := h.Svc.GetWithdrawnCCLFFiles(ctx, CMSID, after, fileType)
+	if err != nil {
+		log.GetCtxLogger(ctx).Errorf("failed to retrieve withdrawn attribution files: %+v", err)
+		return nil
+	}
+
+	statusType := "withdrawn_attribution_update"
+	if fileType == models.FileTypeRunout {
+		statusType = "withdrawn_runout_update"
+	}
+
+	statuses := make([]AttributionFileStatus, 0, len(files))
+	for _, f := range files {
+		statuses = append(statuses, AttributionFileStatus{Timestamp: f.Timestamp, Type: statusType, Status: f.ImportStatus})
+	}
+	return statuses
+}
+
 // bulkRequest generates a job ID for a bulk export request. It will not queue a job
 // until auth, attribution, and request resources are validated.
 func (h *Handler) bulkRequest(w http.ResponseWriter, r *http.Request, reqType constants.DataRequestType) {
@@ -748,6 +794,12 @@ func (h *Handler) bulkRequest(w http.ResponseWriter, r *http.Request, reqType co
 		return
 	}
 
+	var model string
+	if acoConfig, ok := h.Svc.GetACOConfigForID(ad.CMSID); ok {
+		model = acoConfig.Model
+	}
+	metrics.JobCreated(model)
+
 	if newJob.ID != 0 {
 		ctx, _ = log.WriteInfoWithFields(
 			ctx,
@@ -773,6 +825,7 @@ func (h *Handler) bulkRequest(w http.ResponseWriter, r *http.Request, reqType co
 		ClaimsDate:             timeConstraints.ClaimsDate,
 		OptOutDate:             timeConstraints.OptOutDate,
 		TransactionID:          ctx.Value(m.CtxTransactionKey).(string),
+		TraceContext:           tracing.Inject(ctx),
 	}
 
 	logger.Infof("Adding jobs using %T", h.Enq)
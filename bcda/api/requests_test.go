@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -496,6 +497,53 @@ func (s *RequestsTestSuite) TestJobStatus_SuccessReturnsProperFiles() {
 	assert.Equal(s.T(), `{"transactionTime":"0001-01-01T00:00:00Z","request":"https://bcda.test.gov/v2/this-is-a-test","requiresAccessToken":true,"output":[{"type":"","url":"http://bcda.ms.gov/data/1/success1.ndjson"},{"type":"","url":"http://bcda.ms.gov/data/1/success2.ndjson"}],"error":[{"type":"OperationOutcome","url":"http://bcda.ms.gov/data/1/warnings-and-info.ndjson"},{"type":"OperationOutcome","url":"http://bcda.ms.gov/data/1/success1-error.ndjson"}],"JobID":1}`, string(body))
 }
 
+func (s *RequestsTestSuite) TestJobStatus_SignedURLs() {
+	conf.SetEnv(s.T(), "BCDA_SIGNED_DATA_URLS", "true")
+	conf.SetEnv(s.T(), "BCDA_SIGNED_URL_KEY", "test-signing-key")
+	defer func() {
+		_ = conf.UnsetEnv(s.T(), "BCDA_SIGNED_DATA_URLS")
+		_ = conf.UnsetEnv(s.T(), "BCDA_SIGNED_URL_KEY")
+	}()
+
+	mockSvc := &service.MockService{}
+	mockSvc.On("GetJobAndKeys", testUtils.CtxMatcher, mock.Anything).Return(
+		&models.Job{
+			ID:         1,
+			Status:     models.JobStatusCompleted,
+			RequestURL: "https://bcda.test.gov/v2/this-is-a-test",
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		},
+		[]*models.JobKey{{JobID: 1, FileName: "signed1.ndjson"}},
+		nil,
+	)
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "http://bcda.ms.gov/api/v2/jobs/1", nil)
+	assert.NoError(s.T(), err)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("jobID", "1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	h := &Handler{Svc: mockSvc, JobTimeout: (time.Hour * 24)}
+	h.RespWriter = responseutils.NewFhirResponseWriter()
+
+	h.JobStatus(rr, req)
+
+	assert.Equal(s.T(), http.StatusOK, rr.Code)
+	var rb BulkResponseBody
+	assert.NoError(s.T(), json.Unmarshal(rr.Body.Bytes(), &rb))
+	assert.False(s.T(), rb.RequiresAccessToken)
+	assert.Len(s.T(), rb.Files, 1)
+
+	u, err := url.Parse(rb.Files[0].URL)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "/data/1/signed1.ndjson", u.Path)
+	q := u.Query()
+	assert.NoError(s.T(), auth.VerifyDataURLSignature(1, "signed1.ndjson", q.Get(auth.SignedURLExpiryParam), q.Get(auth.SignedURLSignatureParam), time.Now()))
+}
+
 func (s *RequestsTestSuite) addNewJob(jobs []*models.Job, id uint, status models.JobStatus, apiVersion string) []*models.Job {
 	return append(jobs, &models.Job{
 		ID:         id,
@@ -527,6 +575,7 @@ func (s *RequestsTestSuite) TestAttributionStatus() {
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
 			mockSvc := &service.MockService{}
+			mockSvc.On("GetWithdrawnCCLFFiles", testUtils.CtxMatcher, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
 			for i, name := range tt.fileNames {
 				fileType := models.FileTypeDefault
@@ -602,6 +651,31 @@ func (s *RequestsTestSuite) TestAttributionStatus() {
 	}
 }
 
+func (s *RequestsTestSuite) TestAttributionStatusWithdrawnFiles() {
+	current := &models.CCLFFile{ID: 1, Name: "cclf_test_file_1", Timestamp: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), CCLFNum: 8}
+	withdrawn := &models.CCLFFile{ID: 2, Name: "cclf_test_file_2", Timestamp: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), CCLFNum: 8, ImportStatus: constants.ImportInvalid}
+
+	mockSvc := &service.MockService{}
+	mockSvc.On("GetLatestCCLFFile", testUtils.CtxMatcher, mock.Anything, mock.Anything, mock.Anything, models.FileTypeDefault).Return(current, nil)
+	mockSvc.On("GetLatestCCLFFile", testUtils.CtxMatcher, mock.Anything, mock.Anything, mock.Anything, models.FileTypeRunout).Return(nil, service.CCLFNotFoundError{FileNumber: 8})
+	mockSvc.On("GetWithdrawnCCLFFiles", testUtils.CtxMatcher, mock.Anything, current.Timestamp, models.FileTypeDefault).Return([]*models.CCLFFile{withdrawn}, nil)
+	mockSvc.On("GetWithdrawnCCLFFiles", testUtils.CtxMatcher, mock.Anything, time.Time{}, models.FileTypeRunout).Return(nil, nil)
+
+	h := newHandler(s.resourceType, "/v1/fhir", "v1", s.db, s.pool)
+	h.Svc = mockSvc
+
+	rr := httptest.NewRecorder()
+	h.AttributionStatus(rr, s.genASRequest())
+	assert.Equal(s.T(), http.StatusOK, rr.Code)
+
+	var resp AttributionFileStatusResponse
+	assert.NoError(s.T(), json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(s.T(), []AttributionFileStatus{
+		{Timestamp: current.Timestamp, Type: "last_attribution_update"},
+		{Timestamp: withdrawn.Timestamp, Type: "withdrawn_attribution_update", Status: constants.ImportInvalid},
+	}, resp.Data)
+}
+
 func (s *RequestsTestSuite) TestRunoutDisabled() {
 	err := conf.SetEnv(s.T(), "BCDA_ENABLE_RUNOUT", "false")
 	assert.Empty(s.T(), err)
@@ -440,7 +440,7 @@ func (a ApiV1) GetVersion(w http.ResponseWriter, r *http.Request) {
 }
 
 func (a ApiV1) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	m := make(map[string]string)
+	m := make(map[string]interface{})
 
 	dbStatus, dbOK := a.healthChecker.IsDatabaseOK()
 	ssasStatus, ssasOK := a.healthChecker.IsSsasOK()
@@ -450,6 +450,16 @@ func (a ApiV1) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	m["ssas"] = ssasStatus
 	m["ssas_introspect"] = introspectStatus
 
+	// BFD stats are informational; a slow or failing BFD doesn't make the API itself unhealthy
+	if r.URL.Query().Get("detail") == "true" {
+		if stats, err := a.healthChecker.BlueButtonStats(); err != nil {
+			log.API.Error("Health check: failed to get BFD stats: ", err.Error())
+			m["bfd"] = "error getting BFD stats"
+		} else {
+			m["bfd"] = stats
+		}
+	}
+
 	if !dbOK || !ssasOK || !introspectOK {
 		w.WriteHeader(http.StatusBadGateway)
 	} else {
@@ -475,7 +485,8 @@ swagger:route GET /_auth metadata getAuthInfo
 
 # Get details about auth
 
-Returns the auth provider that is currently being used. Note that this endpoint is **not** prefixed with the base path (e.g. /api/v1).
+Returns the auth provider that is currently being used. When a valid token is presented, also returns when the
+client's credentials expire. Note that this endpoint is **not** prefixed with the base path (e.g. /api/v1).
 
 Produces:
 - application/json
@@ -495,6 +506,15 @@ func (a ApiV1) GetAuthInfo(w http.ResponseWriter, r *http.Request) {
 	} else {
 		respMap["error message"] = err.Error()
 	}
+	if ad, ok := r.Context().Value(auth.AuthDataContextKey).(auth.AuthData); ok && ad.ClientID != "" {
+		if creds, err := a.provider.GetAuthData(ad.ClientID); err == nil && !creds.CredentialsExpireAt.IsZero() {
+			respMap["credentials_expire_at"] = creds.CredentialsExpireAt.UTC().Format(time.RFC3339)
+			if warning := auth.CredentialExpiryWarning(creds, time.Now()); warning != "" {
+				respMap["credentials_warning"] = warning
+				w.Header().Set("Warning", warning)
+			}
+		}
+	}
 	respBytes, err := json.Marshal(respMap)
 	if err != nil {
 		logger := log.GetCtxLogger(r.Context())
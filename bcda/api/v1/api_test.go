@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,8 +24,10 @@ import (
 
 	"github.com/CMSgov/bcda-app/bcda/api"
 	"github.com/CMSgov/bcda-app/bcda/auth"
+	"github.com/CMSgov/bcda-app/bcda/client"
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	"github.com/CMSgov/bcda-app/bcda/database"
+	"github.com/CMSgov/bcda-app/bcda/health"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/fhir/stu3"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres/postgrestest"
@@ -52,7 +55,9 @@ type APITestSuite struct {
 
 func (s *APITestSuite) SetupSuite() {
 	s.db = database.Connect()
-	s.provider = auth.NewProvider(s.db)
+	var err error
+	s.provider, err = auth.NewProvider(s.db)
+	s.Require().NoError(err)
 	s.apiV1 = NewApiV1(s.db, s.pool, s.provider)
 
 	origDate := conf.GetEnv("CCLF_REF_DATE")
@@ -600,6 +605,52 @@ func (s *APITestSuite) TestHealthCheck() {
 	handler.ServeHTTP(s.rr, req)
 	assert.Equal(s.T(), http.StatusOK, s.rr.Code)
 }
+
+func TestHealthCheckDetail(t *testing.T) {
+	lastSuccess := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	stats := []health.WorkerBFDStats{{
+		Instance:  "worker-1",
+		BFDStats:  client.BFDStats{BasePath: constants.BFDV3Path, Requests: 10, ErrorRate: 0.2, P50Ms: 120, P95Ms: 900, LastSuccess: &lastSuccess},
+		UpdatedAt: lastSuccess,
+	}}
+
+	tests := []struct {
+		name     string
+		target   string
+		stats    []health.WorkerBFDStats
+		statsErr error
+		expected interface{}
+	}{
+		{"Without detail", "/_health", nil, nil, nil},
+		{"With detail", "/_health?detail=true", stats, nil, []interface{}{map[string]interface{}{
+			"instance": "worker-1", "base_path": constants.BFDV3Path, "requests": float64(10), "error_rate": 0.2,
+			"p50_ms": float64(120), "p95_ms": float64(900), "last_success": "2025-01-02T03:04:05Z", "updated_at": "2025-01-02T03:04:05Z",
+		}}},
+		{"Stats error", "/_health?detail=true", nil, errors.New("connection refused"), "error getting BFD stats"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := health.NewMockHealthChecker(t)
+			hc.EXPECT().IsDatabaseOK().Return("ok", true)
+			hc.EXPECT().IsSsasOK().Return("ok", true)
+			hc.EXPECT().IsSsasIntrospectOK().Return("ok", true)
+			if tt.expected != nil {
+				hc.EXPECT().BlueButtonStats().Return(tt.stats, tt.statsErr)
+			}
+
+			rr := httptest.NewRecorder()
+			ApiV1{healthChecker: hc}.HealthCheck(rr, httptest.NewRequest("GET", tt.target, nil))
+			assert.Equal(t, http.StatusOK, rr.Code)
+
+			var resp map[string]interface{}
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, "ok", resp["database"])
+			assert.Equal(t, tt.expected, resp["bfd"])
+		})
+	}
+}
+
 func (s *APITestSuite) TestAuthInfo() {
 	req, err := http.NewRequest("GET", "/_auth", nil)
 	assert.Nil(s.T(), err)
@@ -1,25 +1,39 @@
 package v3
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/CMSgov/bcda-app/bcda/api"
 	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/fhir/r4"
+	"github.com/CMSgov/bcda-app/bcda/models/postgres"
+	"github.com/CMSgov/bcda-app/bcda/responseutils"
+	responseutilsv3 "github.com/CMSgov/bcda-app/bcda/responseutils/v3"
 	"github.com/CMSgov/bcda-app/bcda/service"
 	"github.com/CMSgov/bcda-app/bcda/servicemux"
+	"github.com/CMSgov/bcda-app/bcda/utils"
+	"github.com/CMSgov/bcda-app/bcda/web/middleware"
 	"github.com/CMSgov/bcda-app/conf"
 	"github.com/CMSgov/bcda-app/log"
+	"github.com/go-chi/chi/v5"
 	pgxv5Pool "github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pborman/uuid"
+	"github.com/sirupsen/logrus"
 )
 
 type ApiV3 struct {
 	handler *api.Handler
 	db      *sql.DB
+	r       models.Repository
 }
 
 func NewApiV3(db *sql.DB, pool *pgxv5Pool.Pool) *ApiV3 {
@@ -33,7 +47,7 @@ func NewApiV3(db *sql.DB, pool *pgxv5Pool.Pool) *ApiV3 {
 		panic("Failed to configure resource DataTypes")
 	} else {
 		h := api.NewHandler(resources, constants.BFDV3Path, constants.V3Version, db, pool)
-		return &ApiV3{handler: h, db: db}
+		return &ApiV3{handler: h, db: db, r: postgres.NewRepository(db)}
 	}
 }
 
@@ -219,6 +233,344 @@ func (a ApiV3) AttributionStatus(w http.ResponseWriter, r *http.Request) {
 	a.handler.AttributionStatus(w, r)
 }
 
+/*
+swagger:route GET /api/v3/AuditEvent auditEventv3 auditEvent
+
+# Get audit events
+
+Returns the API activity of your ACO as FHIR AuditEvent resources: each bulk export request (including the files it produced) and each file download.
+Use the `_since` parameter to only return activity recorded at or after the given FHIR instant.
+Events are returned a page at a time, most recent first: use `_count` to set the page size (default 1000, at most 5000) and `_offset` to skip events.
+When more events remain, a `Link` header with `rel="next"` points to the next page.
+
+Produces:
+- application/fhir+json
+
+Schemes: http, https
+
+Security:
+
+	bearer_token:
+
+Responses:
+
+	200: auditEventResponse
+	400: badRequestResponse
+	401: invalidCredentials
+	500: errorResponse
+*/
+func (a ApiV3) AuditEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ad, err := api.GetAuthDataFromCtx(r)
+	if err != nil {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.TokenErr, err),
+			logrus.Fields{"resp_status": http.StatusUnauthorized},
+		)
+		a.handler.RespWriter.OpOutcome(ctx, w, http.StatusUnauthorized, responseutils.TokenErr, "")
+		return
+	}
+
+	offset, count, err := parsePaging(r.URL.Query())
+	if err != nil {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.RequestErr, err),
+			logrus.Fields{"resp_status": http.StatusBadRequest},
+		)
+		a.handler.RespWriter.OpOutcome(ctx, w, http.StatusBadRequest, responseutils.RequestErr, err.Error())
+		return
+	}
+
+	var since time.Time
+	if rp, ok := middleware.GetRequestParamsFromCtx(ctx); ok {
+		since = rp.Since
+	}
+
+	acoID := uuid.Parse(ad.ACOID)
+	total, jobs, jobKeys, downloads, err := a.getAuditEventPage(ctx, acoID, since, offset, count)
+	if err != nil {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.DbErr, err),
+			logrus.Fields{"resp_status": http.StatusInternalServerError},
+		)
+		a.handler.RespWriter.Exception(ctx, w, http.StatusInternalServerError, responseutils.DbErr, "")
+		return
+	}
+
+	setNextLink(w, r, offset, count, total)
+
+	scheme := "http"
+	if servicemux.IsHTTPS(r) {
+		scheme = "https"
+	}
+	host := fmt.Sprintf("%s://%s", scheme, r.Host)
+
+	responseutilsv3.NewFhirResponseWriter().AuditEventBundle(w, ad.CMSID, total, jobs, jobKeys, downloads, host)
+}
+
+// getAuditEventPage returns the jobs (along with their job keys) and downloads that make up count of the ACO's
+// audit events recorded at or after since, skipping the first offset events, and the total number of events.
+func (a ApiV3) getAuditEventPage(ctx context.Context, acoID uuid.UUID, since time.Time, offset, count int) (
+	total int, jobs []*models.Job, jobKeys map[uint][]*models.JobKey, downloads []*models.JobKeyDownload, err error) {
+	jobs, downloads, total, err = a.r.GetAuditEvents(ctx, acoID, since, offset, count)
+	if err != nil {
+		return 0, nil, nil, nil, err
+	}
+
+	jobIDs := make([]uint, len(jobs))
+	for idx, job := range jobs {
+		jobIDs[idx] = job.ID
+	}
+	if jobKeys, err = a.r.GetJobKeysByJobIDs(ctx, acoID, jobIDs); err != nil {
+		return 0, nil, nil, nil, err
+	}
+
+	return total, jobs, jobKeys, downloads, nil
+}
+
+const (
+	groupAll    = "all"
+	groupRunout = "runout"
+
+	// Page sizes of searches paged with _count and _offset
+	defaultPageSize = 1000
+	maxPageSize     = 5000
+	// Far past the end of any search, and small enough that offset plus count can't overflow
+	maxOffset = 10000000
+)
+
+/*
+swagger:route GET /api/v3/Group/{groupId} groupv3 group
+
+# Get group
+
+Returns the beneficiaries attributed to your ACO as a FHIR Group, using the same attribution file a new export of the group would.
+Each member is identified by MBI, and members who have opted out of data sharing are marked inactive.
+Members are returned a page at a time: use `_count` to set the page size (default 1000, at most 5000) and `_offset` to skip members.
+When more members remain, a `Link` header with `rel="next"` points to the next page.
+
+Produces:
+- application/fhir+json
+
+Schemes: http, https
+
+Security:
+
+	bearer_token:
+
+Responses:
+
+	200: groupResponse
+	400: badRequestResponse
+	401: invalidCredentials
+	404: notFoundResponse
+	500: errorResponse
+*/
+func (a ApiV3) Group(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ad, err := api.GetAuthDataFromCtx(r)
+	if err != nil {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.TokenErr, err),
+			logrus.Fields{"resp_status": http.StatusUnauthorized},
+		)
+		a.handler.RespWriter.OpOutcome(ctx, w, http.StatusUnauthorized, responseutils.TokenErr, "")
+		return
+	}
+
+	groupID := chi.URLParam(r, "groupId")
+	if !isAvailableGroup(groupID) {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: Invalid group ID (%+v)", responseutils.RequestErr, groupID),
+			logrus.Fields{"resp_status": http.StatusBadRequest},
+		)
+		a.handler.RespWriter.OpOutcome(ctx, w, http.StatusBadRequest, responseutils.RequestErr, "Invalid group ID")
+		return
+	}
+
+	offset, count, err := parsePaging(r.URL.Query())
+	if err != nil {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.RequestErr, err),
+			logrus.Fields{"resp_status": http.StatusBadRequest},
+		)
+		a.handler.RespWriter.OpOutcome(ctx, w, http.StatusBadRequest, responseutils.RequestErr, err.Error())
+		return
+	}
+
+	file, tc, err := a.getGroupFile(ctx, ad.CMSID, groupID)
+	if err != nil {
+		if errors.As(err, &service.CCLFNotFoundError{}) {
+			msg := fmt.Sprintf("no attribution information is available for Group '%s'", groupID)
+			ctx, _ = log.WriteWarnWithFields(
+				ctx,
+				fmt.Sprintf("%s: %s: %+v", responseutils.NotFoundErr, msg, err),
+				logrus.Fields{"resp_status": http.StatusNotFound},
+			)
+			a.handler.RespWriter.NotFound(ctx, w, http.StatusNotFound, responseutils.NotFoundErr, msg)
+			return
+		}
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.InternalErr, err),
+			logrus.Fields{"resp_status": http.StatusInternalServerError},
+		)
+		a.handler.RespWriter.Exception(ctx, w, http.StatusInternalServerError, responseutils.InternalErr, "")
+		return
+	}
+
+	roster, err := a.handler.Svc.GetAttributionRoster(ctx, ad.CMSID, file.ID, tc.OptOutDate, offset, count)
+	if err != nil {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.DbErr, err),
+			logrus.Fields{"resp_status": http.StatusInternalServerError},
+		)
+		a.handler.RespWriter.Exception(ctx, w, http.StatusInternalServerError, responseutils.DbErr, "")
+		return
+	}
+
+	setNextLink(w, r, offset, count, roster.Total)
+
+	rw := responseutilsv3.NewFhirResponseWriter()
+	rw.WriteGroupResponse(rw.CreateGroup(groupID, ad.CMSID, file, roster.Total, roster.Beneficiaries, roster.SuppressedMBIs), w)
+}
+
+/*
+swagger:route GET /api/v3/Group groupv3 groupSearch
+
+# Search groups
+
+Returns the groups available to your ACO as a searchset of FHIR Groups. Members are not included; use the group read to page through them.
+
+Produces:
+- application/fhir+json
+
+Schemes: http, https
+
+Security:
+
+	bearer_token:
+
+Responses:
+
+	200: groupSearchResponse
+	401: invalidCredentials
+	500: errorResponse
+*/
+func (a ApiV3) GroupSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ad, err := api.GetAuthDataFromCtx(r)
+	if err != nil {
+		ctx, _ = log.WriteErrorWithFields(
+			ctx,
+			fmt.Sprintf("%s: %+v", responseutils.TokenErr, err),
+			logrus.Fields{"resp_status": http.StatusUnauthorized},
+		)
+		a.handler.RespWriter.OpOutcome(ctx, w, http.StatusUnauthorized, responseutils.TokenErr, "")
+		return
+	}
+
+	rw := responseutilsv3.NewFhirResponseWriter()
+	var groups []*r4.Group
+	for _, groupID := range []string{groupAll, groupRunout} {
+		if !isAvailableGroup(groupID) {
+			continue
+		}
+
+		file, tc, err := a.getGroupFile(ctx, ad.CMSID, groupID)
+		if errors.As(err, &service.CCLFNotFoundError{}) {
+			continue
+		}
+		var roster service.AttributionRoster
+		if err == nil {
+			roster, err = a.handler.Svc.GetAttributionRoster(ctx, ad.CMSID, file.ID, tc.OptOutDate, 0, 0)
+		}
+		if err != nil {
+			ctx, _ = log.WriteErrorWithFields(
+				ctx,
+				fmt.Sprintf("%s: %+v", responseutils.InternalErr, err),
+				logrus.Fields{"resp_status": http.StatusInternalServerError},
+			)
+			a.handler.RespWriter.Exception(ctx, w, http.StatusInternalServerError, responseutils.InternalErr, "")
+			return
+		}
+		groups = append(groups, rw.CreateGroup(groupID, ad.CMSID, file, roster.Total, nil, nil))
+	}
+
+	rw.WriteBundleResponse(rw.CreateGroupBundle(groups), w)
+}
+
+func isAvailableGroup(groupID string) bool {
+	switch groupID {
+	case groupAll:
+		return true
+	case groupRunout:
+		return utils.GetEnvBool("BCDA_ENABLE_RUNOUT", true)
+	}
+	return false
+}
+
+// getGroupFile returns the attribution file a new export of the group would use, so the roster matches the
+// beneficiaries the ACO would receive data for.
+func (a ApiV3) getGroupFile(ctx context.Context, cmsID, groupID string) (*models.CCLFFile, service.TimeConstraints, error) {
+	reqType, fileType := constants.DefaultRequest, models.FileTypeDefault
+	if groupID == groupRunout {
+		reqType, fileType = constants.Runout, models.FileTypeRunout
+	}
+
+	tc, err := a.handler.Svc.GetTimeConstraints(ctx, cmsID)
+	if err != nil {
+		return nil, tc, err
+	}
+	cutoffTime, _ := a.handler.Svc.GetCutoffTime(ctx, reqType, time.Time{}, tc, fileType)
+	file, err := a.handler.Svc.GetLatestCCLFFile(ctx, cmsID, cutoffTime, tc.AttributionDate, fileType)
+	if err != nil {
+		return nil, tc, err
+	}
+	return file, tc, nil
+}
+
+func parsePaging(params url.Values) (offset, count int, err error) {
+	count = defaultPageSize
+	if v := params.Get("_count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil || count < 1 || count > maxPageSize {
+			return 0, 0, fmt.Errorf("invalid _count %s: must be a number between 1 and %d", v, maxPageSize)
+		}
+	}
+	if v := params.Get("_offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 || offset > maxOffset {
+			return 0, 0, fmt.Errorf("invalid _offset %s: must be a number between 0 and %d", v, maxOffset)
+		}
+	}
+	return offset, count, nil
+}
+
+// setNextLink points the client to the next page of a search when there is one.
+func setNextLink(w http.ResponseWriter, r *http.Request, offset, count, total int) {
+	next := offset + count
+	if next >= total {
+		return
+	}
+	scheme := "http"
+	if servicemux.IsHTTPS(r) {
+		scheme = "https"
+	}
+	query := r.URL.Query()
+	query.Set("_offset", strconv.Itoa(next))
+	query.Set("_count", strconv.Itoa(count))
+	w.Header().Set("Link", fmt.Sprintf(`<%s://%s%s?%s>; rel="next"`, scheme, r.Host, r.URL.Path, query.Encode()))
+}
+
 /*
 swagger:route GET /api/v3/metadata metadatav3 metadata
 
@@ -335,6 +687,8 @@ func (a ApiV3) Metadata(w http.ResponseWriter, r *http.Request) {
 							restResourceSearchParam("_since", r4.SearchParamTypeDate, "Return resources updated after the date provided for existing enrollees and all resources for newly attributed enrollees."),
 							restResourceSearchParam("_type", r4.SearchParamTypeString, "Comma-delimited list of FHIR resource types to include in the export. By default, all supported resource types are returned."),
 							restResourceSearchParam("_typeFilter", r4.SearchParamTypeString, "Use a URL-encoded FHIR subquery to further-refine group export results."),
+							restResourceSearchParam("_count", r4.SearchParamTypeNumber, "Number of members to return when reading a group. Defaults to 1000, with a maximum of 5000."),
+							restResourceSearchParam("_offset", r4.SearchParamTypeNumber, "Number of members to skip when reading a group."),
 						},
 					},
 					{
@@ -345,6 +699,14 @@ func (a ApiV3) Metadata(w http.ResponseWriter, r *http.Request) {
 							restResourceSearchParam("service-date", r4.SearchParamTypeDate, "Filter ExplanationOfBenefit based on the claim's service date. The service date is the date that the care occurred within a billable period. This is a FHIR date param format (ex. `gt2026-01-14`)"),
 						},
 					},
+					{
+						Type: r4.ResourceTypeCodeAuditEvent,
+						SearchParam: []r4.SearchParam{
+							restResourceSearchParam("_since", r4.SearchParamTypeDate, "Return export requests and file downloads recorded at or after the instant provided."),
+							restResourceSearchParam("_count", r4.SearchParamTypeNumber, "Number of audit events to return. Defaults to 1000, with a maximum of 5000."),
+							restResourceSearchParam("_offset", r4.SearchParamTypeNumber, "Number of audit events to skip."),
+						},
+					},
 				},
 			},
 		},
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,6 +23,7 @@ import (
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/fhir/r4"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres/postgrestest"
+	responseutilsv3 "github.com/CMSgov/bcda-app/bcda/responseutils/v3"
 	"github.com/CMSgov/bcda-app/bcda/service"
 	"github.com/CMSgov/bcda-app/bcda/testUtils"
 	"github.com/CMSgov/bcda-app/bcda/utils"
@@ -344,6 +346,224 @@ func (s *APITestSuite) TestJobsStatus() {
 	assert.Equal(s.T(), http.StatusOK, rr.Code)
 }
 
+func (s *APITestSuite) TestAuditEvent() {
+	j := models.Job{
+		ACOID:      acoUnderTest,
+		RequestURL: fmt.Sprintf("%sPatient/$export?_type=Patient", constants.V3Path),
+		Status:     models.JobStatusCompleted,
+	}
+	postgrestest.CreateJobs(s.T(), s.db, &j)
+	defer postgrestest.DeleteJobByID(s.T(), s.db, j.ID)
+
+	fileName := fmt.Sprintf("%s.ndjson", uuid.NewRandom())
+	download := models.JobKeyDownload{JobID: j.ID, ACOID: acoUnderTest, FileName: fileName, ResourceType: "Patient"}
+	assert.NoError(s.T(), s.apiV3.r.CreateJobKeyDownload(context.Background(), download))
+	defer func() {
+		_, err := s.db.Exec("DELETE FROM job_key_downloads WHERE job_id = $1", j.ID)
+		assert.NoError(s.T(), err)
+	}()
+
+	tests := []struct {
+		name      string
+		since     time.Time
+		expEvents int
+	}{
+		{"All activity", time.Time{}, 2},
+		{"Activity since the future", time.Now().Add(time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf("%sAuditEvent", constants.V3Path), nil)
+			ad := s.makeContextValues(acoUnderTest)
+			req = req.WithContext(context.WithValue(req.Context(), auth.AuthDataContextKey, ad))
+			req = req.WithContext(middleware.SetRequestParamsCtx(req.Context(), middleware.RequestParameters{Since: tt.since}))
+			newLogEntry := MakeTestStructuredLoggerEntry(logrus.Fields{"cms_id": "A9999", "request_id": uuid.NewRandom().String()})
+			req = req.WithContext(context.WithValue(req.Context(), log.CtxLoggerKey, newLogEntry))
+			rr := httptest.NewRecorder()
+
+			s.apiV3.AuditEvent(rr, req)
+			assert.Equal(t, http.StatusOK, rr.Code)
+
+			var bundle r4.Bundle
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &bundle))
+			assert.Equal(t, "searchset", bundle.Type)
+			assert.Len(t, bundle.Entry, tt.expEvents)
+		})
+	}
+}
+
+func (s *APITestSuite) TestAuditEventPaging() {
+	now := time.Now().Truncate(time.Second)
+	job1 := &models.Job{ID: 1, ACOID: acoUnderTest, Status: models.JobStatusCompleted, CreatedAt: now.Add(-time.Hour)}
+	job2 := &models.Job{ID: 2, ACOID: acoUnderTest, Status: models.JobStatusCompleted, CreatedAt: now.Add(-3 * time.Hour)}
+	download1 := &models.JobKeyDownload{ID: 1, JobID: 1, ACOID: acoUnderTest, FileName: "1.ndjson", CreatedAt: now}
+	download2 := &models.JobKeyDownload{ID: 2, JobID: 1, ACOID: acoUnderTest, FileName: "1.ndjson", CreatedAt: now.Add(-2 * time.Hour)}
+
+	tests := []struct {
+		name      string
+		query     string
+		dbErr     error
+		expCode   int
+		expOffset int
+		expCount  int
+		jobs      []*models.Job
+		downloads []*models.JobKeyDownload
+		expJobIDs []uint
+		expEvents []string
+		expNext   string
+	}{
+		{"First page", "?_count=2", nil, http.StatusOK, 0, 2, []*models.Job{job1}, []*models.JobKeyDownload{download1}, []uint{1}, []string{"download-1", "job-1"}, "_count=2&_offset=2"},
+		{"Last page", "?_count=2&_offset=2", nil, http.StatusOK, 2, 2, []*models.Job{job2}, []*models.JobKeyDownload{download2}, []uint{2}, []string{"download-2", "job-2"}, ""},
+		{"Past the last page", "?_offset=4", nil, http.StatusOK, 4, defaultPageSize, nil, nil, []uint{}, nil, ""},
+		{"Far past the last page", "?_offset=10000000", nil, http.StatusOK, maxOffset, defaultPageSize, nil, nil, []uint{}, nil, ""},
+		{"Offset too large", "?_offset=9223372036854775807", nil, http.StatusBadRequest, 0, 0, nil, nil, nil, nil, ""},
+		{"Invalid count", "?_count=0", nil, http.StatusBadRequest, 0, 0, nil, nil, nil, nil, ""},
+		{"Count too large", "?_count=5001", nil, http.StatusBadRequest, 0, 0, nil, nil, nil, nil, ""},
+		{"Database failure", "", errors.New("db down"), http.StatusInternalServerError, 0, defaultPageSize, nil, nil, nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			mockRepo := models.NewMockRepository(t)
+			mockRepo.On("GetAuditEvents", mock.Anything, acoUnderTest, time.Time{}, tt.expOffset, tt.expCount).Return(tt.jobs, tt.downloads, 4, tt.dbErr).Maybe()
+			mockRepo.On("GetJobKeysByJobIDs", mock.Anything, acoUnderTest, tt.expJobIDs).Return(map[uint][]*models.JobKey{}, nil).Maybe()
+			a := &ApiV3{handler: &api.Handler{RespWriter: responseutilsv3.NewFhirResponseWriter()}, r: mockRepo}
+
+			req := httptest.NewRequest("GET", fmt.Sprintf("%sAuditEvent%s", constants.V3Path, tt.query), nil)
+			ad := auth.AuthData{ACOID: acoUnderTest.String(), CMSID: "A9999", TokenID: uuid.NewRandom().String()}
+			req = req.WithContext(context.WithValue(req.Context(), auth.AuthDataContextKey, ad))
+			newLogEntry := MakeTestStructuredLoggerEntry(logrus.Fields{"cms_id": "A9999", "request_id": uuid.NewRandom().String()})
+			req = req.WithContext(context.WithValue(req.Context(), log.CtxLoggerKey, newLogEntry))
+			rr := httptest.NewRecorder()
+
+			a.AuditEvent(rr, req)
+			assert.Equal(t, tt.expCode, rr.Code)
+			if tt.expCode != http.StatusOK {
+				return
+			}
+
+			var bundle r4.Bundle
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &bundle))
+			assert.Equal(t, uint32(4), bundle.Total)
+			var ids []string
+			for _, entry := range bundle.Entry {
+				var event r4.AuditEvent
+				b, err := json.Marshal(entry.Resource)
+				assert.NoError(t, err)
+				assert.NoError(t, json.Unmarshal(b, &event))
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, tt.expEvents, ids)
+			if tt.expNext == "" {
+				assert.Empty(t, rr.Header().Get("Link"))
+			} else {
+				assert.Equal(t, fmt.Sprintf(`<http://example.com%sAuditEvent?%s>; rel="next"`, constants.V3Path, tt.expNext), rr.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func (s *APITestSuite) TestAuditEventNoAuthData() {
+	req := httptest.NewRequest("GET", fmt.Sprintf("%sAuditEvent", constants.V3Path), nil)
+	newLogEntry := MakeTestStructuredLoggerEntry(logrus.Fields{"cms_id": "A9999", "request_id": uuid.NewRandom().String()})
+	req = req.WithContext(context.WithValue(req.Context(), log.CtxLoggerKey, newLogEntry))
+	rr := httptest.NewRecorder()
+
+	s.apiV3.AuditEvent(rr, req)
+	assert.Equal(s.T(), http.StatusUnauthorized, rr.Code)
+}
+
+func (s *APITestSuite) TestGroup() {
+	file := &models.CCLFFile{ID: 5, Timestamp: time.Now()}
+	tc := service.TimeConstraints{OptOutDate: time.Now()}
+	roster := service.AttributionRoster{
+		Total:          3,
+		Beneficiaries:  []*models.CCLFBeneficiary{{MBI: "MBI0"}, {MBI: "MBI1"}},
+		SuppressedMBIs: map[string]struct{}{"MBI1": {}},
+	}
+
+	tests := []struct {
+		name      string
+		groupID   string
+		query     string
+		fileErr   error
+		expCode   int
+		expOffset int
+		expCount  int
+		expNext   string
+	}{
+		{"First page", "all", "?_count=2", nil, http.StatusOK, 0, 2, "_count=2&_offset=2"},
+		{"Last page", "all", "?_count=2&_offset=2", nil, http.StatusOK, 2, 2, ""},
+		{"Default page size", "runout", "", nil, http.StatusOK, 0, defaultPageSize, ""},
+		{"Invalid group", "other", "", nil, http.StatusBadRequest, 0, 0, ""},
+		{"Invalid count", "all", "?_count=0", nil, http.StatusBadRequest, 0, 0, ""},
+		{"Count too large", "all", "?_count=5001", nil, http.StatusBadRequest, 0, 0, ""},
+		{"Invalid offset", "all", "?_offset=-1", nil, http.StatusBadRequest, 0, 0, ""},
+		{"No attribution file", "all", "", service.CCLFNotFoundError{FileNumber: 8, CMSID: "A9999"}, http.StatusNotFound, 0, 0, ""},
+		{"File lookup failure", "all", "", errors.New("db down"), http.StatusInternalServerError, 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			mockSvc := service.NewMockService(t)
+			mockSvc.On("GetTimeConstraints", mock.Anything, "A9999").Return(tc, nil).Maybe()
+			mockSvc.On("GetCutoffTime", mock.Anything, mock.Anything, time.Time{}, tc, mock.Anything).Return(time.Time{}, constants.GetExistingBenes).Maybe()
+			mockSvc.On("GetLatestCCLFFile", mock.Anything, "A9999", time.Time{}, tc.AttributionDate, mock.Anything).Return(file, tt.fileErr).Maybe()
+			mockSvc.On("GetAttributionRoster", mock.Anything, "A9999", file.ID, tc.OptOutDate, tt.expOffset, tt.expCount).Return(roster, nil).Maybe()
+			a := &ApiV3{handler: &api.Handler{Svc: mockSvc, RespWriter: responseutilsv3.NewFhirResponseWriter()}}
+
+			rr := httptest.NewRecorder()
+			a.Group(rr, s.createGroupRequest(tt.groupID, tt.query))
+			assert.Equal(t, tt.expCode, rr.Code)
+			if tt.expCode != http.StatusOK {
+				return
+			}
+
+			var group r4.Group
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &group))
+			assert.Equal(t, tt.groupID, group.ID)
+			assert.Equal(t, uint32(3), group.Quantity)
+			assert.Len(t, group.Member, 2)
+			assert.True(t, group.Member[1].Inactive)
+			if tt.expNext == "" {
+				assert.Empty(t, rr.Header().Get("Link"))
+			} else {
+				assert.Equal(t, fmt.Sprintf(`<http://example.com%sGroup/%s?%s>; rel="next"`, constants.V3Path, tt.groupID, tt.expNext), rr.Header().Get("Link"))
+			}
+		})
+	}
+}
+
+func (s *APITestSuite) TestGroupSearch() {
+	file := &models.CCLFFile{ID: 5, Timestamp: time.Now()}
+	tc := service.TimeConstraints{}
+	mockSvc := service.NewMockService(s.T())
+	mockSvc.On("GetTimeConstraints", mock.Anything, "A9999").Return(tc, nil)
+	mockSvc.On("GetCutoffTime", mock.Anything, mock.Anything, time.Time{}, tc, mock.Anything).Return(time.Time{}, constants.GetExistingBenes)
+	mockSvc.On("GetLatestCCLFFile", mock.Anything, "A9999", time.Time{}, time.Time{}, models.FileTypeDefault).Return(file, nil)
+	mockSvc.On("GetLatestCCLFFile", mock.Anything, "A9999", time.Time{}, time.Time{}, models.FileTypeRunout).Return(nil, service.CCLFNotFoundError{FileNumber: 8, CMSID: "A9999"})
+	mockSvc.On("GetAttributionRoster", mock.Anything, "A9999", file.ID, time.Time{}, 0, 0).Return(service.AttributionRoster{Total: 42}, nil)
+	a := &ApiV3{handler: &api.Handler{Svc: mockSvc, RespWriter: responseutilsv3.NewFhirResponseWriter()}}
+
+	rr := httptest.NewRecorder()
+	a.GroupSearch(rr, s.createGroupRequest("", ""))
+	assert.Equal(s.T(), http.StatusOK, rr.Code)
+
+	var bundle struct {
+		Total uint32 `json:"total"`
+		Entry []struct {
+			Resource r4.Group `json:"resource"`
+		} `json:"entry"`
+	}
+	assert.NoError(s.T(), json.Unmarshal(rr.Body.Bytes(), &bundle))
+	// The runout group has no attribution file, so only the current group is listed
+	assert.Equal(s.T(), uint32(1), bundle.Total)
+	assert.Equal(s.T(), "all", bundle.Entry[0].Resource.ID)
+	assert.Equal(s.T(), uint32(42), bundle.Entry[0].Resource.Quantity)
+	assert.Empty(s.T(), bundle.Entry[0].Resource.Member)
+}
+
 func (s *APITestSuite) TestJobsStatusNotFound() {
 	req := httptest.NewRequest("GET", fmt.Sprintf("%sjobs", constants.V3Path), nil)
 	ad := s.makeContextValues(acoUnderTest)
@@ -508,7 +728,7 @@ func (s *APITestSuite) TestMetadataResponse() {
 	// Expecting an R4 response so we'll evaluate some fields to reflect that
 	assert.Equal(s.T(), "4.0.1", cs.FhirVersion)
 	assert.Equal(s.T(), 1, len(cs.Rest))
-	assert.Equal(s.T(), 3, len(cs.Rest[0].Resource))
+	assert.Equal(s.T(), 4, len(cs.Rest[0].Resource))
 	assert.Len(s.T(), cs.Instantiates, 2)
 	assert.Contains(s.T(), cs.Instantiates[0], fmt.Sprintf("%s/metadata", constants.BFDV3Path))
 	resourceData := []struct {
@@ -657,6 +877,21 @@ func (s *APITestSuite) createJobStatusRequest(acoID uuid.UUID, jobID uint) *http
 	return req.WithContext(context.WithValue(req.Context(), auth.AuthDataContextKey, ad))
 }
 
+func (s *APITestSuite) createGroupRequest(groupID, query string) *http.Request {
+	path := fmt.Sprintf("%sGroup", constants.V3Path)
+	if groupID != "" {
+		path = fmt.Sprintf("%s/%s", path, groupID)
+	}
+	req := httptest.NewRequest("GET", path+query, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("groupId", groupID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	ad := auth.AuthData{ACOID: uuid.NewRandom().String(), CMSID: "A9999", TokenID: uuid.NewRandom().String()}
+	newLogEntry := MakeTestStructuredLoggerEntry(logrus.Fields{"cms_id": "A9999", "request_id": uuid.NewRandom().String()})
+	req = req.WithContext(context.WithValue(req.Context(), log.CtxLoggerKey, newLogEntry))
+	return req.WithContext(context.WithValue(req.Context(), auth.AuthDataContextKey, ad))
+}
+
 // Compare expiry header against the expected time value.
 // There seems to be some slight difference in precision here,
 // so we'll compare up to seconds
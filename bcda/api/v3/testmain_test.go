@@ -0,0 +1,12 @@
+package v3
+
+import (
+	"os"
+	"testing"
+
+	"github.com/CMSgov/bcda-app/log"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(log.RunTests(m))
+}
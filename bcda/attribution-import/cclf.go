@@ -192,6 +192,13 @@ func (importer CclfImporter) importCCLF8(ctx context.Context, zipMetadata *cclfZ
 
 	fileMetadata.fileID = cclfFile.ID
 
+	layout, err := cclfRecordLayout(fileMetadata.acoID)
+	if err != nil {
+		err = errors.Wrapf(err, "could not determine the record layout for CCLF%d file %s", fileMetadata.cclfNum, cclfFile.Name)
+		importer.logger.Error(err)
+		return err
+	}
+
 	rc, err := zipMetadata.cclf8File.Open()
 	if err != nil {
 		err = errors.Wrapf(err, "could not read file %s for CCLF%d in archive %s", cclfFile.Name, fileMetadata.cclfNum, zipMetadata.filePath)
@@ -199,10 +206,9 @@ func (importer CclfImporter) importCCLF8(ctx context.Context, zipMetadata *cclfZ
 		return err
 	}
 	defer rc.Close()
-	sc := bufio.NewScanner(rc)
 
 	// Step 4: Bulk insert using pgx transaction
-	importedCount, recordCount, err := CopyFrom(ctx, pgxTx, sc, cclfFile.ID, utils.GetEnvInt("CCLF_IMPORT_STATUS_RECORDS_INTERVAL", 10000), importer.logger, validator.maxRecordLength)
+	importedCount, recordCount, err := CopyFrom(ctx, pgxTx, rc, layout, cclfFile.ID, utils.GetEnvInt("CCLF_IMPORT_STATUS_RECORDS_INTERVAL", 10000), importer.logger, validator.maxRecordLength)
 	if err != nil {
 		return errors.Wrap(err, "failed to copy data to beneficiaries table")
 	}
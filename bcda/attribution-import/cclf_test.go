@@ -195,7 +195,7 @@ func (s *CCLFTestSuite) TestImportCCLF8() {
 	}
 
 	err := s.importer.importCCLF8(ctx, metadata, validator)
-	s.ErrorContains(err, "incorrect record length for file (expected: 2, actual: 549)")
+	s.ErrorContains(err, "incorrect record length (expected: 2, actual: 549)")
 
 	// validation error -- records too long
 	validator.maxRecordLength = 549
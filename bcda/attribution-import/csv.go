@@ -1,9 +1,7 @@
 package attributionimport
 
 import (
-	"bytes"
 	"context"
-	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
@@ -18,24 +16,36 @@ import (
 	ers "github.com/CMSgov/bcda-app/bcda/errors"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
+	"github.com/CMSgov/bcda-app/bcda/service"
 )
 
 // FileProcessors for attribution are created as interfaces so that they can be passed in place of the implementation; local development and other envs will require different processors.
 // This interface has two implementations; one for ingesting and testing locally, and one for ingesting in s3.
 type CSVFileProcessor interface {
 	// Fetch the csv attribution file to be imported.
-	LoadCSV(ctx context.Context, path string) (*bytes.Reader, func(), error)
+	LoadCSV(ctx context.Context, path string) (io.Reader, func(), error)
 	// Remove csv attribution file that was successfully imported.
 	CleanUpCSV(ctx context.Context, file csvFile) (err error)
 }
 
 type csvFile struct {
 	metadata csvFileMetadata
-	data     *bytes.Reader
+	layout   service.AttributionLayout
+	data     io.Reader
 	imported bool
 	filepath string
 }
 
+// recordLayout returns the layout of the file's records, falling back to a single column of MBIs with a
+// header row when the file was not matched to an ACO config.
+func (file csvFile) recordLayout() service.AttributionLayout {
+	if file.layout.MBI.IsSet() {
+		return file.layout
+	}
+	layout, _ := service.AttributionFormat("csv")
+	return layout
+}
+
 type csvFileMetadata struct {
 	name         string
 	env          string
@@ -60,17 +70,19 @@ func (importer CSVImporter) ImportCSV(ctx context.Context, filepath string) erro
 
 	short := f.Base(filepath)
 
-	metadata, err := GetCSVMetadata(short)
+	metadata, layout, err := parseCSVFileName(short)
 	if err != nil {
 		importer.Logger.Errorf("error parsing CSV metadata: %w", err)
 		return &ers.InvalidCSVMetadata{Msg: err.Error()}
 	}
 	file.metadata = metadata
+	file.layout = layout
 
-	data, _, err := importer.FileProcessor.LoadCSV(ctx, filepath)
+	data, closer, err := importer.FileProcessor.LoadCSV(ctx, filepath)
 	if err != nil {
 		return err
 	}
+	defer closer()
 
 	file.data = data
 
@@ -142,17 +154,17 @@ func (importer CSVImporter) ProcessCSV(csv csvFile) error {
 
 	csv.metadata.fileID = record.ID
 
-	rows, count, err := importer.prepareCSVData(csv.data, record.ID)
+	source, err := newCSVBeneficiarySource(ctx, csv.data, csv.recordLayout(), record.ID)
 	if err != nil {
 		return err
 	}
 
-	importedCount, err := pgxTx.CopyFrom(ctx, pgxv5.Identifier{"cclf_beneficiaries"}, []string{"file_id", "mbi"}, pgxv5.CopyFromRows(rows))
+	importedCount, err := pgxTx.CopyFrom(ctx, pgxv5.Identifier{"cclf_beneficiaries"}, beneficiaryColumns, source)
 	records = int(importedCount)
 	if err != nil {
 		return fmt.Errorf("failed to write attribution beneficiaries to database: %w", err)
 	}
-	if count != records {
+	if count := len(source.processed); count != records {
 		return fmt.Errorf("unexpected number of records imported (expected: %d, actual: %d)", count, records)
 	}
 
@@ -166,41 +178,6 @@ func (importer CSVImporter) ProcessCSV(csv csvFile) error {
 	}
 
 	successMsg := fmt.Sprintf("successfully imported %d records from csv file %s.", records, csv.metadata.name)
-	importer.Logger.WithFields(logrus.Fields{"imported_count": records}).Info(successMsg)
+	importer.Logger.WithFields(logrus.Fields{"imported_count": records, "record_count": source.recordCount}).Info(successMsg)
 	return nil
 }
-
-func (importer CSVImporter) prepareCSVData(csvfile *bytes.Reader, id uint) ([][]interface{}, int, error) {
-	var rows [][]interface{}
-	r := csv.NewReader(csvfile)
-
-	_, err := r.Read()
-	if err == io.EOF {
-		return nil, 0, errors.New("empty attribution file")
-	}
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to read csv attribution header: %s", err)
-	}
-
-	count := 0
-
-	for {
-		var record []string
-		record, err = r.Read()
-		if err == io.EOF {
-			err = nil
-			break
-		}
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to read csv attribution file: %s", err)
-		}
-		row := make([]interface{}, 2)
-		row[0] = id
-		row[1] = record[0]
-
-		rows = append(rows, row)
-		count++
-	}
-	return rows, count, err
-
-}
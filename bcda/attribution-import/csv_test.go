@@ -235,40 +235,3 @@ func (s *CSVTestSuite) TestProcessCSV_Integration() {
 		})
 	}
 }
-
-func (s *CSVTestSuite) TestPrepareCSVData() {
-
-	c := CSVImporter{
-		PgxPool: s.pool,
-	}
-	tests := []struct {
-		name     string
-		data     *bytes.Reader
-		err      error
-		expected [][]interface{}
-	}{
-		{"Valid CSV file with content", bytes.NewReader([]byte("MBIS\nMBI000001\nMBI000002\nMBI000003")), nil, [][]interface{}{
-			{uint(1), "MBI000001"},
-			{uint(1), "MBI000002"},
-			{uint(1), "MBI000003"},
-		}},
-		{"Empty CSV file", bytes.NewReader([]byte("")), errors.New("empty attribution file"), [][]interface{}(nil)},
-		{"Valid CSV file with unexpected content - more columns than headers", bytes.NewReader([]byte("MBIS\nMBI000001,10\nMBI000002,bar\nMBI000003,")), errors.New("failed to read csv attribution file"), [][]interface{}(nil)},
-		{"Valid CSV file with unexpected content - extra column and header", bytes.NewReader([]byte("MBIS,foo\nMBI000001,10\nMBI000002,bar\nMBI000003,")), nil, [][]interface{}{
-			{uint(1), "MBI000001"},
-			{uint(1), "MBI000002"},
-			{uint(1), "MBI000003"},
-		}},
-	}
-	for _, test := range tests {
-		s.Run(test.name, func() {
-			fmt.Print(test.name)
-			rows, _, err := c.prepareCSVData(test.data, uint(1))
-			assert.Equal(s.T(), test.expected, rows)
-			if err != nil {
-				assert.Contains(s.T(), err.Error(), test.err.Error())
-			}
-		})
-	}
-
-}
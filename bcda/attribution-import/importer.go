@@ -1,11 +1,13 @@
 package attributionimport
 
 import (
-	"bufio"
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
+	"github.com/CMSgov/bcda-app/bcda/service"
 	"github.com/ccoveille/go-safecast"
 	"github.com/jackc/pgx/v5"
 	"github.com/sirupsen/logrus"
@@ -16,108 +18,186 @@ import (
 type cclf8Importer struct {
 	ctx context.Context
 
-	scanner        *bufio.Scanner
+	reader         *recordReader
+	record         attributionRecord
+	err            error
 	reportInterval int
 	cclfFileID     uint // CCLFFile ID that will be associated with all created benes
 
-	recordCount          int
-	importCount          int
-	processedMBIs        map[string]struct{}
-	logger               logrus.FieldLogger
-	expectedRecordLength int
+	recordCount int
+	importCount int
+	processed   map[beneficiaryPeriod]struct{}
+	logger      logrus.FieldLogger
+}
+
+// Columns copied for each beneficiary in an attribution file, in the order the importers' Values return them
+var beneficiaryColumns = []string{"file_id", "mbi", "attribution_start", "attribution_end"}
+
+// A beneficiaryPeriod identifies one row copied to the beneficiaries table. Files list a beneficiary once for
+// each period they were aligned to the entity, so only exact repeats are skipped.
+type beneficiaryPeriod struct {
+	mbi        string
+	start, end time.Time
+}
+
+func (rec attributionRecord) period() beneficiaryPeriod {
+	return beneficiaryPeriod{rec.mbi, rec.effectiveDate, rec.terminationDate}
 }
 
 func (importer *cclf8Importer) Next() bool {
-	// Loops through the scanner until we either:
-	// 1. Encounter the end of the data
-	// 2. Find an MBI that we have not processed yet
+	// Loops through the records until we either:
+	// 1. Encounter the end of the data or a malformed record
+	// 2. Find an MBI and period that we have not processed yet
 	//
 	// This logic exists in the Next() function because it
-	// is the only way for us to ignore an already ingested MBI.
+	// is the only way for us to ignore an already ingested record.
 	// If we made this check in Values() and return an error or
 	// return empty data, then the copy will fail.
 	// NOTE: This choice was based on pgx v3.1.0.
-	//
-	// Since we're using the bufio.Scanner, we can make multiple calls
-	// to scanner.Bytes() without advancing the cursor.
 	for {
-		hasNext := importer.scanner.Scan()
-		if !hasNext {
-			return hasNext
+		record, err := importer.reader.Read()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			var recErr *recordError
+			if errors.As(err, &recErr) {
+				err = fmt.Errorf("%w for file on line %d", err, recErr.line)
+			}
+			importer.logger.Error(err)
+			importer.err = err
+			return false
 		}
 
 		importer.recordCount++
-		mbi := importer.getMBI()
-		// We've already processed this MBI before
-		if _, found := importer.processedMBIs[mbi]; found {
+		// We've already processed this MBI and period before
+		if _, found := importer.processed[record.period()]; found {
 			continue
 		}
 
-		// We have an MBI we haven't processed yet
-		importer.processedMBIs[mbi] = struct{}{}
+		// We have a record we haven't processed yet
+		importer.record = record
+		importer.processed[record.period()] = struct{}{}
 		return true
 	}
 }
 
 func (importer *cclf8Importer) Values() ([]interface{}, error) {
-	// Verify record length
-	b := importer.scanner.Bytes()
-	trimmed := bytes.TrimSpace(b)
-
-	// Currently only errors if record is longer than expected
-	if len(trimmed) == 0 || len(trimmed) > importer.expectedRecordLength {
-		err := fmt.Errorf("incorrect record length for file (expected: %d, actual: %d)", importer.expectedRecordLength, len(trimmed))
-		importer.logger.Error(err)
-		return nil, err
-	}
-
 	fileID, err := safecast.ToInt32(importer.cclfFileID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert fileID to int32: %w", err)
 	}
 
-	mbi := importer.getMBI()
-
 	importer.importCount++
 	if importer.importCount%importer.reportInterval == 0 {
 		importer.logger.Infof("CCLF8 records imported: %d\n", importer.importCount)
 	}
 
-	return []interface{}{fileID, mbi}, nil
+	return []interface{}{fileID, importer.record.mbi, nullDate(importer.record.effectiveDate), nullDate(importer.record.terminationDate)}, nil
 }
 
 // Err allows us to report back the the CopyFrom if and when
-// the underlying context has been stopped.
-// NOTE: This is specifc to pgx v3. In pgx v4, the CopyFrom function accepts a context argument
+// a record could not be read or the underlying context has been stopped.
 func (importer *cclf8Importer) Err() error {
+	if importer.err != nil {
+		return importer.err
+	}
 	return importer.ctx.Err()
 }
 
-// getMBI returns the current MBI contained at the scanner's current cursor.
-// Since calls the bufio.Scanner.Bytes() does not advance the cursor, this call
-// can be invoked multiple times and return the same result.
-func (importer *cclf8Importer) getMBI() string {
-	const (
-		mbiStart, mbiEnd = 0, 11
-	)
-	b := importer.scanner.Bytes()
-	return string(bytes.TrimSpace(b[mbiStart:mbiEnd]))
-}
+// CopyFrom writes all of the beneficiary records in data, read according to layout, to the beneficiaries
+// table. It returns the number of rows written and the number of records read, along with any error that
+// occurred. Records longer than expectedRecordLength are rejected.
+func CopyFrom(ctx context.Context, tx pgx.Tx, data io.Reader, layout service.AttributionLayout, fileID uint, reportInterval int, logger logrus.FieldLogger, expectedRecordLength int) (int, int, error) {
+	reader, err := newRecordReader(data, layout)
+	if err != nil {
+		return 0, 0, err
+	}
+	reader.maxLength = expectedRecordLength
 
-// CopyFrom writes all of the beneficiary data captured in the scanner to the beneficiaries table.
-// It returns the number of rows written along with any error that occurred.
-func CopyFrom(ctx context.Context, tx pgx.Tx, scanner *bufio.Scanner, fileID uint, reportInterval int, logger logrus.FieldLogger, expectedRecordLength int) (int, int, error) {
 	importer := &cclf8Importer{
-		scanner:    scanner,
+		reader:     reader,
 		ctx:        ctx,
 		cclfFileID: fileID,
 
-		reportInterval:       reportInterval,
-		processedMBIs:        make(map[string]struct{}),
-		logger:               logger,
-		expectedRecordLength: expectedRecordLength,
+		reportInterval: reportInterval,
+		processed:      make(map[beneficiaryPeriod]struct{}),
+		logger:         logger,
 	}
 	tableName := pgx.Identifier{"cclf_beneficiaries"}
-	importedCount, err := tx.CopyFrom(ctx, tableName, []string{"file_id", "mbi"}, importer)
+	importedCount, err := tx.CopyFrom(ctx, tableName, beneficiaryColumns, importer)
 	return int(importedCount), importer.recordCount, err
 }
+
+// A csvBeneficiarySource streams the MBIs in a CSV attribution file to pgx.CopyFrom so the file never has to be
+// held in memory. Like the cclf8Importer, records repeating an MBI and period that were already copied are
+// skipped and it is not safe for concurrent use by multiple goroutines.
+type csvBeneficiarySource struct {
+	ctx context.Context
+
+	reader *recordReader
+	fileID int32
+	record attributionRecord
+	err    error
+
+	recordCount int
+	processed   map[beneficiaryPeriod]struct{}
+}
+
+// newCSVBeneficiarySource reads the header of the attribution file in data and returns a source for the
+// records that follow it.
+func newCSVBeneficiarySource(ctx context.Context, data io.Reader, layout service.AttributionLayout, cclfFileID uint) (*csvBeneficiarySource, error) {
+	fileID, err := safecast.ToInt32(cclfFileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert fileID to int32: %w", err)
+	}
+
+	r, err := newRecordReader(data, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csvBeneficiarySource{ctx: ctx, reader: r, fileID: fileID, processed: make(map[beneficiaryPeriod]struct{})}, nil
+}
+
+func (source *csvBeneficiarySource) Next() bool {
+	for {
+		record, err := source.reader.Read()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			source.err = fmt.Errorf("failed to read csv attribution file: %s", err)
+			return false
+		}
+
+		source.recordCount++
+		if _, found := source.processed[record.period()]; found {
+			continue
+		}
+
+		source.record = record
+		source.processed[record.period()] = struct{}{}
+		return true
+	}
+}
+
+func (source *csvBeneficiarySource) Values() ([]interface{}, error) {
+	return []interface{}{source.fileID, source.record.mbi, nullDate(source.record.effectiveDate), nullDate(source.record.terminationDate)}, nil
+}
+
+// nullDate copies a missing attribution date as NULL rather than the zero time.
+func nullDate(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// Err reports a malformed record or a stopped context; pgx checks it once Next returns false.
+func (source *csvBeneficiarySource) Err() error {
+	if source.err != nil {
+		return source.err
+	}
+	return source.ctx.Err()
+}
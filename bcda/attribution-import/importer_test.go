@@ -1,51 +1,105 @@
 package attributionimport
 
 import (
-	"bufio"
 	"context"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/CMSgov/bcda-app/bcda/service"
 	"github.com/CMSgov/bcda-app/bcda/testUtils"
 	"github.com/ccoveille/go-safecast"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
+func newTestCCLF8Importer(t *testing.T, data string, maxLength int) *cclf8Importer {
+	layout, _ := service.AttributionFormat(service.CCLFFormat)
+	reader, err := newRecordReader(strings.NewReader(data), layout)
+	assert.NoError(t, err)
+	reader.maxLength = maxLength
+
+	u, err := safecast.ToUint(testUtils.CryptoRandInt31())
+	if err != nil {
+		t.Fatalf("failed to convert to uint: %v", err)
+	}
+	return &cclf8Importer{ctx: context.Background(), cclfFileID: u, reader: reader,
+		processed: make(map[beneficiaryPeriod]struct{}), reportInterval: 1, logger: logrus.StandardLogger()}
+}
+
 func TestNext(t *testing.T) {
 	mbi1, mbi2, mbi3 := testUtils.RandomMBI(t), testUtils.RandomMBI(t), testUtils.RandomMBI(t)
 	// Create set of duplicate MBIs to verify that we skip them when invoking the next call
 	mbis := []string{mbi1, mbi1, mbi3, mbi2, mbi2}
-	scanner := bufio.NewScanner(strings.NewReader(strings.Join(mbis, "\n")))
 
-	importer := &cclf8Importer{processedMBIs: make(map[string]struct{}), scanner: scanner}
+	importer := newTestCCLF8Importer(t, strings.Join(mbis, "\n"), 11)
 	for _, expected := range []string{mbi1, mbi3, mbi2} {
 		assert.True(t, importer.Next())
-		assert.Equal(t, expected, string(importer.scanner.Bytes()))
+		assert.Equal(t, expected, importer.record.mbi)
 	}
 
 	assert.False(t, importer.Next())
+	assert.NoError(t, importer.Err())
+	assert.Equal(t, 5, importer.recordCount)
 }
 
-func TestValues(t *testing.T) {
+func TestNextInvalidRecord(t *testing.T) {
 	mbi := testUtils.RandomMBI(t)
-	scanner := bufio.NewScanner(strings.NewReader(mbi))
-	u, err := safecast.ToUint(testUtils.CryptoRandInt31())
-	if err != nil {
-		t.Fatalf("failed to convert to uint: %v", err)
-	}
 
-	importer := &cclf8Importer{ctx: context.Background(), cclfFileID: u,
-		scanner: scanner, processedMBIs: make(map[string]struct{}),
-		reportInterval: 1, logger: logrus.StandardLogger(), expectedRecordLength: 11}
+	// Records must fit within the length declared by CCLF0
+	importer := newTestCCLF8Importer(t, mbi+"\n"+mbi+"EXTRA", 11)
+	assert.True(t, importer.Next())
+	assert.False(t, importer.Next())
+	assert.EqualError(t, importer.Err(), "incorrect record length (expected: 11, actual: 16) for file on line 2")
+
+	// Short records no longer panic when the MBI is read
+	importer = newTestCCLF8Importer(t, "1AA0AA", 11)
+	assert.False(t, importer.Next())
+	assert.EqualError(t, importer.Err(), "record is 6 bytes, expected at least 11 for file on line 1")
+}
+
+func TestValues(t *testing.T) {
+	mbi := testUtils.RandomMBI(t)
+	importer := newTestCCLF8Importer(t, mbi, 11)
 	assert.True(t, importer.Next())
 
 	values, err := importer.Values()
 	assert.NoError(t, err)
-	assert.Len(t, values, 2)
+	assert.Len(t, values, 4)
 
 	assert.EqualValues(t, importer.cclfFileID, values[0].(int32))
 	assert.EqualValues(t, mbi, values[1].(string))
+	assert.Nil(t, values[2])
+	assert.Nil(t, values[3])
+}
+
+func TestValuesAttributionPeriod(t *testing.T) {
+	mbi := testUtils.RandomMBI(t)
+	layout, _ := service.AttributionFormat(service.CCLFFormat)
+	layout.EffectiveDate = service.AttributionField{Start: 11, End: 21}
+	layout.TerminationDate = service.AttributionField{Start: 21, End: 31}
+	// Each period a beneficiary was aligned is kept, repeats of the same period are not
+	reader, err := newRecordReader(strings.NewReader(strings.Join([]string{
+		mbi + "2025-01-012025-03-31",
+		mbi + "2025-07-01",
+		mbi + "2025-01-012025-03-31",
+	}, "\n")), layout)
+	assert.NoError(t, err)
+	importer := newTestCCLF8Importer(t, "", 31)
+	importer.reader = reader
+
+	var rows [][]interface{}
+	for importer.Next() {
+		values, err := importer.Values()
+		assert.NoError(t, err)
+		rows = append(rows, values[1:])
+	}
+	assert.NoError(t, importer.Err())
+	assert.Equal(t, [][]interface{}{
+		{mbi, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		{mbi, time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC), nil},
+	}, rows)
+	assert.Equal(t, 3, importer.recordCount)
 }
 
 func TestErr(t *testing.T) {
@@ -58,3 +112,75 @@ func TestErr(t *testing.T) {
 	importer = &cclf8Importer{ctx: context.Background()}
 	assert.NoError(t, importer.Err())
 }
+
+func TestCSVBeneficiarySource(t *testing.T) {
+	csvLayout, _ := service.AttributionFormat("csv")
+	tests := []struct {
+		name     string
+		data     string
+		err      string
+		expected [][]interface{}
+	}{
+		{"Valid CSV file with content", "MBIS\nMBI000001\nMBI000002\nMBI000003", "", [][]interface{}{
+			{int32(1), "MBI000001", nil, nil},
+			{int32(1), "MBI000002", nil, nil},
+			{int32(1), "MBI000003", nil, nil},
+		}},
+		{"Duplicate MBIs are only copied once", "MBIS\nMBI000001\nMBI000002\nMBI000001", "", [][]interface{}{
+			{int32(1), "MBI000001", nil, nil},
+			{int32(1), "MBI000002", nil, nil},
+		}},
+		{"Valid CSV file with unexpected content - more columns than headers", "MBIS\nMBI000001,10\nMBI000002,bar\nMBI000003,", "failed to read csv attribution file", nil},
+		{"Valid CSV file with unexpected content - extra column and header", "MBIS,foo\nMBI000001,10\nMBI000002,bar\nMBI000003,", "", [][]interface{}{
+			{int32(1), "MBI000001", nil, nil},
+			{int32(1), "MBI000002", nil, nil},
+			{int32(1), "MBI000003", nil, nil},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, err := newCSVBeneficiarySource(context.Background(), strings.NewReader(test.data), csvLayout, 1)
+			assert.NoError(t, err)
+
+			var rows [][]interface{}
+			for source.Next() {
+				values, err := source.Values()
+				assert.NoError(t, err)
+				rows = append(rows, values)
+			}
+			assert.Equal(t, test.expected, rows)
+			if test.err == "" {
+				assert.NoError(t, source.Err())
+			} else {
+				assert.ErrorContains(t, source.Err(), test.err)
+			}
+		})
+	}
+
+	_, err := newCSVBeneficiarySource(context.Background(), strings.NewReader(""), csvLayout, 1)
+	assert.EqualError(t, err, "empty attribution file")
+
+	t.Run("Attribution period", func(t *testing.T) {
+		layout := csvLayout
+		layout.EffectiveDate = service.AttributionField{Column: 2}
+		layout.TerminationDate = service.AttributionField{Column: 3}
+		source, err := newCSVBeneficiarySource(context.Background(), strings.NewReader("MBI,START,END\nMBI000001,2025-01-01,\nMBI000002,2025-01-01,2025-03-31\nMBI000002,2025-07-01,\nMBI000002,2025-01-01,2025-03-31"), layout, 1)
+		assert.NoError(t, err)
+
+		start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+		var rows [][]interface{}
+		for source.Next() {
+			values, err := source.Values()
+			assert.NoError(t, err)
+			rows = append(rows, values)
+		}
+		assert.NoError(t, source.Err())
+		assert.Equal(t, [][]interface{}{
+			{int32(1), "MBI000001", start, nil},
+			{int32(1), "MBI000002", start, time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)},
+			{int32(1), "MBI000002", time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC), nil},
+		}, rows)
+		assert.Equal(t, 4, source.recordCount)
+	})
+}
@@ -2,9 +2,9 @@ package attributionimport
 
 import (
 	"archive/zip"
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	fp "path/filepath"
 	"strings"
@@ -22,16 +22,22 @@ import (
 
 type LocalFileProcessor struct {
 	Handler bp.LocalFileHandler
+	// DryRun leaves unrecognized archives in place instead of moving them to the pending deletion directory
+	DryRun bool
 }
 
 func (processor *LocalFileProcessor) LoadCclfFiles(ctx context.Context, path string) (cclfList map[string][]*cclfZipMetadata, skipped int, failed int, err error) {
-	return processCCLFArchives(path)
+	return walkCCLFArchives(path, processor.DryRun)
 }
 
 // processCCLFArchives walks through all of the CCLF files captured in the root path and generates
 // a mapping between CMS_ID + perf year and associated CCLF Metadata
 func processCCLFArchives(rootPath string) (map[string][]*cclfZipMetadata, int, int, error) {
-	p := &processor{0, 0, make(map[string][]*cclfZipMetadata)}
+	return walkCCLFArchives(rootPath, false)
+}
+
+func walkCCLFArchives(rootPath string, dryRun bool) (map[string][]*cclfZipMetadata, int, int, error) {
+	p := &processor{cclfMap: make(map[string][]*cclfZipMetadata), dryRun: dryRun}
 	if err := fp.Walk(rootPath, p.walk); err != nil {
 		return nil, 0, 0, err
 	}
@@ -42,6 +48,7 @@ type processor struct {
 	skipped int
 	failure int
 	cclfMap map[string][]*cclfZipMetadata
+	dryRun  bool
 }
 
 func (p *processor) walk(path string, info os.FileInfo, err error) error {
@@ -184,6 +191,9 @@ func (p *processor) handleArchiveError(path string, info os.FileInfo, cause erro
 	msg := fmt.Sprintf("Skipping CCLF archive (%s): %s.", info.Name(), cause)
 	fmt.Println(msg)
 	log.API.Warn(msg)
+	if p.dryRun {
+		return nil
+	}
 	err := checkDeliveryDate(path, info.ModTime())
 	if err != nil {
 		err = fmt.Errorf("error moving unknown file %s to pending deletion dir", path)
@@ -318,17 +328,19 @@ func (processor *LocalFileProcessor) CleanUpCSV(ctx context.Context, file csvFil
 	return err
 }
 
-func (processor *LocalFileProcessor) LoadCSV(ctx context.Context, filepath string) (*bytes.Reader, func(), error) {
+func (processor *LocalFileProcessor) LoadCSV(ctx context.Context, filepath string) (io.Reader, func(), error) {
 	c := fp.Clean(filepath)
 	if !strings.HasPrefix(filepath, "/tmp") {
 		return nil, nil, fmt.Errorf("invalid path, %s", filepath)
 	}
-	byte_arr, err := os.ReadFile(c)
+	file, err := os.Open(c)
 	if err != nil {
 		return nil, nil, err
 	}
-	reader := bytes.NewReader(byte_arr)
-
-	return reader, func() {}, err
 
+	return file, func() {
+		if err := file.Close(); err != nil {
+			processor.Handler.Logger.Warningf("Failed to close %s: %s", c, err)
+		}
+	}, nil
 }
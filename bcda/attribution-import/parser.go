@@ -64,15 +64,23 @@ func getACOConfigs() ([]service.ACOConfig, error) {
 // GetCSVMetadata builds a metadata struct based on the filename parts.
 // The filename regex is part of aco configuration.
 func GetCSVMetadata(path string) (csvFileMetadata, error) {
+	metadata, _, err := parseCSVFileName(path)
+	return metadata, err
+}
+
+// parseCSVFileName finds the ACO config whose attribution file name pattern matches path and returns the
+// file's metadata along with the layout of its records.
+func parseCSVFileName(path string) (csvFileMetadata, service.AttributionLayout, error) {
 	var metadata csvFileMetadata
+	var layout service.AttributionLayout
 	var err error
 
 	acos, err := getACOConfigs()
 	if err != nil {
-		return csvFileMetadata{}, errors.New("Failed to load ACO configs")
+		return csvFileMetadata{}, layout, errors.New("Failed to load ACO configs")
 	}
 	if acos == nil {
-		return csvFileMetadata{}, errors.New("No ACO configs found.")
+		return csvFileMetadata{}, layout, errors.New("No ACO configs found.")
 	}
 
 	for _, v := range acos {
@@ -80,28 +88,28 @@ func GetCSVMetadata(path string) (csvFileMetadata, error) {
 		if v.AttributionFile.FileType == "csv" {
 			filenameRegexp := regexp.MustCompile(v.AttributionFile.NamePattern)
 			matches := filenameRegexp.FindStringSubmatch(path)
-			// safely check slice that contains the model identifier
-			if len(matches) >= 2 {
-				// matches can happen with similarly named models, verify it using the full model name
-				if v.AttributionFile.ModelIdentifier == matches[2] {
-					metadata, err = validateCSVMetadata(v.AttributionFile, matches)
-					if err != nil {
-						return csvFileMetadata{}, err
-					}
-					break
+			// matches can happen with similarly named models, verify it using the full model name
+			if v.AttributionFile.MatchesModel(matches) {
+				metadata, err = validateCSVMetadata(v.AttributionFile, matches)
+				if err != nil {
+					return csvFileMetadata{}, layout, err
+				}
+				if layout, err = v.AttributionFile.RecordLayout(); err != nil {
+					return csvFileMetadata{}, layout, fmt.Errorf("invalid attribution file layout for %s: %w", v.Model, err)
 				}
+				break
 			}
 		}
 
 	}
 
 	if metadata == (csvFileMetadata{}) {
-		return metadata, errors.New("Invalid filename for csv attribution file")
+		return metadata, layout, errors.New("Invalid filename for csv attribution file")
 	}
 
 	metadata.name = path
 	metadata.cclfNum = 8
-	return metadata, nil
+	return metadata, layout, nil
 }
 
 // Validate the csv attribution filename contains the required values.
@@ -147,20 +155,23 @@ func validateCSVMetadata(attributionFile service.AttributionFile, subMatches []s
 		metadata.env = "production"
 	}
 
-	switch attributionFile.ModelIdentifier {
-	case "PCPB":
-		metadata.acoID = "CT000000"
-	case "BCD", "ACCESS":
-		metadata.acoID = subMatches[3]
-	case "GUIDE":
-		metadata.acoID = fmt.Sprintf("%s%s", subMatches[3], subMatches[4])
-	default:
-		return csvFileMetadata{}, errors.New("failed to get aco ID for attribution file")
+	metadata.acoID, err = attributionFile.CMSIDFromMatches(subMatches)
+	if err != nil {
+		return csvFileMetadata{}, err
 	}
 
 	return metadata, nil
 }
 
+// cclfRecordLayout returns the layout of the CCLF8 records delivered for the ACO cmsID.
+func cclfRecordLayout(cmsID string) (service.AttributionLayout, error) {
+	cfg, err := service.LoadConfig()
+	if err != nil {
+		return service.AttributionLayout{}, errors.New("Failed to load ACO configs")
+	}
+	return cfg.CCLFRecordLayout(cmsID)
+}
+
 // getCCLFFileMetadata takes an attribution file name and converts it to a cclfFileMetadata entry.
 // The cclfFileMetadat entry will be insert into the database as a record in the cclf_files table.
 func getCCLFFileMetadata(cmsID, fileName string) (cclfFileMetadata, error) {
@@ -0,0 +1,193 @@
+package attributionimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+var errEmptyAttributionFile = errors.New("empty attribution file")
+
+// attributionRecord is a single beneficiary read from an attribution file. The effective and termination
+// dates are zero when the file's layout does not include them or the record leaves them blank.
+type attributionRecord struct {
+	line            int
+	mbi             string
+	effectiveDate   time.Time
+	terminationDate time.Time
+}
+
+// recordError is returned by recordReader.Read for a record that could not be parsed. Reading can continue
+// with the next record.
+type recordError struct {
+	line int
+	err  error
+}
+
+func (e *recordError) Error() string {
+	return e.err.Error()
+}
+
+func (e *recordError) Unwrap() error {
+	return e.err
+}
+
+// recordReader reads the records of an attribution file according to an AttributionLayout, skipping the
+// header and any trailer lines.
+type recordReader struct {
+	layout service.AttributionLayout
+
+	// Only one of csv and lines is set, depending on whether the layout is delimited
+	csv   *csv.Reader
+	lines *bufio.Scanner
+	line  int
+
+	// maxLength, when set, is the longest a fixed-width record may be once trimmed. Blank records are then
+	// rejected rather than skipped.
+	maxLength int
+}
+
+// newRecordReader reads the header of the attribution file in data and returns a reader for the records
+// that follow it.
+func newRecordReader(data io.Reader, layout service.AttributionLayout) (*recordReader, error) {
+	rr := &recordReader{layout: layout}
+	if layout.IsDelimited() {
+		rr.csv = csv.NewReader(data)
+		rr.csv.Comma = layout.Comma()
+		rr.csv.ReuseRecord = true
+		// Trailers rarely have the same number of fields as the records they follow
+		if layout.TrailerPrefix != "" {
+			rr.csv.FieldsPerRecord = -1
+		}
+	} else {
+		rr.lines = bufio.NewScanner(data)
+	}
+
+	for i := 0; i < layout.HeaderLines; i++ {
+		if err := rr.skip(); err == io.EOF {
+			return nil, errEmptyAttributionFile
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read csv attribution header: %s", err)
+		}
+	}
+	return rr, nil
+}
+
+func (rr *recordReader) skip() error {
+	if rr.csv != nil {
+		_, err := rr.csv.Read()
+		return err
+	}
+	if !rr.lines.Scan() {
+		if err := rr.lines.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	rr.line++
+	return nil
+}
+
+// Read returns the next record in the file, or io.EOF once there are none left. A *recordError is returned
+// for a malformed record; any other error means the file cannot be read any further.
+func (rr *recordReader) Read() (attributionRecord, error) {
+	for {
+		var (
+			fields []string
+			line   int
+		)
+		if rr.csv != nil {
+			record, err := rr.csv.Read()
+			if err != nil {
+				var parseErr *csv.ParseError
+				// Field count errors still return the record, anything else leaves the reader unusable
+				if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+					return attributionRecord{}, &recordError{line: parseErr.Line, err: err}
+				}
+				return attributionRecord{}, err
+			}
+			if rr.layout.TrailerPrefix != "" && strings.HasPrefix(record[0], rr.layout.TrailerPrefix) {
+				continue
+			}
+			line, _ = rr.csv.FieldPos(0)
+			fields = record
+		} else {
+			if err := rr.skip(); err != nil {
+				return attributionRecord{}, err
+			}
+			text := rr.lines.Text()
+			if rr.layout.TrailerPrefix != "" && strings.HasPrefix(text, rr.layout.TrailerPrefix) {
+				continue
+			}
+			if rr.maxLength > 0 {
+				if length := len(strings.TrimSpace(text)); length == 0 || length > rr.maxLength {
+					return attributionRecord{}, &recordError{line: rr.line,
+						err: fmt.Errorf("incorrect record length (expected: %d, actual: %d)", rr.maxLength, length)}
+				}
+			} else if text == "" {
+				continue
+			}
+			line = rr.line
+			fields = []string{text}
+		}
+
+		rec, err := rr.parse(fields)
+		if err != nil {
+			return attributionRecord{}, &recordError{line: line, err: err}
+		}
+		rec.line = line
+		return rec, nil
+	}
+}
+
+func (rr *recordReader) parse(fields []string) (attributionRecord, error) {
+	var (
+		rec attributionRecord
+		err error
+	)
+	if rec.mbi, err = rr.value(fields, rr.layout.MBI); err != nil {
+		return rec, err
+	}
+	if rec.effectiveDate, err = rr.date(fields, rr.layout.EffectiveDate); err != nil {
+		return rec, fmt.Errorf("invalid effective date: %w", err)
+	}
+	if rec.terminationDate, err = rr.date(fields, rr.layout.TerminationDate); err != nil {
+		return rec, fmt.Errorf("invalid termination date: %w", err)
+	}
+	return rec, nil
+}
+
+func (rr *recordReader) value(fields []string, field service.AttributionField) (string, error) {
+	if !field.IsSet() {
+		return "", nil
+	}
+	if rr.csv != nil {
+		if field.Column > len(fields) {
+			return "", fmt.Errorf("record has %d fields, expected at least %d", len(fields), field.Column)
+		}
+		return strings.TrimSpace(fields[field.Column-1]), nil
+	}
+	if field.End > len(fields[0]) {
+		return "", fmt.Errorf("record is %d bytes, expected at least %d", len(fields[0]), field.End)
+	}
+	return strings.TrimSpace(fields[0][field.Start:field.End]), nil
+}
+
+func (rr *recordReader) date(fields []string, field service.AttributionField) (time.Time, error) {
+	// Fixed-width records often have trailing blanks trimmed, so a record ending before an optional date is
+	// treated as leaving it blank
+	if rr.csv == nil && field.IsSet() && field.End > len(fields[0]) {
+		field.End = max(len(fields[0]), field.Start)
+	}
+	v, err := rr.value(fields, field)
+	if err != nil || v == "" {
+		return time.Time{}, err
+	}
+	return time.Parse(rr.layout.DateLayout, v)
+}
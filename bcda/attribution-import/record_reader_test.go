@@ -0,0 +1,88 @@
+package attributionimport
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+func readAll(t *testing.T, rr *recordReader) ([]attributionRecord, []error) {
+	var (
+		records []attributionRecord
+		errs    []error
+	)
+	for {
+		rec, err := rr.Read()
+		if err == io.EOF {
+			return records, errs
+		}
+		if err != nil {
+			errs = append(errs, err)
+			var recErr *recordError
+			require.ErrorAs(t, err, &recErr, "only record errors are expected")
+			continue
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestRecordReaderDelimited(t *testing.T) {
+	layout := service.AttributionLayout{
+		Delimiter:       "|",
+		HeaderLines:     2,
+		TrailerPrefix:   "TRL",
+		MBI:             service.AttributionField{Column: 2},
+		EffectiveDate:   service.AttributionField{Column: 3},
+		TerminationDate: service.AttributionField{Column: 4},
+		DateLayout:      "20060102",
+	}
+	data := "HDR|2025\nID|MBI|EFF|TERM\n1|1AA0AA0AA00|20250101|\n2| 1AA0AA0AA01 |20250101|20250630\n3|1AA0AA0AA02|2025-01-01|\nTRL|3\n"
+
+	rr, err := newRecordReader(strings.NewReader(data), layout)
+	require.NoError(t, err)
+	records, errs := readAll(t, rr)
+
+	assert.Equal(t, []attributionRecord{
+		{line: 3, mbi: "1AA0AA0AA00", effectiveDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{line: 4, mbi: "1AA0AA0AA01", effectiveDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), terminationDate: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)},
+	}, records)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "invalid effective date")
+	assert.Equal(t, 5, errs[0].(*recordError).line)
+}
+
+func TestRecordReaderFixedWidth(t *testing.T) {
+	layout, ok := service.AttributionFormat(service.CCLFFormat)
+	require.True(t, ok)
+	layout.EffectiveDate = service.AttributionField{Start: 11, End: 21}
+
+	rr, err := newRecordReader(strings.NewReader("1AA0AA0AA002025-02-01\n\n1AA0AA0AA01\n1AA0AA\n"), layout)
+	require.NoError(t, err)
+	records, errs := readAll(t, rr)
+
+	assert.Equal(t, []attributionRecord{
+		{line: 1, mbi: "1AA0AA0AA00", effectiveDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{line: 3, mbi: "1AA0AA0AA01"},
+	}, records)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "record is 6 bytes, expected at least 11")
+	assert.Equal(t, 4, errs[0].(*recordError).line)
+}
+
+func TestRecordReaderEmptyFile(t *testing.T) {
+	layout, _ := service.AttributionFormat("csv")
+	_, err := newRecordReader(strings.NewReader(""), layout)
+	assert.ErrorIs(t, err, errEmptyAttributionFile)
+
+	layout.HeaderLines = 0
+	rr, err := newRecordReader(strings.NewReader(""), layout)
+	require.NoError(t, err)
+	_, err = rr.Read()
+	assert.Equal(t, io.EOF, err)
+}
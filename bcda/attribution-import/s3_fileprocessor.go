@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 
 	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
@@ -177,13 +178,16 @@ func (processor *S3FileProcessor) CleanUpCSV(ctx context.Context, file csvFile)
 	return nil
 }
 
-func (processor *S3FileProcessor) LoadCSV(ctx context.Context, filepath string) (*bytes.Reader, func(), error) {
-	byte_arr, err := processor.Handler.OpenFileBytes(ctx, filepath)
+func (processor *S3FileProcessor) LoadCSV(ctx context.Context, filepath string) (io.Reader, func(), error) {
+	body, err := processor.Handler.OpenFileStream(ctx, filepath)
 	if err != nil {
 		processor.Handler.Errorf("Failed to download %s\n", filepath)
 		return nil, nil, err
 	}
 
-	reader := bytes.NewReader(byte_arr)
-	return reader, func() {}, err
+	return body, func() {
+		if err := body.Close(); err != nil {
+			processor.Handler.Warningf("Failed to close %s: %s\n", filepath, err)
+		}
+	}, nil
 }
@@ -0,0 +1,46 @@
+package attributionimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
+)
+
+// ParseFileStatus maps a status supplied by an operator (case insensitive) to the import status it represents.
+// Only the statuses used to withdraw or restore an imported file are accepted.
+func ParseFileStatus(status string) (string, error) {
+	for _, s := range []string{constants.ImportSuperseded, constants.ImportInvalid, constants.ImportComplete} {
+		if strings.EqualFold(status, s) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported attribution file status '%s', must be one of superseded, invalid or completed", status)
+}
+
+// UpdateFileStatus withdraws an imported attribution file by marking it superseded or invalid, or restores a
+// previously withdrawn file by marking it completed. Exports only use completed files, so withdrawing the
+// latest file causes them to fall back to the previous one. The change is recorded along with the reason
+// and the operator who made it.
+func UpdateFileStatus(ctx context.Context, r models.Repository, fileID uint, status, reason, operator string) (*models.CCLFFileStatusChange, error) {
+	if fileID == 0 {
+		return nil, errors.New("attribution file ID is required")
+	}
+	if strings.TrimSpace(reason) == "" || strings.TrimSpace(operator) == "" {
+		return nil, errors.New("a reason and the operator making the change are required")
+	}
+
+	s, err := ParseFileStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := r.UpdateCCLFFileStatus(ctx, fileID, s, reason, operator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status of attribution file %d: %w", fileID, err)
+	}
+	return change, nil
+}
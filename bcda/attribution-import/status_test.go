@@ -0,0 +1,48 @@
+package attributionimport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
+)
+
+func TestParseFileStatus(t *testing.T) {
+	for input, expected := range map[string]string{"superseded": constants.ImportSuperseded, "INVALID": constants.ImportInvalid, "Completed": constants.ImportComplete} {
+		status, err := ParseFileStatus(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, status)
+	}
+
+	for _, input := range []string{"", "failed", constants.ImportInprog} {
+		_, err := ParseFileStatus(input)
+		assert.ErrorContains(t, err, "unsupported attribution file status", input)
+	}
+}
+
+func TestUpdateFileStatus(t *testing.T) {
+	ctx := context.Background()
+
+	repository := models.NewMockRepository(t)
+	change := &models.CCLFFileStatusChange{ID: 1, FileID: 7, PreviousStatus: constants.ImportComplete, Status: constants.ImportInvalid}
+	repository.On("UpdateCCLFFileStatus", ctx, uint(7), constants.ImportInvalid, "truncated roster", "jdoe").Return(change, nil)
+	result, err := UpdateFileStatus(ctx, repository, 7, "invalid", "truncated roster", "jdoe")
+	assert.NoError(t, err)
+	assert.Equal(t, change, result)
+
+	repository.On("UpdateCCLFFileStatus", ctx, uint(8), constants.ImportSuperseded, mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("no cclf file 8 that can be moved to Superseded: %w", sql.ErrNoRows))
+	_, err = UpdateFileStatus(ctx, repository, 8, "superseded", "replaced by a corrected file", "jdoe")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = UpdateFileStatus(ctx, repository, 7, "invalid", " ", "jdoe")
+	assert.ErrorContains(t, err, "a reason and the operator")
+	_, err = UpdateFileStatus(ctx, repository, 0, "invalid", "truncated roster", "jdoe")
+	assert.ErrorContains(t, err, "file ID is required")
+}
@@ -0,0 +1,302 @@
+package attributionimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	f "path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// mbiPattern matches the Medicare Beneficiary Identifier format. Letters exclude S, L, O, I, B and Z.
+var mbiPattern = regexp.MustCompile(`^[1-9][AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9][0-9][AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9][0-9][AC-HJKMNP-RT-Y]{2}[0-9]{2}$`)
+
+// ValidationIssue is a single problem found while validating an attribution file.
+// Line is the 1-based line number within the file, or zero when the issue applies to the whole file.
+type ValidationIssue struct {
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// FileValidation is the result of validating a single attribution file.
+type FileValidation struct {
+	Name                string            `json:"name"`
+	CMSID               string            `json:"cms_id,omitempty"`
+	PerformanceYear     int               `json:"performance_year,omitempty"`
+	ExpectedRecordCount int               `json:"expected_record_count,omitempty"`
+	RecordCount         int               `json:"record_count"`
+	UniqueMBICount      int               `json:"unique_mbi_count"`
+	Issues              []ValidationIssue `json:"issues"`
+}
+
+// ValidationReport summarizes a dry run of an attribution import. No data is written and no files are
+// cleaned up while it is produced.
+type ValidationReport struct {
+	Files []FileValidation `json:"files"`
+	// Archives that could not be matched to an ACO or opened; see the logs for details
+	SkippedArchives int `json:"skipped_archives"`
+	FailedArchives  int `json:"failed_archives"`
+}
+
+// Valid reports whether the delivery can be imported, i.e. no archives failed to load and no file has errors.
+func (r ValidationReport) Valid() bool {
+	if r.FailedArchives > 0 {
+		return false
+	}
+	for _, fv := range r.Files {
+		if !fv.Valid() {
+			return false
+		}
+	}
+	return true
+}
+
+func (fv FileValidation) Valid() bool {
+	for _, issue := range fv.Issues {
+		if issue.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// maxSummaryLines caps the line numbers listed per file in a ValidationSummary
+const maxSummaryLines = 100
+
+// ValidationSummary is a ValidationReport reduced to counts and line numbers. Issue messages can contain MBIs,
+// so only the summary may be logged.
+type ValidationSummary struct {
+	Files           []FileValidationSummary `json:"files"`
+	SkippedArchives int                     `json:"skipped_archives"`
+	FailedArchives  int                     `json:"failed_archives"`
+}
+
+type FileValidationSummary struct {
+	Name            string `json:"name"`
+	CMSID           string `json:"cms_id,omitempty"`
+	PerformanceYear int    `json:"performance_year,omitempty"`
+	RecordCount     int    `json:"record_count"`
+	UniqueMBICount  int    `json:"unique_mbi_count"`
+	ErrorCount      int    `json:"error_count"`
+	WarningCount    int    `json:"warning_count"`
+	// The first lines with errors; zero means the error applies to the whole file
+	ErrorLines []int `json:"error_lines,omitempty"`
+}
+
+// Summary returns the report without issue messages.
+func (r ValidationReport) Summary() ValidationSummary {
+	summary := ValidationSummary{SkippedArchives: r.SkippedArchives, FailedArchives: r.FailedArchives}
+	for _, fv := range r.Files {
+		fs := FileValidationSummary{Name: fv.Name, CMSID: fv.CMSID, PerformanceYear: fv.PerformanceYear,
+			RecordCount: fv.RecordCount, UniqueMBICount: fv.UniqueMBICount}
+		for _, issue := range fv.Issues {
+			if issue.Severity != SeverityError {
+				fs.WarningCount++
+				continue
+			}
+			fs.ErrorCount++
+			if len(fs.ErrorLines) < maxSummaryLines {
+				fs.ErrorLines = append(fs.ErrorLines, issue.Line)
+			}
+		}
+		summary.Files = append(summary.Files, fs)
+	}
+	return summary
+}
+
+func (fv *FileValidation) addIssue(line int, severity, format string, args ...interface{}) {
+	fv.Issues = append(fv.Issues, ValidationIssue{Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// mbiChecker tracks the MBIs seen in a file so duplicates can be reported against the line they first appeared on.
+type mbiChecker struct {
+	fv   *FileValidation
+	seen map[string]int
+}
+
+func newMBIChecker(fv *FileValidation) *mbiChecker {
+	return &mbiChecker{fv: fv, seen: make(map[string]int)}
+}
+
+func (c *mbiChecker) check(line int, mbi string) {
+	if !mbiPattern.MatchString(mbi) {
+		c.fv.addIssue(line, SeverityError, "invalid MBI format '%s'", mbi)
+	}
+	if first, found := c.seen[mbi]; found {
+		c.fv.addIssue(line, SeverityWarning, "duplicate MBI '%s' (first seen on line %d); it will only be imported once", mbi, first)
+		return
+	}
+	c.seen[mbi] = line
+	c.fv.UniqueMBICount++
+}
+
+// checkPerformanceYear flags performance years more than a year away from the year the file was generated.
+// Runout files are generated the year after their performance year.
+func checkPerformanceYear(fv *FileValidation, perfYear int, fileTime time.Time) {
+	fileYear := fileTime.Year() % 100
+	if perfYear < fileYear-1 || perfYear > fileYear+1 {
+		fv.addIssue(0, SeverityError, "performance year %d is not within one year of the file date %s", perfYear, fileTime.Format("2006-01-02"))
+	}
+}
+
+// ValidateCCLFDirectory runs the same parsing and validation as ImportCCLFDirectory against every CCLF archive
+// found at filePath without writing to the database or cleaning up the archives.
+func (importer CclfImporter) ValidateCCLFDirectory(ctx context.Context, filePath string) (ValidationReport, error) {
+	var report ValidationReport
+
+	cclfMap, skipped, failure, err := importer.fileProcessor.LoadCclfFiles(ctx, filePath)
+	if err != nil {
+		return report, err
+	}
+	report.SkippedArchives, report.FailedArchives = skipped, failure
+
+	for acoID := range cclfMap {
+		for _, zipMetadata := range cclfMap[acoID] {
+			func() {
+				defer zipMetadata.zipCloser()
+				report.Files = append(report.Files, importer.validateCCLFArchive(ctx, zipMetadata))
+			}()
+		}
+	}
+
+	return report, nil
+}
+
+func (importer CclfImporter) validateCCLFArchive(ctx context.Context, zipMetadata *cclfZipMetadata) FileValidation {
+	cclf8 := zipMetadata.cclf8Metadata
+	fv := FileValidation{Name: cclf8.name, CMSID: cclf8.acoID, PerformanceYear: cclf8.perfYear}
+
+	checkPerformanceYear(&fv, cclf8.perfYear, cclf8.timestamp)
+	if cclf0 := zipMetadata.cclf0Metadata; cclf0.perfYear != cclf8.perfYear {
+		fv.addIssue(0, SeverityError, "CCLF0 file %s has performance year %d, expected %d", cclf0.name, cclf0.perfYear, cclf8.perfYear)
+	}
+
+	validator, err := importer.importCCLF0(ctx, zipMetadata)
+	if err != nil {
+		fv.addIssue(0, SeverityError, "invalid CCLF0 file %s: %s", zipMetadata.cclf0Metadata.name, err.Error())
+		return fv
+	}
+	fv.ExpectedRecordCount = validator.totalRecordCount
+
+	layout, err := cclfRecordLayout(cclf8.acoID)
+	if err != nil {
+		fv.addIssue(0, SeverityError, "could not determine the CCLF8 record layout: %s", err.Error())
+		return fv
+	}
+
+	rc, err := zipMetadata.cclf8File.Open()
+	if err != nil {
+		fv.addIssue(0, SeverityError, "could not read CCLF8 file: %s", err.Error())
+		return fv
+	}
+	defer rc.Close()
+
+	r, err := newRecordReader(rc, layout)
+	if err != nil {
+		fv.addIssue(0, SeverityError, "could not read CCLF8 file: %s", err.Error())
+		return fv
+	}
+	// Mirrors the record length check applied by cclf8Importer during the import
+	r.maxLength = validator.maxRecordLength
+
+	mbis := newMBIChecker(&fv)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var recErr *recordError
+		if errors.As(err, &recErr) {
+			fv.RecordCount++
+			fv.addIssue(recErr.line, SeverityError, "%s", err.Error())
+			continue
+		}
+		if err != nil {
+			fv.addIssue(fv.RecordCount+1, SeverityError, "could not read CCLF8 file: %s", err.Error())
+			break
+		}
+
+		fv.RecordCount++
+		mbis.check(record.line, record.mbi)
+	}
+
+	if fv.RecordCount > validator.totalRecordCount {
+		fv.addIssue(0, SeverityError, "unexpected number of records (expected: %d, actual: %d)", validator.totalRecordCount, fv.RecordCount)
+	}
+
+	return fv
+}
+
+// ValidateCSV runs the same parsing and validation as ImportCSV against the CSV attribution file at filepath
+// without writing to the database or cleaning up the file.
+func (importer CSVImporter) ValidateCSV(ctx context.Context, filepath string) (ValidationReport, error) {
+	fv := FileValidation{Name: f.Base(filepath)}
+	report := ValidationReport{}
+
+	metadata, layout, err := parseCSVFileName(fv.Name)
+	if err != nil {
+		fv.addIssue(0, SeverityError, "invalid CSV attribution file name: %s", err.Error())
+		report.Files = append(report.Files, fv)
+		return report, nil
+	}
+	fv.CMSID, fv.PerformanceYear = metadata.acoID, metadata.perfYear
+	checkPerformanceYear(&fv, metadata.perfYear, metadata.timestamp)
+
+	data, closer, err := importer.FileProcessor.LoadCSV(ctx, filepath)
+	if err != nil {
+		return report, err
+	}
+	if closer != nil {
+		defer closer()
+	}
+
+	validateCSVRecords(&fv, data, layout)
+	report.Files = append(report.Files, fv)
+	return report, nil
+}
+
+func validateCSVRecords(fv *FileValidation, data io.Reader, layout service.AttributionLayout) {
+	r, err := newRecordReader(data, layout)
+	if err != nil {
+		line := 1
+		if errors.Is(err, errEmptyAttributionFile) {
+			line = 0
+		}
+		fv.addIssue(line, SeverityError, "%s", err.Error())
+		return
+	}
+
+	mbis := newMBIChecker(fv)
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var recErr *recordError
+			if !errors.As(err, &recErr) {
+				fv.addIssue(0, SeverityError, "failed to read csv attribution record: %s", err.Error())
+				return
+			}
+			fv.addIssue(recErr.line, SeverityError, "failed to read csv attribution record: %s", err.Error())
+			continue
+		}
+
+		fv.RecordCount++
+		mbis.check(record.line, record.mbi)
+		if !record.terminationDate.IsZero() && record.terminationDate.Before(record.effectiveDate) {
+			fv.addIssue(record.line, SeverityError, "termination date %s is before effective date %s",
+				record.terminationDate.Format(time.DateOnly), record.effectiveDate.Format(time.DateOnly))
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package attributionimport
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/service"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+func TestMBIPattern(t *testing.T) {
+	assert.True(t, mbiPattern.MatchString("1AA0AA0AA00"))
+	assert.True(t, mbiPattern.MatchString("9Y99Y99YY99"))
+	assert.False(t, mbiPattern.MatchString("0AA0AA0AA00"), "leading zero")
+	assert.False(t, mbiPattern.MatchString("1SA0AA0AA00"), "excluded letter")
+	assert.False(t, mbiPattern.MatchString("1AA0AA0AA0"), "too short")
+	assert.False(t, mbiPattern.MatchString("1aa0aa0aa00"), "lower case")
+}
+
+func TestCheckPerformanceYear(t *testing.T) {
+	fileTime := time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+	for perfYear, valid := range map[int]bool{23: false, 24: true, 25: true, 26: true, 27: false} {
+		fv := FileValidation{}
+		checkPerformanceYear(&fv, perfYear, fileTime)
+		assert.Equal(t, valid, fv.Valid(), "performance year %d", perfYear)
+	}
+}
+
+func TestValidateCSVRecords(t *testing.T) {
+	csvLayout, _ := service.AttributionFormat("csv")
+	tests := []struct {
+		name     string
+		data     string
+		records  int
+		unique   int
+		valid    bool
+		expected []ValidationIssue
+	}{
+		{"Valid", "MBIs\n1AA0AA0AA00\n1AA0AA0AA01\n", 2, 2, true, nil},
+		{"Empty", "", 0, 0, false, []ValidationIssue{{Line: 0, Severity: SeverityError, Message: "empty attribution file"}}},
+		{"InvalidMBI", "MBIs\n1AA0AA0AA00\nnot-an-mbi\n", 2, 2, false,
+			[]ValidationIssue{{Line: 3, Severity: SeverityError, Message: "invalid MBI format 'not-an-mbi'"}}},
+		{"DuplicateMBI", "MBIs\n1AA0AA0AA00\n1AA0AA0AA01\n1AA0AA0AA00\n", 3, 2, true,
+			[]ValidationIssue{{Line: 4, Severity: SeverityWarning, Message: "duplicate MBI '1AA0AA0AA00' (first seen on line 2); it will only be imported once"}}},
+		{"FieldCount", "MBIs\n1AA0AA0AA00,extra\n1AA0AA0AA01\n", 1, 1, false,
+			[]ValidationIssue{{Line: 2, Severity: SeverityError, Message: "failed to read csv attribution record: record on line 2: wrong number of fields"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fv := FileValidation{}
+			validateCSVRecords(&fv, strings.NewReader(tt.data), csvLayout)
+			assert.Equal(t, tt.records, fv.RecordCount)
+			assert.Equal(t, tt.unique, fv.UniqueMBICount)
+			assert.Equal(t, tt.valid, fv.Valid())
+			assert.Equal(t, tt.expected, fv.Issues)
+		})
+	}
+
+	t.Run("TerminationBeforeEffective", func(t *testing.T) {
+		layout := service.AttributionLayout{Delimiter: ",", HeaderLines: 1, DateLayout: service.DefaultAttributionDateLayout,
+			MBI: service.AttributionField{Column: 1}, EffectiveDate: service.AttributionField{Column: 2}, TerminationDate: service.AttributionField{Column: 3}}
+		fv := FileValidation{}
+		validateCSVRecords(&fv, strings.NewReader("MBI,EFF,TERM\n1AA0AA0AA00,2025-03-01,2025-01-31\n"), layout)
+		assert.Equal(t, []ValidationIssue{{Line: 2, Severity: SeverityError, Message: "termination date 2025-01-31 is before effective date 2025-03-01"}}, fv.Issues)
+	})
+}
+
+func TestValidationReportValid(t *testing.T) {
+	warning := FileValidation{Issues: []ValidationIssue{{Severity: SeverityWarning}}}
+	invalid := FileValidation{Issues: []ValidationIssue{{Severity: SeverityError}}}
+
+	assert.True(t, ValidationReport{Files: []FileValidation{{}, warning}}.Valid())
+	assert.False(t, ValidationReport{Files: []FileValidation{warning, invalid}}.Valid())
+	assert.False(t, ValidationReport{FailedArchives: 1}.Valid())
+}
+
+func TestValidationReportSummary(t *testing.T) {
+	report := ValidationReport{FailedArchives: 1, Files: []FileValidation{{
+		Name: "T.A0001.ACO.ZC8Y18.D181120.T1000009", CMSID: "A0001", PerformanceYear: 18, RecordCount: 3, UniqueMBICount: 2,
+		Issues: []ValidationIssue{
+			{Line: 2, Severity: SeverityError, Message: "invalid MBI format 'not-an-mbi'"},
+			{Line: 3, Severity: SeverityWarning, Message: "duplicate MBI '1AA0AA0AA00' (first seen on line 1); it will only be imported once"},
+			{Severity: SeverityError, Message: "unexpected number of records (expected: 4, actual: 3)"},
+		},
+	}}}
+
+	summary := report.Summary()
+	assert.Equal(t, ValidationSummary{FailedArchives: 1, Files: []FileValidationSummary{{
+		Name: "T.A0001.ACO.ZC8Y18.D181120.T1000009", CMSID: "A0001", PerformanceYear: 18, RecordCount: 3, UniqueMBICount: 2,
+		ErrorCount: 2, WarningCount: 1, ErrorLines: []int{2, 0},
+	}}}, summary)
+
+	b, err := json.Marshal(summary)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "not-an-mbi")
+	assert.NotContains(t, string(b), "1AA0AA0AA00")
+}
+
+func (s *LocalFileProcessorTestSuite) TestValidateCCLFDirectory() {
+	path := filepath.Join(s.basePath, "cclf/archives/valid/")
+	before, err := filepath.Glob(filepath.Join(path, "*"))
+	require.NoError(s.T(), err)
+
+	importer := NewCclfImporter(log.API, &LocalFileProcessor{Handler: s.cclfProcessor.(*LocalFileProcessor).Handler, DryRun: true}, nil)
+	report, err := importer.ValidateCCLFDirectory(s.T().Context(), path)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), report.Valid())
+	assert.NotEmpty(s.T(), report.Files)
+	for _, fv := range report.Files {
+		assert.Equal(s.T(), 18, fv.PerformanceYear)
+		assert.Equal(s.T(), fv.RecordCount, fv.UniqueMBICount, fv.Name)
+		assert.Equal(s.T(), fv.ExpectedRecordCount, fv.RecordCount, fv.Name)
+	}
+
+	// Validation must leave the delivery in place so it can still be imported
+	after, err := filepath.Glob(filepath.Join(path, "*"))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), before, after)
+}
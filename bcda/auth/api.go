@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"strconv"
+	"time"
 
 	"github.com/CMSgov/bcda-app/conf"
 	"github.com/CMSgov/bcda-app/log"
@@ -62,6 +63,13 @@ func (a BaseApi) GetAuthToken(w http.ResponseWriter, r *http.Request) {
 		ctxLogger = ctxLogger.WithFields(logrus.Fields{"cms_id": ad.CMSID})
 	}
 
+	// Rotated credentials are refused once their grace window ends, even if they have not been revoked yet
+	if CredentialsExpired(ad, time.Now()) {
+		ctxLogger.WithField("resp_status", http.StatusUnauthorized).Errorf("Error making access token - rotated credentials expired at %s | HTTPS Status Code: %v", ad.CredentialsExpireAt, http.StatusUnauthorized)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
 	tokenInfo, err := a.provider.MakeAccessToken(Credentials{ClientID: clientId, ClientSecret: secret}, r)
 	if err != nil {
 		switch err.(type) {
@@ -99,6 +107,9 @@ func (a BaseApi) GetAuthToken(w http.ResponseWriter, r *http.Request) {
 	// https://tools.ietf.org/html/rfc6749#section-5.1
 
 	w.Header().Set("Content-Type", "application/json")
+	if warning := CredentialExpiryWarning(ad, time.Now()); warning != "" {
+		w.Header().Set("Warning", warning)
+	}
 	w.Header().Set("Cache-Control", "no-store")
 	w.Header().Set("Pragma", "no-cache")
 	_, err = w.Write([]byte(tokenInfo)) // #nosec G705
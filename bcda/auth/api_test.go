@@ -232,7 +232,10 @@ func BenchmarkAuthToken(b *testing.B) {
 	defer db.Close()
 
 	repository := postgres.NewRepository(db)
-	provider := auth.NewProvider(db)
+	provider, err := auth.NewProvider(db)
+	if err != nil {
+		b.Fatal(err)
+	}
 	baseApi := auth.NewBaseApi(provider)
 	router := web.NewAuthRouter(provider)
 	server := httptest.NewServer(router)
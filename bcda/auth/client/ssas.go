@@ -18,6 +18,7 @@ import (
 
 	"github.com/CMSgov/bcda-app/bcda/client"
 	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/conf"
 	"github.com/CMSgov/bcda-app/log"
 	"github.com/CMSgov/bcda-app/middleware"
@@ -72,7 +73,7 @@ func NewSSASClient() (*SSASClient, error) {
 		return nil, errors.New("SSAS client could not be created: no URL provided")
 	}
 
-	c := http.Client{Transport: transport, Timeout: time.Duration(timeout) * time.Millisecond}
+	c := http.Client{Transport: tracing.Transport(transport), Timeout: time.Duration(timeout) * time.Millisecond}
 
 	return &SSASClient{c, ssasURL}, nil
 }
@@ -304,7 +305,7 @@ func (c *SSASClient) RevokeAccessToken(tokenID string) error {
 func (c *SSASClient) GetToken(credentials Credentials, r http.Request) (string, error) {
 	public := conf.GetEnv("SSAS_PUBLIC_URL")
 	tokenUrl := fmt.Sprintf("%s/token", public)
-	req, err := http.NewRequest("POST", tokenUrl, nil)
+	req, err := http.NewRequestWithContext(r.Context(), "POST", tokenUrl, nil)
 	if err != nil {
 		return "", &customErrors.InternalParsingError{Err: err, Msg: constants.RequestStructErr}
 	}
@@ -359,6 +360,7 @@ func (c *SSASClient) CallSSASIntrospect(ctx context.Context, tokenString string)
 	if err != nil {
 		return nil, err
 	}
+	request = request.WithContext(ctx)
 
 	tid := ctx.Value(middleware.CtxTransactionKey)
 	if tid != nil {
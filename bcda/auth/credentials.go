@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/utils"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+// CredentialExpiryWarningWindow is how long before expiry clients are warned that their credentials need replacing.
+func CredentialExpiryWarningWindow() time.Duration {
+	return time.Duration(utils.GetEnvInt("BCDA_CREDENTIAL_EXPIRY_WARNING_DAYS", 14)) * 24 * time.Hour
+}
+
+// CredentialsExpired reports whether rotated credentials are being used after their grace window ended.
+func CredentialsExpired(ad AuthData, now time.Time) bool {
+	return ad.CredentialsSuperseded && now.After(ad.CredentialsExpireAt)
+}
+
+// CredentialExpiryWarning returns an RFC 7234 Warning header value when the credentials described by ad have been
+// rotated or are close to expiring, and an empty string otherwise.
+func CredentialExpiryWarning(ad AuthData, now time.Time) string {
+	if ad.CredentialsExpireAt.IsZero() {
+		return ""
+	}
+
+	expiry := ad.CredentialsExpireAt.UTC().Format(time.RFC3339)
+	if ad.CredentialsSuperseded {
+		return fmt.Sprintf(`299 - "Client credentials have been rotated and will stop working at %s; switch to the new credentials"`, expiry)
+	}
+
+	if ad.CredentialsExpireAt.Sub(now) <= CredentialExpiryWarningWindow() {
+		return fmt.Sprintf(`299 - "Client credentials expire at %s; rotate them before then"`, expiry)
+	}
+
+	return ""
+}
+
+// rotateSecret registers a new system for the ACO that owns clientID and records the rotation so the previous
+// credentials stay usable until the grace period ends.
+func rotateSecret(r models.Repository, p Provider, clientID string, gracePeriod time.Duration, ips []string) (Credentials, error) {
+	ctx := context.Background()
+	aco, err := r.GetACOByClientID(ctx, clientID)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	creds, err := p.RegisterSystem(aco.UUID.String(), "", aco.GroupID, ips...)
+	if err != nil {
+		return creds, err
+	}
+
+	rotation := models.CredentialRotation{
+		ACOID:            aco.UUID,
+		PreviousClientID: aco.ClientID,
+		PreviousSystemID: aco.SystemID,
+		ClientID:         creds.ClientID,
+		SystemID:         creds.SystemID,
+		GraceExpiresAt:   time.Now().Add(gracePeriod),
+	}
+	if !creds.ExpiresAt.IsZero() {
+		rotation.SecretExpiresAt = &creds.ExpiresAt
+	}
+	if err = r.CreateCredentialRotation(ctx, rotation); err != nil {
+		return creds, errors.Wrapf(err, "could not record credential rotation for client %s", clientID)
+	}
+
+	return creds, nil
+}
+
+// applyCredentialRotation fills in the credential expiry of ad from the most recent rotation involving its client.
+// Lookup failures are logged and leave ad unchanged so they never block token issuance.
+func applyCredentialRotation(r models.Repository, ad AuthData) AuthData {
+	rotation, err := r.GetCredentialRotationByClientID(context.Background(), ad.ClientID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Auth.Warnf("Failed to look up credential rotation for client %s: %s", ad.ClientID, err.Error())
+		}
+		return ad
+	}
+
+	if rotation.PreviousClientID == ad.ClientID {
+		ad.CredentialsSuperseded = true
+		ad.CredentialsExpireAt = rotation.GraceExpiresAt
+	} else if rotation.SecretExpiresAt != nil {
+		ad.CredentialsExpireAt = *rotation.SecretExpiresAt
+	}
+
+	return ad
+}
@@ -0,0 +1,75 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+
+	"github.com/CMSgov/bcda-app/bcda/auth"
+)
+
+func TestCredentialExpiryWarning(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		ad         auth.AuthData
+		expWarning string
+		expExpired bool
+	}{
+		{"NoExpiry", auth.AuthData{}, "", false},
+		{"ExpiryFarAway", auth.AuthData{CredentialsExpireAt: now.Add(90 * 24 * time.Hour)}, "", false},
+		{"ExpiryApproaching", auth.AuthData{CredentialsExpireAt: now.Add(24 * time.Hour)}, "Client credentials expire at", false},
+		{"Superseded", auth.AuthData{CredentialsExpireAt: now.Add(90 * 24 * time.Hour), CredentialsSuperseded: true}, "have been rotated", false},
+		{"SupersededAndExpired", auth.AuthData{CredentialsExpireAt: now.Add(-time.Minute), CredentialsSuperseded: true}, "have been rotated", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning := auth.CredentialExpiryWarning(tt.ad, now)
+			if tt.expWarning == "" {
+				assert.Empty(t, warning)
+			} else {
+				assert.Contains(t, warning, tt.expWarning)
+				assert.Regexp(t, `^299 - ".+"$`, warning)
+			}
+			assert.Equal(t, tt.expExpired, auth.CredentialsExpired(tt.ad, now))
+		})
+	}
+}
+
+func TestGetAuthTokenRotatedCredentials(t *testing.T) {
+	tests := []struct {
+		name       string
+		ad         auth.AuthData
+		expStatus  int
+		expWarning bool
+	}{
+		{"WithinGracePeriod", auth.AuthData{CMSID: "cms_test", CredentialsSuperseded: true, CredentialsExpireAt: time.Now().Add(time.Hour)}, http.StatusOK, true},
+		{"AfterGracePeriod", auth.AuthData{CMSID: "cms_test", CredentialsSuperseded: true, CredentialsExpireAt: time.Now().Add(-time.Hour)}, http.StatusUnauthorized, false},
+		{"CurrentCredentials", auth.AuthData{CMSID: "cms_test"}, http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockP := &auth.MockProvider{}
+			mockP.On("GetAuthData", "good").Return(tt.ad, nil)
+			if tt.expStatus == http.StatusOK {
+				mockP.On("MakeAccessToken", auth.Credentials{ClientID: "good", ClientSecret: "client"}, mock.Anything).Return(`{ "token_type": "bearer", "access_token": "goodToken" }`, nil)
+			}
+
+			req := httptest.NewRequest("POST", "/auth/token", nil)
+			req.SetBasicAuth("good", "client")
+			rr := httptest.NewRecorder()
+			auth.NewAuthRouter(mockP).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expStatus, rr.Code)
+			assert.Equal(t, tt.expWarning, rr.Header().Get("Warning") != "")
+			mockP.AssertExpectations(t)
+		})
+	}
+}
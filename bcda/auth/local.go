@@ -0,0 +1,356 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	customErrors "github.com/CMSgov/bcda-app/bcda/errors"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/utils"
+	"github.com/CMSgov/bcda-app/conf"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+// localTokenTTL matches the lifetime of SSAS access tokens
+const localTokenTTL = 20 * time.Minute
+
+// LocalProvider is an implementation of Provider that stores systems in BCDA's own database and issues RS256
+// signed tokens itself. It allows BCDA to run in environments where SSAS is not deployed.
+type LocalProvider struct {
+	repository models.Repository
+	signingKey *rsa.PrivateKey
+}
+
+// validates that LocalProvider implements the interface
+var _ Provider = LocalProvider{}
+
+// NewLocalProvider returns a LocalProvider that signs tokens with the RSA key in BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE.
+// When no key file is configured an ephemeral key is generated, so tokens do not survive a restart and are not
+// accepted by other API instances.
+func NewLocalProvider(r models.Repository) (LocalProvider, error) {
+	p := LocalProvider{repository: r}
+
+	keyPath := conf.GetEnv("BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE")
+	if keyPath == "" {
+		log.Auth.Warn("No BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE value; generating an ephemeral token signing key")
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return p, errors.Wrap(err, "failed to generate token signing key")
+		}
+		p.signingKey = key
+		return p, nil
+	}
+
+	keyPEM, err := os.ReadFile(keyPath) // #nosec G304
+	if err != nil {
+		return p, errors.Wrap(err, "failed to read token signing key")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return p, errors.Wrap(err, "failed to parse token signing key")
+	}
+	p.signingKey = key
+	return p, nil
+}
+
+func (l LocalProvider) FindAndCreateACOCredentials(ACOID string, ips []string) (string, error) {
+	aco, err := l.repository.GetACOByCMSID(context.Background(), ACOID)
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := l.RegisterSystem(aco.UUID.String(), "", aco.GroupID, ips...)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not register system for %s", ACOID)
+	}
+
+	return strings.Join([]string{creds.ClientName, creds.ClientID, creds.ClientSecret, creds.ExpiresAt.String()}, "\n"), nil
+}
+
+// RegisterSystem creates a system with a new client ID and secret for the ACO identified by localID.
+func (l LocalProvider) RegisterSystem(localID, publicKey, groupID string, ips ...string) (Credentials, error) {
+	ctx := context.Background()
+	creds := Credentials{}
+	aco, err := l.repository.GetACOByUUID(ctx, uuid.Parse(localID))
+	if err != nil {
+		return creds, errors.Wrap(err, "failed to create system")
+	}
+
+	secret, secretHash, err := newClientSecret()
+	if err != nil {
+		return creds, errors.Wrap(err, "failed to create system")
+	}
+
+	system := models.AuthSystem{
+		ACOID:           aco.UUID,
+		ClientID:        uuid.NewRandom().String(),
+		ClientName:      aco.Name,
+		SecretHash:      secretHash,
+		PublicKey:       publicKey,
+		IPs:             ips,
+		SecretExpiresAt: localSecretExpiry(),
+	}
+	id, err := l.repository.CreateAuthSystem(ctx, system)
+	if err != nil {
+		return creds, errors.Wrap(err, "failed to create system")
+	}
+
+	creds = Credentials{
+		ClientID:     system.ClientID,
+		ClientSecret: secret,
+		ClientName:   system.ClientName,
+		SystemID:     strconv.FormatUint(uint64(id), 10),
+		ExpiresAt:    system.SecretExpiresAt,
+	}
+
+	if err = l.repository.UpdateACO(ctx, aco.UUID,
+		map[string]interface{}{"client_id": creds.ClientID, "system_id": creds.SystemID}); err != nil {
+		return creds, errors.Wrapf(err, "could not update ACO %s with client and system IDs", aco.UUID)
+	}
+
+	return creds, nil
+}
+
+// ResetSecret replaces the secret of the system registered with clientID.
+func (l LocalProvider) ResetSecret(clientID string) (Credentials, error) {
+	ctx := context.Background()
+	system, err := l.repository.GetAuthSystemByClientID(ctx, clientID)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	secret, secretHash, err := newClientSecret()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	expiresAt := localSecretExpiry()
+	if err = l.repository.UpdateAuthSystemSecret(ctx, system.ID, secretHash, expiresAt); err != nil {
+		return Credentials{}, err
+	}
+
+	return Credentials{
+		ClientID:     system.ClientID,
+		ClientSecret: secret,
+		ClientName:   system.ClientName,
+		SystemID:     strconv.FormatUint(uint64(system.ID), 10),
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+func (l LocalProvider) RotateSecret(clientID string, gracePeriod time.Duration, ips []string) (Credentials, error) {
+	return rotateSecret(l.repository, l, clientID, gracePeriod, ips)
+}
+
+// RevokeSystemCredentials deactivates the system so its credentials can no longer be used to get tokens.
+func (l LocalProvider) RevokeSystemCredentials(systemID string) error {
+	id, err := strconv.ParseUint(systemID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid system ID %s", systemID)
+	}
+	return l.repository.DeleteAuthSystem(context.Background(), uint(id))
+}
+
+// MakeAccessToken verifies the client credentials and mints a signed access token for the client's ACO.
+func (l LocalProvider) MakeAccessToken(credentials Credentials, r *http.Request) (string, error) {
+	ctx := context.Background()
+	system, err := l.repository.GetAuthSystemByClientID(ctx, credentials.ClientID)
+	if err != nil {
+		return "", localUnauthorized(err)
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(system.SecretHash), []byte(credentials.ClientSecret)); err != nil {
+		return "", localUnauthorized(err)
+	}
+
+	if time.Now().After(system.SecretExpiresAt) {
+		return "", localUnauthorized(errors.New("client secret has expired"))
+	}
+
+	if len(system.IPs) > 0 && !slices.Contains(system.IPs, requestIP(r)) {
+		return "", localUnauthorized(fmt.Errorf("request IP %s is not registered for client", requestIP(r)))
+	}
+
+	aco, err := l.repository.GetACOByUUID(ctx, system.ACOID)
+	if err != nil {
+		return "", &customErrors.InternalParsingError{Err: err, Msg: "failed to load ACO for client"}
+	}
+	if aco.CMSID == nil {
+		return "", &customErrors.InternalParsingError{Err: fmt.Errorf("ACO %s has no CMS ID", aco.UUID), Msg: "failed to load ACO for client"}
+	}
+
+	data, err := json.Marshal(struct {
+		IDList []string `json:"cms_ids"`
+	}{IDList: []string{*aco.CMSID}})
+	if err != nil {
+		return "", &customErrors.InternalParsingError{Err: err, Msg: "failed to build token data claim"}
+	}
+
+	now := time.Now()
+	claims := CommonClaims{
+		ClientID: system.ClientID,
+		SystemID: strconv.FormatUint(uint64(system.ID), 10),
+		Data:     string(data),
+		ACOID:    aco.UUID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    constants.IssuerLocalAuth,
+			Subject:   system.ClientID,
+			ID:        uuid.NewRandom().String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(localTokenTTL)),
+		},
+	}
+	// Bind the token to the client certificate when the client connected over mutual TLS
+	if cert := VerifiedClientCert(r); cert != nil {
+		claims.Confirmation = &Confirmation{X5tS256: CertificateThumbprint(cert)}
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(l.signingKey)
+	if err != nil {
+		return "", &customErrors.InternalParsingError{Err: err, Msg: "failed to sign access token"}
+	}
+
+	return fmt.Sprintf(`{"access_token": "%s", "expires_in": "%d", "token_type":"bearer"}`, tokenString, int(localTokenTTL.Seconds())), nil
+}
+
+// RevokeAccessToken records the token's ID so it is rejected for the rest of its lifetime.
+func (l LocalProvider) RevokeAccessToken(tokenString string) error {
+	token, err := l.parseToken(tokenString)
+	if err != nil {
+		return err
+	}
+
+	claims := token.Claims.(*CommonClaims)
+	return l.repository.CreateRevokedToken(context.Background(), claims.ID, claims.ExpiresAt.Time)
+}
+
+// VerifyToken checks the token's signature, expiry and issuer, and that it has not been revoked.
+func (l LocalProvider) VerifyToken(ctx context.Context, tokenString string) (*jwt.Token, error) {
+	token, err := l.parseToken(tokenString)
+	if err != nil {
+		log.Auth.Errorf("Failed to verify token; %s", err.Error())
+		return nil, err
+	}
+
+	claims := token.Claims.(*CommonClaims)
+	if claims.Data == constants.EmptyString {
+		return nil, &customErrors.RequestorDataError{Err: errors.New("Incorrect Token Payload From Requestor"), Msg: "token CommonClaims Data is missing/empty"}
+	}
+
+	revoked, err := l.repository.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, &customErrors.InternalParsingError{Err: err, Msg: "failed to check token revocation"}
+	}
+	if revoked {
+		return nil, &customErrors.ExpiredTokenError{Err: errors.New("Revoked Token"), Msg: "the provided token has been revoked"}
+	}
+
+	return token, nil
+}
+
+func (l LocalProvider) parseToken(tokenString string) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &CommonClaims{}, func(t *jwt.Token) (interface{}, error) {
+		return &l.signingKey.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}), jwt.WithIssuer(constants.IssuerLocalAuth), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, &customErrors.ExpiredTokenError{Err: err, Msg: "the provided token has expired"}
+		}
+		return nil, errors.Wrap(err, "invalid token")
+	}
+	return token, nil
+}
+
+func (l LocalProvider) GetVersion() (string, error) {
+	return constants.Version, nil
+}
+
+func (l LocalProvider) getAuthDataFromClaims(claims *CommonClaims) (AuthData, error) {
+	return authDataFromClaims(l.repository, claims)
+}
+
+func (l LocalProvider) GetAuthData(clientID string) (AuthData, error) {
+	ctx := context.Background()
+	system, err := l.repository.GetAuthSystemByClientID(ctx, clientID)
+	if err != nil {
+		return AuthData{}, err
+	}
+
+	aco, err := l.repository.GetACOByUUID(ctx, system.ACOID)
+	if err != nil {
+		return AuthData{}, err
+	}
+	if aco.CMSID == nil {
+		return AuthData{}, fmt.Errorf("ACO %s has no CMS ID", aco.UUID)
+	}
+
+	ad := AuthData{
+		ACOID:               aco.UUID.String(),
+		CMSID:               *aco.CMSID,
+		ClientID:            clientID,
+		CredentialsExpireAt: system.SecretExpiresAt,
+	}
+	return applyCredentialRotation(l.repository, ad), nil
+}
+
+func (l LocalProvider) GetAuthDataFromCertificate(cert *x509.Certificate) (AuthData, error) {
+	return authDataFromCertificate(l.repository, cert)
+}
+
+// newClientSecret returns a random client secret along with the bcrypt hash that is stored in its place.
+func newClientSecret() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret := hex.EncodeToString(b)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, string(hash), nil
+}
+
+func localSecretExpiry() time.Time {
+	return time.Now().Add(time.Duration(utils.GetEnvInt("BCDA_LOCAL_AUTH_SECRET_TTL_DAYS", 90)) * 24 * time.Hour)
+}
+
+func localUnauthorized(err error) error {
+	return &customErrors.SSASErrorUnauthorized{Err: err, Msg: "invalid client credentials", SsasStatusCode: http.StatusUnauthorized}
+}
+
+// requestIP returns the IP of the client connected to our load balancer. The load balancer appends that IP to
+// X-Forwarded-For, so only the right-most entry is trusted; anything to the left of it came from the client and
+// may be spoofed. Requests that didn't pass through the load balancer use the connection's remote address.
+func requestIP(r *http.Request) string {
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		entries := strings.Split(forwarded[len(forwarded)-1], ",")
+		if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
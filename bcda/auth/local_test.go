@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	customErrors "github.com/CMSgov/bcda-app/bcda/errors"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+func newTestLocalProvider(t *testing.T) (LocalProvider, *models.MockRepository) {
+	conf.UnsetEnv(t, "BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE")
+	repository := models.NewMockRepository(t)
+	p, err := NewLocalProvider(repository)
+	require.NoError(t, err)
+	return p, repository
+}
+
+func newTestAuthSystem(t *testing.T) (*models.AuthSystem, *models.ACO, string) {
+	secret, hash, err := newClientSecret()
+	require.NoError(t, err)
+
+	cmsID := "A9994"
+	aco := &models.ACO{UUID: uuid.NewRandom(), CMSID: &cmsID, Name: "ACO Dev"}
+	system := &models.AuthSystem{ID: 42, ACOID: aco.UUID, ClientID: uuid.New(), ClientName: aco.Name,
+		SecretHash: hash, SecretExpiresAt: time.Now().Add(time.Hour)}
+	return system, aco, secret
+}
+
+// setProviderName sets BCDA_AUTH_PROVIDER and restores the configured value after the test
+func setProviderName(t *testing.T, name string) {
+	orig := conf.GetEnv("BCDA_AUTH_PROVIDER")
+	t.Cleanup(func() { conf.SetEnv(t, "BCDA_AUTH_PROVIDER", orig) })
+	conf.SetEnv(t, "BCDA_AUTH_PROVIDER", name)
+}
+
+func TestGetProviderName(t *testing.T) {
+	setProviderName(t, "")
+	assert.Equal(t, SSAS, GetProviderName())
+
+	setProviderName(t, LOCAL)
+	assert.Equal(t, LOCAL, GetProviderName())
+}
+
+func TestNewLocalProviderKeyFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "signing.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(keyPath, keyPEM, 0600))
+
+	conf.SetEnv(t, "BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE", keyPath)
+	defer conf.UnsetEnv(t, "BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE")
+	p, err := NewLocalProvider(models.NewMockRepository(t))
+	assert.NoError(t, err)
+	assert.True(t, key.Equal(p.signingKey))
+
+	conf.SetEnv(t, "BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE", filepath.Join(t.TempDir(), "missing.pem"))
+	_, err = NewLocalProvider(models.NewMockRepository(t))
+	assert.ErrorContains(t, err, "failed to read token signing key")
+}
+
+func TestLocalProviderRegisterSystem(t *testing.T) {
+	p, repository := newTestLocalProvider(t)
+	_, aco, _ := newTestAuthSystem(t)
+
+	var created models.AuthSystem
+	repository.On("GetACOByUUID", mock.Anything, aco.UUID).Return(aco, nil)
+	repository.On("CreateAuthSystem", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(models.AuthSystem)
+	}).Return(uint(7), nil)
+	repository.On("UpdateACO", mock.Anything, aco.UUID, mock.Anything).Return(nil)
+
+	creds, err := p.RegisterSystem(aco.UUID.String(), "", "", "127.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "7", creds.SystemID)
+	assert.Equal(t, created.ClientID, creds.ClientID)
+	assert.Equal(t, []string{"127.0.0.1"}, created.IPs)
+	assert.NotEqual(t, creds.ClientSecret, created.SecretHash, "only the secret hash should be stored")
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.SecretHash), []byte(creds.ClientSecret)))
+	repository.AssertCalled(t, "UpdateACO", mock.Anything, aco.UUID,
+		map[string]interface{}{"client_id": creds.ClientID, "system_id": "7"})
+}
+
+func TestLocalProviderTokenLifecycle(t *testing.T) {
+	p, repository := newTestLocalProvider(t)
+	system, aco, secret := newTestAuthSystem(t)
+	repository.On("GetAuthSystemByClientID", mock.Anything, system.ClientID).Return(system, nil)
+	repository.On("GetACOByUUID", mock.Anything, aco.UUID).Return(aco, nil)
+
+	resp, err := p.MakeAccessToken(Credentials{ClientID: system.ClientID, ClientSecret: secret}, httptest.NewRequest("POST", "/auth/token", nil))
+	require.NoError(t, err)
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal([]byte(resp), &body))
+	assert.Equal(t, "bearer", body["token_type"])
+	assert.Equal(t, "1200", body["expires_in"])
+
+	repository.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil).Once()
+	token, err := p.VerifyToken(t.Context(), body["access_token"])
+	require.NoError(t, err)
+	claims := token.Claims.(*CommonClaims)
+	assert.Equal(t, constants.IssuerLocalAuth, claims.Issuer)
+	assert.Equal(t, aco.UUID.String(), claims.ACOID)
+	assert.Equal(t, "42", claims.SystemID)
+
+	repository.On("GetACOByCMSID", mock.Anything, "A9994").Return(aco, nil)
+	ad, err := p.getAuthDataFromClaims(claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "A9994", ad.CMSID)
+
+	repository.On("CreateRevokedToken", mock.Anything, claims.ID, claims.ExpiresAt.Time).Return(nil)
+	assert.NoError(t, p.RevokeAccessToken(body["access_token"]))
+
+	repository.On("IsTokenRevoked", mock.Anything, claims.ID).Return(true, nil).Once()
+	_, err = p.VerifyToken(t.Context(), body["access_token"])
+	assert.IsType(t, &customErrors.ExpiredTokenError{}, err)
+}
+
+func TestLocalProviderMakeAccessTokenUnauthorized(t *testing.T) {
+	system, aco, secret := newTestAuthSystem(t)
+	expired := *system
+	expired.SecretExpiresAt = time.Now().Add(-time.Minute)
+	restricted := *system
+	restricted.IPs = []string{"10.0.0.1"}
+
+	tests := []struct {
+		name         string
+		system       *models.AuthSystem
+		secret       string
+		forwardedFor string
+	}{
+		{"WrongSecret", system, "not-the-secret", ""},
+		{"ExpiredSecret", &expired, secret, ""},
+		{"UnregisteredIP", &restricted, secret, ""},
+		{"SpoofedForwardedFor", &restricted, secret, "10.0.0.1, 203.0.113.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, repository := newTestLocalProvider(t)
+			repository.On("GetAuthSystemByClientID", mock.Anything, system.ClientID).Return(tt.system, nil)
+
+			req := httptest.NewRequest("POST", "/auth/token", nil)
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			_, err := p.MakeAccessToken(Credentials{ClientID: system.ClientID, ClientSecret: tt.secret}, req)
+			assert.IsType(t, &customErrors.SSASErrorUnauthorized{}, err)
+			repository.AssertNotCalled(t, "GetACOByUUID", mock.Anything, aco.UUID)
+		})
+	}
+}
+
+func TestLocalProviderMakeAccessTokenForwardedIP(t *testing.T) {
+	p, repository := newTestLocalProvider(t)
+	system, aco, secret := newTestAuthSystem(t)
+	system.IPs = []string{"10.0.0.1"}
+	repository.On("GetAuthSystemByClientID", mock.Anything, system.ClientID).Return(system, nil)
+	repository.On("GetACOByUUID", mock.Anything, aco.UUID).Return(aco, nil)
+
+	req := httptest.NewRequest("POST", "/auth/token", nil)
+	req.Header.Set("X-Forwarded-For", "203.0.113.9, 10.0.0.1")
+	_, err := p.MakeAccessToken(Credentials{ClientID: system.ClientID, ClientSecret: secret}, req)
+	assert.NoError(t, err)
+}
+
+func TestLocalProviderMissingCMSID(t *testing.T) {
+	p, repository := newTestLocalProvider(t)
+	system, aco, secret := newTestAuthSystem(t)
+	aco.CMSID = nil
+	repository.On("GetAuthSystemByClientID", mock.Anything, system.ClientID).Return(system, nil)
+	repository.On("GetACOByUUID", mock.Anything, aco.UUID).Return(aco, nil)
+
+	_, err := p.MakeAccessToken(Credentials{ClientID: system.ClientID, ClientSecret: secret}, httptest.NewRequest("POST", "/auth/token", nil))
+	assert.IsType(t, &customErrors.InternalParsingError{}, err)
+
+	_, err = p.GetAuthData(system.ClientID)
+	assert.ErrorContains(t, err, "has no CMS ID")
+}
+
+func TestNewProviderLocalSetupError(t *testing.T) {
+	setProviderName(t, LOCAL)
+	conf.SetEnv(t, "BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE", filepath.Join(t.TempDir(), "missing.pem"))
+	defer conf.UnsetEnv(t, "BCDA_LOCAL_AUTH_PRIVATE_KEY_FILE")
+	_, err := NewProvider(nil)
+	assert.ErrorContains(t, err, "failed to set up local auth provider")
+}
+
+func TestLocalProviderVerifyTokenInvalid(t *testing.T) {
+	p, _ := newTestLocalProvider(t)
+	other, _ := newTestLocalProvider(t)
+
+	sign := func(p LocalProvider, claims CommonClaims) string {
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(p.signingKey)
+		require.NoError(t, err)
+		return tokenString
+	}
+	valid := jwt.RegisteredClaims{Issuer: constants.IssuerLocalAuth, ID: uuid.New(), ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+
+	expiredClaims := valid
+	expiredClaims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	_, err := p.VerifyToken(t.Context(), sign(p, CommonClaims{Data: "{}", RegisteredClaims: expiredClaims}))
+	assert.IsType(t, &customErrors.ExpiredTokenError{}, err)
+
+	_, err = p.VerifyToken(t.Context(), sign(other, CommonClaims{Data: "{}", RegisteredClaims: valid}))
+	assert.ErrorContains(t, err, "invalid token")
+
+	wrongIssuer := valid
+	wrongIssuer.Issuer = "ssas"
+	_, err = p.VerifyToken(t.Context(), sign(p, CommonClaims{Data: "{}", RegisteredClaims: wrongIssuer}))
+	assert.ErrorContains(t, err, "invalid token")
+
+	_, err = p.VerifyToken(t.Context(), sign(p, CommonClaims{RegisteredClaims: valid}))
+	assert.IsType(t, &customErrors.RequestorDataError{}, err)
+}
+
+func TestRequestIP(t *testing.T) {
+	req := httptest.NewRequest("POST", "/auth/token", nil)
+	req.RemoteAddr = "192.0.2.10:4321"
+	assert.Equal(t, "192.0.2.10", requestIP(req))
+
+	// Only the right-most entry is appended by the load balancer; anything before it is client supplied
+	req.Header.Set("X-Forwarded-For", "198.51.100.7, 10.0.0.2")
+	assert.Equal(t, "10.0.0.2", requestIP(req))
+
+	req.Header.Add("X-Forwarded-For", "203.0.113.4")
+	assert.Equal(t, "203.0.113.4", requestIP(req))
+}
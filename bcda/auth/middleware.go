@@ -16,6 +16,7 @@ import (
 
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	customErrors "github.com/CMSgov/bcda-app/bcda/errors"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
 	responseutils "github.com/CMSgov/bcda-app/bcda/responseutils"
@@ -30,8 +31,9 @@ type contextKey struct {
 }
 
 var (
-	TokenContextKey    = &contextKey{"token"}
-	AuthDataContextKey = &contextKey{"ad"}
+	TokenContextKey      = &contextKey{"token"}
+	AuthDataContextKey   = &contextKey{"ad"}
+	ClientCertContextKey = &contextKey{"clientCert"}
 )
 
 type AuthMiddleware struct {
@@ -51,19 +53,40 @@ func NewAuthMiddleware(provider Provider) AuthMiddleware {
 func (m AuthMiddleware) ParseToken(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
+		rw := GetRespWriter(r.URL.Path)
+
+		// A client certificate is only present when the listener is configured for mutual TLS. It either
+		// authenticates the request on its own or is checked against a certificate-bound token.
+		cert := VerifiedClientCert(r)
+		if cert != nil {
+			ctx = context.WithValue(ctx, ClientCertContextKey, cert)
+		}
+
 		// ParseToken is called on every request, but not every request has a token
 		// Continue serving if not Auth token is found and let RequireToken throw the error
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			next.ServeHTTP(w, r)
+			if cert != nil {
+				// Certificates are also presented to unauthenticated endpoints, so a certificate that can't be
+				// mapped to an ACO only leaves the request without AuthData for RequireTokenAuth to reject
+				ad, err := m.provider.GetAuthDataFromCertificate(cert)
+				switch {
+				case err == nil:
+					ctx = context.WithValue(ctx, AuthDataContextKey, ad)
+				case errors.Is(err, sql.ErrNoRows):
+					log.Auth.Warn(err)
+				default:
+					log.Auth.Error(err)
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
 
-		rw := GetRespWriter(r.URL.Path)
-
 		authRegexp := regexp.MustCompile(`^Bearer (\S+)$`)
 		authSubmatches := authRegexp.FindStringSubmatch(authHeader)
 		if len(authSubmatches) < 2 {
+			metrics.AuthFailure("invalid_header")
 			ctx, _ = log.WriteErrorWithFields(
 				ctx,
 				fmt.Sprintf("%s: Invalid Authorization header value", responseutils.TokenErr),
@@ -114,6 +137,12 @@ func (m AuthMiddleware) AuthorizeAccess(ctx context.Context, tokenString string)
 		return nil, ad, err
 	}
 
+	if err = checkCertificateBinding(ctx, claims); err != nil {
+		tknEvent.help = fmt.Sprintf("certificate binding check failed in AuthorizeAccess; %s", err.Error())
+		operationFailed(tknEvent)
+		return nil, ad, err
+	}
+
 	operationSucceeded(tknEvent)
 	return token, ad, nil
 }
@@ -122,6 +151,7 @@ func handleTokenVerificationError(ctx context.Context, w http.ResponseWriter, rw
 	if err != nil {
 		switch err.(type) {
 		case *customErrors.ExpiredTokenError:
+			metrics.AuthFailure("expired_token")
 			ctx, _ = log.WriteWarnWithFields(
 				ctx,
 				fmt.Sprintf("%s: Verification error: %+v", responseutils.ExpiredErr, err),
@@ -129,6 +159,7 @@ func handleTokenVerificationError(ctx context.Context, w http.ResponseWriter, rw
 			)
 			rw.OpOutcome(ctx, w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized), responseutils.ExpiredErr)
 		case *customErrors.EntityNotFoundError:
+			metrics.AuthFailure("entity_not_found")
 			ctx, _ = log.WriteWarnWithFields(
 				ctx,
 				fmt.Sprintf("%s: Verification error: %+v", responseutils.UnauthorizedErr, err),
@@ -136,6 +167,7 @@ func handleTokenVerificationError(ctx context.Context, w http.ResponseWriter, rw
 			)
 			rw.OpOutcome(ctx, w, http.StatusForbidden, http.StatusText(http.StatusForbidden), responseutils.UnauthorizedErr)
 		case *customErrors.RequestorDataError:
+			metrics.AuthFailure("requestor_data")
 			ctx, _ = log.WriteWarnWithFields(
 				ctx,
 				fmt.Sprintf("%s: Verification error: %+v", responseutils.RequestErr, err),
@@ -143,6 +175,7 @@ func handleTokenVerificationError(ctx context.Context, w http.ResponseWriter, rw
 			)
 			rw.OpOutcome(ctx, w, http.StatusBadRequest, http.StatusText(http.StatusBadRequest), responseutils.RequestErr)
 		case *customErrors.RequestTimeoutError:
+			metrics.AuthFailure("ssas_timeout")
 			ctx, _ = log.WriteErrorWithFields(
 				ctx,
 				fmt.Sprintf("%s: Verification error: %+v", responseutils.InternalErr, err),
@@ -150,6 +183,7 @@ func handleTokenVerificationError(ctx context.Context, w http.ResponseWriter, rw
 			)
 			rw.Exception(ctx, w, http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable), responseutils.InternalErr)
 		case *customErrors.ConfigError, *customErrors.InternalParsingError, *customErrors.UnexpectedSSASError:
+			metrics.AuthFailure("internal_error")
 			ctx, _ = log.WriteErrorWithFields(
 				ctx,
 				fmt.Sprintf("%s: Verification error: %+v", responseutils.InternalErr, err),
@@ -157,6 +191,7 @@ func handleTokenVerificationError(ctx context.Context, w http.ResponseWriter, rw
 			)
 			rw.Exception(ctx, w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), responseutils.InternalErr)
 		default:
+			metrics.AuthFailure("invalid_token")
 			ctx, _ = log.WriteWarnWithFields(
 				ctx,
 				fmt.Sprintf("%s: Verification error: %+v", responseutils.TokenErr, err),
@@ -167,7 +202,7 @@ func handleTokenVerificationError(ctx context.Context, w http.ResponseWriter, rw
 	}
 }
 
-// Verify that a token was verified and stored in the request context.
+// Verify that a token (or a client certificate mapped to an ACO) was verified and stored in the request context.
 // This depends on ParseToken being called beforehand in the routing middleware.
 func RequireTokenAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -175,7 +210,16 @@ func RequireTokenAuth(next http.Handler) http.Handler {
 		ctx := r.Context()
 
 		token := ctx.Value(TokenContextKey)
+		if token == nil && ctx.Value(ClientCertContextKey) != nil {
+			// The request was authenticated by a client certificate mapped to an ACO in ParseToken
+			if _, ok := ctx.Value(AuthDataContextKey).(AuthData); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
 		if token == nil {
+			metrics.AuthFailure("no_token")
 			ctx, _ = log.WriteWarnWithFields(
 				ctx,
 				fmt.Sprintf("%s: No token found", responseutils.TokenErr),
@@ -209,6 +253,7 @@ func CheckBlacklist(next http.Handler) http.Handler {
 		}
 
 		if ad.Blacklisted {
+			metrics.AuthFailure("denylisted")
 			ctx, _ = log.WriteWarnWithFields(
 				ctx,
 				fmt.Sprintf("%s: ACO %s is denylisted: ", responseutils.UnauthorizedErr, ad.CMSID),
@@ -274,6 +319,7 @@ func (m AuthMiddleware) RequireTokenJobMatch(db *sql.DB) func(next http.Handler)
 
 			// ACO did not create the job
 			if !strings.EqualFold(ad.ACOID, job.ACOID.String()) {
+				metrics.AuthFailure("job_not_owned")
 				log.Auth.Errorf("ACO %s does not have access to job ID %d %s",
 					ad.ACOID, job.ID, job.ACOID)
 				ctx, _ = log.WriteWarnWithFields(
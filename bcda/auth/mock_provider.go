@@ -6,7 +6,9 @@ package auth
 
 import (
 	"context"
+	"crypto/x509"
 	"net/http"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	mock "github.com/stretchr/testify/mock"
@@ -165,6 +167,66 @@ func (_c *MockProvider_GetAuthData_Call) RunAndReturn(run func(clientID string)
 	return _c
 }
 
+// GetAuthDataFromCertificate provides a mock function for the type MockProvider
+func (_mock *MockProvider) GetAuthDataFromCertificate(cert *x509.Certificate) (AuthData, error) {
+	ret := _mock.Called(cert)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthDataFromCertificate")
+	}
+
+	var r0 AuthData
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*x509.Certificate) (AuthData, error)); ok {
+		return returnFunc(cert)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*x509.Certificate) AuthData); ok {
+		r0 = returnFunc(cert)
+	} else {
+		r0 = ret.Get(0).(AuthData)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*x509.Certificate) error); ok {
+		r1 = returnFunc(cert)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockProvider_GetAuthDataFromCertificate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuthDataFromCertificate'
+type MockProvider_GetAuthDataFromCertificate_Call struct {
+	*mock.Call
+}
+
+// GetAuthDataFromCertificate is a helper method to define mock.On call
+//   - cert *x509.Certificate
+func (_e *MockProvider_Expecter) GetAuthDataFromCertificate(cert interface{}) *MockProvider_GetAuthDataFromCertificate_Call {
+	return &MockProvider_GetAuthDataFromCertificate_Call{Call: _e.mock.On("GetAuthDataFromCertificate", cert)}
+}
+
+func (_c *MockProvider_GetAuthDataFromCertificate_Call) Run(run func(cert *x509.Certificate)) *MockProvider_GetAuthDataFromCertificate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *x509.Certificate
+		if args[0] != nil {
+			arg0 = args[0].(*x509.Certificate)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockProvider_GetAuthDataFromCertificate_Call) Return(r0 AuthData, r1 error) *MockProvider_GetAuthDataFromCertificate_Call {
+	_c.Call.Return(r0, r1)
+	return _c
+}
+
+func (_c *MockProvider_GetAuthDataFromCertificate_Call) RunAndReturn(run func(*x509.Certificate) (AuthData, error)) *MockProvider_GetAuthDataFromCertificate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetVersion provides a mock function for the type MockProvider
 func (_mock *MockProvider) GetVersion() (string, error) {
 	ret := _mock.Called()
@@ -533,6 +595,78 @@ func (_c *MockProvider_RevokeSystemCredentials_Call) RunAndReturn(run func(clien
 	return _c
 }
 
+// RotateSecret provides a mock function for the type MockProvider
+func (_mock *MockProvider) RotateSecret(clientID string, gracePeriod time.Duration, ips []string) (Credentials, error) {
+	ret := _mock.Called(clientID, gracePeriod, ips)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateSecret")
+	}
+
+	var r0 Credentials
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, time.Duration, []string) (Credentials, error)); ok {
+		return returnFunc(clientID, gracePeriod, ips)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, time.Duration, []string) Credentials); ok {
+		r0 = returnFunc(clientID, gracePeriod, ips)
+	} else {
+		r0 = ret.Get(0).(Credentials)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, time.Duration, []string) error); ok {
+		r1 = returnFunc(clientID, gracePeriod, ips)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockProvider_RotateSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateSecret'
+type MockProvider_RotateSecret_Call struct {
+	*mock.Call
+}
+
+// RotateSecret is a helper method to define mock.On call
+//   - clientID string
+//   - gracePeriod time.Duration
+//   - ips []string
+func (_e *MockProvider_Expecter) RotateSecret(clientID interface{}, gracePeriod interface{}, ips interface{}) *MockProvider_RotateSecret_Call {
+	return &MockProvider_RotateSecret_Call{Call: _e.mock.On("RotateSecret", clientID, gracePeriod, ips)}
+}
+
+func (_c *MockProvider_RotateSecret_Call) Run(run func(clientID string, gracePeriod time.Duration, ips []string)) *MockProvider_RotateSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 time.Duration
+		if args[1] != nil {
+			arg1 = args[1].(time.Duration)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockProvider_RotateSecret_Call) Return(r0 Credentials, r1 error) *MockProvider_RotateSecret_Call {
+	_c.Call.Return(r0, r1)
+	return _c
+}
+
+func (_c *MockProvider_RotateSecret_Call) RunAndReturn(run func(string, time.Duration, []string) (Credentials, error)) *MockProvider_RotateSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // VerifyToken provides a mock function for the type MockProvider
 func (_mock *MockProvider) VerifyToken(ctx context.Context, tokenString string) (*jwt.Token, error) {
 	ret := _mock.Called(ctx, tokenString)
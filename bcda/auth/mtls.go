@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/CMSgov/bcda-app/bcda/models"
+)
+
+// VerifiedClientCert returns the leaf certificate presented by the client when it was verified against the
+// configured client CA bundle during the TLS handshake, or nil if no verified certificate was presented.
+func VerifiedClientCert(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// CertificateThumbprint is the base64url-encoded SHA-256 hash of the DER encoded certificate, as used in the
+// x5t#S256 confirmation claim of certificate-bound access tokens (RFC 8705).
+func CertificateThumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// certificateSubjects lists the identities that may be mapped to an ACO, most specific first:
+// URI SANs, then DNS SANs, then the subject common name.
+func certificateSubjects(cert *x509.Certificate) []string {
+	var subjects []string
+	for _, u := range cert.URIs {
+		subjects = append(subjects, u.String())
+	}
+	subjects = append(subjects, cert.DNSNames...)
+	if cert.Subject.CommonName != "" {
+		subjects = append(subjects, cert.Subject.CommonName)
+	}
+	return subjects
+}
+
+// checkCertificateBinding verifies that a certificate-bound token is presented over a connection
+// authenticated with the certificate it was issued to. Tokens without a confirmation claim are not bound.
+func checkCertificateBinding(ctx context.Context, claims *CommonClaims) error {
+	if claims.Confirmation == nil || claims.Confirmation.X5tS256 == "" {
+		return nil
+	}
+
+	cert, ok := ctx.Value(ClientCertContextKey).(*x509.Certificate)
+	if !ok {
+		return errors.New("certificate-bound token presented without a client certificate")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(CertificateThumbprint(cert)), []byte(claims.Confirmation.X5tS256)) != 1 {
+		return errors.New("client certificate does not match certificate-bound token")
+	}
+	return nil
+}
+
+// authDataFromCertificate maps a verified client certificate to the ACO registered for the most specific of its
+// identities. The error wraps sql.ErrNoRows when none of them are mapped to an ACO.
+func authDataFromCertificate(r models.Repository, cert *x509.Certificate) (AuthData, error) {
+	var ad AuthData
+	aco, err := r.GetACOByClientCertSubjects(context.Background(), certificateSubjects(cert))
+	if err != nil {
+		return ad, fmt.Errorf("failed to find ACO mapped to client certificate %s: %w", cert.Subject.String(), err)
+	}
+
+	if aco.CMSID != nil {
+		ad.CMSID = *aco.CMSID
+	}
+	ad.ACOID = aco.UUID.String()
+	ad.ClientID = aco.ClientID
+	ad.Blacklisted = aco.Denylisted()
+	return ad, nil
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/models"
+)
+
+func newTestClientCert(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "partner.example.com"},
+		DNSNames:     []string{"api.partner.example.com"},
+		URIs:         []*url.URL{{Scheme: "urn", Opaque: "bcda:aco:A9994"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestCertificateThumbprint(t *testing.T) {
+	cert := newTestClientCert(t)
+	sum := sha256.Sum256(cert.Raw)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), CertificateThumbprint(cert))
+}
+
+func TestCertificateSubjects(t *testing.T) {
+	cert := newTestClientCert(t)
+	assert.Equal(t, []string{"urn:bcda:aco:A9994", "api.partner.example.com", "partner.example.com"}, certificateSubjects(cert))
+}
+
+func TestVerifiedClientCert(t *testing.T) {
+	cert := newTestClientCert(t)
+	req := httptest.NewRequest("GET", "/", nil)
+	assert.Nil(t, VerifiedClientCert(req))
+
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	assert.Nil(t, VerifiedClientCert(req), "unverified certificates should be ignored")
+
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+	assert.Equal(t, cert, VerifiedClientCert(req))
+}
+
+func TestCheckCertificateBinding(t *testing.T) {
+	cert, other := newTestClientCert(t), newTestClientCert(t)
+	bound := &CommonClaims{Confirmation: &Confirmation{X5tS256: CertificateThumbprint(cert)}}
+
+	assert.NoError(t, checkCertificateBinding(context.Background(), &CommonClaims{}))
+	assert.NoError(t, checkCertificateBinding(context.WithValue(context.Background(), ClientCertContextKey, cert), bound))
+	assert.ErrorContains(t, checkCertificateBinding(context.Background(), bound), "without a client certificate")
+	assert.ErrorContains(t, checkCertificateBinding(context.WithValue(context.Background(), ClientCertContextKey, other), bound), "does not match")
+}
+
+func TestAuthDataFromCertificate(t *testing.T) {
+	cert := newTestClientCert(t)
+	cmsID := "A9994"
+	aco := &models.ACO{UUID: uuid.Parse("dbbd1ce1-ae24-435c-807d-ed45953077d3"), CMSID: &cmsID, ClientID: "client-id"}
+	subjects := []string{"urn:bcda:aco:A9994", "api.partner.example.com", "partner.example.com"}
+
+	repo := models.NewMockRepository(t)
+	repo.On("GetACOByClientCertSubjects", mock.Anything, subjects).Return(aco, nil).Once()
+	ad, err := authDataFromCertificate(repo, cert)
+	assert.NoError(t, err)
+	assert.Equal(t, AuthData{ACOID: aco.UUID.String(), CMSID: cmsID, ClientID: "client-id"}, ad)
+
+	repo.On("GetACOByClientCertSubjects", mock.Anything, subjects).Return(nil, fmt.Errorf("no ACO: %w", sql.ErrNoRows)).Once()
+	_, err = authDataFromCertificate(repo, cert)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	dbErr := errors.New("connection refused")
+	repo.On("GetACOByClientCertSubjects", mock.Anything, subjects).Return(nil, dbErr).Once()
+	_, err = authDataFromCertificate(repo, cert)
+	assert.ErrorIs(t, err, dbErr)
+}
+
+func TestParseTokenWithClientCert(t *testing.T) {
+	cert := newTestClientCert(t)
+	ad := AuthData{ACOID: "dbbd1ce1-ae24-435c-807d-ed45953077d3", CMSID: "A9994"}
+
+	tests := []struct {
+		name       string
+		path       string
+		protected  bool
+		providerAD AuthData
+		providerEr error
+		expStatus  int
+		expAD      AuthData
+	}{
+		{"MappedCertificate", "/api/v2/Patient/$export", true, ad, nil, http.StatusOK, ad},
+		{"UnmappedCertificate", "/api/v2/Patient/$export", true, AuthData{}, fmt.Errorf("no ACO: %w", sql.ErrNoRows), http.StatusUnauthorized, AuthData{}},
+		{"LookupFailure", "/api/v2/Patient/$export", true, AuthData{}, errors.New("connection refused"), http.StatusUnauthorized, AuthData{}},
+		{"UnmappedCertificateUnauthenticatedEndpoint", "/_health", false, AuthData{}, fmt.Errorf("no ACO: %w", sql.ErrNoRows), http.StatusOK, AuthData{}},
+		{"LookupFailureUnauthenticatedEndpoint", "/_version", false, AuthData{}, errors.New("connection refused"), http.StatusOK, AuthData{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewMockProvider(t)
+			provider.On("GetAuthDataFromCertificate", cert).Return(tt.providerAD, tt.providerEr)
+			am := NewAuthMiddleware(provider)
+
+			var gotAD AuthData
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAD, _ = r.Context().Value(AuthDataContextKey).(AuthData)
+			})
+			if tt.protected {
+				handler = RequireTokenAuth(handler)
+			}
+			handler = am.ParseToken(handler)
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expStatus, rr.Code)
+			assert.Equal(t, tt.expAD, gotAD)
+		})
+	}
+}
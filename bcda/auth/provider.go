@@ -2,33 +2,56 @@ package auth
 
 import (
 	"context"
+	"crypto/x509"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
 
 	"github.com/CMSgov/bcda-app/bcda/auth/client"
+	customErrors "github.com/CMSgov/bcda-app/bcda/errors"
+	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
+	"github.com/CMSgov/bcda-app/conf"
 	"github.com/CMSgov/bcda-app/log"
 )
 
 const (
-	SSAS = "ssas"
+	SSAS  = "ssas"
+	LOCAL = "local"
 )
 
+// GetProviderName returns the configured auth provider. SSAS is used unless BCDA_AUTH_PROVIDER is set to local.
 func GetProviderName() string {
+	if conf.GetEnv("BCDA_AUTH_PROVIDER") == LOCAL {
+		return LOCAL
+	}
 	return SSAS
 }
 
-func NewProvider(db *sql.DB) Provider {
+// NewProvider returns the configured Provider. An error is returned when the local provider can't load its
+// token signing key, since it would be unable to issue or verify tokens.
+func NewProvider(db *sql.DB) (Provider, error) {
 	r := postgres.NewRepository(db)
+	if GetProviderName() == LOCAL {
+		p, err := NewLocalProvider(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to set up local auth provider")
+		}
+		return p, nil
+	}
+
 	c, err := client.NewSSASClient()
 	if err != nil {
 		log.Auth.Errorf("no client for SSAS. no provider set; %s", err.Error())
 	}
 
-	return SSASPlugin{client: c, repository: r}
+	return SSASPlugin{client: c, repository: r}, nil
 }
 
 type AuthData struct {
@@ -38,6 +61,10 @@ type AuthData struct {
 	SystemID    string
 	CMSID       string
 	Blacklisted bool
+	// CredentialsExpireAt is when the client's credentials stop working, if known
+	CredentialsExpireAt time.Time
+	// CredentialsSuperseded is true when the client's credentials were rotated and are in their grace window
+	CredentialsSuperseded bool
 }
 
 type Credentials struct {
@@ -56,9 +83,15 @@ type CommonClaims struct {
 	Scopes   []string `json:"scp,omitempty"`
 	ACOID    string   `json:"aco,omitempty"`
 	UUID     string   `json:"id,omitempty"`
+	// Confirmation binds the token to a client certificate (RFC 8705)
+	Confirmation *Confirmation `json:"cnf,omitempty"`
 	jwt.RegisteredClaims
 }
 
+type Confirmation struct {
+	X5tS256 string `json:"x5t#S256,omitempty"`
+}
+
 // Provider defines operations performed through an authentication provider.
 type Provider interface {
 	// FindAndCreateACOCredentials takes an ACO ID and calls RegisterSystem, then formats the results
@@ -70,6 +103,10 @@ type Provider interface {
 	// ResetSecret new or replace existing Credentials for the given clientID
 	ResetSecret(clientID string) (Credentials, error)
 
+	// RotateSecret issues new Credentials for the given clientID while the existing Credentials remain valid
+	// for the grace period
+	RotateSecret(clientID string, gracePeriod time.Duration, ips []string) (Credentials, error)
+
 	// RevokeSystemCredentials any existing Credentials for the given clientID
 	RevokeSystemCredentials(clientID string) error
 
@@ -90,4 +127,51 @@ type Provider interface {
 
 	// GetAuthData returns the auth data associated with the given client ID
 	GetAuthData(clientID string) (AuthData, error)
+
+	// GetAuthDataFromCertificate returns the auth data for the ACO mapped to a verified client certificate
+	GetAuthDataFromCertificate(cert *x509.Certificate) (AuthData, error)
+}
+
+// authDataFromClaims extracts the values common to every provider's token claims and loads the ACO they refer to.
+func authDataFromClaims(r models.Repository, claims *CommonClaims) (AuthData, error) {
+	var (
+		ad  AuthData
+		err error
+	)
+
+	ad.SystemID = claims.SystemID
+	ad.ClientID = claims.ClientID
+	ad.TokenID = claims.ID
+
+	if claims.Data == "" {
+		return ad, errors.New("incomplete token")
+	}
+	d := claims.Data
+	if ud, err := strconv.Unquote(claims.Data); err == nil {
+		// unquote will fail when the argument string has no quotes to remove!
+		d = ud
+	}
+	type XData struct {
+		IDList []string `json:"cms_ids"`
+	}
+	var xData XData
+	if err = json.Unmarshal([]byte(d), &xData); err != nil {
+		return ad, fmt.Errorf("can't decode data claim %s; %v", d, err)
+	}
+
+	if len(xData.IDList) != 1 {
+		return ad, fmt.Errorf("expected one id in list; got %v; source %s", xData.IDList, claims.Data)
+	}
+	ad.CMSID = xData.IDList[0]
+
+	var aco *models.ACO
+	if aco, err = r.GetACOByCMSID(context.Background(), ad.CMSID); err != nil {
+		entityNotFoundError := &customErrors.EntityNotFoundError{Err: err, CMSID: ad.CMSID}
+		log.Auth.Errorf(entityNotFoundError.Error())
+		return ad, entityNotFoundError
+	}
+	ad.ACOID = aco.UUID.String()
+	ad.Blacklisted = aco.Denylisted()
+
+	return ad, nil
 }
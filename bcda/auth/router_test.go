@@ -19,11 +19,25 @@ type AuthRouterTestSuite struct {
 	suite.Suite
 	provider   Provider
 	authRouter http.Handler
+	// providerName is the auth provider the suite runs against
+	providerName string
+	origProvider string
+}
+
+func (s *AuthRouterTestSuite) SetupSuite() {
+	s.origProvider = conf.GetEnv("BCDA_AUTH_PROVIDER")
+	conf.SetEnv(s.T(), "BCDA_AUTH_PROVIDER", s.providerName)
+}
+
+func (s *AuthRouterTestSuite) TearDownSuite() {
+	conf.SetEnv(s.T(), "BCDA_AUTH_PROVIDER", s.origProvider)
 }
 
 func (s *AuthRouterTestSuite) SetupTest() {
 	conf.SetEnv(s.T(), "DEBUG", "true")
-	s.provider = NewProvider(database.Connect())
+	var err error
+	s.provider, err = NewProvider(database.Connect())
+	s.Require().NoError(err)
 	s.authRouter = NewAuthRouter(s.provider)
 }
 
@@ -40,5 +54,9 @@ func (s *AuthRouterTestSuite) TestAuthTokenRoute() {
 }
 
 func TestAuthRouterTestSuite(t *testing.T) {
-	suite.Run(t, new(AuthRouterTestSuite))
+	for _, name := range []string{SSAS, LOCAL} {
+		t.Run(name, func(t *testing.T) {
+			suite.Run(t, &AuthRouterTestSuite{providerName: name})
+		})
+	}
 }
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/models/postgres"
+	responseutils "github.com/CMSgov/bcda-app/bcda/responseutils"
+	"github.com/CMSgov/bcda-app/bcda/utils"
+	"github.com/CMSgov/bcda-app/conf"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+const (
+	SignedURLExpiryParam    = "exp"
+	SignedURLSignatureParam = "sig"
+)
+
+// SignedURLsEnabled reports whether job status responses should hand out signed download URLs in place of
+// URLs that require a bearer token. Signing is only enabled when a signing key has also been configured.
+func SignedURLsEnabled() bool {
+	return utils.GetEnvBool("BCDA_SIGNED_DATA_URLS", false) && conf.GetEnv("BCDA_SIGNED_URL_KEY") != ""
+}
+
+// SignedURLTTL is how long a signed download URL remains valid after it is issued.
+func SignedURLTTL() time.Duration {
+	return time.Duration(utils.GetEnvInt("BCDA_SIGNED_URL_TTL_MINUTES", 60)) * time.Minute
+}
+
+// SignDataURL returns the query parameters that grant access to a single data file of a job until expiry.
+func SignDataURL(jobID uint, fileName string, expiry time.Time) url.Values {
+	exp := strconv.FormatInt(expiry.Unix(), 10)
+	v := url.Values{}
+	v.Set(SignedURLExpiryParam, exp)
+	v.Set(SignedURLSignatureParam, dataURLSignature(jobID, fileName, exp))
+	return v
+}
+
+// VerifyDataURLSignature checks that sig was issued for the job, file and expiry and that it has not expired.
+func VerifyDataURLSignature(jobID uint, fileName, exp, sig string, now time.Time) error {
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid signed URL expiry")
+	}
+
+	expected := dataURLSignature(jobID, fileName, exp)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return errors.New("signed URL signature does not match")
+	}
+
+	if now.After(time.Unix(expUnix, 0)) {
+		return errors.New("signed URL has expired")
+	}
+
+	return nil
+}
+
+func dataURLSignature(jobID uint, fileName, exp string) string {
+	mac := hmac.New(sha256.New, []byte(conf.GetEnv("BCDA_SIGNED_URL_KEY")))
+	fmt.Fprintf(mac, "%d\n%s\n%s", jobID, fileName, exp)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// RequireSignedURLOrToken authorizes data file requests. Requests carrying a signature are validated against the
+// job, file and expiry they were signed for; everything else is passed through the tokenAuth middleware.
+func RequireSignedURLOrToken(db *sql.DB, tokenAuth func(http.Handler) http.Handler) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		tokenHandler := tokenAuth(next)
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			sig := query.Get(SignedURLSignatureParam)
+			if sig == "" || !SignedURLsEnabled() {
+				tokenHandler.ServeHTTP(w, r)
+				return
+			}
+
+			rw := GetRespWriter(r.URL.Path)
+			ctx := r.Context()
+
+			jobID, err := strconv.ParseUint(chi.URLParam(r, "jobID"), 10, 64)
+			if err != nil {
+				ctx, _ = log.WriteWarnWithFields(
+					ctx,
+					fmt.Sprintf("%s: Failed to parse jobID: %+v", responseutils.RequestErr, err),
+					logrus.Fields{"resp_status": http.StatusBadRequest},
+				)
+				rw.OpOutcome(log.NewStructuredLoggerEntry(log.Auth, ctx), w, http.StatusBadRequest, responseutils.RequestErr, "")
+				return
+			}
+
+			fileName := chi.URLParam(r, "fileName")
+			err = VerifyDataURLSignature(uint(jobID), fileName, query.Get(SignedURLExpiryParam), sig, time.Now())
+			if err != nil {
+				ctx, _ = log.WriteWarnWithFields(
+					ctx,
+					fmt.Sprintf("%s: Invalid signed URL for job %d: %+v", responseutils.UnauthorizedErr, jobID, err),
+					logrus.Fields{"resp_status": http.StatusUnauthorized},
+				)
+				rw.OpOutcome(log.NewStructuredLoggerEntry(log.Auth, ctx), w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized), responseutils.UnauthorizedErr)
+				return
+			}
+
+			repository := postgres.NewRepository(db)
+
+			job, err := repository.GetJobByID(ctx, uint(jobID))
+			if err != nil {
+				ctx, _ = log.WriteWarnWithFields(
+					ctx,
+					fmt.Sprintf("%s: Job not found, ID: %+v", responseutils.NotFoundErr, jobID),
+					logrus.Fields{"resp_status": http.StatusNotFound},
+				)
+				rw.NotFound(log.NewStructuredLoggerEntry(log.Auth, ctx), w, http.StatusNotFound, responseutils.NotFoundErr, "")
+				return
+			}
+
+			if job.Status == models.JobStatusExpired || job.Status == models.JobStatusArchived {
+				ctx, _ = log.WriteWarnWithFields(
+					ctx,
+					fmt.Sprintf("%s: Job found but expired or archived, ID: %+v", responseutils.JobExpiredErr, jobID),
+					logrus.Fields{"resp_status": http.StatusNotFound},
+				)
+				rw.NotFound(log.NewStructuredLoggerEntry(log.Auth, ctx), w, http.StatusNotFound, responseutils.JobExpiredErr, "")
+				return
+			}
+
+			aco, err := repository.GetACOByUUID(ctx, job.ACOID)
+			if err != nil {
+				ctx, _ = log.WriteWarnWithFields(
+					ctx,
+					fmt.Sprintf("%s: ACO not found for job ID %d: %+v", responseutils.NotFoundErr, jobID, err),
+					logrus.Fields{"resp_status": http.StatusNotFound},
+				)
+				rw.NotFound(log.NewStructuredLoggerEntry(log.Auth, ctx), w, http.StatusNotFound, responseutils.NotFoundErr, "")
+				return
+			}
+
+			ad := AuthData{ACOID: aco.UUID.String(), Blacklisted: aco.Denylisted()}
+			if aco.CMSID != nil {
+				ad.CMSID = *aco.CMSID
+			}
+
+			if ad.Blacklisted {
+				ctx, _ = log.WriteWarnWithFields(
+					ctx,
+					fmt.Sprintf("%s: ACO %s is denylisted: ", responseutils.UnauthorizedErr, ad.CMSID),
+					logrus.Fields{"resp_status": http.StatusForbidden},
+				)
+				rw.OpOutcome(log.NewStructuredLoggerEntry(log.Auth, ctx), w, http.StatusForbidden, responseutils.UnauthorizedErr, fmt.Sprintf("ACO (CMS_ID: %s) is unauthorized", ad.CMSID))
+				return
+			}
+
+			ctx = context.WithValue(ctx, AuthDataContextKey, ad)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
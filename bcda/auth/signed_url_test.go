@@ -0,0 +1,113 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CMSgov/bcda-app/bcda/auth"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+func unsetSignedURLEnv(t *testing.T) {
+	t.Cleanup(func() {
+		_ = conf.UnsetEnv(t, "BCDA_SIGNED_DATA_URLS")
+		_ = conf.UnsetEnv(t, "BCDA_SIGNED_URL_KEY")
+	})
+}
+
+func TestSignedURLsEnabled(t *testing.T) {
+	unsetSignedURLEnv(t)
+	conf.SetEnv(t, "BCDA_SIGNED_DATA_URLS", "true")
+	conf.SetEnv(t, "BCDA_SIGNED_URL_KEY", "")
+	assert.False(t, auth.SignedURLsEnabled(), "signing requires a key")
+
+	conf.SetEnv(t, "BCDA_SIGNED_URL_KEY", "some-key")
+	assert.True(t, auth.SignedURLsEnabled())
+
+	conf.SetEnv(t, "BCDA_SIGNED_DATA_URLS", "false")
+	assert.False(t, auth.SignedURLsEnabled())
+}
+
+func TestVerifyDataURLSignature(t *testing.T) {
+	unsetSignedURLEnv(t)
+	conf.SetEnv(t, "BCDA_SIGNED_URL_KEY", "some-key")
+	now := time.Now()
+	v := auth.SignDataURL(1, "file.ndjson", now.Add(time.Hour))
+	exp, sig := v.Get(auth.SignedURLExpiryParam), v.Get(auth.SignedURLSignatureParam)
+
+	tests := []struct {
+		name     string
+		jobID    uint
+		fileName string
+		exp      string
+		sig      string
+		now      time.Time
+		errMsg   string
+	}{
+		{"Valid", 1, "file.ndjson", exp, sig, now, ""},
+		{"DifferentJob", 2, "file.ndjson", exp, sig, now, "signature does not match"},
+		{"DifferentFile", 1, "other.ndjson", exp, sig, now, "signature does not match"},
+		{"TamperedExpiry", 1, "file.ndjson", "9999999999", sig, now, "signature does not match"},
+		{"InvalidExpiry", 1, "file.ndjson", "abc", sig, now, "invalid signed URL expiry"},
+		{"Expired", 1, "file.ndjson", exp, sig, now.Add(2 * time.Hour), "signed URL has expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := auth.VerifyDataURLSignature(tt.jobID, tt.fileName, tt.exp, tt.sig, tt.now)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+
+	conf.SetEnv(t, "BCDA_SIGNED_URL_KEY", "another-key")
+	assert.ErrorContains(t, auth.VerifyDataURLSignature(1, "file.ndjson", exp, sig, now), "signature does not match")
+}
+
+func TestRequireSignedURLOrToken(t *testing.T) {
+	unsetSignedURLEnv(t)
+	conf.SetEnv(t, "BCDA_SIGNED_DATA_URLS", "true")
+	conf.SetEnv(t, "BCDA_SIGNED_URL_KEY", "some-key")
+
+	tokenAuthCalled := false
+	tokenAuth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenAuthCalled = true
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	r := chi.NewRouter()
+	r.With(auth.RequireSignedURLOrToken(nil, tokenAuth)).Get("/data/{jobID}/{fileName}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Requests without a signature fall back to token auth
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/data/1/file.ndjson", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, tokenAuthCalled)
+
+	// A signature for another file is rejected before token auth or the database is reached
+	tokenAuthCalled = false
+	v := auth.SignDataURL(1, "other.ndjson", time.Now().Add(time.Hour))
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/data/1/file.ndjson?"+v.Encode(), nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, tokenAuthCalled)
+
+	// Signatures are ignored when signed URLs are disabled
+	conf.SetEnv(t, "BCDA_SIGNED_DATA_URLS", "false")
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/data/1/file.ndjson?"+v.Encode(), nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, tokenAuthCalled)
+}
@@ -2,11 +2,12 @@ package auth
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/pborman/uuid"
@@ -113,6 +114,12 @@ func (s SSASPlugin) ResetSecret(clientID string) (Credentials, error) {
 	return creds, nil
 }
 
+// RotateSecret registers a new system for the ACO that owns clientID and records the rotation. The previous
+// system's credentials are left in place so clients can switch over before the grace period ends.
+func (s SSASPlugin) RotateSecret(clientID string, gracePeriod time.Duration, ips []string) (Credentials, error) {
+	return rotateSecret(s.repository, s, clientID, gracePeriod, ips)
+}
+
 // RevokeSystemCredentials revokes any existing credentials for the given clientID.
 func (s SSASPlugin) RevokeSystemCredentials(ssasID string) error {
 	return s.client.DeleteCredentials(ssasID)
@@ -142,46 +149,7 @@ func (s SSASPlugin) RevokeAccessToken(tokenString string) error {
 
 // Extract available values from SSAS token claims.
 func (s SSASPlugin) getAuthDataFromClaims(claims *CommonClaims) (AuthData, error) {
-	var (
-		ad  AuthData
-		err error
-	)
-
-	ad.SystemID = claims.SystemID
-	ad.ClientID = claims.ClientID
-	ad.TokenID = claims.ID
-
-	if claims.Data == "" {
-		return ad, errors.New("incomplete ssas token")
-	}
-	d := claims.Data
-	if ud, err := strconv.Unquote(claims.Data); err == nil {
-		// unquote will fail when the argument string has no quotes to remove!
-		d = ud
-	}
-	type XData struct {
-		IDList []string `json:"cms_ids"`
-	}
-	var xData XData
-	if err = json.Unmarshal([]byte(d), &xData); err != nil {
-		return ad, fmt.Errorf("can't decode data claim %s; %v", d, err)
-	}
-
-	if len(xData.IDList) != 1 {
-		return ad, fmt.Errorf("expected one id in list; got %v; source %s", xData.IDList, claims.Data)
-	}
-	ad.CMSID = xData.IDList[0]
-
-	var aco *models.ACO
-	if aco, err = s.repository.GetACOByCMSID(context.Background(), ad.CMSID); err != nil {
-		entityNotFoundError := &customErrors.EntityNotFoundError{Err: err, CMSID: ad.CMSID}
-		log.SSAS.Errorf(entityNotFoundError.Error())
-		return ad, entityNotFoundError
-	}
-	ad.ACOID = aco.UUID.String()
-	ad.Blacklisted = aco.Denylisted()
-
-	return ad, nil
+	return authDataFromClaims(s.repository, claims)
 }
 
 // VerifyToken decodes a base64-encoded token string into a structured token,
@@ -270,8 +238,14 @@ func (s SSASPlugin) GetAuthData(clientID string) (AuthData, error) {
 	if err != nil {
 		return AuthData{}, err
 	}
-	return AuthData{
+	ad := AuthData{
 		CMSID:    cmsID,
 		ClientID: clientID,
-	}, nil
+	}
+
+	return applyCredentialRotation(s.repository, ad), nil
+}
+
+func (s SSASPlugin) GetAuthDataFromCertificate(cert *x509.Certificate) (AuthData, error) {
+	return authDataFromCertificate(s.repository, cert)
 }
@@ -38,7 +38,9 @@ type SSASMiddlewareTestSuite struct {
 
 func (s *SSASMiddlewareTestSuite) createRouter() http.Handler {
 	router := chi.NewRouter()
-	am := auth.NewAuthMiddleware(auth.NewProvider(database.Connect()))
+	p, err := auth.NewProvider(database.Connect())
+	s.Require().NoError(err)
+	am := auth.NewAuthMiddleware(p)
 	router.Use(am.ParseToken)
 	router.With(auth.RequireTokenAuth).Get("/v1/", func(w http.ResponseWriter, r *http.Request) {
 		ad := r.Context().Value(auth.AuthDataContextKey).(auth.AuthData)
@@ -217,6 +217,40 @@ func (s *SSASPluginTestSuite) TestResetSecret() {
 	assert.Equal(s.T(), constants.FakeSecret, creds.ClientSecret)
 }
 
+func (s *SSASPluginTestSuite) TestRotateSecret() {
+	router := chi.NewRouter()
+	router.Post("/system", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(201)
+		fmt.Fprintf(w, `{"system_id": "2", "client_id": "%s", "client_secret": "%s", "client_name": "Rotated"}`, constants.FakeClientID, constants.FakeSecret)
+	})
+	server := httptest.NewServer(router)
+
+	conf.SetEnv(s.T(), "SSAS_URL", server.URL)
+	conf.SetEnv(s.T(), "SSAS_PUBLIC_URL", server.URL)
+	conf.SetEnv(s.T(), "SSAS_USE_TLS", "false")
+
+	c, err := client.NewSSASClient()
+	require.NotNil(s.T(), c, sSasClientErrorMsg, err)
+
+	aco := &models.ACO{UUID: uuid.Parse(testACOUUID), ClientID: "previous-client", SystemID: "1", GroupID: "group"}
+	mock := &models.MockRepository{}
+	mock.On("GetACOByClientID", m.Anything, aco.ClientID).Return(aco, nil)
+	mock.On("GetACOByUUID", m.Anything, m.Anything).Return(aco, nil)
+	mock.On("UpdateACO", m.Anything, aco.UUID, m.Anything).Return(nil)
+	mock.On("CreateCredentialRotation", m.Anything, m.MatchedBy(func(r models.CredentialRotation) bool {
+		return r.PreviousClientID == "previous-client" && r.PreviousSystemID == "1" &&
+			r.ClientID == constants.FakeClientID && r.SystemID == "2" &&
+			r.GraceExpiresAt.After(time.Now().Add(47*time.Hour))
+	})).Return(nil)
+	s.p = SSASPlugin{client: c, repository: mock}
+
+	creds, err := s.p.RotateSecret("previous-client", 48*time.Hour, nil)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), constants.FakeClientID, creds.ClientID)
+	assert.Equal(s.T(), constants.FakeSecret, creds.ClientSecret)
+	mock.AssertExpectations(s.T())
+}
+
 func (s *SSASPluginTestSuite) TestRevokeSystemCredentials() {
 	// TestRevokeSystemCredentials is a test script to showcase the ability to revoke client ids/credentials
 }
@@ -287,7 +321,9 @@ func (s *SSASPluginTestSuite) TestRevokeAccessToken() {
 }
 
 func (s *SSASPluginTestSuite) TestAuthorizeAccessErrIsNilWhenHappyPath() {
-	am := NewAuthMiddleware(NewProvider(s.db))
+	p, err := NewProvider(s.db)
+	require.NoError(s.T(), err)
+	am := NewAuthMiddleware(p)
 	_, tokenString, _, err := MockSSASToken()
 	require.NotNil(s.T(), tokenString, sSasTErrorMsg, err)
 	require.Nil(s.T(), err, unexpectedErrorMsg, err)
@@ -310,7 +346,9 @@ func (s *SSASPluginTestSuite) TestAuthorizeAccessErrISReturnedWhenVerifyTokenChe
 	require.NotNil(s.T(), c, sSasClientErrorMsg, err)
 	s.p = SSASPlugin{client: c, repository: s.r}
 
-	am := NewAuthMiddleware(NewProvider(s.db))
+	p, err := NewProvider(s.db)
+	require.NoError(s.T(), err)
+	am := NewAuthMiddleware(p)
 
 	invalidTokenString := ""
 	_, _, err = am.AuthorizeAccess(context.Background(), invalidTokenString)
@@ -380,7 +418,9 @@ func (s *SSASPluginTestSuite) TestAuthorizeAccessErrIsReturnedWhenGetAuthDataFro
 
 	MockSSASServer(ts)
 
-	am := NewAuthMiddleware(NewProvider(s.db))
+	p, err := NewProvider(s.db)
+	require.NoError(s.T(), err)
+	am := NewAuthMiddleware(p)
 
 	c, err := client.NewSSASClient()
 	require.NotNil(s.T(), c, sSasClientErrorMsg, err)
@@ -463,7 +503,7 @@ func (s *SSASPluginTestSuite) TestgetAuthDataFromClaimsReturnErrorWhenCommonClai
 		commonClaims    CommonClaims
 		expectedMessage string
 	}{
-		{"CommonClaims Data has no quotes to remove (incomplete ssas token)", createCommonClaimsForTesting("", constants.IssuerSSAS), "incomplete ssas token"},
+		{"CommonClaims Data has no quotes to remove (incomplete token)", createCommonClaimsForTesting("", constants.IssuerSSAS), "incomplete token"},
 		{"CommonClaims Data is unable to be decoded", createCommonClaimsForTesting("abcdefg", constants.IssuerSSAS), "can't decode data claim abcdefg; invalid character 'a' looking for beginning of value"},
 	}
 
@@ -511,6 +551,7 @@ func (s *SSASPluginTestSuite) TestGetAuthDataSuccess() {
 
 	mock := &models.MockRepository{}
 	mock.On("GetCMSIDByClientID", m.MatchedBy(func(req context.Context) bool { return true }), clientID).Return(cmsID, nil)
+	mock.On("GetCredentialRotationByClientID", m.Anything, clientID).Return(nil, fmt.Errorf("no rotation: %w", sql.ErrNoRows))
 
 	c, err := client.NewSSASClient()
 	require.NotNil(s.T(), c, sSasClientErrorMsg, err)
@@ -520,6 +561,35 @@ func (s *SSASPluginTestSuite) TestGetAuthDataSuccess() {
 	require.Nil(s.T(), err)
 	assert.Equal(s.T(), cmsID, ad.CMSID)
 	assert.Equal(s.T(), clientID, ad.ClientID)
+	assert.True(s.T(), ad.CredentialsExpireAt.IsZero())
+	assert.False(s.T(), ad.CredentialsSuperseded)
+}
+
+func (s *SSASPluginTestSuite) TestGetAuthDataRotatedCredentials() {
+	previousClientID, clientID := uuid.New(), uuid.New()
+	cmsID := testUtils.RandomHexID()[0:4]
+	graceExpiresAt := time.Now().Add(time.Hour)
+	secretExpiresAt := time.Now().Add(90 * 24 * time.Hour)
+	rotation := &models.CredentialRotation{PreviousClientID: previousClientID, ClientID: clientID,
+		GraceExpiresAt: graceExpiresAt, SecretExpiresAt: &secretExpiresAt}
+
+	mock := &models.MockRepository{}
+	mock.On("GetCMSIDByClientID", m.Anything, m.Anything).Return(cmsID, nil)
+	mock.On("GetCredentialRotationByClientID", m.Anything, m.Anything).Return(rotation, nil)
+
+	c, err := client.NewSSASClient()
+	require.NotNil(s.T(), c, sSasClientErrorMsg, err)
+	s.p = SSASPlugin{client: c, repository: mock}
+
+	ad, err := s.p.GetAuthData(previousClientID)
+	require.Nil(s.T(), err)
+	assert.True(s.T(), ad.CredentialsSuperseded)
+	assert.Equal(s.T(), graceExpiresAt, ad.CredentialsExpireAt)
+
+	ad, err = s.p.GetAuthData(clientID)
+	require.Nil(s.T(), err)
+	assert.False(s.T(), ad.CredentialsSuperseded)
+	assert.Equal(s.T(), secretExpiresAt, ad.CredentialsExpireAt)
 }
 
 func (s *SSASPluginTestSuite) TestGetAuthDataError() {
@@ -20,13 +20,18 @@ import (
 	"github.com/CMSgov/bcda-app/bcda/auth"
 	authclient "github.com/CMSgov/bcda-app/bcda/auth/client"
 
+	ai "github.com/CMSgov/bcda-app/bcda/attribution-import"
 	cclfUtils "github.com/CMSgov/bcda-app/bcda/attribution-import/utils"
+	bp "github.com/CMSgov/bcda-app/bcda/bene-prefs"
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	"github.com/CMSgov/bcda-app/bcda/database"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
 	"github.com/CMSgov/bcda-app/bcda/service"
 	"github.com/CMSgov/bcda-app/bcda/servicemux"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
+	"github.com/CMSgov/bcda-app/bcda/usagereport"
 	"github.com/CMSgov/bcda-app/bcda/utils"
 	"github.com/CMSgov/bcda-app/bcda/web"
 	"github.com/CMSgov/bcda-app/log"
@@ -34,6 +39,7 @@ import (
 	pgxv5Pool "github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
 	"github.com/DataDog/dd-trace-go/v2/profiler"
@@ -64,12 +70,19 @@ func setUpApp() *cli.App {
 		db = database.Connect()
 		pool = database.ConnectPool()
 		repository = postgres.NewRepository(db)
-		provider = auth.NewProvider(db)
+		var err error
+		if provider, err = auth.NewProvider(db); err != nil {
+			return err
+		}
 		log.API.Info(fmt.Sprintf(`Auth is made possible by %T`, provider))
 		return nil
 	}
-	var acoName, acoCMSID, acoID, accessToken, acoSize, filePath, environment, groupID, groupName, ips, fileType string
-	var httpPort, httpsPort int
+	var acoName, acoCMSID, acoID, accessToken, acoSize, filePath, directory, environment, groupID, groupName, ips, fileType string
+	var fileStatus, statusReason, operator string
+	var reportStart, reportEnd, reportFormat string
+	var httpPort, httpsPort, gracePeriodHours int
+	var fileID uint
+	var revokeExpired bool
 	app.Commands = []cli.Command{
 		{
 			Name:  "start-api",
@@ -100,6 +113,17 @@ func setUpApp() *cli.App {
 				}
 				defer profiler.Stop()
 
+				shutdownTracing, err := tracing.Init(context.Background(), "api")
+				if err != nil {
+					log.API.Warn(err)
+				} else {
+					defer func() {
+						if err := shutdownTracing(context.Background()); err != nil {
+							log.API.Warn(err)
+						}
+					}()
+				}
+
 				var httpAddr, httpsAddr string
 				if httpPort != 0 {
 					httpAddr = fmt.Sprintf(":%d", httpPort)
@@ -150,6 +174,14 @@ func setUpApp() *cli.App {
 				smux := servicemux.New(httpsAddr)
 				smux.AddServer(fileserver, "/data")
 				smux.AddServer(auth, "/auth")
+				if utils.GetEnvBool("METRICS_ENDPOINT_ACTIVE", false) {
+					smux.AddServer(&http.Server{
+						Handler:           metrics.Handler(),
+						ReadTimeout:       5 * time.Second,
+						WriteTimeout:      10 * time.Second,
+						ReadHeaderTimeout: 2 * time.Second,
+					}, "/metrics")
+				}
 				smux.AddServer(api, "")
 				smux.Serve()
 
@@ -284,6 +316,57 @@ func setUpApp() *cli.App {
 				return nil
 			},
 		},
+		{
+			Name:     "rotate-client-credentials",
+			Category: constants.CliAuthToolsCategory,
+			Usage:    "Issue new credentials for a client specified by ACO CMS ID, keeping the current credentials valid for a grace period",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        constants.CliCMSIDArg,
+					Usage:       constants.CliCMSIDDesc,
+					Destination: &acoCMSID,
+				},
+				cli.StringFlag{
+					Name:        "ips",
+					Usage:       "Comma separated list of IPs associated with the ACO",
+					Destination: &ips,
+				},
+				cli.IntFlag{
+					Name:        "grace-period-hours",
+					Value:       72,
+					Usage:       "Number of hours the current credentials remain valid after rotation",
+					Destination: &gracePeriodHours,
+				},
+				cli.BoolFlag{
+					Name:        "revoke-expired",
+					Usage:       "Revoke previous credentials whose grace period has ended instead of rotating",
+					Destination: &revokeExpired,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if revokeExpired {
+					count, err := revokeExpiredCredentials(repository, provider, time.Now())
+					if err != nil {
+						return err
+					}
+					fmt.Fprintf(app.Writer, "Revoked %d expired credential(s)\n", count)
+					return nil
+				}
+				if acoCMSID == "" {
+					return errors.New("ACO CMS ID (--cms-id) is required")
+				}
+				var ipAddr []string
+				if len(ips) > 0 {
+					ipAddr = strings.Split(ips, ",")
+				}
+				msg, err := rotateClientCredentials(repository, provider, acoCMSID, time.Duration(gracePeriodHours)*time.Hour, ipAddr)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(app.Writer, "%s\n", msg)
+				return nil
+			},
+		},
 		{
 			Name:     "generate-cclf-runout-files",
 			Category: constants.CliDataImpCategory,
@@ -305,6 +388,98 @@ func setUpApp() *cli.App {
 				return nil
 			},
 		},
+		{
+			Name:     "validate-attribution",
+			Category: constants.CliDataImpCategory,
+			Usage:    "Validate a CSV attribution file or a directory of CCLF archives without importing them",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "file",
+					Usage:       "Path to a CSV attribution file",
+					Destination: &filePath,
+				},
+				cli.StringFlag{
+					Name:        "directory",
+					Usage:       "Directory where CCLF archives are located",
+					Destination: &directory,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return validateAttribution(context.Background(), app.Writer, filePath, directory)
+			},
+		},
+		{
+			Name:     "update-attribution-status",
+			Category: constants.CliDataImpCategory,
+			Usage:    "Withdraw an imported attribution file by marking it superseded or invalid, or restore it by marking it completed",
+			Flags: []cli.Flag{
+				cli.UintFlag{
+					Name:        "file-id",
+					Usage:       "ID of the attribution file in cclf_files",
+					Destination: &fileID,
+				},
+				cli.StringFlag{
+					Name:        "status",
+					Usage:       "New status for the file. Must be one of 'superseded', 'invalid', 'completed'",
+					Destination: &fileStatus,
+				},
+				cli.StringFlag{
+					Name:        "reason",
+					Usage:       "Why the status is being changed",
+					Destination: &statusReason,
+				},
+				cli.StringFlag{
+					Name:        "operator",
+					Usage:       "Name of the person making the change",
+					Destination: &operator,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				change, err := ai.UpdateFileStatus(context.Background(), repository, fileID, fileStatus, statusReason, operator)
+				if err != nil {
+					return err
+				}
+				log.API.WithFields(logrus.Fields{
+					"file_id":         change.FileID,
+					"previous_status": change.PreviousStatus,
+					"status":          change.Status,
+					"reason":          change.Reason,
+					"changed_by":      change.ChangedBy,
+				}).Info("Updated attribution file status")
+				fmt.Fprintf(app.Writer, "Attribution file %d changed from %s to %s\n", change.FileID, change.PreviousStatus, change.Status)
+				return nil
+			},
+		},
+		{
+			Name:     "reprocess-bene-prefs",
+			Category: constants.CliDataImpCategory,
+			Usage:    "Import a corrected bene-prefs file, replacing any earlier import of it. Files already imported with the same contents are skipped",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "file",
+					Usage:       "Path to the bene-prefs file",
+					Destination: &filePath,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				importer := bp.BenePrefsImporter{
+					FileHandler:          &bp.LocalFileHandler{Logger: log.API},
+					Repo:                 repository,
+					Logger:               log.API,
+					ImportStatusInterval: utils.GetEnvInt("SUPPRESS_IMPORT_STATUS_RECORDS_INTERVAL", 1000),
+				}
+				imported, err := importer.ReprocessFile(context.Background(), filePath)
+				if err != nil {
+					return err
+				}
+				if imported {
+					fmt.Fprintf(app.Writer, "Imported bene-prefs file %s\n", filePath)
+				} else {
+					fmt.Fprintf(app.Writer, "Bene-prefs file %s was already imported with the same contents\n", filePath)
+				}
+				return nil
+			},
+		},
 		{
 			Name:     "import-synthetic-cclf-package",
 			Category: constants.CliDataImpCategory,
@@ -375,10 +550,57 @@ func setUpApp() *cli.App {
 				return setDenylistState(repository, acoCMSID, nil)
 			},
 		},
+		{
+			Name:     "usage-report",
+			Category: constants.CliReportingCategory,
+			Usage:    "Report the exports each ACO ran, the resource types they pulled, their downloads and failure rate. Defaults to last month",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "start",
+					Usage:       "First day of the report (YYYY-MM-DD, UTC)",
+					Destination: &reportStart,
+				},
+				cli.StringFlag{
+					Name:        "end",
+					Usage:       "Last day of the report, inclusive (YYYY-MM-DD, UTC)",
+					Destination: &reportEnd,
+				},
+				cli.StringFlag{
+					Name:        "format",
+					Usage:       "Output format. Must be one of 'csv', 'json'",
+					Value:       usagereport.FormatCSV,
+					Destination: &reportFormat,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return usageReport(context.Background(), app.Writer, repository, reportStart, reportEnd, reportFormat)
+			},
+		},
 	}
 	return app
 }
 
+func usageReport(ctx context.Context, w io.Writer, r models.Repository, startDate, endDate, format string) error {
+	if !usagereport.ValidFormat(format) {
+		return fmt.Errorf("format (--format) must be one of %s, %s", usagereport.FormatCSV, usagereport.FormatJSON)
+	}
+	start, end, err := usagereport.ParsePeriod(startDate, endDate, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cfg, err := service.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	report, err := usagereport.Generate(ctx, r, service.NewService(r, cfg, ""), start, end)
+	if err != nil {
+		return err
+	}
+	return report.Write(w, format)
+}
+
 func createGroup(r models.Repository, id, name, acoID string) (string, error) {
 	if id == "" || name == "" || acoID == "" {
 		return "", errors.New("ID (--id), name (--name), and ACO ID (--aco-id) are required")
@@ -492,6 +714,53 @@ func resetClientCredentials(r models.Repository, p auth.Provider, acoCMSID strin
 	return fmt.Sprintf("%s\n%s\n%s", creds.ClientName, creds.ClientID, creds.ClientSecret), nil
 }
 
+func rotateClientCredentials(r models.Repository, p auth.Provider, acoCMSID string, gracePeriod time.Duration, ips []string) (string, error) {
+	if gracePeriod <= 0 {
+		return "", errors.New("grace period must be greater than zero")
+	}
+
+	aco, err := r.GetACOByCMSID(context.Background(), acoCMSID)
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := p.RotateSecret(aco.ClientID, gracePeriod, ips)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not rotate credentials for %s", acoCMSID)
+	}
+
+	// Report the grace expiry that was stored, since that is what access token requests are checked against
+	rotation, err := r.GetCredentialRotationByClientID(context.Background(), creds.ClientID)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not find credential rotation for %s", acoCMSID)
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s\nPrevious credentials (client ID %s) remain valid until %s",
+		creds.ClientName, creds.ClientID, creds.ClientSecret, rotation.PreviousClientID,
+		rotation.GraceExpiresAt.UTC().Format(time.RFC3339)), nil
+}
+
+// revokeExpiredCredentials revokes the previous credentials of every rotation whose grace period ended before now.
+func revokeExpiredCredentials(r models.Repository, p auth.Provider, now time.Time) (int, error) {
+	ctx := context.Background()
+	rotations, err := r.GetExpiredCredentialRotations(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, rotation := range rotations {
+		if err := p.RevokeSystemCredentials(rotation.PreviousSystemID); err != nil {
+			return i, errors.Wrapf(err, "could not revoke credentials for client %s", rotation.PreviousClientID)
+		}
+		if err := r.MarkCredentialRotationRevoked(ctx, rotation.ID); err != nil {
+			return i, err
+		}
+		log.API.Infof("Revoked rotated credentials for client %s (ACO %s)", rotation.PreviousClientID, rotation.ACOID)
+	}
+
+	return len(rotations), nil
+}
+
 func revokeAccessToken(p auth.Provider, accessToken string) error {
 	if accessToken == "" {
 		return errors.New("Access token (--access-token) must be provided")
@@ -514,6 +783,45 @@ const cclfPattern = `((?:T|P).*\.ZC[A-B0-9]*)Y(\d{2}\.D\d{6}\.T\d{7})`
 
 var cclfregex = regexp.MustCompile(cclfPattern)
 
+// validateAttribution performs a dry run import of the CSV attribution file or the CCLF archives in directory
+// and writes the validation report to w. An error is returned when the delivery would fail to import.
+func validateAttribution(ctx context.Context, w io.Writer, file, directory string) error {
+	if (file == "") == (directory == "") {
+		return errors.New("exactly one of a CSV attribution file (--file) or a CCLF directory (--directory) is required")
+	}
+
+	fileProcessor := &ai.LocalFileProcessor{
+		Handler: bp.LocalFileHandler{Logger: log.API},
+		DryRun:  true,
+	}
+
+	var (
+		report ai.ValidationReport
+		err    error
+	)
+	if file != "" {
+		importer := ai.CSVImporter{Logger: log.API, FileProcessor: fileProcessor}
+		report, err = importer.ValidateCSV(ctx, file)
+	} else {
+		importer := ai.NewCclfImporter(log.API, fileProcessor, nil)
+		report, err = importer.ValidateCCLFDirectory(ctx, directory)
+	}
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		return err
+	}
+
+	if !report.Valid() {
+		return errors.New("attribution validation failed; see the report for details")
+	}
+	return nil
+}
+
 func renameCCLF(name string) string {
 	return cclfregex.ReplaceAllString(name, "${1}R${2}")
 }
@@ -3,7 +3,9 @@ package bcdacli
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -32,6 +34,7 @@ import (
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/urfave/cli"
 )
@@ -205,6 +208,68 @@ func (s *CLITestSuite) TestResetSecretCLI() {
 	mock.AssertExpectations(s.T())
 }
 
+func (s *CLITestSuite) TestRotateClientCredentials() {
+	assert := assert.New(s.T())
+
+	creds := auth.Credentials{ClientName: *s.testACO.CMSID, ClientID: uuid.New(), ClientSecret: uuid.New()}
+	graceExpiresAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	provider := &auth.MockProvider{}
+	provider.On("RotateSecret", s.testACO.ClientID, 24*time.Hour, []string(nil)).Run(func(args mock.Arguments) {
+		// Providers record the rotation along with the new credentials
+		aco, err := repository.GetACOByCMSID(context.Background(), *s.testACO.CMSID)
+		assert.NoError(err)
+		assert.NoError(repository.CreateCredentialRotation(context.Background(), models.CredentialRotation{
+			ACOID:            aco.UUID,
+			PreviousClientID: aco.ClientID,
+			PreviousSystemID: aco.SystemID,
+			ClientID:         creds.ClientID,
+			SystemID:         "new-system",
+			GraceExpiresAt:   graceExpiresAt,
+		}))
+	}).Return(creds, nil)
+	defer func() {
+		_, err := s.db.Exec("DELETE FROM credential_rotations WHERE client_id = $1", creds.ClientID)
+		assert.NoError(err)
+	}()
+
+	msg, err := rotateClientCredentials(repository, provider, *s.testACO.CMSID, 24*time.Hour, nil)
+	assert.Nil(err)
+	assert.Equal(fmt.Sprintf("%s\n%s\n%s\nPrevious credentials (client ID %s) remain valid until 2026-03-01T12:00:00Z",
+		creds.ClientName, creds.ClientID, creds.ClientSecret, s.testACO.ClientID), msg)
+
+	msg, err = rotateClientCredentials(repository, provider, "BLAH", 24*time.Hour, nil)
+	assert.Equal("no ACO record found for BLAH", err.Error())
+	assert.Empty(msg)
+
+	_, err = rotateClientCredentials(repository, provider, *s.testACO.CMSID, 0, nil)
+	assert.EqualError(err, "grace period must be greater than zero")
+
+	provider.AssertExpectations(s.T())
+}
+
+func (s *CLITestSuite) TestRevokeExpiredCredentials() {
+	assert := assert.New(s.T())
+	now := time.Now()
+
+	mockRepo := &models.MockRepository{}
+	mockRepo.On("GetExpiredCredentialRotations", mock.Anything, now).Return([]*models.CredentialRotation{
+		{ID: 1, PreviousClientID: "client-1", PreviousSystemID: "1"},
+		{ID: 2, PreviousClientID: "client-2", PreviousSystemID: "2"},
+	}, nil)
+	mockRepo.On("MarkCredentialRotationRevoked", mock.Anything, uint(1)).Return(nil)
+
+	mockProvider := &auth.MockProvider{}
+	mockProvider.On("RevokeSystemCredentials", "1").Return(nil)
+	mockProvider.On("RevokeSystemCredentials", "2").Return(errors.New("SSAS unavailable"))
+
+	count, err := revokeExpiredCredentials(mockRepo, mockProvider, now)
+	assert.ErrorContains(err, "could not revoke credentials for client client-2")
+	assert.Equal(1, count)
+
+	mockRepo.AssertExpectations(s.T())
+	mockProvider.AssertExpectations(s.T())
+}
+
 func (s *CLITestSuite) TestRevokeToken() {
 	assert := assert.New(s.T())
 
@@ -414,6 +479,26 @@ func (s *CLITestSuite) TestDenylistACO() {
 	s.True(newlyDenylistedACO.Denylisted())
 }
 
+func (s *CLITestSuite) TestUsageReport() {
+	job := &models.Job{ACOID: s.testACO.UUID, RequestURL: "/api/v2/Patient/$export", Status: models.JobStatusFailed}
+	postgrestest.CreateJobs(s.T(), s.db, job)
+	defer postgrestest.DeleteJobByID(s.T(), s.db, job.ID)
+	today := time.Now().UTC().Format("2006-01-02")
+
+	buf := new(bytes.Buffer)
+	s.testApp.Writer = buf
+	s.NoError(s.testApp.Run([]string{"bcda", "usage-report", "--start", today, "--end", today}))
+	s.Contains(buf.String(), "cms_id,model,jobs,completed_jobs,failed_jobs,failure_rate,resource_types,downloads,bytes_downloaded\n")
+	s.Contains(buf.String(), fmt.Sprintf("\n%s,", *s.testACO.CMSID))
+
+	buf.Reset()
+	s.NoError(s.testApp.Run([]string{"bcda", "usage-report", "--start", today, "--end", today, "--format", "json"}))
+	s.Contains(buf.String(), fmt.Sprintf(`"cms_id": "%s"`, *s.testACO.CMSID))
+
+	s.ErrorContains(s.testApp.Run([]string{"bcda", "usage-report", "--format", "xml"}), "format (--format) must be one of csv, json")
+	s.ErrorContains(s.testApp.Run([]string{"bcda", "usage-report", "--start", today}), "both start and end dates are required")
+}
+
 func getRandomPort(t *testing.T) int {
 	listener, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
@@ -20,4 +20,6 @@ type BenePrefsFileHandler interface {
 	CleanupBenePrefsFiles(ctx context.Context, suppressList []*models.BenePrefsFilenameMetadata) error
 	// Open a given bene-prefs file, specified by the metadata struct.
 	OpenFile(ctx context.Context, metadata *models.BenePrefsFilenameMetadata) (*bufio.Scanner, func(), error)
+	// Move a file that failed validation or import out of the import path, storing the report alongside it.
+	QuarantineBenePrefsFile(ctx context.Context, metadata *models.BenePrefsFilenameMetadata, report QuarantineReport) error
 }
@@ -3,6 +3,8 @@ package beneprefs
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
@@ -10,6 +12,7 @@ import (
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
 )
 
 const (
@@ -27,6 +30,8 @@ type BenePrefsImporter struct {
 	Repo                 models.Repository
 	Logger               logrus.FieldLogger
 	ImportStatusInterval int
+	// Optional client used to alert operators when a file is quarantined
+	SlackClient *slack.Client
 }
 
 // ImportDirectory takes a dir path and processes all bene-prefs files, creating a suppression_files db record for each file and a suppressions db record for each entry in that file.
@@ -47,10 +52,12 @@ func (importer BenePrefsImporter) ImportDirectory(ctx context.Context, path stri
 		err = importer.validate(ctx, metadata)
 		if err != nil {
 			importer.Logger.Errorf("failed to validate bene-prefs file: %s", metadata)
+			importer.quarantine(ctx, metadata, QuarantineStageValidation, err)
 			failure++
 		} else {
 			if err = importer.importFile(ctx, metadata); err != nil {
 				importer.Logger.Errorf("failed to import bene-prefs file: %s ", metadata)
+				importer.quarantine(ctx, metadata, QuarantineStageImport, err)
 				failure++
 			} else {
 				metadata.Imported = true
@@ -74,11 +81,13 @@ func (importer BenePrefsImporter) ImportDirectory(ctx context.Context, path stri
 	return success, failure, skipped, err
 }
 
-// validate scans a bene prefs file by checking header and trailer codes and ensuring the record count matches the number of records in the file
+// validate scans a bene prefs file by checking header and trailer codes and ensuring the record count matches the number of records in the file.
+// The checksum of a valid file is recorded in metadata. It covers the file's lines rather than its raw bytes so that line endings don't affect it.
 func (importer BenePrefsImporter) validate(ctx context.Context, metadata *models.BenePrefsFilenameMetadata) error {
 	importer.Logger.Infof("validating bene-prefs file %s...", metadata)
 
 	count := 0
+	hash := sha256.New()
 	sc, close, err := importer.FileHandler.OpenFile(ctx, metadata)
 	if err != nil {
 		err = fmt.Errorf("could not read file %s, err: %w", metadata, err)
@@ -90,6 +99,8 @@ func (importer BenePrefsImporter) validate(ctx context.Context, metadata *models
 
 	for sc.Scan() {
 		b := sc.Bytes()
+		hash.Write(b)
+		hash.Write([]byte{'\n'})
 		metaInfo := string(bytes.TrimSpace(b[headTrailStart:headTrailEnd]))
 		if count == 0 {
 			if metaInfo != headerCode {
@@ -121,6 +132,7 @@ func (importer BenePrefsImporter) validate(ctx context.Context, metadata *models
 		}
 	}
 
+	metadata.Checksum = hex.EncodeToString(hash.Sum(nil))
 	importer.Logger.Infof("Successfully validated bene-prefs file %s.", metadata)
 	return nil
 }
@@ -136,9 +148,10 @@ func (importer BenePrefsImporter) importFile(ctx context.Context, metadata *mode
 		return err
 	}
 
-	err = importer.scanAndImport(ctx, metadata)
+	err = importer.inTransaction(ctx, func(txImporter BenePrefsImporter) error {
+		return txImporter.importRecords(ctx, metadata)
+	})
 	if err != nil {
-		err := fmt.Errorf("error scanning and importing records from file: %s, err: %w", metadata, err)
 		importer.Logger.Error(err)
 
 		repoErr := importer.Repo.UpdateBenePrefsImportStatus(ctx, metadata.FileID, constants.ImportFail)
@@ -150,14 +163,32 @@ func (importer BenePrefsImporter) importFile(ctx context.Context, metadata *mode
 	}
 
 	importer.Logger.Infof("Successfully imported file: %s", metadata.Name)
+	return nil
+}
 
-	err = importer.Repo.UpdateBenePrefsImportStatus(ctx, metadata.FileID, constants.ImportComplete)
+// inTransaction calls fn with a copy of the importer whose repository calls all run in a single transaction.
+func (importer BenePrefsImporter) inTransaction(ctx context.Context, fn func(BenePrefsImporter) error) error {
+	return importer.Repo.InTransaction(ctx, func(repo models.Repository) error {
+		importer.Repo = repo
+		return fn(importer)
+	})
+}
+
+// importRecords creates the suppression records in the file, links their MBIs and marks the file record
+// complete. It should run in a transaction so a failure leaves none of them behind.
+func (importer BenePrefsImporter) importRecords(ctx context.Context, metadata *models.BenePrefsFilenameMetadata) error {
+	err := importer.scanAndImport(ctx, metadata)
+	if err == nil {
+		err = importer.linkMBIs(ctx, metadata)
+	}
 	if err != nil {
-		err = fmt.Errorf("could not update bene-prefs file import status for file: %s, err: %w", metadata, err)
-		importer.Logger.Error(err)
-		return err
+		return fmt.Errorf("error scanning and importing records from file: %s, err: %w", metadata, err)
 	}
 
+	err = importer.Repo.UpdateBenePrefsImportStatus(ctx, metadata.FileID, constants.ImportComplete)
+	if err != nil {
+		return fmt.Errorf("could not update bene-prefs file import status for file: %s, err: %w", metadata, err)
+	}
 	return nil
 }
 
@@ -169,6 +200,7 @@ func (importer BenePrefsImporter) createBenePrefsFileRecord(ctx context.Context,
 		Name:         metadata.Name,
 		Timestamp:    metadata.Timestamp,
 		ImportStatus: constants.ImportInprog,
+		Checksum:     metadata.Checksum,
 	}
 
 	bpFile.ID, err = importer.Repo.CreateBenePrefsFile(ctx, bpFile)
@@ -183,6 +215,17 @@ func (importer BenePrefsImporter) createBenePrefsFileRecord(ctx context.Context,
 	return nil
 }
 
+// linkMBIs adds the MBIs in the file to the MBI crosswalk so a beneficiary whose MBI has been reissued can
+// still be found under the MBI their ACO knows them by.
+func (importer BenePrefsImporter) linkMBIs(ctx context.Context, metadata *models.BenePrefsFilenameMetadata) error {
+	count, err := importer.Repo.UpsertMBILinks(ctx, metadata.FileID)
+	if err != nil {
+		return fmt.Errorf("failed to update MBI links: %w", err)
+	}
+	importer.Logger.Infof("Updated %d MBI links from file: %s", count, metadata.Name)
+	return nil
+}
+
 // scanAndImport scans the file and creates a suppression record for each entry in the file
 func (importer BenePrefsImporter) scanAndImport(ctx context.Context, metadata *models.BenePrefsFilenameMetadata) error {
 	var (
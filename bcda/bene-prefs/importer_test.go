@@ -2,6 +2,7 @@ package beneprefs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -46,6 +47,12 @@ func (s *BenePrefsTestSuite) SetupTest() {
 }
 
 func (s *BenePrefsTestSuite) createImporter(repo models.Repository) BenePrefsImporter {
+	// Transactions run against the mock itself
+	if m, ok := repo.(*models.MockRepository); ok {
+		m.On("InTransaction", mock.Anything, mock.Anything).Return(func(_ context.Context, fn func(models.Repository) error) error {
+			return fn(m)
+		}).Maybe()
+	}
 	return BenePrefsImporter{
 		FileHandler: &LocalFileHandler{
 			Logger:                 log.StandardLogger(),
@@ -86,6 +93,7 @@ func (s *BenePrefsTestSuite) TestImportFile() {
 	repo.On("CreateBenePrefsFile", mock.Anything, mock.Anything).Return(uint(1), nil)
 	repo.On("UpdateBenePrefsImportStatus", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	repo.On("CreateBenePrefsRecord", mock.Anything, mock.Anything).Return(nil)
+	repo.On("UpsertMBILinks", mock.Anything, uint(1)).Return(int64(1), nil)
 	importer := s.createImporter(repo)
 	err := importer.importFile(ctx, metadata)
 	assert.Nil(err)
@@ -103,6 +111,7 @@ func (s *BenePrefsTestSuite) TestImportFile() {
 	repo.On("CreateBenePrefsFile", mock.Anything, mock.Anything).Return(uint(1), nil)
 	repo.On("UpdateBenePrefsImportStatus", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("throw db error"))
 	repo.On("CreateBenePrefsRecord", mock.Anything, mock.Anything).Return(nil)
+	repo.On("UpsertMBILinks", mock.Anything, uint(1)).Return(int64(1), nil)
 	importer = s.createImporter(repo)
 	err = importer.importFile(ctx, metadata)
 	assert.ErrorContains(err, "could not update bene-prefs file import status for file")
@@ -117,6 +126,25 @@ func (s *BenePrefsTestSuite) TestImportFile() {
 	err = importer.importFile(ctx, metadata)
 	assert.ErrorContains(err, "failed to create bene-prefs record")
 	assert.ErrorContains(err, "throw db error")
+
+	// issue updating the MBI crosswalk; the records are written in the same transaction as the links, so only
+	// the file record is left to mark failed
+	repo = &models.MockRepository{}
+	var inTx bool
+	repo.On("InTransaction", mock.Anything, mock.Anything).Return(func(_ context.Context, fn func(models.Repository) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(repo)
+	})
+	repo.On("CreateBenePrefsFile", mock.Anything, mock.Anything).Run(func(mock.Arguments) { assert.False(inTx) }).Return(uint(1), nil)
+	repo.On("CreateBenePrefsRecord", mock.Anything, mock.Anything).Run(func(mock.Arguments) { assert.True(inTx) }).Return(nil)
+	repo.On("UpsertMBILinks", mock.Anything, uint(1)).Run(func(mock.Arguments) { assert.True(inTx) }).Return(int64(0), errors.New("throw db error"))
+	repo.On("UpdateBenePrefsImportStatus", mock.Anything, uint(1), constants.ImportFail).Run(func(mock.Arguments) { assert.False(inTx) }).Return(nil)
+	importer = s.createImporter(repo)
+	err = importer.importFile(ctx, metadata)
+	assert.ErrorContains(err, "failed to update MBI links")
+	assert.ErrorContains(err, "throw db error")
+	repo.AssertExpectations(s.T())
 }
 
 func (s *BenePrefsTestSuite) TestImport_MissingData() {
@@ -450,6 +478,7 @@ func (s *BenePrefsTestSuite) TestImportDirectoryTable() {
 				repo.On("CreateBenePrefsFile", mock.Anything, mock.Anything).Return(uint(1), nil)
 				repo.On("UpdateBenePrefsImportStatus", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 				repo.On("CreateBenePrefsRecord", mock.Anything, mock.Anything).Return(nil)
+				repo.On("UpsertMBILinks", mock.Anything, uint(1)).Return(int64(1), nil)
 			}
 
 			importer := s.createImporter(repo)
@@ -465,3 +494,119 @@ func (s *BenePrefsTestSuite) TestImportDirectoryTable() {
 		})
 	}
 }
+
+func (s *BenePrefsTestSuite) TestImportDirectoryQuarantine() {
+	assert := assert.New(s.T())
+	ctx := context.Background()
+
+	path, cleanup := testUtils.CopyToTemporaryDirectory(s.T(), "../../shared_files/suppressionfile_BadHeader/")
+	defer cleanup()
+	// Keep the quarantine dir inside the import path to check it is not walked on the next run
+	quarantineDir := filepath.Join(path, "quarantine")
+
+	importer := s.createImporter(&models.MockRepository{})
+	importer.FileHandler.(*LocalFileHandler).QuarantineDir = quarantineDir
+
+	success, failure, _, err := importer.ImportDirectory(ctx, path)
+	assert.ErrorContains(err, "one or more bene-prefs files failed to import correctly")
+	assert.Equal(0, success)
+	assert.Equal(1, failure)
+
+	fileName := "T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009"
+	assert.NoFileExists(filepath.Join(path, fileName))
+	assert.FileExists(filepath.Join(quarantineDir, fileName))
+
+	b, err := os.ReadFile(filepath.Join(quarantineDir, fileName+".error.json"))
+	assert.NoError(err)
+	var report QuarantineReport
+	assert.NoError(json.Unmarshal(b, &report))
+	assert.Equal(fileName, report.FileName)
+	assert.Equal(QuarantineStageValidation, report.Stage)
+	assert.Contains(report.Error, "invalid file header")
+
+	success, failure, _, err = importer.ImportDirectory(ctx, path)
+	assert.NoError(err)
+	assert.Equal(0, success)
+	assert.Equal(0, failure)
+}
+
+func (s *BenePrefsTestSuite) TestQuarantineWithoutDir() {
+	assert := assert.New(s.T())
+	importer := s.createImporter(&models.MockRepository{})
+
+	filePath := filepath.Join(s.basePath, "suppressionfile_BadHeader/T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009")
+	metadata := &models.BenePrefsFilenameMetadata{Name: "T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009", FilePath: filePath}
+	importer.quarantine(context.Background(), metadata, QuarantineStageValidation, errors.New("invalid file header"))
+
+	// The file stays where it is and is left for the regular cleanup
+	assert.False(metadata.Quarantined)
+	assert.FileExists(filePath)
+}
+
+func (s *BenePrefsTestSuite) TestReprocessFile() {
+	ctx := context.Background()
+	fileName := "T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009"
+	filePath := filepath.Join(s.basePath, "synthetic1800MedicareFiles/test/"+fileName)
+
+	// Find the checksum of the file to set up the previously imported copies
+	metadata := &models.BenePrefsFilenameMetadata{FilePath: filePath}
+	assert.NoError(s.T(), s.createImporter(&models.MockRepository{}).validate(ctx, metadata))
+	checksum := metadata.Checksum
+	assert.Len(s.T(), checksum, 64)
+
+	tests := []struct {
+		name      string
+		filePath  string
+		existing  *models.BenePrefsFile
+		imported  bool
+		deleted   bool
+		recordErr error
+		expErr    string
+	}{
+		{"New file", filePath, nil, true, false, nil, ""},
+		{"Same contents already imported", filePath, &models.BenePrefsFile{ID: 3, Name: fileName, Checksum: checksum, ImportStatus: constants.ImportComplete}, false, false, nil, ""},
+		{"Same contents failed to import", filePath, &models.BenePrefsFile{ID: 3, Name: fileName, Checksum: checksum, ImportStatus: constants.ImportFail}, true, true, nil, ""},
+		{"Corrected contents", filePath, &models.BenePrefsFile{ID: 3, Name: fileName, Checksum: "old", ImportStatus: constants.ImportComplete}, true, true, nil, ""},
+		{"Corrected contents fail to import", filePath, &models.BenePrefsFile{ID: 3, Name: fileName, Checksum: "old", ImportStatus: constants.ImportComplete}, false, true, errors.New("throw db error"), "throw db error"},
+		{"Invalid file", filepath.Join(s.basePath, "suppressionfile_BadHeader/"+fileName), nil, false, false, nil, "invalid file header"},
+		{"Invalid file name", filepath.Join(s.basePath, "suppressionfile_BadFileNames/T#EFT.ON.ACO.NGD1800.FRPD.D191220.T1000009"), nil, false, false, nil, "invalid filename"},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			repo := models.NewMockRepository(t)
+			// The earlier copy is removed in the same transaction as the new one is imported, so a failed
+			// import rolls the removal back rather than marking the new file record failed
+			var inTx bool
+			repo.On("InTransaction", mock.Anything, mock.Anything).Return(func(_ context.Context, fn func(models.Repository) error) error {
+				inTx = true
+				defer func() { inTx = false }()
+				return fn(repo)
+			}).Maybe()
+			assertInTx := func(mock.Arguments) { assert.True(t, inTx) }
+
+			repo.On("GetBenePrefsFileByName", mock.Anything, fileName).Return(tt.existing, nil).Maybe()
+			if tt.deleted {
+				repo.On("DeleteBenePrefsFile", mock.Anything, uint(3)).Run(assertInTx).Return(nil)
+			}
+			if tt.imported || tt.recordErr != nil {
+				repo.On("CreateBenePrefsFile", mock.Anything, mock.MatchedBy(func(f models.BenePrefsFile) bool {
+					return f.Name == fileName && f.Checksum == checksum
+				})).Run(assertInTx).Return(uint(4), nil)
+				repo.On("CreateBenePrefsRecord", mock.Anything, mock.Anything).Run(assertInTx).Return(tt.recordErr)
+			}
+			if tt.imported {
+				repo.On("UpsertMBILinks", mock.Anything, uint(4)).Run(assertInTx).Return(int64(1), nil)
+				repo.On("UpdateBenePrefsImportStatus", mock.Anything, uint(4), constants.ImportComplete).Run(assertInTx).Return(nil)
+			}
+
+			imported, err := s.createImporter(repo).ReprocessFile(ctx, tt.filePath)
+			if tt.expErr != "" {
+				assert.ErrorContains(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.imported, imported)
+		})
+	}
+}
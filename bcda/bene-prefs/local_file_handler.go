@@ -3,6 +3,7 @@ package beneprefs
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,6 +20,8 @@ type LocalFileHandler struct {
 	Logger                 logrus.FieldLogger
 	PendingDeletionDir     string
 	FileArchiveThresholdHr uint
+	// Directory that files failing validation or import are moved to. Files are left in place when it is empty.
+	QuarantineDir string
 }
 
 func (handler *LocalFileHandler) LoadBenePrefsFiles(ctx context.Context, path string) (suppressList *[]*models.BenePrefsFilenameMetadata, skipped int, err error) {
@@ -41,6 +44,10 @@ func (handler *LocalFileHandler) getBenePrefsFileMetadata(ctx context.Context, s
 		}
 		// Directories are not Suppression files
 		if info.IsDir() {
+			// Don't pick quarantined files back up when the quarantine dir is inside the import path
+			if handler.QuarantineDir != "" && filepath.Clean(path) == filepath.Clean(handler.QuarantineDir) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -93,9 +100,38 @@ func (handler *LocalFileHandler) OpenFile(ctx context.Context, metadata *models.
 	}, nil
 }
 
+func (handler *LocalFileHandler) QuarantineBenePrefsFile(ctx context.Context, metadata *models.BenePrefsFilenameMetadata, report QuarantineReport) error {
+	if handler.QuarantineDir == "" {
+		return fmt.Errorf("no quarantine directory configured for file %s", metadata)
+	}
+	if err := os.MkdirAll(handler.QuarantineDir, 0750); err != nil {
+		return fmt.Errorf("failed to create quarantine dir %s: %w", handler.QuarantineDir, err)
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	reportPath := filepath.Join(handler.QuarantineDir, quarantineReportName(metadata.Name))
+	if err = os.WriteFile(reportPath, b, 0600); err != nil {
+		return fmt.Errorf("failed to write quarantine report %s: %w", reportPath, err)
+	}
+
+	newpath := filepath.Join(handler.QuarantineDir, metadata.Name)
+	if err = os.Rename(metadata.FilePath, newpath); err != nil {
+		return fmt.Errorf("failed to move file %s to quarantine: %w", metadata, err)
+	}
+
+	handler.Logger.Warnf("File %s quarantined to %s", metadata, newpath)
+	return nil
+}
+
 func (handler *LocalFileHandler) CleanupBenePrefsFiles(ctx context.Context, suppresslist []*models.BenePrefsFilenameMetadata) error {
 	errCount := 0
 	for _, bpFile := range suppresslist {
+		if bpFile.Quarantined {
+			continue
+		}
 		fmt.Printf("Cleaning up file %s.\n", bpFile)
 		handler.Logger.Infof("Cleaning up file %s", bpFile)
 		newpath := fmt.Sprintf("%s/%s", handler.PendingDeletionDir, bpFile.Name)
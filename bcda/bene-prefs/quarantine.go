@@ -0,0 +1,107 @@
+package beneprefs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	msgr "github.com/CMSgov/bcda-app/bcda/slackmessenger"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+// DefaultQuarantinePrefix is the S3 key prefix failed files are moved under when the handler does not set one.
+const DefaultQuarantinePrefix = "quarantine"
+
+const (
+	QuarantineStageValidation = "validation"
+	QuarantineStageImport     = "import"
+)
+
+// QuarantineReport is stored next to a quarantined file to explain why it was pulled from the import path.
+type QuarantineReport struct {
+	FileName      string    `json:"file_name"`
+	FilePath      string    `json:"file_path"`
+	Checksum      string    `json:"checksum,omitempty"`
+	Stage         string    `json:"stage"`
+	Error         string    `json:"error"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+func quarantineReportName(fileName string) string {
+	return fileName + ".error.json"
+}
+
+// quarantine moves a file that failed the given stage out of the import path and alerts operators. An import
+// failure leaves only the file record, marked by its failed import status, until the file is reprocessed.
+func (importer BenePrefsImporter) quarantine(ctx context.Context, metadata *models.BenePrefsFilenameMetadata, stage string, cause error) {
+	report := QuarantineReport{
+		FileName:      metadata.Name,
+		FilePath:      metadata.FilePath,
+		Checksum:      metadata.Checksum,
+		Stage:         stage,
+		Error:         cause.Error(),
+		QuarantinedAt: time.Now().UTC(),
+	}
+
+	msg := fmt.Sprintf("%s: Bene-prefs file %s failed %s in %s env and was quarantined. Rerun it with reprocess-bene-prefs once corrected.", msgr.FailureMsg, metadata.Name, stage, conf.GetEnv("ENV"))
+	if err := importer.FileHandler.QuarantineBenePrefsFile(ctx, metadata, report); err != nil {
+		importer.Logger.Errorf("failed to quarantine bene-prefs file %s: %s", metadata, err)
+		msg = fmt.Sprintf("%s: Bene-prefs file %s failed %s in %s env and could not be quarantined.", msgr.FailureMsg, metadata.Name, stage, conf.GetEnv("ENV"))
+	} else {
+		metadata.Quarantined = true
+	}
+
+	if importer.SlackClient != nil {
+		msgr.SendSlackMessage(importer.SlackClient, msgr.AlertsChannel, msg, msgr.Danger)
+	}
+}
+
+// ReprocessFile imports a single bene-prefs file, typically a corrected copy of one that was quarantined. Files
+// are keyed by name and checksum: rerunning a file that was already imported with the same contents does nothing,
+// while a file whose contents changed, or whose earlier import failed, replaces the records from the earlier copy
+// in a single transaction. It reports whether the file was imported.
+func (importer BenePrefsImporter) ReprocessFile(ctx context.Context, filePath string) (bool, error) {
+	metadata, err := parseMetadata(filePath)
+	if err != nil {
+		return false, err
+	}
+	metadata.FilePath = filePath
+
+	if err = importer.validate(ctx, &metadata); err != nil {
+		return false, err
+	}
+
+	existing, err := importer.Repo.GetBenePrefsFileByName(ctx, metadata.Name)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up bene-prefs file %s: %w", metadata.Name, err)
+	}
+	if existing != nil && existing.Checksum == metadata.Checksum && existing.ImportStatus == constants.ImportComplete {
+		importer.Logger.Infof("bene-prefs file %s was already imported with checksum %s, skipping", metadata.Name, metadata.Checksum)
+		return false, nil
+	}
+
+	// The earlier copy is only removed along with a successful import, so a failure leaves it in place
+	err = importer.inTransaction(ctx, func(txImporter BenePrefsImporter) error {
+		if existing != nil {
+			importer.Logger.Infof("replacing records from bene-prefs file %s (id %d, status %s)", metadata.Name, existing.ID, existing.ImportStatus)
+			if err := txImporter.Repo.DeleteBenePrefsFile(ctx, existing.ID); err != nil {
+				return fmt.Errorf("failed to remove previous import of bene-prefs file %s: %w", metadata.Name, err)
+			}
+		}
+
+		importer.Logger.Infof("importing bene-prefs file %s...", metadata)
+		if err := txImporter.createBenePrefsFileRecord(ctx, &metadata); err != nil {
+			return fmt.Errorf("failed to create bene-prefs file record for file: %s, err: %w", metadata, err)
+		}
+		return txImporter.importRecords(ctx, &metadata)
+	})
+	if err != nil {
+		importer.Logger.Error(err)
+		return false, err
+	}
+
+	importer.Logger.Infof("Successfully imported file: %s", metadata.Name)
+	return true, nil
+}
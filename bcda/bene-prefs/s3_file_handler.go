@@ -4,7 +4,10 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"path"
 	"time"
 
 	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
@@ -23,6 +26,9 @@ type S3FileHandler struct {
 	Logger logrus.FieldLogger
 	// Optional S3 endpoint to use for connection.
 	Endpoint string
+	// Key prefix, within the bucket the file was delivered to, that failed files are moved under.
+	// Defaults to DefaultQuarantinePrefix.
+	QuarantinePrefix string
 }
 
 // Define logger functions to ensure that logs get sent to:
@@ -128,10 +134,33 @@ func (handler *S3FileHandler) OpenFileBytes(ctx context.Context, filePath string
 	return buff, err
 }
 
+// OpenFileStream returns the contents of the file at filePath as a stream rather than downloading the whole
+// file into memory. The caller is responsible for closing it.
+func (handler *S3FileHandler) OpenFileStream(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	handler.Infof("Opening file stream %s\n", filePath)
+	bucket, file := bcdaaws.ParseS3Uri(filePath)
+
+	output, err := handler.Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(file),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if output == nil || output.Body == nil {
+		return nil, fmt.Errorf("file %s is empty or does not exist", filePath)
+	}
+
+	return output.Body, nil
+}
+
 func (handler *S3FileHandler) CleanupBenePrefsFiles(ctx context.Context, suppresslist []*models.BenePrefsFilenameMetadata) error {
 	errCount := 0
 
 	for _, bpFile := range suppresslist {
+		if bpFile.Quarantined {
+			continue
+		}
 		if !bpFile.Imported {
 			// Don't do anything. The S3 bucket should have a retention policy that
 			// automatically cleans up files after a specified period of time,
@@ -157,6 +186,42 @@ func (handler *S3FileHandler) CleanupBenePrefsFiles(ctx context.Context, suppres
 	return nil
 }
 
+func (handler *S3FileHandler) QuarantineBenePrefsFile(ctx context.Context, metadata *models.BenePrefsFilenameMetadata, report QuarantineReport) error {
+	b, err := handler.OpenFileBytes(ctx, metadata.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s for quarantine: %w", metadata, err)
+	}
+
+	prefix := handler.QuarantinePrefix
+	if prefix == "" {
+		prefix = DefaultQuarantinePrefix
+	}
+	bucket, key := bcdaaws.ParseS3Uri(metadata.FilePath)
+	quarantineKey := path.Join(prefix, path.Base(key))
+
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	for k, body := range map[string][]byte{quarantineKey: b, path.Join(prefix, quarantineReportName(path.Base(key))): reportBytes} {
+		_, err = handler.Client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(k),
+			Body:   bytes.NewReader(body),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write s3://%s/%s: %w", bucket, k, err)
+		}
+	}
+
+	if err = handler.Delete(ctx, metadata.FilePath); err != nil {
+		return err
+	}
+
+	handler.Warningf("File %s quarantined to s3://%s/%s", metadata, bucket, quarantineKey)
+	return nil
+}
+
 func (handler *S3FileHandler) Delete(ctx context.Context, filePath string) error {
 	bucket, path := bcdaaws.ParseS3Uri(filePath)
 	timeoutDuration := time.Duration(utils.GetEnvInt("S3_DELETE_TIMEOUT", 60)) * time.Second
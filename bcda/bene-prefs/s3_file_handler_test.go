@@ -72,6 +72,15 @@ func TestOpenFileBytes(t *testing.T) {
 	assert.Len(t, fileBytes, 0)
 }
 
+func TestOpenFileStream(t *testing.T) {
+	handler := mockHandler()
+	path := "s3://test-bucket/test-prefix/test-file.txt"
+
+	body, err := handler.OpenFileStream(t.Context(), path)
+	assert.ErrorContains(t, err, "is empty or does not exist")
+	assert.Nil(t, body)
+}
+
 func TestCleanupBenePrefsFiles(t *testing.T) {
 	handler := mockHandler()
 
@@ -86,3 +95,11 @@ func TestDelete(t *testing.T) {
 	err := handler.Delete(t.Context(), "s3://test-bucket/test-prefix/test-file.txt")
 	assert.ErrorContains(t, err, "exceeded max wait time for ObjectNotExists waiter")
 }
+
+func TestQuarantineBenePrefsFile(t *testing.T) {
+	handler := mockHandler()
+	metadata := &models.BenePrefsFilenameMetadata{FilePath: "s3://test-bucket/test-prefix/test-file.txt"}
+
+	err := handler.QuarantineBenePrefsFile(t.Context(), metadata, QuarantineReport{})
+	assert.ErrorContains(t, err, "failed to read file")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,7 +21,9 @@ import (
 
 	"github.com/CMSgov/bcda-app/bcda/client/fhir"
 	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
 	fhirModels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/bcda/utils"
 	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
 	"github.com/CMSgov/bcda-app/conf"
@@ -66,13 +69,42 @@ type ClaimsWindow struct {
 	UpperBound time.Time
 }
 
+// ForAttributionPeriod narrows the window to the period a beneficiary was attributed to the entity.
+// Zero bounds leave that side of the window unchanged.
+func (cw ClaimsWindow) ForAttributionPeriod(start, end time.Time) ClaimsWindow {
+	if !start.IsZero() && start.After(cw.LowerBound) {
+		cw.LowerBound = start
+	}
+	if !end.IsZero() && (cw.UpperBound.IsZero() || end.Before(cw.UpperBound)) {
+		cw.UpperBound = end
+	}
+	return cw
+}
+
+// IsEmpty reports whether no claims can fall within the window.
+func (cw ClaimsWindow) IsEmpty() bool {
+	return !cw.LowerBound.IsZero() && !cw.UpperBound.IsZero() && cw.LowerBound.After(cw.UpperBound)
+}
+
+// PageHandler is called with each page of a bundle as it is retrieved. Returning an error stops paging and
+// the error is returned to the caller of the Stream method.
+type PageHandler func(page *fhirModels.Bundle) error
+
 type APIClient interface {
-	GetExplanationOfBenefit(jobData worker_types.JobEnqueueArgs, patientID string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error)
-	GetPatient(jobData worker_types.JobEnqueueArgs, patientID string) (*fhirModels.Bundle, error)
-	GetCoverage(jobData worker_types.JobEnqueueArgs, beneficiaryID string) (*fhirModels.Bundle, error)
-	GetPatientByMbi(jobData worker_types.JobEnqueueArgs, mbi string) (string, error)
-	GetClaim(jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error)
-	GetClaimResponse(jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error)
+	GetExplanationOfBenefit(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error)
+	GetPatient(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string) (*fhirModels.Bundle, error)
+	GetCoverage(ctx context.Context, jobData worker_types.JobEnqueueArgs, beneficiaryID string) (*fhirModels.Bundle, error)
+	GetPatientByMbi(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string) (string, error)
+	GetClaim(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error)
+	GetClaimResponse(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error)
+
+	// The Stream methods make the same requests as their Get counterparts but hand each page to fn as it
+	// arrives rather than holding every page in memory.
+	StreamExplanationOfBenefit(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, claimsWindow ClaimsWindow, fn PageHandler) error
+	StreamPatient(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, fn PageHandler) error
+	StreamCoverage(ctx context.Context, jobData worker_types.JobEnqueueArgs, beneficiaryID string, fn PageHandler) error
+	StreamClaim(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow, fn PageHandler) error
+	StreamClaimResponse(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow, fn PageHandler) error
 }
 
 type BlueButtonClient struct {
@@ -134,7 +166,7 @@ func NewBlueButtonClient(config BlueButtonConfig) (*BlueButtonClient, error) {
 	}
 
 	hl := &httpLogger{transport, logger}
-	httpClient := &http.Client{Transport: hl, Timeout: time.Duration(timeout) * time.Millisecond}
+	httpClient := &http.Client{Transport: tracing.Transport(hl), Timeout: time.Duration(timeout) * time.Millisecond}
 	client := fhir.NewClient(httpClient, pageSize)
 	maxTries, err := safecast.ToUint64(utils.GetEnvInt("BB_REQUEST_MAX_TRIES", 3))
 	if err != nil {
@@ -151,7 +183,13 @@ func SetLogger(log logrus.FieldLogger) {
 	logger = log
 }
 
-func (bbc *BlueButtonClient) GetPatient(jobData worker_types.JobEnqueueArgs, patientID string) (*fhirModels.Bundle, error) {
+func (bbc *BlueButtonClient) GetPatient(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string) (*fhirModels.Bundle, error) {
+	return collectPages(func(fn PageHandler) error {
+		return bbc.StreamPatient(ctx, jobData, patientID, fn)
+	})
+}
+
+func (bbc *BlueButtonClient) StreamPatient(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, fn PageHandler) error {
 	header := make(http.Header)
 	header.Add("IncludeAddressFields", "true")
 	params := GetDefaultParams()
@@ -160,13 +198,13 @@ func (bbc *BlueButtonClient) GetPatient(jobData worker_types.JobEnqueueArgs, pat
 
 	u, err := bbc.getURL("Patient", params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return bbc.makeBundleDataRequest("GET", u, jobData, header, nil)
+	return bbc.streamBundleData(ctx, "GET", u, jobData, header, nil, fn)
 }
 
-func (bbc *BlueButtonClient) GetPatientByMbi(jobData worker_types.JobEnqueueArgs, mbi string) (string, error) {
+func (bbc *BlueButtonClient) GetPatientByMbi(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string) (string, error) {
 	headers := createURLEncodedHeader()
 	params := GetDefaultParams()
 	params.Set("identifier", fmt.Sprintf("http://hl7.org/fhir/sid/us-mbi|%s", mbi))
@@ -176,23 +214,35 @@ func (bbc *BlueButtonClient) GetPatientByMbi(jobData worker_types.JobEnqueueArgs
 		return "", err
 	}
 
-	return bbc.getRawData("POST", jobData, u, headers, strings.NewReader(params.Encode()))
+	return bbc.getRawData(ctx, "POST", jobData, u, headers, strings.NewReader(params.Encode()))
+}
+
+func (bbc *BlueButtonClient) GetCoverage(ctx context.Context, jobData worker_types.JobEnqueueArgs, beneficiaryID string) (*fhirModels.Bundle, error) {
+	return collectPages(func(fn PageHandler) error {
+		return bbc.StreamCoverage(ctx, jobData, beneficiaryID, fn)
+	})
 }
 
-func (bbc *BlueButtonClient) GetCoverage(jobData worker_types.JobEnqueueArgs, beneficiaryID string) (*fhirModels.Bundle, error) {
+func (bbc *BlueButtonClient) StreamCoverage(ctx context.Context, jobData worker_types.JobEnqueueArgs, beneficiaryID string, fn PageHandler) error {
 	params := GetDefaultParams()
 	params.Set("beneficiary", beneficiaryID)
 	updateParamWithLastUpdated(&params, jobData.Since, jobData.TransactionTime)
 
 	u, err := bbc.getURL("Coverage", params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return bbc.makeBundleDataRequest("GET", u, jobData, nil, nil)
+	return bbc.streamBundleData(ctx, "GET", u, jobData, nil, nil, fn)
 }
 
-func (bbc *BlueButtonClient) GetClaim(jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+func (bbc *BlueButtonClient) GetClaim(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+	return collectPages(func(fn PageHandler) error {
+		return bbc.StreamClaim(ctx, jobData, mbi, claimsWindow, fn)
+	})
+}
+
+func (bbc *BlueButtonClient) StreamClaim(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow, fn PageHandler) error {
 	headers := createURLEncodedHeader()
 	params := GetDefaultParams()
 	updateParamsWithClaimsDefaults(&params, mbi)
@@ -201,13 +251,19 @@ func (bbc *BlueButtonClient) GetClaim(jobData worker_types.JobEnqueueArgs, mbi s
 
 	u, err := bbc.getURL("Claim/_search", url.Values{})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return bbc.makeBundleDataRequest("POST", u, jobData, headers, strings.NewReader(params.Encode()))
+	return bbc.streamBundleData(ctx, "POST", u, jobData, headers, strings.NewReader(params.Encode()), fn)
 }
 
-func (bbc *BlueButtonClient) GetClaimResponse(jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+func (bbc *BlueButtonClient) GetClaimResponse(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+	return collectPages(func(fn PageHandler) error {
+		return bbc.StreamClaimResponse(ctx, jobData, mbi, claimsWindow, fn)
+	})
+}
+
+func (bbc *BlueButtonClient) StreamClaimResponse(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow, fn PageHandler) error {
 	headers := createURLEncodedHeader()
 	params := GetDefaultParams()
 	updateParamsWithClaimsDefaults(&params, mbi)
@@ -216,13 +272,19 @@ func (bbc *BlueButtonClient) GetClaimResponse(jobData worker_types.JobEnqueueArg
 
 	u, err := bbc.getURL("ClaimResponse/_search", url.Values{})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return bbc.makeBundleDataRequest("POST", u, jobData, headers, strings.NewReader(params.Encode()))
+	return bbc.streamBundleData(ctx, "POST", u, jobData, headers, strings.NewReader(params.Encode()), fn)
 }
 
-func (bbc *BlueButtonClient) GetExplanationOfBenefit(jobData worker_types.JobEnqueueArgs, patientID string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+func (bbc *BlueButtonClient) GetExplanationOfBenefit(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+	return collectPages(func(fn PageHandler) error {
+		return bbc.StreamExplanationOfBenefit(ctx, jobData, patientID, claimsWindow, fn)
+	})
+}
+
+func (bbc *BlueButtonClient) StreamExplanationOfBenefit(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, claimsWindow ClaimsWindow, fn PageHandler) error {
 	header := make(http.Header)
 	header.Add("IncludeTaxNumbers", "true")
 	params := GetDefaultParams()
@@ -241,10 +303,10 @@ func (bbc *BlueButtonClient) GetExplanationOfBenefit(jobData worker_types.JobEnq
 
 	u, err := bbc.getURL("ExplanationOfBenefit", params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return bbc.makeBundleDataRequest("GET", u, jobData, header, nil)
+	return bbc.streamBundleData(ctx, "GET", u, jobData, header, nil, fn)
 }
 
 func (bbc *BlueButtonClient) GetMetadata() (string, error) {
@@ -254,31 +316,44 @@ func (bbc *BlueButtonClient) GetMetadata() (string, error) {
 	}
 	jobData := worker_types.JobEnqueueArgs{}
 
-	return bbc.getRawData("GET", jobData, u, nil, nil)
+	return bbc.getRawData(context.Background(), "GET", jobData, u, nil, nil)
 }
 
-func (bbc *BlueButtonClient) makeBundleDataRequest(method string, u *url.URL, jobData worker_types.JobEnqueueArgs, headers http.Header, body io.Reader) (*fhirModels.Bundle, error) {
-	var b *fhirModels.Bundle
-	for ok := true; ok; {
-		result, nextURL, err := bbc.tryBundleRequest(method, u, jobData, headers, body)
+// streamBundleData requests the bundle at u and follows its next links, passing each page to fn.
+func (bbc *BlueButtonClient) streamBundleData(ctx context.Context, method string, u *url.URL, jobData worker_types.JobEnqueueArgs, headers http.Header, body io.Reader, fn PageHandler) error {
+	for u != nil {
+		page, nextURL, err := bbc.tryBundleRequest(ctx, method, u, jobData, headers, body)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err = fn(page); err != nil {
+			return err
 		}
+		u = nextURL
+	}
+
+	return nil
+}
 
+// collectPages combines every page streamed by stream into a single bundle.
+func collectPages(stream func(fn PageHandler) error) (*fhirModels.Bundle, error) {
+	var b *fhirModels.Bundle
+	err := stream(func(page *fhirModels.Bundle) error {
 		if b == nil {
-			b = result
+			b = page
 		} else {
-			b.Entries = append(b.Entries, result.Entries...)
+			b.Entries = append(b.Entries, page.Entries...)
 		}
-
-		u = nextURL
-		ok = nextURL != nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return b, nil
 }
 
-func (bbc *BlueButtonClient) tryBundleRequest(method string, u *url.URL, jobData worker_types.JobEnqueueArgs, headers http.Header, body io.Reader) (*fhirModels.Bundle, *url.URL, error) {
+func (bbc *BlueButtonClient) tryBundleRequest(ctx context.Context, method string, u *url.URL, jobData worker_types.JobEnqueueArgs, headers http.Header, body io.Reader) (*fhirModels.Bundle, *url.URL, error) {
 	var (
 		result  *fhirModels.Bundle
 		nextURL *url.URL
@@ -287,10 +362,17 @@ func (bbc *BlueButtonClient) tryBundleRequest(method string, u *url.URL, jobData
 
 	eb := backoff.NewExponentialBackOff()
 	eb.InitialInterval = bbc.retryInterval
-	b := backoff.WithMaxRetries(eb, bbc.maxTries)
+	b := backoff.WithContext(backoff.WithMaxRetries(eb, bbc.maxTries), ctx)
 
+	t := throttleFor(bbc.bbServer)
 	err = backoff.RetryNotify(func() error {
-		req, err := http.NewRequest(method, u.String(), body)
+		release, err := t.acquire(ctx)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		defer release()
+
+		req, err := http.NewRequestWithContext(tracing.Extract(ctx, jobData.TraceContext), method, u.String(), body)
 		if err != nil {
 			logger.Error(err)
 			return err
@@ -304,6 +386,7 @@ func (bbc *BlueButtonClient) tryBundleRequest(method string, u *url.URL, jobData
 		addDefaultRequestHeaders(req, uuid.NewRandom(), jobData)
 
 		result, nextURL, err = bbc.client.DoBundleRequest(req)
+		t.record(err)
 		if err != nil {
 			logger.Error(err)
 		}
@@ -315,22 +398,32 @@ func (bbc *BlueButtonClient) tryBundleRequest(method string, u *url.URL, jobData
 		},
 	)
 
+	if _, ok := goerrors.AsType[*CircuitOpenError](err); ok {
+		return nil, nil, err
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("blue button request failed %d time(s) %s", bbc.maxTries, err.Error())
+		return nil, nil, fmt.Errorf("blue button request failed %d time(s) %w", bbc.maxTries, err)
 	}
 
 	return result, nextURL, nil
 }
 
-func (bbc *BlueButtonClient) getRawData(method string, jobData worker_types.JobEnqueueArgs, u *url.URL, headers http.Header, body io.Reader) (string, error) {
+func (bbc *BlueButtonClient) getRawData(ctx context.Context, method string, jobData worker_types.JobEnqueueArgs, u *url.URL, headers http.Header, body io.Reader) (string, error) {
 	eb := backoff.NewExponentialBackOff()
 	eb.InitialInterval = bbc.retryInterval
-	b := backoff.WithMaxRetries(eb, bbc.maxTries)
+	b := backoff.WithContext(backoff.WithMaxRetries(eb, bbc.maxTries), ctx)
 
 	var result string
 
+	t := throttleFor(bbc.bbServer)
 	err := backoff.RetryNotify(func() error {
-		req, err := http.NewRequest(method, u.String(), body)
+		release, err := t.acquire(ctx)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		defer release()
+
+		req, err := http.NewRequestWithContext(tracing.Extract(ctx, jobData.TraceContext), method, u.String(), body)
 		if err != nil {
 			logger.Error(err)
 			return err
@@ -344,6 +437,7 @@ func (bbc *BlueButtonClient) getRawData(method string, jobData worker_types.JobE
 		addDefaultRequestHeaders(req, uuid.NewRandom(), jobData)
 
 		result, err = bbc.client.DoRaw(req)
+		t.record(err)
 		if err != nil {
 			logger.Error(err)
 		}
@@ -355,8 +449,11 @@ func (bbc *BlueButtonClient) getRawData(method string, jobData worker_types.JobE
 		},
 	)
 
+	if _, ok := goerrors.AsType[*CircuitOpenError](err); ok {
+		return "", err
+	}
 	if err != nil {
-		return "", fmt.Errorf("blue button request failed %d time(s) %s", bbc.maxTries, err.Error())
+		return "", fmt.Errorf("blue button request failed %d time(s) %w", bbc.maxTries, err)
 	}
 
 	return result, nil
@@ -563,7 +660,15 @@ type httpLogger struct {
 
 func (h *httpLogger) RoundTrip(req *http.Request) (*http.Response, error) {
 	go h.logRequest(req.Clone(context.Background()))
+	start := time.Now()
 	resp, err := h.t.RoundTrip(req)
+	latency := time.Since(start)
+	bfdStats.record(req.URL.Path, latency, resp, err)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	metrics.BFDRequest(basePathOf(req.URL.Path), status, latency)
 	if resp != nil {
 		h.logResponse(req, resp)
 	}
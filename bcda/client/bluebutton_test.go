@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -83,6 +84,11 @@ func (s *BBRequestTestSuite) BeforeTest(suiteName, testName string) {
 		s.ts = ts200
 	}
 
+	// Start each test with the circuit closed so failures from earlier tests don't short-circuit requests
+	throttlesMu.Lock()
+	delete(throttles, s.ts.URL)
+	throttlesMu.Unlock()
+
 	config := BlueButtonConfig{
 		BBServer: s.ts.URL,
 	}
@@ -240,7 +246,7 @@ func (s *BBTestSuite) TestGetDefaultParams() {
 
 func (s *BBRequestTestSuite) TestGetBBLogs() {
 	hook := test.NewLocal(testUtils.GetLogger(logger))
-	_, err := s.bbClient.GetPatient(jobData, "012345")
+	_, err := s.bbClient.GetPatient(context.Background(), jobData, "012345")
 	var logCMSID, logJobID, logTransID bool
 	for _, entry := range hook.AllEntries() {
 		test := entry.Data
@@ -263,64 +269,64 @@ func (s *BBRequestTestSuite) TestGetBBLogs() {
 
 /* Tests that make requests, using clients configured with the 200 response and 500 response httptest.Servers initialized in SetupSuite() */
 func (s *BBRequestTestSuite) TestGetPatient() {
-	p, err := s.bbClient.GetPatient(jobData, "012345")
+	p, err := s.bbClient.GetPatient(context.Background(), jobData, "012345")
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), 1, len(p.Entries))
 	assert.Equal(s.T(), "20000000000001", p.Entries[0]["resource"].(map[string]interface{})["id"])
 }
 
 func (s *BBRequestTestSuite) TestGetPatient_500() {
-	p, err := s.bbClient.GetPatient(jobData, "012345")
+	p, err := s.bbClient.GetPatient(context.Background(), jobData, "012345")
 	assert.Regexp(s.T(), `blue button request failed \d+ time\(s\) failed to get bundle response`, err.Error())
 	assert.Nil(s.T(), p)
 }
 
 func (s *BBRequestTestSuite) TestGetCoverage() {
-	c, err := s.bbClient.GetCoverage(jobData, "012345")
+	c, err := s.bbClient.GetCoverage(context.Background(), jobData, "012345")
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), 3, len(c.Entries))
 	assert.Equal(s.T(), "part-b-20000000000001", c.Entries[1]["resource"].(map[string]interface{})["id"])
 }
 
 func (s *BBRequestTestSuite) TestGetCoverage_500() {
-	c, err := s.bbClient.GetCoverage(jobData, "012345")
+	c, err := s.bbClient.GetCoverage(context.Background(), jobData, "012345")
 	assert.Regexp(s.T(), `blue button request failed \d+ time\(s\) failed to get bundle response`, err.Error())
 	assert.Nil(s.T(), c)
 }
 
 func (s *BBRequestTestSuite) TestGetExplanationOfBenefit() {
-	e, err := s.bbClient.GetExplanationOfBenefit(jobData, "012345", ClaimsWindow{})
+	e, err := s.bbClient.GetExplanationOfBenefit(context.Background(), jobData, "012345", ClaimsWindow{})
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), 33, len(e.Entries))
 	assert.Equal(s.T(), "carrier-10525061996", e.Entries[3]["resource"].(map[string]interface{})["id"])
 }
 
 func (s *BBRequestTestSuite) TestGetExplanationOfBenefit_500() {
-	e, err := s.bbClient.GetExplanationOfBenefit(jobData, "012345", ClaimsWindow{})
+	e, err := s.bbClient.GetExplanationOfBenefit(context.Background(), jobData, "012345", ClaimsWindow{})
 	assert.Regexp(s.T(), `blue button request failed \d+ time\(s\) failed to get bundle response`, err.Error())
 	assert.Nil(s.T(), e)
 }
 
 func (s *BBRequestTestSuite) TestGetClaim() {
-	e, err := s.bbClient.GetClaim(jobData, "1234567890hashed", ClaimsWindow{})
+	e, err := s.bbClient.GetClaim(context.Background(), jobData, "1234567890hashed", ClaimsWindow{})
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), 1, len(e.Entries))
 }
 
 func (s *BBRequestTestSuite) TestGetClaim_500() {
-	e, err := s.bbClient.GetClaim(jobData, "1234567890hashed", ClaimsWindow{})
+	e, err := s.bbClient.GetClaim(context.Background(), jobData, "1234567890hashed", ClaimsWindow{})
 	assert.Regexp(s.T(), `blue button request failed \d+ time\(s\) failed to get bundle response`, err.Error())
 	assert.Nil(s.T(), e)
 }
 
 func (s *BBRequestTestSuite) TestGetClaimResponse() {
-	e, err := s.bbClient.GetClaimResponse(jobData, "1234567890hashed", ClaimsWindow{})
+	e, err := s.bbClient.GetClaimResponse(context.Background(), jobData, "1234567890hashed", ClaimsWindow{})
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), 1, len(e.Entries))
 }
 
 func (s *BBRequestTestSuite) TestGetClaimResponse_500() {
-	e, err := s.bbClient.GetClaimResponse(jobData, "1234567890hashed", ClaimsWindow{})
+	e, err := s.bbClient.GetClaimResponse(context.Background(), jobData, "1234567890hashed", ClaimsWindow{})
 	assert.Regexp(s.T(), `blue button request failed \d+ time\(s\) failed to get bundle response`, err.Error())
 	assert.Nil(s.T(), e)
 }
@@ -338,7 +344,7 @@ func (s *BBRequestTestSuite) TestGetMetadata_500() {
 }
 
 func (s *BBRequestTestSuite) TestGetPatientByMbi() {
-	p, err := s.bbClient.GetPatientByMbi(worker_types.JobEnqueueArgs{}, "mbi")
+	p, err := s.bbClient.GetPatientByMbi(context.Background(), worker_types.JobEnqueueArgs{}, "mbi")
 	assert.Nil(s.T(), err)
 	assert.Contains(s.T(), p, `"id": "20000000000001"`)
 }
@@ -350,7 +356,7 @@ func (s *BBRequestTestSuite) TestGetPatientByMbi_500() {
 		ID:    1,
 		CMSID: "A0000",
 	}
-	p, err := s.bbClient.GetPatientByMbi(jobData, "mbi")
+	p, err := s.bbClient.GetPatientByMbi(context.Background(), jobData, "mbi")
 	entry := hook.AllEntries()
 	for _, t := range entry {
 		s.T().Log(t.Data)
@@ -396,7 +402,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetExplanationOfBenefit",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetExplanationOfBenefit(jobData, "patient1", ClaimsWindow{})
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobData, "patient1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -418,7 +424,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetExplanationOfBenefitNoSince",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetExplanationOfBenefit(jobDataNoSince, "patient1", ClaimsWindow{})
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobDataNoSince, "patient1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -440,7 +446,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetExplanationOfBenefitWithUpperBoundServiceDate",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetExplanationOfBenefit(jobData, "patient1", ClaimsWindow{UpperBound: claimsDate.UpperBound})
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobData, "patient1", ClaimsWindow{UpperBound: claimsDate.UpperBound})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -463,7 +469,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetExplanationOfBenefitWithLowerBoundServiceDate",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetExplanationOfBenefit(jobData, "patient1", ClaimsWindow{LowerBound: claimsDate.LowerBound})
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobData, "patient1", ClaimsWindow{LowerBound: claimsDate.LowerBound})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -486,7 +492,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetExplanationOfBenefitWithLowerAndUpperBoundServiceDate",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetExplanationOfBenefit(jobData, "patient1", claimsDate)
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobData, "patient1", claimsDate)
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -509,7 +515,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetPatient",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetPatient(jobData, "patient2")
+				return bbClient.GetPatient(context.Background(), jobData, "patient2")
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -530,7 +536,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetPatientNoSince",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetPatient(jobDataNoSince, "patient2")
+				return bbClient.GetPatient(context.Background(), jobDataNoSince, "patient2")
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -551,7 +557,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetCoverage",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetCoverage(jobData, "beneID1")
+				return bbClient.GetCoverage(context.Background(), jobData, "beneID1")
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -572,7 +578,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetCoverageNoSince",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetCoverage(jobDataNoSince, "beneID1")
+				return bbClient.GetCoverage(context.Background(), jobDataNoSince, "beneID1")
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -593,7 +599,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetPatientByMbi",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetPatientByMbi(worker_types.JobEnqueueArgs{}, "mbi")
+				return bbClient.GetPatientByMbi(context.Background(), worker_types.JobEnqueueArgs{}, "mbi")
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(string)
@@ -614,7 +620,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaim",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaim(jobData, "beneID1", ClaimsWindow{})
+				return bbClient.GetClaim(context.Background(), jobData, "beneID1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -630,7 +636,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimNoSinceChecker",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaim(jobDataNoSince, "beneID1", ClaimsWindow{})
+				return bbClient.GetClaim(context.Background(), jobDataNoSince, "beneID1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -646,7 +652,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimNoServiceDateUpperBound",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaim(jobData, "beneID1", ClaimsWindow{LowerBound: claimsDate.LowerBound})
+				return bbClient.GetClaim(context.Background(), jobData, "beneID1", ClaimsWindow{LowerBound: claimsDate.LowerBound})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -662,7 +668,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimNoServiceDateLowerBound",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaim(jobData, "beneID1", ClaimsWindow{UpperBound: claimsDate.UpperBound})
+				return bbClient.GetClaim(context.Background(), jobData, "beneID1", ClaimsWindow{UpperBound: claimsDate.UpperBound})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -678,7 +684,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimWithUpperAndLowerBoundServiceDate",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaim(jobData, "beneID1", claimsDate)
+				return bbClient.GetClaim(context.Background(), jobData, "beneID1", claimsDate)
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -694,7 +700,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimResponse",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaimResponse(jobData, "beneID1", ClaimsWindow{})
+				return bbClient.GetClaimResponse(context.Background(), jobData, "beneID1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -710,7 +716,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimResponseNoSinceChecker",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaimResponse(jobDataNoSince, "beneID1", ClaimsWindow{})
+				return bbClient.GetClaimResponse(context.Background(), jobDataNoSince, "beneID1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -726,7 +732,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimResponseNoServiceDateUpperBound",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaimResponse(jobData, "beneID1", ClaimsWindow{LowerBound: claimsDate.LowerBound})
+				return bbClient.GetClaimResponse(context.Background(), jobData, "beneID1", ClaimsWindow{LowerBound: claimsDate.LowerBound})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -742,7 +748,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimResponseNoServiceDateLowerBound",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaimResponse(jobData, "beneID1", ClaimsWindow{UpperBound: claimsDate.UpperBound})
+				return bbClient.GetClaimResponse(context.Background(), jobData, "beneID1", ClaimsWindow{UpperBound: claimsDate.UpperBound})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -758,7 +764,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetClaimResponseWithUpperAndLowerBoundServiceDate",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetClaimResponse(jobData, "beneID1", claimsDate)
+				return bbClient.GetClaimResponse(context.Background(), jobData, "beneID1", claimsDate)
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -775,7 +781,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 			"GetExplanationOfBenefitV3",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
 				bbClient.BBBasePath = constants.BFDV3Path
-				return bbClient.GetExplanationOfBenefit(jobDataWithTypeFilter, "patient1", ClaimsWindow{})
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobDataWithTypeFilter, "patient1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -796,7 +802,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 			"GetExplanationOfBenefitV3_WithClaimsWindow",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
 				bbClient.BBBasePath = constants.BFDV3Path
-				return bbClient.GetExplanationOfBenefit(jobDataWithTypeFilter, "patient1", claimsDate)
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobDataWithTypeFilter, "patient1", claimsDate)
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -816,7 +822,7 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 		{
 			"GetExplanationOfBenefitWithTypeFilterServiceDate",
 			func(bbClient *BlueButtonClient) (interface{}, error) {
-				return bbClient.GetExplanationOfBenefit(jobDataWithTypeFilter, "patient1", ClaimsWindow{})
+				return bbClient.GetExplanationOfBenefit(context.Background(), jobDataWithTypeFilter, "patient1", ClaimsWindow{})
 			},
 			func(t *testing.T, payload interface{}) {
 				result, ok := payload.(*fhirModels.Bundle)
@@ -833,6 +839,26 @@ func (s *BBRequestTestSuite) TestValidateRequest() {
 				hasBulkRequestHeaders,
 			},
 		},
+		{
+			"GetExplanationOfBenefitWithTraceContext",
+			func(bbClient *BlueButtonClient) (interface{}, error) {
+				tracedJobData := jobData
+				tracedJobData.TraceContext = map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+				return bbClient.GetExplanationOfBenefit(context.Background(), tracedJobData, "patient1", ClaimsWindow{})
+			},
+			func(t *testing.T, payload interface{}) {
+				result, ok := payload.(*fhirModels.Bundle)
+				assert.True(t, ok)
+				assert.NotEmpty(t, result.Entries)
+			},
+			[]func(*testing.T, *http.Request){
+				hasDefaultRequestHeaders,
+				hasBulkRequestHeaders,
+				func(t *testing.T, req *http.Request) {
+					assert.Contains(t, req.Header.Get("traceparent"), "4bf92f3577b34da6a3ce929d0e0e4736")
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1271,3 +1297,92 @@ func TestSetRestrictiveServiceDateWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestClaimsWindowForAttributionPeriod(t *testing.T) {
+	jan := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	dec := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		window     ClaimsWindow
+		start, end time.Time
+		expected   ClaimsWindow
+		empty      bool
+	}{
+		{"No attribution period", ClaimsWindow{LowerBound: jan, UpperBound: dec}, time.Time{}, time.Time{}, ClaimsWindow{LowerBound: jan, UpperBound: dec}, false},
+		{"Period within window", ClaimsWindow{LowerBound: jan, UpperBound: dec}, mar, jun, ClaimsWindow{LowerBound: mar, UpperBound: jun}, false},
+		{"Period wider than window", ClaimsWindow{LowerBound: mar, UpperBound: jun}, jan, dec, ClaimsWindow{LowerBound: mar, UpperBound: jun}, false},
+		{"Open window", ClaimsWindow{}, mar, jun, ClaimsWindow{LowerBound: mar, UpperBound: jun}, false},
+		{"Only an end date", ClaimsWindow{LowerBound: jan}, time.Time{}, jun, ClaimsWindow{LowerBound: jan, UpperBound: jun}, false},
+		{"Period ended before window", ClaimsWindow{LowerBound: jun, UpperBound: dec}, jan, mar, ClaimsWindow{LowerBound: jun, UpperBound: mar}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cw := tt.window.ForAttributionPeriod(tt.start, tt.end)
+			assert.Equal(t, tt.expected, cw)
+			assert.Equal(t, tt.empty, cw.IsEmpty())
+		})
+	}
+}
+
+// pagedFHIRClient serves pages in order, linking each to the next by its index.
+type pagedFHIRClient struct {
+	pages    []*fhirModels.Bundle
+	requests []string
+}
+
+func (c *pagedFHIRClient) DoBundleRequest(req *http.Request) (*fhirModels.Bundle, *url.URL, error) {
+	c.requests = append(c.requests, req.URL.String())
+	idx, _ := strconv.Atoi(req.URL.Query().Get("page"))
+	if idx+1 == len(c.pages) {
+		return c.pages[idx], nil, nil
+	}
+	next := *req.URL
+	next.RawQuery = url.Values{"page": {strconv.Itoa(idx + 1)}}.Encode()
+	return c.pages[idx], &next, nil
+}
+
+func (c *pagedFHIRClient) DoRaw(req *http.Request) (string, error) {
+	return "", fmt.Errorf("unexpected request %s", req.URL)
+}
+
+func TestStreamBundlePages(t *testing.T) {
+	page := func(ids ...string) *fhirModels.Bundle {
+		b := &fhirModels.Bundle{}
+		for _, id := range ids {
+			b.Entries = append(b.Entries, fhirModels.BundleEntry{"resource": map[string]interface{}{"id": id}})
+		}
+		return b
+	}
+	newClient := func() (*BlueButtonClient, *pagedFHIRClient) {
+		fc := &pagedFHIRClient{pages: []*fhirModels.Bundle{page("1", "2"), page("3"), page("4")}}
+		return &BlueButtonClient{client: fc, maxTries: 1, bbServer: "https://bfd.local", BBBasePath: constants.BFDV2Path}, fc
+	}
+
+	bbc, fc := newClient()
+	var sizes []int
+	err := bbc.StreamExplanationOfBenefit(context.Background(), jobData, "-1", ClaimsWindow{}, func(b *fhirModels.Bundle) error {
+		sizes = append(sizes, len(b.Entries))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 1, 1}, sizes)
+	assert.Len(t, fc.requests, 3)
+
+	// The Get methods still return every page in one bundle
+	bbc, _ = newClient()
+	b, err := bbc.GetExplanationOfBenefit(context.Background(), jobData, "-1", ClaimsWindow{})
+	assert.NoError(t, err)
+	assert.Len(t, b.Entries, 4)
+
+	// An error from the handler stops paging
+	bbc, fc = newClient()
+	err = bbc.StreamCoverage(context.Background(), jobData, "-1", func(b *fhirModels.Bundle) error {
+		return fmt.Errorf("disk full")
+	})
+	assert.EqualError(t, err, "disk full")
+	assert.Len(t, fc.requests, 1)
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	models "github.com/CMSgov/bcda-app/bcda/models/fhir"
 )
@@ -125,7 +126,7 @@ func getBundleResponse(c *http.Client, req *http.Request) (*models.Bundle, error
 func getResponse(c *http.Client, req *http.Request) (body []byte, err error) {
 	resp, err := c.Do(req) //#nosec G704
 	if err != nil {
-		return nil, fmt.Errorf("BFD request failed: %s", err)
+		return nil, fmt.Errorf("BFD request failed: %w", err)
 	}
 	if resp == nil {
 		return nil, fmt.Errorf("BFD response is empty")
@@ -137,8 +138,11 @@ func getResponse(c *http.Client, req *http.Request) (body []byte, err error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse response body %+v", err)
 		}
-		return nil, fmt.Errorf("received incorrect status code %d body %s",
-			resp.StatusCode, string(body))
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
 	body, err = io.ReadAll(resp.Body)
@@ -148,3 +152,29 @@ func getResponse(c *http.Client, req *http.Request) (body []byte, err error) {
 
 	return body, nil
 }
+
+// StatusError is returned when BFD responds with an error status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	// RetryAfter is how long BFD asked us to wait before trying again, or zero if it did not say
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("received incorrect status code %d body %s", e.StatusCode, e.Body)
+}
+
+// parseRetryAfter reads a Retry-After header given either as a number of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -12,6 +12,7 @@ import (
 	"path"
 	"strconv"
 	"testing"
+	"time"
 
 	models "github.com/CMSgov/bcda-app/bcda/models/fhir"
 	"github.com/stretchr/testify/assert"
@@ -153,3 +154,32 @@ func (rh *requestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+func TestStatusError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer s.Close()
+
+	client := NewClient(http.DefaultClient, 0)
+	req, err := http.NewRequest("GET", s.URL, nil)
+	assert.NoError(t, err)
+
+	_, _, err = client.DoBundleRequest(req)
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.Equal(t, 2*time.Minute, statusErr.RetryAfter)
+	assert.ErrorContains(t, err, "received incorrect status code 429 body slow down")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-5", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+}
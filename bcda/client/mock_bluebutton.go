@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -18,7 +19,7 @@ type MockBlueButtonClient struct {
 	MBI  *string
 }
 
-func (bbc *MockBlueButtonClient) GetExplanationOfBenefit(jobData worker_types.JobEnqueueArgs, patientID string, serviceDate ClaimsWindow) (*fhirModels.Bundle, error) {
+func (bbc *MockBlueButtonClient) GetExplanationOfBenefit(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, serviceDate ClaimsWindow) (*fhirModels.Bundle, error) {
 	args := bbc.Called(jobData, patientID, serviceDate)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -26,31 +27,62 @@ func (bbc *MockBlueButtonClient) GetExplanationOfBenefit(jobData worker_types.Jo
 	return args.Get(0).(*fhirModels.Bundle), args.Error(1)
 }
 
-func (bbc *MockBlueButtonClient) GetPatientByMbi(jobData worker_types.JobEnqueueArgs, mbi string) (string, error) {
+func (bbc *MockBlueButtonClient) GetPatientByMbi(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string) (string, error) {
 	args := bbc.Called(mbi)
 	return args.String(0), args.Error(1)
 }
 
-func (bbc *MockBlueButtonClient) GetPatient(jobData worker_types.JobEnqueueArgs, patientID string) (*fhirModels.Bundle, error) {
+func (bbc *MockBlueButtonClient) GetPatient(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string) (*fhirModels.Bundle, error) {
 	args := bbc.Called(jobData, patientID)
 	return args.Get(0).(*fhirModels.Bundle), args.Error(1)
 }
 
-func (bbc *MockBlueButtonClient) GetCoverage(jobData worker_types.JobEnqueueArgs, beneficiaryID string) (*fhirModels.Bundle, error) {
+func (bbc *MockBlueButtonClient) GetCoverage(ctx context.Context, jobData worker_types.JobEnqueueArgs, beneficiaryID string) (*fhirModels.Bundle, error) {
 	args := bbc.Called(jobData, beneficiaryID)
 	return args.Get(0).(*fhirModels.Bundle), args.Error(1)
 }
 
-func (bbc *MockBlueButtonClient) GetClaim(jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+func (bbc *MockBlueButtonClient) GetClaim(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
 	args := bbc.Called(jobData, mbi, claimsWindow)
 	return args.Get(0).(*fhirModels.Bundle), args.Error(1)
 }
 
-func (bbc *MockBlueButtonClient) GetClaimResponse(jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
+func (bbc *MockBlueButtonClient) GetClaimResponse(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow) (*fhirModels.Bundle, error) {
 	args := bbc.Called(jobData, mbi, claimsWindow)
 	return args.Get(0).(*fhirModels.Bundle), args.Error(1)
 }
 
+// The Stream methods return whatever bundle the matching Get expectation returns as a single page, so tests
+// only need to set up the Get calls.
+func (bbc *MockBlueButtonClient) StreamExplanationOfBenefit(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, claimsWindow ClaimsWindow, fn PageHandler) error {
+	return streamBundle(fn)(bbc.GetExplanationOfBenefit(ctx, jobData, patientID, claimsWindow))
+}
+
+func (bbc *MockBlueButtonClient) StreamPatient(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, fn PageHandler) error {
+	return streamBundle(fn)(bbc.GetPatient(ctx, jobData, patientID))
+}
+
+func (bbc *MockBlueButtonClient) StreamCoverage(ctx context.Context, jobData worker_types.JobEnqueueArgs, beneficiaryID string, fn PageHandler) error {
+	return streamBundle(fn)(bbc.GetCoverage(ctx, jobData, beneficiaryID))
+}
+
+func (bbc *MockBlueButtonClient) StreamClaim(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow, fn PageHandler) error {
+	return streamBundle(fn)(bbc.GetClaim(ctx, jobData, mbi, claimsWindow))
+}
+
+func (bbc *MockBlueButtonClient) StreamClaimResponse(ctx context.Context, jobData worker_types.JobEnqueueArgs, mbi string, claimsWindow ClaimsWindow, fn PageHandler) error {
+	return streamBundle(fn)(bbc.GetClaimResponse(ctx, jobData, mbi, claimsWindow))
+}
+
+func streamBundle(fn PageHandler) func(*fhirModels.Bundle, error) error {
+	return func(b *fhirModels.Bundle, err error) error {
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	}
+}
+
 // Returns copy of a static json file (From Blue Button Sandbox originally) after replacing the patient ID of 20000000000001 with the requested identifier
 // This is private in the real function and should remain so, but in the test client it makes maintenance easier to expose it.
 func (bbc *MockBlueButtonClient) GetData(endpoint, patientID string) (string, error) {
@@ -66,6 +98,8 @@ func (bbc *MockBlueButtonClient) GetData(endpoint, patientID string) (string, er
 	if bbc.MBI != nil {
 		// no longer hashed, but this is only a test file with synthetic test data
 		cleanData = strings.ReplaceAll(cleanData, "-1Q03Z002871", *bbc.MBI)
+		// MBI used by the partially adjudicated (Claim/ClaimResponse) files
+		cleanData = strings.ReplaceAll(cleanData, "ABC12345678", *bbc.MBI)
 	}
 	return cleanData, err
 }
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	statsWindow     = 5 * time.Minute
+	maxStatsSamples = 1000
+)
+
+// BFDStats summarizes the BFD requests made by this process to one base path over the last few minutes.
+type BFDStats struct {
+	BasePath    string     `json:"base_path"`
+	Requests    int        `json:"requests"`
+	ErrorRate   float64    `json:"error_rate"`
+	P50Ms       float64    `json:"p50_ms"`
+	P95Ms       float64    `json:"p95_ms"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+type requestSample struct {
+	at      time.Time
+	latency time.Duration
+	ok      bool
+}
+
+// requestStats keeps a rolling window of request samples per BFD base path. Samples come from real export and
+// health check traffic, so a BFD version that is slow or failing shows up without any extra calls to BFD.
+type requestStats struct {
+	mu          sync.Mutex
+	samples     map[string][]requestSample
+	lastSuccess map[string]time.Time
+	now         func() time.Time
+}
+
+var bfdStats = newRequestStats()
+
+func newRequestStats() *requestStats {
+	return &requestStats{
+		samples:     make(map[string][]requestSample),
+		lastSuccess: make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// BlueButtonStats returns the rolling request stats for each BFD base path this process has called, ordered by base path.
+func BlueButtonStats() []BFDStats {
+	return bfdStats.snapshot()
+}
+
+// record adds the outcome of a request. Transport errors, 429s and 5xx responses count as errors; other 4xx
+// responses mean BFD is answering and are counted as successes.
+func (s *requestStats) record(path string, latency time.Duration, resp *http.Response, err error) {
+	basePath := basePathOf(path)
+	if basePath == "" {
+		return
+	}
+	ok := err == nil && resp != nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	samples := s.expire(append(s.samples[basePath], requestSample{now, latency, ok}), now)
+	if len(samples) > maxStatsSamples {
+		samples = samples[len(samples)-maxStatsSamples:]
+	}
+	s.samples[basePath] = samples
+	if ok {
+		s.lastSuccess[basePath] = now
+	}
+}
+
+func (s *requestStats) snapshot() []BFDStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	stats := make([]BFDStats, 0, len(s.samples))
+	for basePath, samples := range s.samples {
+		samples = s.expire(samples, now)
+		s.samples[basePath] = samples
+
+		st := BFDStats{BasePath: basePath, Requests: len(samples)}
+		if last, ok := s.lastSuccess[basePath]; ok {
+			st.LastSuccess = &last
+		}
+		if len(samples) > 0 {
+			latencies := make([]time.Duration, len(samples))
+			failed := 0
+			for i, sample := range samples {
+				latencies[i] = sample.latency
+				if !sample.ok {
+					failed++
+				}
+			}
+			slices.Sort(latencies)
+			st.ErrorRate = float64(failed) / float64(len(samples))
+			st.P50Ms = percentile(latencies, 50)
+			st.P95Ms = percentile(latencies, 95)
+		}
+		stats = append(stats, st)
+	}
+
+	slices.SortFunc(stats, func(a, b BFDStats) int { return strings.Compare(a.BasePath, b.BasePath) })
+	return stats
+}
+
+// expire drops the samples that have aged out of the window. Samples are in the order they were recorded.
+func (s *requestStats) expire(samples []requestSample, now time.Time) []requestSample {
+	i := 0
+	for i < len(samples) && now.Sub(samples[i].at) > statsWindow {
+		i++
+	}
+	return samples[i:]
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies in milliseconds.
+func percentile(sorted []time.Duration, p int) float64 {
+	rank := max((p*len(sorted)+99)/100, 1)
+	return float64(sorted[rank-1].Microseconds()) / 1000
+}
+
+// basePathOf returns the BFD version prefix of a request path, e.g. /v2/fhir for /v2/fhir/Coverage/.
+func basePathOf(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	return "/" + parts[0] + "/" + parts[1]
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestStats(t *testing.T) {
+	now := time.Now()
+	s := newRequestStats()
+	s.now = func() time.Time { return now }
+
+	ok := &http.Response{StatusCode: http.StatusOK}
+	for i := 1; i <= 100; i++ {
+		s.record("/v2/fhir/ExplanationOfBenefit/", time.Duration(i)*time.Millisecond, ok, nil)
+	}
+	s.record("/v3/fhir/Coverage/", 10*time.Millisecond, ok, nil)
+	s.record("/v3/fhir/Coverage/", 20*time.Millisecond, &http.Response{StatusCode: http.StatusNotFound}, nil)
+	s.record("/v3/fhir/Coverage/", 30*time.Millisecond, &http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+	s.record("/v3/fhir/Coverage/", 40*time.Millisecond, nil, errors.New("connection refused"))
+	s.record("/", time.Millisecond, ok, nil)
+
+	stats := s.snapshot()
+	require.Len(t, stats, 2)
+	assert.Equal(t, BFDStats{BasePath: "/v2/fhir", Requests: 100, P50Ms: 50, P95Ms: 95, LastSuccess: &now}, stats[0])
+	assert.Equal(t, BFDStats{BasePath: "/v3/fhir", Requests: 4, ErrorRate: 0.5, P50Ms: 20, P95Ms: 40, LastSuccess: &now}, stats[1])
+
+	// Samples age out of the window, but the last success is kept
+	later := now.Add(statsWindow + time.Second)
+	s.now = func() time.Time { return later }
+	s.record("/v3/fhir/Coverage/", 5*time.Millisecond, &http.Response{StatusCode: http.StatusInternalServerError}, nil)
+	stats = s.snapshot()
+	require.Len(t, stats, 2)
+	assert.Equal(t, BFDStats{BasePath: "/v2/fhir", LastSuccess: &now}, stats[0])
+	assert.Equal(t, BFDStats{BasePath: "/v3/fhir", Requests: 1, ErrorRate: 1, P50Ms: 5, P95Ms: 5, LastSuccess: &now}, stats[1])
+}
+
+func TestRequestStatsMaxSamples(t *testing.T) {
+	s := newRequestStats()
+	for i := 0; i < maxStatsSamples+10; i++ {
+		s.record("/v2/fhir/Patient/", time.Millisecond, nil, errors.New("timeout"))
+	}
+	stats := s.snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, maxStatsSamples, stats[0].Requests)
+	assert.Nil(t, stats[0].LastSuccess)
+}
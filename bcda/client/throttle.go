@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/CMSgov/bcda-app/bcda/client/fhir"
+	"github.com/CMSgov/bcda-app/bcda/utils"
+)
+
+// CircuitOpenError is returned instead of making a request while BFD is considered unavailable.
+type CircuitOpenError struct {
+	// RetryAfter is how long until the circuit lets a request through to check whether BFD has recovered
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("BFD circuit breaker is open, retry in %s", e.RetryAfter)
+}
+
+// throttle is shared by every client of a BFD server in the process. It rate limits requests with a token
+// bucket that slows down when BFD responds with 429 and recovers as requests succeed, and it trips a circuit
+// breaker after consecutive server failures so jobs stop spending their retries against an unhealthy BFD.
+type throttle struct {
+	limiter *rate.Limiter
+	maxRate rate.Limit
+
+	// A threshold of 0 disables the circuit breaker
+	threshold int
+	cooldown  time.Duration
+
+	mu          sync.Mutex
+	failures    int
+	openUntil   time.Time
+	probing     bool
+	probes      int
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+var (
+	throttlesMu sync.Mutex
+	throttles   = make(map[string]*throttle)
+)
+
+// throttleFor returns the throttle for the BFD server, creating it from the environment on first use.
+func throttleFor(server string) *throttle {
+	throttlesMu.Lock()
+	defer throttlesMu.Unlock()
+
+	t, ok := throttles[server]
+	if !ok {
+		t = newThrottle(
+			utils.GetEnvFloat("BB_RATE_LIMIT_RPS", 0),
+			utils.GetEnvInt("BB_RATE_LIMIT_BURST", 10),
+			utils.GetEnvInt("BB_CIRCUIT_FAILURE_THRESHOLD", 20),
+			time.Duration(utils.GetEnvInt("BB_CIRCUIT_COOLDOWN_MS", 30000))*time.Millisecond,
+		)
+		throttles[server] = t
+	}
+	return t
+}
+
+// newThrottle creates a throttle allowing rps requests per second (unlimited when not positive).
+func newThrottle(rps float64, burst, threshold int, cooldown time.Duration) *throttle {
+	limit := rate.Inf
+	if rps > 0 {
+		limit = rate.Limit(rps)
+	}
+	return &throttle{
+		limiter:   rate.NewLimiter(limit, max(burst, 1)),
+		maxRate:   limit,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// CircuitOpen reports whether requests to the configured BFD server are currently being refused and, if so,
+// how long until the circuit lets a request through again.
+func CircuitOpen(config BlueButtonConfig) (time.Duration, bool) {
+	throttlesMu.Lock()
+	t, ok := throttles[config.BBServer]
+	throttlesMu.Unlock()
+	if !ok {
+		return 0, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if wait := t.openUntil.Sub(t.now()); wait > 0 {
+		return wait, true
+	}
+	return 0, false
+}
+
+// acquire waits until a request can be made. A *CircuitOpenError is returned if the circuit is open, or if it
+// is half-open and another request is already checking whether BFD has recovered. On success the caller must
+// call release once the request is finished so the check is handed to another request if this one never
+// reached record.
+func (t *throttle) acquire(ctx context.Context) (release func(), err error) {
+	t.mu.Lock()
+	now := t.now()
+	probe := 0
+	if !t.openUntil.IsZero() {
+		if wait := t.openUntil.Sub(now); wait > 0 {
+			t.mu.Unlock()
+			return nil, &CircuitOpenError{RetryAfter: wait}
+		}
+		if t.probing {
+			t.mu.Unlock()
+			return nil, &CircuitOpenError{RetryAfter: t.cooldown}
+		}
+		t.probing = true
+		t.probes++
+		probe = t.probes
+	}
+	pause := t.pausedUntil.Sub(now)
+	t.mu.Unlock()
+
+	release = func() {
+		if probe == 0 {
+			return
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.probes == probe {
+			t.probing = false
+		}
+	}
+
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if err := t.limiter.Wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// record updates the throttle with the outcome of a request.
+func (t *throttle) record(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var statusErr *fhir.StatusError
+	isStatusErr := goerrors.As(err, &statusErr)
+	switch {
+	case isStatusErr && statusErr.StatusCode == http.StatusTooManyRequests:
+		// BFD is up but we are sending too much, so back off without counting it against the circuit
+		t.closeCircuit()
+		wait := statusErr.RetryAfter
+		if wait <= 0 {
+			wait = time.Second
+		}
+		if until := t.now().Add(wait); until.After(t.pausedUntil) {
+			t.pausedUntil = until
+		}
+		if t.maxRate != rate.Inf {
+			t.limiter.SetLimit(max(t.limiter.Limit()/2, t.maxRate/10))
+		}
+		logger.Warnf("BFD rate limited requests, pausing for %s", wait)
+	case isServerFailure(err):
+		t.failures++
+		if t.threshold > 0 && (t.probing || t.failures >= t.threshold) {
+			t.openUntil = t.now().Add(t.cooldown)
+			t.probing = false
+			logger.Warnf("Opening BFD circuit breaker for %s after %d consecutive failures", t.cooldown, t.failures)
+		}
+	default:
+		t.closeCircuit()
+		if err == nil && t.limiter.Limit() < t.maxRate {
+			t.limiter.SetLimit(min(t.limiter.Limit()+t.maxRate/100, t.maxRate))
+		}
+	}
+}
+
+func (t *throttle) closeCircuit() {
+	if !t.openUntil.IsZero() {
+		logger.Info("Closing BFD circuit breaker")
+	}
+	t.failures = 0
+	t.openUntil = time.Time{}
+	t.probing = false
+}
+
+// isServerFailure reports whether err means BFD could not handle the request, as opposed to rejecting it.
+func isServerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *fhir.StatusError
+	if goerrors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	var urlErr *url.Error
+	return goerrors.As(err, &urlErr)
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/CMSgov/bcda-app/bcda/client/fhir"
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	fhirModels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcda/testUtils"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+func TestThrottleCircuitBreaker(t *testing.T) {
+	SetLogger(testUtils.GetLogger(logrus.StandardLogger()))
+	ctx := context.Background()
+	now := time.Now()
+	th := newThrottle(0, 1, 2, time.Minute)
+	th.now = func() time.Time { return now }
+
+	const server = "https://circuit.bfd.local"
+	throttlesMu.Lock()
+	throttles[server] = th
+	throttlesMu.Unlock()
+	defer func() {
+		throttlesMu.Lock()
+		delete(throttles, server)
+		throttlesMu.Unlock()
+	}()
+
+	// Client errors mean BFD is healthy, so they reset the failure count
+	th.record(&fhir.StatusError{StatusCode: http.StatusInternalServerError})
+	th.record(&fhir.StatusError{StatusCode: http.StatusNotFound})
+	th.record(&url.Error{Op: "Get", URL: server, Err: errors.New("connection refused")})
+	assert.NoError(t, acquireErr(th, ctx))
+	_, open := CircuitOpen(BlueButtonConfig{BBServer: server})
+	assert.False(t, open)
+
+	th.record(&fhir.StatusError{StatusCode: http.StatusBadGateway})
+	err := acquireErr(th, ctx)
+	circuitErr, ok := errors.AsType[*CircuitOpenError](err)
+	require.True(t, ok, "expected circuit to be open, got %v", err)
+	assert.Equal(t, time.Minute, circuitErr.RetryAfter)
+	wait, open := CircuitOpen(BlueButtonConfig{BBServer: server})
+	assert.True(t, open)
+	assert.Equal(t, time.Minute, wait)
+
+	// After the cooldown a single request checks whether BFD has recovered
+	now = now.Add(time.Minute)
+	assert.NoError(t, acquireErr(th, ctx))
+	_, ok = errors.AsType[*CircuitOpenError](acquireErr(th, ctx))
+	assert.True(t, ok)
+
+	// A failed check opens the circuit again immediately
+	th.record(&fhir.StatusError{StatusCode: http.StatusServiceUnavailable})
+	_, ok = errors.AsType[*CircuitOpenError](acquireErr(th, ctx))
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, acquireErr(th, ctx))
+	th.record(nil)
+	assert.NoError(t, acquireErr(th, ctx))
+	assert.NoError(t, acquireErr(th, ctx))
+	_, open = CircuitOpen(BlueButtonConfig{BBServer: server})
+	assert.False(t, open)
+}
+
+func TestThrottleProbeReleased(t *testing.T) {
+	SetLogger(testUtils.GetLogger(logrus.StandardLogger()))
+	now := time.Now()
+	th := newThrottle(0, 1, 1, time.Minute)
+	th.now = func() time.Time { return now }
+
+	th.record(&fhir.StatusError{StatusCode: http.StatusInternalServerError})
+	now = now.Add(time.Minute)
+
+	// A check that ends without a response lets the next request check instead
+	release, err := th.acquire(context.Background())
+	require.NoError(t, err)
+	_, ok := errors.AsType[*CircuitOpenError](acquireErr(th, context.Background()))
+	assert.True(t, ok)
+	release()
+	release, err = th.acquire(context.Background())
+	require.NoError(t, err)
+
+	// Releasing a check that has already been recorded does not affect a later check
+	th.record(&fhir.StatusError{StatusCode: http.StatusInternalServerError})
+	now = now.Add(time.Minute)
+	assert.NoError(t, acquireErr(th, context.Background()))
+	release()
+	_, ok = errors.AsType[*CircuitOpenError](acquireErr(th, context.Background()))
+	assert.True(t, ok)
+
+	// A check whose context is cancelled while waiting is released
+	th.record(&fhir.StatusError{StatusCode: http.StatusInternalServerError})
+	th.record(&fhir.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute})
+	th.openUntil = now
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, acquireErr(th, ctx), context.Canceled)
+	th.pausedUntil = time.Time{}
+	assert.NoError(t, acquireErr(th, context.Background()))
+}
+
+func TestThrottleCircuitBreakerDisabled(t *testing.T) {
+	th := newThrottle(0, 1, 0, time.Minute)
+	for range 100 {
+		th.record(&fhir.StatusError{StatusCode: http.StatusInternalServerError})
+	}
+	assert.NoError(t, acquireErr(th, context.Background()))
+}
+
+func TestThrottleRateLimited(t *testing.T) {
+	SetLogger(testUtils.GetLogger(logrus.StandardLogger()))
+	th := newThrottle(100, 1, 2, time.Minute)
+
+	th.record(&fhir.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 50 * time.Millisecond})
+	assert.Equal(t, rate.Limit(50), th.limiter.Limit())
+
+	start := time.Now()
+	assert.NoError(t, acquireErr(th, context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	// Repeated rate limiting slows down to a floor of a tenth of the configured rate
+	for range 10 {
+		th.record(&fhir.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Nanosecond})
+	}
+	assert.Equal(t, rate.Limit(10), th.limiter.Limit())
+
+	// Successful requests gradually restore the rate
+	th.record(nil)
+	assert.Equal(t, rate.Limit(11), th.limiter.Limit())
+	for range 200 {
+		th.record(nil)
+	}
+	assert.Equal(t, rate.Limit(100), th.limiter.Limit())
+
+	// Rate limiting is not counted as a failure
+	th.record(&fhir.StatusError{StatusCode: http.StatusInternalServerError})
+	th.record(&fhir.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Nanosecond})
+	th.record(&fhir.StatusError{StatusCode: http.StatusInternalServerError})
+	assert.NoError(t, acquireErr(th, context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	th.record(&fhir.StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute})
+	assert.ErrorIs(t, acquireErr(th, ctx), context.Canceled)
+}
+
+// acquireErr acquires th without releasing it, returning only the error.
+func acquireErr(th *throttle, ctx context.Context) error {
+	_, err := th.acquire(ctx)
+	return err
+}
+
+// failingFHIRClient fails every request with the same error.
+type failingFHIRClient struct {
+	err      error
+	requests int
+}
+
+func (c *failingFHIRClient) DoBundleRequest(req *http.Request) (*fhirModels.Bundle, *url.URL, error) {
+	c.requests++
+	return nil, nil, c.err
+}
+
+func (c *failingFHIRClient) DoRaw(req *http.Request) (string, error) {
+	c.requests++
+	return "", c.err
+}
+
+func TestBlueButtonRequestCircuitOpen(t *testing.T) {
+	SetLogger(testUtils.GetLogger(logrus.StandardLogger()))
+	origThreshold := conf.GetEnv("BB_CIRCUIT_FAILURE_THRESHOLD")
+	defer conf.SetEnv(t, "BB_CIRCUIT_FAILURE_THRESHOLD", origThreshold)
+	conf.SetEnv(t, "BB_CIRCUIT_FAILURE_THRESHOLD", "3")
+
+	const server = "https://unavailable.bfd.local"
+	defer func() {
+		throttlesMu.Lock()
+		delete(throttles, server)
+		throttlesMu.Unlock()
+	}()
+
+	fc := &failingFHIRClient{err: &fhir.StatusError{StatusCode: http.StatusServiceUnavailable}}
+	bbc := &BlueButtonClient{client: fc, maxTries: 1, retryInterval: time.Millisecond, bbServer: server, BBBasePath: constants.BFDV2Path}
+
+	// The first request uses both of its tries and the failure is reported as before
+	_, err := bbc.GetCoverage(context.Background(), jobData, "-1")
+	assert.Regexp(t, `blue button request failed \d+ time\(s\) received incorrect status code 503`, err)
+	var statusErr *fhir.StatusError
+	assert.ErrorAs(t, err, &statusErr)
+
+	// The third failure opens the circuit, so the retry is not attempted
+	_, err = bbc.GetPatientByMbi(context.Background(), jobData, "1S00E00AA00")
+	_, ok := errors.AsType[*CircuitOpenError](err)
+	assert.True(t, ok, "expected circuit to be open, got %v", err)
+	assert.Equal(t, 3, fc.requests)
+
+	_, err = bbc.GetCoverage(context.Background(), jobData, "-1")
+	_, ok = errors.AsType[*CircuitOpenError](err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, fc.requests)
+}
@@ -9,6 +9,10 @@ const ImportInprog = "In-Progress"
 const ImportComplete = "Completed"
 const ImportFail = "Failed"
 
+// Statuses an operator can assign to a completed attribution file to withdraw it from use
+const ImportSuperseded = "Superseded"
+const ImportInvalid = "Invalid"
+
 // This is set during compilation. See dockerfiles for usage
 var Version = "latest"
 
@@ -22,6 +26,7 @@ const CliArchDesc = "How long files should wait in archive before deletion"
 const CliRemoveArchDesc = "Remove job directory and files from archive and update job status to Expired"
 const CliAuthToolsCategory = "Authentication tools"
 const CliDataImpCategory = "Data import"
+const CliReportingCategory = "Reporting"
 
 const ContentType = "Content-Type"
 const JsonContentType = "application/json"
@@ -39,6 +44,7 @@ const JobKeyCreateErr = "failed to create job key: %w"
 const JOBIDPath = "/jobs/{jobID}"
 
 const IssuerSSAS = "ssas"
+const IssuerLocalAuth = "bcda-local-auth"
 
 const EmptyString = ""
 
@@ -65,4 +71,9 @@ const SmartOAuthURIsExtensionURL = "http://fhir-registry.smarthealthit.org/Struc
 const RestfulSecurityServiceSystem = "http://terminology.hl7.org/CodeSystem/restful-security-service"
 const BFDSystemTypeURL = "https://bluebutton.cms.gov/fhir/CodeSystem/System-Type"
 const BFDFinalActionURL = "https://bluebutton.cms.gov/fhir/CodeSystem/Final-Action"
+const AuditEventTypeSystem = "http://terminology.hl7.org/CodeSystem/audit-event-type"
+const AuditEventDICOMSystem = "http://dicom.nema.org/resources/ontology/DCM"
+const RestfulInteractionSystem = "http://hl7.org/fhir/restful-interaction"
+const FHIRResourceTypesSystem = "http://hl7.org/fhir/resource-types"
+const MBISystem = "http://hl7.org/fhir/sid/us-mbi"
 const WarningsAndInfoFileName = "warnings-and-info.ndjson"
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/sirupsen/logrus"
+
+	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
+	"github.com/CMSgov/bcda-app/bcda/client"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+// Stats from a worker that hasn't reported for this long are left out of the health check and deleted, e.g. after
+// it was replaced
+const bfdStatsTTL = 10 * time.Minute
+
+// WorkerBFDStats are the BFD request stats most recently reported by one worker.
+type WorkerBFDStats struct {
+	Instance string `json:"instance"`
+	client.BFDStats
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReportBlueButtonStats saves this worker's current BFD request stats so the API health check can show them,
+// logs them, and publishes them to CloudWatch when cw is set. Stats no longer reported by any worker are deleted.
+func ReportBlueButtonStats(ctx context.Context, db *sql.DB, cw bcdaaws.CustomCloudwatchClient, instance, environment string) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM bfd_health_stats WHERE updated_at <= $1`, time.Now().Add(-bfdStatsTTL)); err != nil {
+		return err
+	}
+
+	for _, st := range client.BlueButtonStats() {
+		log.Health.WithFields(logrus.Fields{
+			"type":       "bfd_stats",
+			"instance":   instance,
+			"base_path":  st.BasePath,
+			"requests":   st.Requests,
+			"error_rate": st.ErrorRate,
+			"p50_ms":     st.P50Ms,
+			"p95_ms":     st.P95Ms,
+		}).Info()
+
+		var lastSuccess sql.NullTime
+		if st.LastSuccess != nil {
+			lastSuccess = sql.NullTime{Time: *st.LastSuccess, Valid: true}
+		}
+		_, err := db.ExecContext(ctx, `INSERT INTO bfd_health_stats (instance, base_path, requests, error_rate, p50_ms, p95_ms, last_success, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, now())
+			ON CONFLICT (instance, base_path) DO UPDATE SET requests = EXCLUDED.requests, error_rate = EXCLUDED.error_rate,
+			p50_ms = EXCLUDED.p50_ms, p95_ms = EXCLUDED.p95_ms, last_success = EXCLUDED.last_success, updated_at = EXCLUDED.updated_at`,
+			instance, st.BasePath, st.Requests, st.ErrorRate, st.P50Ms, st.P95Ms, lastSuccess)
+		if err != nil {
+			return err
+		}
+
+		if cw == nil || st.Requests == 0 {
+			continue
+		}
+		dims := []types.Dimension{
+			{Name: aws.String("Environment"), Value: aws.String(environment)},
+			{Name: aws.String("BasePath"), Value: aws.String(st.BasePath)},
+		}
+		metrics := []struct {
+			name  string
+			unit  types.StandardUnit
+			value float64
+		}{
+			{"BFDErrorRate", types.StandardUnitPercent, st.ErrorRate * 100},
+			{"BFDLatencyP50", types.StandardUnitMilliseconds, st.P50Ms},
+			{"BFDLatencyP95", types.StandardUnitMilliseconds, st.P95Ms},
+		}
+		for _, m := range metrics {
+			if err := bcdaaws.PutMetricSample(ctx, cw, "BCDA", m.name, m.unit, m.value, dims); err != nil {
+				log.Health.Errorf("Failed to publish %s metric: %s", m.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// BlueButtonStats returns the BFD request stats reported by the workers, by worker and base path.
+func (h healthCheck) BlueButtonStats() ([]WorkerBFDStats, error) {
+	rows, err := h.db.Query(`SELECT instance, base_path, requests, error_rate, p50_ms, p95_ms, last_success, updated_at
+		FROM bfd_health_stats WHERE updated_at > $1 ORDER BY base_path, instance`, time.Now().Add(-bfdStatsTTL))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []WorkerBFDStats{}
+	for rows.Next() {
+		var (
+			st          WorkerBFDStats
+			lastSuccess sql.NullTime
+		)
+		if err := rows.Scan(&st.Instance, &st.BasePath, &st.Requests, &st.ErrorRate, &st.P50Ms, &st.P95Ms, &lastSuccess, &st.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if lastSuccess.Valid {
+			st.LastSuccess = &lastSuccess.Time
+		}
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,26 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportBlueButtonStatsPrunesStaleStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM bfd_health_stats WHERE updated_at <=").WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, ReportBlueButtonStats(context.Background(), db, nil, "worker-1", "local"))
+
+	mock.ExpectExec("DELETE FROM bfd_health_stats").WillReturnError(errors.New("connection reset"))
+	assert.EqualError(t, ReportBlueButtonStats(context.Background(), db, nil, "worker-1", "local"), "connection reset")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
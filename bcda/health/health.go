@@ -29,6 +29,7 @@ type HealthChecker interface {
 	IsBlueButtonOK() bool
 	IsSsasOK() (string, bool)
 	IsSsasIntrospectOK() (string, bool)
+	BlueButtonStats() ([]WorkerBFDStats, error)
 }
 
 type healthCheck struct {
@@ -35,6 +35,61 @@ func (_m *MockHealthChecker) EXPECT() *MockHealthChecker_Expecter {
 	return &MockHealthChecker_Expecter{mock: &_m.Mock}
 }
 
+// BlueButtonStats provides a mock function for the type MockHealthChecker
+func (_mock *MockHealthChecker) BlueButtonStats() ([]WorkerBFDStats, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for BlueButtonStats")
+	}
+
+	var r0 []WorkerBFDStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() ([]WorkerBFDStats, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() []WorkerBFDStats); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]WorkerBFDStats)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockHealthChecker_BlueButtonStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BlueButtonStats'
+type MockHealthChecker_BlueButtonStats_Call struct {
+	*mock.Call
+}
+
+// BlueButtonStats is a helper method to define mock.On call
+func (_e *MockHealthChecker_Expecter) BlueButtonStats() *MockHealthChecker_BlueButtonStats_Call {
+	return &MockHealthChecker_BlueButtonStats_Call{Call: _e.mock.On("BlueButtonStats")}
+}
+
+func (_c *MockHealthChecker_BlueButtonStats_Call) Run(run func()) *MockHealthChecker_BlueButtonStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockHealthChecker_BlueButtonStats_Call) Return(workerBFDStatss []WorkerBFDStats, err error) *MockHealthChecker_BlueButtonStats_Call {
+	_c.Call.Return(workerBFDStatss, err)
+	return _c
+}
+
+func (_c *MockHealthChecker_BlueButtonStats_Call) RunAndReturn(run func() ([]WorkerBFDStats, error)) *MockHealthChecker_BlueButtonStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsBlueButtonOK provides a mock function for the type MockHealthChecker
 func (_mock *MockHealthChecker) IsBlueButtonOK() bool {
 	ret := _mock.Called()
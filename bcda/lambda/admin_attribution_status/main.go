@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/CMSgov/bcda-app/conf"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/slack-go/slack"
+
+	ai "github.com/CMSgov/bcda-app/bcda/attribution-import"
+	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
+	"github.com/CMSgov/bcda-app/bcda/database"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/models/postgres"
+	msgr "github.com/CMSgov/bcda-app/bcda/slackmessenger"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type payload struct {
+	FileID   uint   `json:"file_id"`
+	Status   string `json:"status"` // superseded, invalid or completed
+	Reason   string `json:"reason"`
+	Operator string `json:"operator"`
+}
+
+func main() {
+	lambda.Start(handler)
+}
+
+func handler(ctx context.Context, event json.RawMessage) error {
+	log.SetFormatter(&log.JSONFormatter{
+		DisableHTMLEscape: true,
+		TimestampFormat:   time.RFC3339Nano,
+	})
+	log.Info("Starting Attribution Status administrative task")
+
+	var data payload
+	err := json.Unmarshal(event, &data)
+	if err != nil {
+		log.Errorf("Failed to unmarshal event: %v", err)
+		return err
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Errorf("Failed to load default config: %+v", err)
+		return err
+	}
+	ssmClient := ssm.NewFromConfig(cfg)
+
+	slackToken, err := setupEnv(ctx, ssmClient)
+	if err != nil {
+		log.Errorf("Failed to retrieve parameter: %+v", err)
+		return err
+	}
+
+	slackClient := slack.New(slackToken)
+	db := database.Connect()
+	defer db.Close()
+
+	change, err := handleStatusChange(ctx, postgres.NewRepository(db), data)
+	if err != nil {
+		msgr.SendSlackMessage(slackClient, msgr.OperationsChannel, fmt.Sprintf("%s: Attribution Status lambda in %s env.", msgr.FailureMsg, os.Getenv("ENV")), msgr.Danger)
+		log.Errorf("Failed to update attribution file status: %+v", err)
+		return err
+	}
+
+	msgr.SendSlackMessage(slackClient, msgr.OperationsChannel, fmt.Sprintf("%s: Attribution Status lambda in %s env. File %d changed from %s to %s by %s.",
+		msgr.SuccessMsg, os.Getenv("ENV"), change.FileID, change.PreviousStatus, change.Status, change.ChangedBy), msgr.Good)
+	log.Info("Completed Attribution Status administrative task")
+
+	return nil
+}
+
+func handleStatusChange(ctx context.Context, r models.Repository, data payload) (*models.CCLFFileStatusChange, error) {
+	change, err := ai.UpdateFileStatus(ctx, r, data.FileID, data.Status, data.Reason, data.Operator)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"file_id":         change.FileID,
+		"previous_status": change.PreviousStatus,
+		"status":          change.Status,
+		"reason":          change.Reason,
+		"changed_by":      change.ChangedBy,
+	}).Info("Updated attribution file status")
+
+	return change, nil
+}
+
+func setupEnv(ctx context.Context, client bcdaaws.CustomSSMClient) (string, error) {
+	env := conf.GetEnv("ENV")
+
+	slackParamName := "/slack/token/workflow-alerts"
+	dbURLName := fmt.Sprintf("/bcda/%s/sensitive/api/DATABASE_URL", env)
+	params, err := bcdaaws.GetParameters(ctx, client, []string{slackParamName, dbURLName})
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Setenv("DATABASE_URL", params[dbURLName])
+	if err != nil {
+		log.Errorf("Error setting dbURLName env var: %+v", err)
+		return "", err
+	}
+
+	return params[slackParamName], nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
+)
+
+func TestHandleStatusChange(t *testing.T) {
+	ctx := context.Background()
+	repository := models.NewMockRepository(t)
+	expected := &models.CCLFFileStatusChange{ID: 3, FileID: 12, PreviousStatus: constants.ImportComplete, Status: constants.ImportSuperseded, Reason: "resent", ChangedBy: "jdoe"}
+	repository.On("UpdateCCLFFileStatus", ctx, uint(12), constants.ImportSuperseded, "resent", "jdoe").Return(expected, nil)
+
+	change, err := handleStatusChange(ctx, repository, payload{FileID: 12, Status: "superseded", Reason: "resent", Operator: "jdoe"})
+	require.NoError(t, err)
+	assert.Equal(t, expected, change)
+
+	_, err = handleStatusChange(ctx, repository, payload{FileID: 12, Status: "deleted", Reason: "resent", Operator: "jdoe"})
+	assert.ErrorContains(t, err, "unsupported attribution file status")
+}
@@ -74,7 +74,11 @@ func handler(ctx context.Context, event json.RawMessage) (string, error) {
 		return "", err
 	}
 
-	provider := auth.NewProvider(database.Connect())
+	provider, err := auth.NewProvider(database.Connect())
+	if err != nil {
+		log.Errorf("Unable to set up auth provider: %+v", err)
+		return "", err
+	}
 
 	s3Service := s3.NewFromConfig(cfg)
 	slackClient := slack.New(params.slackToken)
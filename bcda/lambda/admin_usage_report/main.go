@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/CMSgov/bcda-app/conf"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+
+	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
+	"github.com/CMSgov/bcda-app/bcda/database"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/models/postgres"
+	"github.com/CMSgov/bcda-app/bcda/service"
+	"github.com/CMSgov/bcda-app/bcda/usagereport"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type payload struct {
+	Start  string `json:"start"`  // first day of the report, YYYY-MM-DD
+	End    string `json:"end"`    // last day of the report, inclusive
+	Format string `json:"format"` // csv (default) or json
+}
+
+func main() {
+	lambda.Start(handler)
+}
+
+func handler(ctx context.Context, event json.RawMessage) (string, error) {
+	log.SetFormatter(&log.JSONFormatter{
+		DisableHTMLEscape: true,
+		TimestampFormat:   time.RFC3339Nano,
+	})
+	log.Info("Starting Usage Report administrative task")
+
+	var data payload
+	err := json.Unmarshal(event, &data)
+	if err != nil {
+		log.Errorf("Failed to unmarshal event: %v", err)
+		return "", err
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		log.Errorf("Failed to load default config: %+v", err)
+		return "", err
+	}
+
+	err = setupEnv(ctx, ssm.NewFromConfig(cfg))
+	if err != nil {
+		log.Errorf("Failed to retrieve parameter: %+v", err)
+		return "", err
+	}
+
+	svcCfg, err := service.LoadConfig()
+	if err != nil {
+		log.Errorf("Failed to load service config: %+v", err)
+		return "", err
+	}
+
+	db := database.Connect()
+	defer db.Close()
+	repository := postgres.NewRepository(db)
+
+	report, err := handleUsageReport(ctx, repository, service.NewService(repository, svcCfg, ""), data, time.Now())
+	if err != nil {
+		log.Errorf("Failed to generate usage report: %+v", err)
+		return "", err
+	}
+
+	log.Info("Completed Usage Report administrative task")
+
+	return report, nil
+}
+
+func handleUsageReport(ctx context.Context, r models.Repository, svc service.Service, data payload, now time.Time) (string, error) {
+	if data.Format == "" {
+		data.Format = usagereport.FormatCSV
+	}
+	if !usagereport.ValidFormat(data.Format) {
+		return "", fmt.Errorf("format must be one of %s, %s", usagereport.FormatCSV, usagereport.FormatJSON)
+	}
+	start, end, err := usagereport.ParsePeriod(data.Start, data.End, now)
+	if err != nil {
+		return "", err
+	}
+
+	report, err := usagereport.Generate(ctx, r, svc, start, end)
+	if err != nil {
+		return "", err
+	}
+
+	log.WithFields(log.Fields{
+		"start": start,
+		"end":   end,
+		"acos":  len(report.ACOs),
+	}).Info("Generated usage report")
+
+	var buf bytes.Buffer
+	if err := report.Write(&buf, data.Format); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func setupEnv(ctx context.Context, client bcdaaws.CustomSSMClient) error {
+	env := conf.GetEnv("ENV")
+
+	dbURLName := fmt.Sprintf("/bcda/%s/sensitive/api/DATABASE_URL", env)
+	params, err := bcdaaws.GetParameters(ctx, client, []string{dbURLName})
+	if err != nil {
+		return err
+	}
+
+	err = os.Setenv("DATABASE_URL", params[dbURLName])
+	if err != nil {
+		log.Errorf("Error setting dbURLName env var: %+v", err)
+		return err
+	}
+
+	return nil
+}
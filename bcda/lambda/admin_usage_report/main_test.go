@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+func TestHandleUsageReport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	repository := models.NewMockRepository(t)
+	repository.On("GetACOUsage", ctx, start, end).Return([]*models.ACOUsage{
+		{CMSID: "A9994", Jobs: map[models.JobStatus]int{models.JobStatusCompleted: 2}, ResourceTypes: map[string]int{"Patient": 2}, Downloads: 2, BytesDownloaded: 512},
+	}, nil)
+	svc := service.NewMockService(t)
+	svc.On("GetACOConfigForID", "A9994").Return(&service.ACOConfig{Model: "SSP"}, true)
+
+	report, err := handleUsageReport(ctx, repository, svc, payload{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "cms_id,model,jobs,completed_jobs,failed_jobs,failure_rate,resource_types,downloads,bytes_downloaded\n"+
+		"A9994,SSP,2,2,0,0.0000,Patient:2,2,512\n", report)
+
+	report, err = handleUsageReport(ctx, repository, svc, payload{Start: "2026-02-01", End: "2026-02-28", Format: "json"}, now)
+	require.NoError(t, err)
+	assert.Contains(t, report, `"cms_id": "A9994"`)
+
+	_, err = handleUsageReport(ctx, repository, svc, payload{Format: "xml"}, now)
+	assert.ErrorContains(t, err, "format must be one of")
+	_, err = handleUsageReport(ctx, repository, svc, payload{Start: "2026-02-01"}, now)
+	assert.ErrorContains(t, err, "both start and end dates are required")
+}
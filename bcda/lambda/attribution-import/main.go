@@ -17,6 +17,7 @@ import (
 	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
 	bp "github.com/CMSgov/bcda-app/bcda/bene-prefs"
 	"github.com/CMSgov/bcda-app/bcda/database"
+	"github.com/CMSgov/bcda-app/bcda/utils"
 
 	"github.com/CMSgov/bcda-app/conf"
 
@@ -84,6 +85,8 @@ func attributionImportHandler(ctx context.Context, sqsEvent events.SQSEvent) (st
 			if err != nil {
 				logger.Errorf("error checking if file is CSV: %v", err)
 				return "", err
+			} else if utils.GetEnvBool("ATTRIBUTION_VALIDATE_ONLY", false) {
+				return handleValidation(ctx, pool, s3Client, filepath, isCSV)
 			} else if isCSV {
 				return handleCSVImport(ctx, pool, s3Client, filepath)
 			} else {
@@ -159,6 +162,50 @@ func handleCclfImport(ctx context.Context, pool *pgxpool.Pool, s3Client bcdaaws.
 	return result, nil
 }
 
+// handleValidation performs a dry run of the import for the file, logging the validation report instead of
+// writing to the database or cleaning up the file.
+func handleValidation(ctx context.Context, pool *pgxpool.Pool, s3Client bcdaaws.CustomS3Client, s3ImportPath string, isCSV bool) (string, error) {
+	env := conf.GetEnv("ENV")
+	appName := conf.GetEnv("APP_NAME")
+	logger := configureLogger(env, appName)
+	logger = logger.WithFields(logrus.Fields{"import_filename": s3ImportPath})
+
+	fileProcessor := &ai.S3FileProcessor{
+		Handler: bp.S3FileHandler{
+			Client: s3Client,
+			Logger: logger,
+		},
+	}
+
+	var (
+		report ai.ValidationReport
+		err    error
+	)
+	if isCSV {
+		importer := ai.CSVImporter{Logger: logger, PgxPool: pool, FileProcessor: fileProcessor}
+		report, err = importer.ValidateCSV(ctx, s3ImportPath)
+	} else {
+		importer := ai.NewCclfImporter(logger, fileProcessor, pool)
+		report, err = importer.ValidateCCLFDirectory(ctx, s3ImportPath)
+	}
+	if err != nil {
+		logger.Error("error returned from attribution validation: ", err)
+		return "", err
+	}
+
+	// Issue messages can contain MBIs, so only the summary is logged
+	logger = logger.WithFields(logrus.Fields{"validation_summary": report.Summary()})
+	if !report.Valid() {
+		result := fmt.Sprintf("Attribution validation failed for %v.  See the validation summary for the lines with errors.", s3ImportPath)
+		logger.Error(result)
+		return result, errors.New("attribution file failed validation")
+	}
+
+	result := fmt.Sprintf("Attribution validation passed for %v.", s3ImportPath)
+	logger.Info(result)
+	return result, nil
+}
+
 func loadBCDAParams() error {
 	env := conf.GetEnv("ENV")
 	conf.LoadLambdaEnvVars(env)
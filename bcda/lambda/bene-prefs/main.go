@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/sirupsen/logrus"
+	"github.com/slack-go/slack"
 
 	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
 	bp "github.com/CMSgov/bcda-app/bcda/bene-prefs"
@@ -76,14 +77,25 @@ func optOutImportHandler(ctx context.Context, sqsEvent events.SQSEvent) (string,
 		return "", err
 	}
 
+	slackToken, err := bcdaaws.GetParameter(ctx, ssmClient, "/slack/token/workflow-alerts")
+	if err != nil {
+		logger.Errorf("error getting slack token param: %+v", err)
+		return "", err
+	}
+
 	db := database.Connect()
 	repo := postgres.NewRepository(db)
 
 	for _, e := range s3Event.Records {
 		if strings.Contains(e.EventName, "ObjectCreated") {
+			// Quarantining a file writes it back to the bucket, which must not trigger another import
+			if isQuarantined(e.S3.Object.Key) {
+				logger.Infof("Skipping %s event for quarantined file %s", e.EventName, e.S3.Object.Key)
+				continue
+			}
 			dir := bcdaaws.ParseS3Directory(e.S3.Bucket.Name, e.S3.Object.Key)
 			logger.Infof("Reading %s event for directory %s", e.EventName, dir)
-			return handleOptOutImport(ctx, repo, s3Client, dir)
+			return handleOptOutImport(ctx, repo, s3Client, slack.New(slackToken), dir)
 		}
 	}
 
@@ -91,7 +103,7 @@ func optOutImportHandler(ctx context.Context, sqsEvent events.SQSEvent) (string,
 	return "", nil
 }
 
-func handleOptOutImport(ctx context.Context, repo models.Repository, s3Client bcdaaws.CustomS3Client, s3ImportPath string) (string, error) {
+func handleOptOutImport(ctx context.Context, repo models.Repository, s3Client bcdaaws.CustomS3Client, slackClient *slack.Client, s3ImportPath string) (string, error) {
 	env := conf.GetEnv("ENV")
 	appName := conf.GetEnv("APP_NAME")
 	logger := configureLogger(env, appName)
@@ -104,6 +116,7 @@ func handleOptOutImport(ctx context.Context, repo models.Repository, s3Client bc
 		Repo:                 repo,
 		Logger:               logger,
 		ImportStatusInterval: utils.GetEnvInt("SUPPRESS_IMPORT_STATUS_RECORDS_INTERVAL", 1000),
+		SlackClient:          slackClient,
 	}
 
 	s, f, sk, err := importer.ImportDirectory(ctx, s3ImportPath)
@@ -112,6 +125,10 @@ func handleOptOutImport(ctx context.Context, repo models.Repository, s3Client bc
 	return result, err
 }
 
+func isQuarantined(key string) bool {
+	return strings.HasPrefix(key, bp.DefaultQuarantinePrefix+"/")
+}
+
 func configureLogger(env, appName string) *logrus.Entry {
 	log := logrus.New()
 	log.SetFormatter(&logrus.JSONFormatter{
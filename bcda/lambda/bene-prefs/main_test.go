@@ -25,7 +25,7 @@ func TestHandleOptOutImport(t *testing.T) {
 	cleanupEnv := testUtils.SetEnvVars(t, []testUtils.EnvVar{{Name: "ENV", Value: env}})
 	defer cleanupEnv()
 
-	res, err := handleOptOutImport(context.Background(), repo, s3Client, path)
+	res, err := handleOptOutImport(context.Background(), repo, s3Client, nil, path)
 	assert.Nil(err)
 	assert.Contains(res, constants.CompleteMedSupDataImp)
 	// due to using mock aws s3 we dont have any actual files to import, so the counts will be 0
@@ -34,6 +34,13 @@ func TestHandleOptOutImport(t *testing.T) {
 	assert.Contains(res, "Files skipped: 0")
 }
 
+func TestIsQuarantined(t *testing.T) {
+	assert.True(t, isQuarantined("quarantine/T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009"))
+	assert.True(t, isQuarantined("quarantine/T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009.error.json"))
+	assert.False(t, isQuarantined("T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009"))
+	assert.False(t, isQuarantined("bfdeft01/quarantine/T#EFT.ON.ACO.NGD1800.DPRF.D181120.T1000009"))
+}
+
 func TestConfigureLogger(t *testing.T) {
 	logger := configureLogger("test_env", "test_app_name")
 	require.NotNil(t, logger)
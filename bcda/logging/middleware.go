@@ -2,6 +2,7 @@ package logging
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/pborman/uuid"
 	"github.com/sirupsen/logrus"
 
 	"github.com/CMSgov/bcda-app/bcda/auth"
@@ -100,8 +102,40 @@ func (rl *ResourceTypeLogger) LogJobResourceType(next http.Handler) http.Handler
 			return
 		}
 
-		ctx, _ = log.SetLoggerFields(ctx, logrus.Fields{"resource_type": jobKey.ResourceType})
-		next.ServeHTTP(w, r.WithContext(ctx))
+		ctx, logger := log.SetLoggerFields(ctx, logrus.Fields{"resource_type": jobKey.ResourceType})
+
+		// The download is recorded once the file has been served so its size is known, and only if it was
+		// served; a status of 0 means the handler wrote nothing and the response defaulted to 200.
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+		if ww.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		// The file has already been sent by now, so a failure to record the download can't fail the request.
+		// Recording first would make downloads depend on the audit table being writable, which we don't want
+		// either; the download is instead logged as an error so the gap in the audit trail can be found and
+		// backfilled from the request logs.
+		if err := rl.recordDownload(r, jobKey, int64(ww.BytesWritten())); err != nil {
+			logger.Errorf("Failed to record download of %s for job %d: %s", jobKey.FileName, jobKey.JobID, err)
+		}
+	})
+}
+
+func (rl *ResourceTypeLogger) recordDownload(r *http.Request, jobKey *models.JobKey, bytes int64) error {
+	ad, ok := r.Context().Value(auth.AuthDataContextKey).(auth.AuthData)
+	if !ok {
+		return errors.New("no auth data found in request context")
+	}
+
+	return rl.Repository.CreateJobKeyDownload(r.Context(), models.JobKeyDownload{
+		JobID:        jobKey.JobID,
+		ACOID:        uuid.Parse(ad.ACOID),
+		FileName:     jobKey.FileName,
+		ResourceType: jobKey.ResourceType,
+		ClientID:     ad.ClientID,
+		RequestID:    middleware.GetReqID(r.Context()),
+		Bytes:        bytes,
 	})
 }
 
@@ -16,6 +16,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/pborman/uuid"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/CMSgov/bcda-app/bcda/auth"
@@ -243,6 +244,63 @@ func TestResourceTypeLogging(t *testing.T) {
 	}
 }
 
+func TestResourceTypeLoggingRecordsDownload(t *testing.T) {
+	req := httptest.NewRequest("GET", fmt.Sprintf("/data/%s/%s", "1234", constants.TestBlobFileName), nil)
+	ad := auth.AuthData{ACOID: constants.TestACOID, CMSID: "A9995", ClientID: "test-client"}
+	req = req.WithContext(context.WithValue(req.Context(), auth.AuthDataContextKey, ad))
+	newLogEntry := &log.StructuredLoggerEntry{Logger: log.API}
+	req = req.WithContext(context.WithValue(req.Context(), log.CtxLoggerKey, newLogEntry))
+
+	repository := models.NewMockRepository(t)
+	j := &models.JobKey{ID: 1, JobID: 1234, FileName: constants.TestBlobFileName, ResourceType: "Patient"}
+	repository.On("GetJobKey", testUtils.CtxMatcher, uint(1234), constants.TestBlobFileName).Return(j, nil)
+	repository.On("CreateJobKeyDownload", testUtils.CtxMatcher, mock.MatchedBy(func(d models.JobKeyDownload) bool {
+		return d.JobID == 1234 &&
+			uuid.Equal(d.ACOID, uuid.Parse(constants.TestACOID)) &&
+			d.FileName == constants.TestBlobFileName &&
+			d.ResourceType == "Patient" &&
+			d.ClientID == "test-client" &&
+			d.Bytes == int64(len(`{"resourceType":"Patient"}`))
+	})).Return(errors.New("failed to record download"))
+
+	logger := logging.ResourceTypeLogger{Repository: repository}
+	r := chi.NewRouter()
+	r.With(logger.LogJobResourceType).Get("/data/{jobID}/{fileName}", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte(`{"resourceType":"Patient"}`))
+	}))
+
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+
+	// Failing to record the download must not block the caller from their data
+	assert.Equal(t, http.StatusOK, rw.Result().StatusCode)
+}
+
+func TestResourceTypeLoggingSkipsFailedDownload(t *testing.T) {
+	req := httptest.NewRequest("GET", fmt.Sprintf("/data/%s/%s", "1234", constants.TestBlobFileName), nil)
+	ad := auth.AuthData{ACOID: constants.TestACOID, CMSID: "A9995", ClientID: "test-client"}
+	req = req.WithContext(context.WithValue(req.Context(), auth.AuthDataContextKey, ad))
+	newLogEntry := &log.StructuredLoggerEntry{Logger: log.API}
+	req = req.WithContext(context.WithValue(req.Context(), log.CtxLoggerKey, newLogEntry))
+
+	repository := models.NewMockRepository(t)
+	j := &models.JobKey{ID: 1, JobID: 1234, FileName: constants.TestBlobFileName, ResourceType: "Patient"}
+	repository.On("GetJobKey", testUtils.CtxMatcher, uint(1234), constants.TestBlobFileName).Return(j, nil)
+
+	logger := logging.ResourceTypeLogger{Repository: repository}
+	r := chi.NewRouter()
+	r.With(logger.LogJobResourceType).Get("/data/{jobID}/{fileName}", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusGone)
+	}))
+
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+
+	// A file that wasn't served is not recorded as downloaded
+	assert.Equal(t, http.StatusGone, rw.Result().StatusCode)
+	repository.AssertNotCalled(t, "CreateJobKeyDownload", mock.Anything, mock.Anything)
+}
+
 func TestMiddlewareLogCtx(t *testing.T) {
 	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		entry := r.Context().Value(log.CtxLoggerKey).(*log.StructuredLoggerEntry)
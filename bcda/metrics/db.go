@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	queueDepthDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "queue_jobs"),
+		"River jobs that have not finished, by kind and state.", []string{"kind", "state"}, nil)
+	importFilesDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "import_files"),
+		"Attribution and suppression files, by type and import status.", []string{"type", "status"}, nil)
+)
+
+// dbCollector reads the queue depth and import counts from the database when metrics are scraped, so they
+// are correct no matter which process enqueued the jobs or imported the files.
+type dbCollector struct {
+	db *sql.DB
+}
+
+// NewDBCollector returns a collector for the metrics kept in the database.
+func NewDBCollector(db *sql.DB) prometheus.Collector {
+	return dbCollector{db}
+}
+
+func (c dbCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- importFilesDesc
+}
+
+func (c dbCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(ch, queueDepthDesc, `SELECT kind, state, COUNT(*) FROM river_job
+		WHERE state NOT IN ('completed', 'cancelled', 'discarded') GROUP BY kind, state`)
+	c.collect(ch, importFilesDesc, `SELECT 'cclf', COALESCE(import_status, ''), COUNT(*) FROM cclf_files GROUP BY import_status
+		UNION ALL SELECT 'suppression', COALESCE(import_status, ''), COUNT(*) FROM suppression_files GROUP BY import_status`)
+}
+
+// collect reports a gauge for each row of query, which must select two label values and a count.
+func (c dbCollector) collect(ch chan<- prometheus.Metric, desc *prometheus.Desc, query string) {
+	rows, err := c.db.Query(query)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(desc, err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			label1, label2 string
+			count          float64
+		)
+		if err := rows.Scan(&label1, &label2, &count); err != nil {
+			ch <- prometheus.NewInvalidMetric(desc, err)
+			return
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, count, label1, label2)
+	}
+	if err := rows.Err(); err != nil {
+		ch <- prometheus.NewInvalidMetric(desc, err)
+	}
+}
@@ -0,0 +1,170 @@
+// Package metrics collects the API and worker's operational metrics and serves them in the Prometheus
+// exposition format.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	gcmw "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bcda"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route, method and status.",
+	}, []string{"route", "method", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+	authFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Requests rejected by the auth middleware, by reason.",
+	}, []string{"reason"})
+	jobsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_created_total",
+		Help:      "Export jobs created, by ACO model.",
+	}, []string{"model"})
+	jobsFinished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_finished_total",
+		Help:      "Export jobs that completed or failed, by ACO model and status.",
+	}, []string{"model", "status"})
+	subJobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "subjob_duration_seconds",
+		Help:      "Time taken to process export sub-jobs, by resource type and result.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800},
+	}, []string{"resource_type", "result"})
+	bfdRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bfd_requests_total",
+		Help:      "Requests made to BFD, by base path and status. Requests that got no response have status error.",
+	}, []string{"base_path", "status"})
+	bfdRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "bfd_request_duration_seconds",
+		Help:      "Time taken for BFD to respond, by base path.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"base_path"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpRequestDuration, authFailures, jobsCreated, jobsFinished,
+		subJobDuration, bfdRequests, bfdRequestDuration,
+	)
+}
+
+// Handler serves the registered metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Register adds collectors that only some processes provide, such as the queue and import gauges.
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Middleware counts and times requests by their chi route pattern rather than their path, so the number of
+// series doesn't grow with job IDs and file names.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := gcmw.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		labels := prometheus.Labels{"route": route, "method": r.Method, "status": strconv.Itoa(status)}
+		httpRequests.With(labels).Inc()
+		httpRequestDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// AuthFailure counts a request rejected for reason, e.g. expired_token.
+func AuthFailure(reason string) {
+	authFailures.WithLabelValues(reason).Inc()
+}
+
+// JobCreated counts an export job created for an ACO in model.
+func JobCreated(model string) {
+	jobsCreated.WithLabelValues(labelOrUnknown(model)).Inc()
+}
+
+var (
+	acoModelMu     sync.RWMutex
+	acoModelLookup func(acoID string) string
+)
+
+// SetACOModelLookup sets how JobFinished finds the model of a job's ACO. Without one, finished jobs are
+// counted under an unknown model.
+func SetACOModelLookup(lookup func(acoID string) string) {
+	acoModelMu.Lock()
+	defer acoModelMu.Unlock()
+	acoModelLookup = lookup
+}
+
+// JobFinished counts an export job for the ACO with acoID reaching the terminal status.
+func JobFinished(acoID, status string) {
+	acoModelMu.RLock()
+	lookup := acoModelLookup
+	acoModelMu.RUnlock()
+
+	var model string
+	if lookup != nil {
+		model = lookup(acoID)
+	}
+	jobsFinished.WithLabelValues(labelOrUnknown(model), status).Inc()
+}
+
+// SubJobProcessed records how long a sub-job for resourceType took and its result, e.g. success or error.
+func SubJobProcessed(resourceType, result string, d time.Duration) {
+	subJobDuration.WithLabelValues(labelOrUnknown(resourceType), result).Observe(d.Seconds())
+}
+
+// BFDRequest records a request to BFD. A status of 0 means no response was received.
+func BFDRequest(basePath string, status int, d time.Duration) {
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	bfdRequests.WithLabelValues(labelOrUnknown(basePath), code).Inc()
+	bfdRequestDuration.WithLabelValues(labelOrUnknown(basePath)).Observe(d.Seconds())
+}
+
+func labelOrUnknown(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/api/v2/jobs/{jobID}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	labels := prometheus.Labels{"route": "/api/v2/jobs/{jobID}", "method": "GET", "status": "202"}
+	before := testutil.ToFloat64(httpRequests.With(labels))
+	for _, id := range []string{"1", "2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v2/jobs/"+id, nil))
+	}
+	assert.Equal(t, before+2, testutil.ToFloat64(httpRequests.With(labels)))
+
+	unmatched := prometheus.Labels{"route": "unmatched", "method": "GET", "status": "404"}
+	before = testutil.ToFloat64(httpRequests.With(unmatched))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/nope", nil))
+	assert.Equal(t, before+1, testutil.ToFloat64(httpRequests.With(unmatched)))
+}
+
+func TestJobFinished(t *testing.T) {
+	t.Cleanup(func() { SetACOModelLookup(nil) })
+
+	before := testutil.ToFloat64(jobsFinished.WithLabelValues("unknown", "Completed"))
+	JobFinished("aco-1", "Completed")
+	assert.Equal(t, before+1, testutil.ToFloat64(jobsFinished.WithLabelValues("unknown", "Completed")))
+
+	SetACOModelLookup(func(acoID string) string {
+		assert.Equal(t, "aco-1", acoID)
+		return "MSSP"
+	})
+	before = testutil.ToFloat64(jobsFinished.WithLabelValues("MSSP", "Failed"))
+	JobFinished("aco-1", "Failed")
+	assert.Equal(t, before+1, testutil.ToFloat64(jobsFinished.WithLabelValues("MSSP", "Failed")))
+}
+
+func TestBFDRequest(t *testing.T) {
+	before := testutil.ToFloat64(bfdRequests.WithLabelValues("/v3/fhir", "error"))
+	BFDRequest("/v3/fhir", 0, time.Second)
+	assert.Equal(t, before+1, testutil.ToFloat64(bfdRequests.WithLabelValues("/v3/fhir", "error")))
+
+	before = testutil.ToFloat64(bfdRequests.WithLabelValues("/v3/fhir", "200"))
+	BFDRequest("/v3/fhir", http.StatusOK, time.Second)
+	assert.Equal(t, before+1, testutil.ToFloat64(bfdRequests.WithLabelValues("/v3/fhir", "200")))
+}
+
+func TestDBCollector(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM river_job").WillReturnRows(sqlmock.NewRows([]string{"kind", "state", "count"}).
+		AddRow("ProcessJob", "available", 12).
+		AddRow("PrepareJob", "running", 1))
+	mock.ExpectQuery("FROM cclf_files").WillReturnRows(sqlmock.NewRows([]string{"type", "status", "count"}).
+		AddRow("cclf", "Completed", 40).
+		AddRow("suppression", "Failed", 2))
+
+	expected := `
+# HELP bcda_import_files Attribution and suppression files, by type and import status.
+# TYPE bcda_import_files gauge
+bcda_import_files{status="Completed",type="cclf"} 40
+bcda_import_files{status="Failed",type="suppression"} 2
+# HELP bcda_queue_jobs River jobs that have not finished, by kind and state.
+# TYPE bcda_queue_jobs gauge
+bcda_queue_jobs{kind="PrepareJob",state="running"} 1
+bcda_queue_jobs{kind="ProcessJob",state="available"} 12
+`
+	assert.NoError(t, testutil.CollectAndCompare(NewDBCollector(db), strings.NewReader(expected)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandler(t *testing.T) {
+	AuthFailure("expired_token")
+
+	rr := httptest.NewRecorder()
+	Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `bcda_auth_failures_total{reason="expired_token"}`)
+	assert.Contains(t, rr.Body.String(), "go_goroutines")
+}
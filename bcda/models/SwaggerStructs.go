@@ -131,6 +131,45 @@ type JobsStatusResponse struct {
 	Body BundleResponse
 }
 
+// JSON object containing the ACO's API activity. The body will contain a FHIR Bundle resource in JSON format https://www.hl7.org/fhir/bundle.html and FHIR AuditEvent resources for the Bundle entries in JSON format https://www.hl7.org/fhir/auditevent.html
+// swagger:response auditEventResponse
+type AuditEventResponse struct {
+	Body BundleResponse
+}
+
+// JSON object containing a FHIR Group resource https://www.hl7.org/fhir/group.html with a page of the beneficiaries attributed to the ACO
+// swagger:response groupResponse
+type GroupResponse struct {
+	// in: body
+	Body struct {
+		// Group
+		ResourceType string `json:"resourceType"`
+		// all or runout
+		ID string `json:"id"`
+		// person
+		Type string `json:"type"`
+		// Total number of attributed beneficiaries
+		Quantity int `json:"quantity"`
+		Member   []struct {
+			Entity struct {
+				Identifier struct {
+					System string `json:"system"`
+					// MBI of the beneficiary
+					Value string `json:"value"`
+				} `json:"identifier"`
+			} `json:"entity"`
+			// The beneficiary has opted out of data sharing
+			Inactive bool `json:"inactive,omitempty"`
+		} `json:"member"`
+	}
+}
+
+// JSON object containing a FHIR Bundle resource in JSON format https://www.hl7.org/fhir/bundle.html with a FHIR Group resource, without members, for each group available to the ACO
+// swagger:response groupSearchResponse
+type GroupSearchResponse struct {
+	Body BundleResponse
+}
+
 // The job has been deleted.
 // swagger:response deleteJobResponse
 type DeleteJobResponse struct {
@@ -153,6 +192,10 @@ type AuthResponse struct {
 	Body struct {
 		// Required: true
 		Version string `json:"auth_provider"`
+		// When the presented token's client credentials expire, if known
+		CredentialsExpireAt string `json:"credentials_expire_at,omitempty"`
+		// Present when the client credentials have been rotated or will expire soon
+		CredentialsWarning string `json:"credentials_warning,omitempty"`
 	}
 }
 
@@ -270,7 +313,7 @@ type BulkRequestHeaders struct {
 // A BulkGroupRequest parameter model.
 //
 // This is used for operations that want the groupID of a group in the path
-// swagger:parameters bulkGroupRequest bulkGroupRequestV2
+// swagger:parameters bulkGroupRequest bulkGroupRequestV2 group
 type GroupIDParam struct {
 	// ID of group export
 	// in: path
@@ -279,6 +322,30 @@ type GroupIDParam struct {
 	GroupID string `json:"groupId"`
 }
 
+// swagger:parameters auditEvent
+type AuditEventPagingParams struct {
+	// Number of audit events to return, at most 5000
+	// in: query
+	// required: false
+	Count int `json:"_count"`
+	// Number of audit events to skip, at most 10000000
+	// in: query
+	// required: false
+	Offset int `json:"_offset"`
+}
+
+// swagger:parameters group
+type GroupPagingParams struct {
+	// Number of members to return, at most 5000
+	// in: query
+	// required: false
+	Count int `json:"_count"`
+	// Number of members to skip, at most 10000000
+	// in: query
+	// required: false
+	Offset int `json:"_offset"`
+}
+
 // JSON with a valid JWT
 // swagger:response tokenResponse
 type TokenResponse struct {
@@ -20,6 +20,86 @@ type Task struct {
 	ExecutionPeriod Period       `json:"executionPeriod,omitempty"`
 }
 
+type AuditEvent struct {
+	ResourceType string             `json:"resourceType"`
+	ID           string             `json:"id,omitempty"`
+	Type         Coding             `json:"type"`
+	Subtype      []Coding           `json:"subtype,omitempty"`
+	Action       AuditEventAction   `json:"action,omitempty"`
+	Period       *Period            `json:"period,omitempty"`
+	Recorded     string             `json:"recorded"`
+	Outcome      AuditEventOutcome  `json:"outcome,omitempty"`
+	Agent        []AuditEventAgent  `json:"agent"`
+	Source       AuditEventSource   `json:"source"`
+	Entity       []AuditEventEntity `json:"entity,omitempty"`
+}
+
+type AuditEventAgent struct {
+	Who       *Reference `json:"who,omitempty"`
+	Requestor bool       `json:"requestor"`
+}
+
+type AuditEventSource struct {
+	Site     string    `json:"site,omitempty"`
+	Observer Reference `json:"observer"`
+}
+
+type AuditEventEntity struct {
+	What        *Reference `json:"what,omitempty"`
+	Type        *Coding    `json:"type,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+type Group struct {
+	ResourceType   string        `json:"resourceType"`
+	ID             string        `json:"id,omitempty"`
+	Meta           *Meta         `json:"meta,omitempty"`
+	Type           GroupType     `json:"type"`
+	Actual         bool          `json:"actual"`
+	Name           string        `json:"name,omitempty"`
+	Quantity       uint32        `json:"quantity"`
+	ManagingEntity *Reference    `json:"managingEntity,omitempty"`
+	Member         []GroupMember `json:"member,omitempty"`
+}
+
+type GroupMember struct {
+	Entity   Reference `json:"entity"`
+	Period   *Period   `json:"period,omitempty"`
+	Inactive bool      `json:"inactive,omitempty"`
+}
+
+type GroupType string
+
+const (
+	GroupTypePerson GroupType = "person"
+)
+
+type Meta struct {
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+type Reference struct {
+	Reference  string      `json:"reference,omitempty"`
+	Identifier *Identifier `json:"identifier,omitempty"`
+	Display    string      `json:"display,omitempty"`
+}
+
+type AuditEventAction string
+
+const (
+	AuditEventActionRead    AuditEventAction = "R"
+	AuditEventActionExecute AuditEventAction = "E"
+)
+
+type AuditEventOutcome string
+
+const (
+	AuditEventOutcomeSuccess        AuditEventOutcome = "0"
+	AuditEventOutcomeMinorFailure   AuditEventOutcome = "4"
+	AuditEventOutcomeSeriousFailure AuditEventOutcome = "8"
+)
+
 type Identifier struct {
 	Use    string `json:"use,omitempty"`
 	System string `json:"system,omitempty"`
@@ -209,4 +289,5 @@ const (
 	ResourceTypeCodeCoverage             ResourceTypeCode = "Coverage"
 	ResourceTypeCodeClaim                ResourceTypeCode = "Claim"
 	ResourceTypeCodeClaimResponse        ResourceTypeCode = "ClaimResponse"
+	ResourceTypeCodeAuditEvent           ResourceTypeCode = "AuditEvent"
 )
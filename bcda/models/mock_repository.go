@@ -96,6 +96,72 @@ func (_c *MockRepository_CreateACO_Call) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
+// CreateAuthSystem provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateAuthSystem(ctx context.Context, system AuthSystem) (uint, error) {
+	ret := _mock.Called(ctx, system)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuthSystem")
+	}
+
+	var r0 uint
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, AuthSystem) (uint, error)); ok {
+		return returnFunc(ctx, system)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, AuthSystem) uint); ok {
+		r0 = returnFunc(ctx, system)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, AuthSystem) error); ok {
+		r1 = returnFunc(ctx, system)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_CreateAuthSystem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAuthSystem'
+type MockRepository_CreateAuthSystem_Call struct {
+	*mock.Call
+}
+
+// CreateAuthSystem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - system AuthSystem
+func (_e *MockRepository_Expecter) CreateAuthSystem(ctx interface{}, system interface{}) *MockRepository_CreateAuthSystem_Call {
+	return &MockRepository_CreateAuthSystem_Call{Call: _e.mock.On("CreateAuthSystem", ctx, system)}
+}
+
+func (_c *MockRepository_CreateAuthSystem_Call) Run(run func(ctx context.Context, system AuthSystem)) *MockRepository_CreateAuthSystem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 AuthSystem
+		if args[1] != nil {
+			arg1 = args[1].(AuthSystem)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_CreateAuthSystem_Call) Return(r0 uint, r1 error) *MockRepository_CreateAuthSystem_Call {
+	_c.Call.Return(r0, r1)
+	return _c
+}
+
+func (_c *MockRepository_CreateAuthSystem_Call) RunAndReturn(run func(context.Context, AuthSystem) (uint, error)) *MockRepository_CreateAuthSystem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateBenePrefsFile provides a mock function for the type MockRepository
 func (_mock *MockRepository) CreateBenePrefsFile(ctx context.Context, file BenePrefsFile) (uint, error) {
 	ret := _mock.Called(ctx, file)
@@ -285,6 +351,63 @@ func (_c *MockRepository_CreateCCLFFile_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
+// CreateCredentialRotation provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateCredentialRotation(ctx context.Context, rotation CredentialRotation) error {
+	ret := _mock.Called(ctx, rotation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCredentialRotation")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, CredentialRotation) error); ok {
+		r0 = returnFunc(ctx, rotation)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_CreateCredentialRotation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCredentialRotation'
+type MockRepository_CreateCredentialRotation_Call struct {
+	*mock.Call
+}
+
+// CreateCredentialRotation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rotation CredentialRotation
+func (_e *MockRepository_Expecter) CreateCredentialRotation(ctx interface{}, rotation interface{}) *MockRepository_CreateCredentialRotation_Call {
+	return &MockRepository_CreateCredentialRotation_Call{Call: _e.mock.On("CreateCredentialRotation", ctx, rotation)}
+}
+
+func (_c *MockRepository_CreateCredentialRotation_Call) Run(run func(ctx context.Context, rotation CredentialRotation)) *MockRepository_CreateCredentialRotation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 CredentialRotation
+		if args[1] != nil {
+			arg1 = args[1].(CredentialRotation)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_CreateCredentialRotation_Call) Return(r0 error) *MockRepository_CreateCredentialRotation_Call {
+	_c.Call.Return(r0)
+	return _c
+}
+
+func (_c *MockRepository_CreateCredentialRotation_Call) RunAndReturn(run func(context.Context, CredentialRotation) error) *MockRepository_CreateCredentialRotation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateJob provides a mock function for the type MockRepository
 func (_mock *MockRepository) CreateJob(ctx context.Context, j Job) (uint, error) {
 	ret := _mock.Called(ctx, j)
@@ -351,55 +474,44 @@ func (_c *MockRepository_CreateJob_Call) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
-// GetACOByCMSID provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetACOByCMSID(ctx context.Context, cmsID string) (*ACO, error) {
-	ret := _mock.Called(ctx, cmsID)
+// CreateJobKeyDownload provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateJobKeyDownload(ctx context.Context, download JobKeyDownload) error {
+	ret := _mock.Called(ctx, download)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetACOByCMSID")
+		panic("no return value specified for CreateJobKeyDownload")
 	}
 
-	var r0 *ACO
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*ACO, error)); ok {
-		return returnFunc(ctx, cmsID)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *ACO); ok {
-		r0 = returnFunc(ctx, cmsID)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ACO)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, cmsID)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, JobKeyDownload) error); ok {
+		r0 = returnFunc(ctx, download)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockRepository_GetACOByCMSID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOByCMSID'
-type MockRepository_GetACOByCMSID_Call struct {
+// MockRepository_CreateJobKeyDownload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateJobKeyDownload'
+type MockRepository_CreateJobKeyDownload_Call struct {
 	*mock.Call
 }
 
-// GetACOByCMSID is a helper method to define mock.On call
+// CreateJobKeyDownload is a helper method to define mock.On call
 //   - ctx context.Context
-//   - cmsID string
-func (_e *MockRepository_Expecter) GetACOByCMSID(ctx interface{}, cmsID interface{}) *MockRepository_GetACOByCMSID_Call {
-	return &MockRepository_GetACOByCMSID_Call{Call: _e.mock.On("GetACOByCMSID", ctx, cmsID)}
+//   - download JobKeyDownload
+func (_e *MockRepository_Expecter) CreateJobKeyDownload(ctx interface{}, download interface{}) *MockRepository_CreateJobKeyDownload_Call {
+	return &MockRepository_CreateJobKeyDownload_Call{Call: _e.mock.On("CreateJobKeyDownload", ctx, download)}
 }
 
-func (_c *MockRepository_GetACOByCMSID_Call) Run(run func(ctx context.Context, cmsID string)) *MockRepository_GetACOByCMSID_Call {
+func (_c *MockRepository_CreateJobKeyDownload_Call) Run(run func(ctx context.Context, download JobKeyDownload)) *MockRepository_CreateJobKeyDownload_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 JobKeyDownload
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(JobKeyDownload)
 		}
 		run(
 			arg0,
@@ -409,57 +521,47 @@ func (_c *MockRepository_GetACOByCMSID_Call) Run(run func(ctx context.Context, c
 	return _c
 }
 
-func (_c *MockRepository_GetACOByCMSID_Call) Return(aCO *ACO, err error) *MockRepository_GetACOByCMSID_Call {
-	_c.Call.Return(aCO, err)
+func (_c *MockRepository_CreateJobKeyDownload_Call) Return(err error) *MockRepository_CreateJobKeyDownload_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_GetACOByCMSID_Call) RunAndReturn(run func(ctx context.Context, cmsID string) (*ACO, error)) *MockRepository_GetACOByCMSID_Call {
+func (_c *MockRepository_CreateJobKeyDownload_Call) RunAndReturn(run func(context.Context, JobKeyDownload) error) *MockRepository_CreateJobKeyDownload_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetACOByClientID provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetACOByClientID(ctx context.Context, clientID string) (*ACO, error) {
-	ret := _mock.Called(ctx, clientID)
+// CreateRevokedToken provides a mock function for the type MockRepository
+func (_mock *MockRepository) CreateRevokedToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ret := _mock.Called(ctx, tokenID, expiresAt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetACOByClientID")
+		panic("no return value specified for CreateRevokedToken")
 	}
 
-	var r0 *ACO
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*ACO, error)); ok {
-		return returnFunc(ctx, clientID)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *ACO); ok {
-		r0 = returnFunc(ctx, clientID)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ACO)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, clientID)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, tokenID, expiresAt)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockRepository_GetACOByClientID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOByClientID'
-type MockRepository_GetACOByClientID_Call struct {
+// MockRepository_CreateRevokedToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRevokedToken'
+type MockRepository_CreateRevokedToken_Call struct {
 	*mock.Call
 }
 
-// GetACOByClientID is a helper method to define mock.On call
+// CreateRevokedToken is a helper method to define mock.On call
 //   - ctx context.Context
-//   - clientID string
-func (_e *MockRepository_Expecter) GetACOByClientID(ctx interface{}, clientID interface{}) *MockRepository_GetACOByClientID_Call {
-	return &MockRepository_GetACOByClientID_Call{Call: _e.mock.On("GetACOByClientID", ctx, clientID)}
+//   - tokenID string
+//   - expiresAt time.Time
+func (_e *MockRepository_Expecter) CreateRevokedToken(ctx interface{}, tokenID interface{}, expiresAt interface{}) *MockRepository_CreateRevokedToken_Call {
+	return &MockRepository_CreateRevokedToken_Call{Call: _e.mock.On("CreateRevokedToken", ctx, tokenID, expiresAt)}
 }
 
-func (_c *MockRepository_GetACOByClientID_Call) Run(run func(ctx context.Context, clientID string)) *MockRepository_GetACOByClientID_Call {
+func (_c *MockRepository_CreateRevokedToken_Call) Run(run func(ctx context.Context, tokenID string, expiresAt time.Time)) *MockRepository_CreateRevokedToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -469,73 +571,67 @@ func (_c *MockRepository_GetACOByClientID_Call) Run(run func(ctx context.Context
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetACOByClientID_Call) Return(aCO *ACO, err error) *MockRepository_GetACOByClientID_Call {
-	_c.Call.Return(aCO, err)
+func (_c *MockRepository_CreateRevokedToken_Call) Return(r0 error) *MockRepository_CreateRevokedToken_Call {
+	_c.Call.Return(r0)
 	return _c
 }
 
-func (_c *MockRepository_GetACOByClientID_Call) RunAndReturn(run func(ctx context.Context, clientID string) (*ACO, error)) *MockRepository_GetACOByClientID_Call {
+func (_c *MockRepository_CreateRevokedToken_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *MockRepository_CreateRevokedToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetACOByUUID provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetACOByUUID(ctx context.Context, uuid1 uuid.UUID) (*ACO, error) {
-	ret := _mock.Called(ctx, uuid1)
+// DeleteAuthSystem provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteAuthSystem(ctx context.Context, id uint) error {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetACOByUUID")
+		panic("no return value specified for DeleteAuthSystem")
 	}
 
-	var r0 *ACO
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*ACO, error)); ok {
-		return returnFunc(ctx, uuid1)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *ACO); ok {
-		r0 = returnFunc(ctx, uuid1)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*ACO)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, uuid1)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockRepository_GetACOByUUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOByUUID'
-type MockRepository_GetACOByUUID_Call struct {
+// MockRepository_DeleteAuthSystem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAuthSystem'
+type MockRepository_DeleteAuthSystem_Call struct {
 	*mock.Call
 }
 
-// GetACOByUUID is a helper method to define mock.On call
+// DeleteAuthSystem is a helper method to define mock.On call
 //   - ctx context.Context
-//   - uuid1 uuid.UUID
-func (_e *MockRepository_Expecter) GetACOByUUID(ctx interface{}, uuid1 interface{}) *MockRepository_GetACOByUUID_Call {
-	return &MockRepository_GetACOByUUID_Call{Call: _e.mock.On("GetACOByUUID", ctx, uuid1)}
+//   - id uint
+func (_e *MockRepository_Expecter) DeleteAuthSystem(ctx interface{}, id interface{}) *MockRepository_DeleteAuthSystem_Call {
+	return &MockRepository_DeleteAuthSystem_Call{Call: _e.mock.On("DeleteAuthSystem", ctx, id)}
 }
 
-func (_c *MockRepository_GetACOByUUID_Call) Run(run func(ctx context.Context, uuid1 uuid.UUID)) *MockRepository_GetACOByUUID_Call {
+func (_c *MockRepository_DeleteAuthSystem_Call) Run(run func(ctx context.Context, id uint)) *MockRepository_DeleteAuthSystem_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 uint
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(uint)
 		}
 		run(
 			arg0,
@@ -545,58 +641,46 @@ func (_c *MockRepository_GetACOByUUID_Call) Run(run func(ctx context.Context, uu
 	return _c
 }
 
-func (_c *MockRepository_GetACOByUUID_Call) Return(aCO *ACO, err error) *MockRepository_GetACOByUUID_Call {
-	_c.Call.Return(aCO, err)
+func (_c *MockRepository_DeleteAuthSystem_Call) Return(r0 error) *MockRepository_DeleteAuthSystem_Call {
+	_c.Call.Return(r0)
 	return _c
 }
 
-func (_c *MockRepository_GetACOByUUID_Call) RunAndReturn(run func(ctx context.Context, uuid1 uuid.UUID) (*ACO, error)) *MockRepository_GetACOByUUID_Call {
+func (_c *MockRepository_DeleteAuthSystem_Call) RunAndReturn(run func(context.Context, uint) error) *MockRepository_DeleteAuthSystem_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCCLFBeneficiaries provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetCCLFBeneficiaries(ctx context.Context, cclfFileID uint, ignoredMBIs []string) ([]*CCLFBeneficiary, error) {
-	ret := _mock.Called(ctx, cclfFileID, ignoredMBIs)
+// DeleteBenePrefsFile provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteBenePrefsFile(ctx context.Context, fileID uint) error {
+	ret := _mock.Called(ctx, fileID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCCLFBeneficiaries")
+		panic("no return value specified for DeleteBenePrefsFile")
 	}
 
-	var r0 []*CCLFBeneficiary
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, []string) ([]*CCLFBeneficiary, error)); ok {
-		return returnFunc(ctx, cclfFileID, ignoredMBIs)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, []string) []*CCLFBeneficiary); ok {
-		r0 = returnFunc(ctx, cclfFileID, ignoredMBIs)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*CCLFBeneficiary)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint, []string) error); ok {
-		r1 = returnFunc(ctx, cclfFileID, ignoredMBIs)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = returnFunc(ctx, fileID)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockRepository_GetCCLFBeneficiaries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFBeneficiaries'
-type MockRepository_GetCCLFBeneficiaries_Call struct {
+// MockRepository_DeleteBenePrefsFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBenePrefsFile'
+type MockRepository_DeleteBenePrefsFile_Call struct {
 	*mock.Call
 }
 
-// GetCCLFBeneficiaries is a helper method to define mock.On call
+// DeleteBenePrefsFile is a helper method to define mock.On call
 //   - ctx context.Context
-//   - cclfFileID uint
-//   - ignoredMBIs []string
-func (_e *MockRepository_Expecter) GetCCLFBeneficiaries(ctx interface{}, cclfFileID interface{}, ignoredMBIs interface{}) *MockRepository_GetCCLFBeneficiaries_Call {
-	return &MockRepository_GetCCLFBeneficiaries_Call{Call: _e.mock.On("GetCCLFBeneficiaries", ctx, cclfFileID, ignoredMBIs)}
+//   - fileID uint
+func (_e *MockRepository_Expecter) DeleteBenePrefsFile(ctx interface{}, fileID interface{}) *MockRepository_DeleteBenePrefsFile_Call {
+	return &MockRepository_DeleteBenePrefsFile_Call{Call: _e.mock.On("DeleteBenePrefsFile", ctx, fileID)}
 }
 
-func (_c *MockRepository_GetCCLFBeneficiaries_Call) Run(run func(ctx context.Context, cclfFileID uint, ignoredMBIs []string)) *MockRepository_GetCCLFBeneficiaries_Call {
+func (_c *MockRepository_DeleteBenePrefsFile_Call) Run(run func(ctx context.Context, fileID uint)) *MockRepository_DeleteBenePrefsFile_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -606,78 +690,73 @@ func (_c *MockRepository_GetCCLFBeneficiaries_Call) Run(run func(ctx context.Con
 		if args[1] != nil {
 			arg1 = args[1].(uint)
 		}
-		var arg2 []string
-		if args[2] != nil {
-			arg2 = args[2].([]string)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFBeneficiaries_Call) Return(cCLFBeneficiarys []*CCLFBeneficiary, err error) *MockRepository_GetCCLFBeneficiaries_Call {
-	_c.Call.Return(cCLFBeneficiarys, err)
+func (_c *MockRepository_DeleteBenePrefsFile_Call) Return(_a0 error) *MockRepository_DeleteBenePrefsFile_Call {
+	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFBeneficiaries_Call) RunAndReturn(run func(ctx context.Context, cclfFileID uint, ignoredMBIs []string) ([]*CCLFBeneficiary, error)) *MockRepository_GetCCLFBeneficiaries_Call {
+func (_c *MockRepository_DeleteBenePrefsFile_Call) RunAndReturn(run func(context.Context, uint) error) *MockRepository_DeleteBenePrefsFile_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCCLFBeneficiaryMBIs provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetCCLFBeneficiaryMBIs(ctx context.Context, cclfFileID uint) ([]string, error) {
-	ret := _mock.Called(ctx, cclfFileID)
+// GetACOByCMSID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetACOByCMSID(ctx context.Context, cmsID string) (*ACO, error) {
+	ret := _mock.Called(ctx, cmsID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCCLFBeneficiaryMBIs")
+		panic("no return value specified for GetACOByCMSID")
 	}
 
-	var r0 []string
+	var r0 *ACO
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) ([]string, error)); ok {
-		return returnFunc(ctx, cclfFileID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*ACO, error)); ok {
+		return returnFunc(ctx, cmsID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) []string); ok {
-		r0 = returnFunc(ctx, cclfFileID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *ACO); ok {
+		r0 = returnFunc(ctx, cmsID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(*ACO)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
-		r1 = returnFunc(ctx, cclfFileID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, cmsID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetCCLFBeneficiaryMBIs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFBeneficiaryMBIs'
-type MockRepository_GetCCLFBeneficiaryMBIs_Call struct {
+// MockRepository_GetACOByCMSID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOByCMSID'
+type MockRepository_GetACOByCMSID_Call struct {
 	*mock.Call
 }
 
-// GetCCLFBeneficiaryMBIs is a helper method to define mock.On call
+// GetACOByCMSID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - cclfFileID uint
-func (_e *MockRepository_Expecter) GetCCLFBeneficiaryMBIs(ctx interface{}, cclfFileID interface{}) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
-	return &MockRepository_GetCCLFBeneficiaryMBIs_Call{Call: _e.mock.On("GetCCLFBeneficiaryMBIs", ctx, cclfFileID)}
+//   - cmsID string
+func (_e *MockRepository_Expecter) GetACOByCMSID(ctx interface{}, cmsID interface{}) *MockRepository_GetACOByCMSID_Call {
+	return &MockRepository_GetACOByCMSID_Call{Call: _e.mock.On("GetACOByCMSID", ctx, cmsID)}
 }
 
-func (_c *MockRepository_GetCCLFBeneficiaryMBIs_Call) Run(run func(ctx context.Context, cclfFileID uint)) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
+func (_c *MockRepository_GetACOByCMSID_Call) Run(run func(ctx context.Context, cmsID string)) *MockRepository_GetACOByCMSID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(uint)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -687,65 +766,65 @@ func (_c *MockRepository_GetCCLFBeneficiaryMBIs_Call) Run(run func(ctx context.C
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFBeneficiaryMBIs_Call) Return(strings []string, err error) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
-	_c.Call.Return(strings, err)
+func (_c *MockRepository_GetACOByCMSID_Call) Return(aCO *ACO, err error) *MockRepository_GetACOByCMSID_Call {
+	_c.Call.Return(aCO, err)
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFBeneficiaryMBIs_Call) RunAndReturn(run func(ctx context.Context, cclfFileID uint) ([]string, error)) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
+func (_c *MockRepository_GetACOByCMSID_Call) RunAndReturn(run func(ctx context.Context, cmsID string) (*ACO, error)) *MockRepository_GetACOByCMSID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCCLFFileByID provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetCCLFFileByID(ctx context.Context, ID uint) (*CCLFFile, error) {
-	ret := _mock.Called(ctx, ID)
+// GetACOByClientCertSubjects provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetACOByClientCertSubjects(ctx context.Context, subjects []string) (*ACO, error) {
+	ret := _mock.Called(ctx, subjects)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCCLFFileByID")
+		panic("no return value specified for GetACOByClientCertSubjects")
 	}
 
-	var r0 *CCLFFile
+	var r0 *ACO
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (*CCLFFile, error)); ok {
-		return returnFunc(ctx, ID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) (*ACO, error)); ok {
+		return returnFunc(ctx, subjects)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) *CCLFFile); ok {
-		r0 = returnFunc(ctx, ID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) *ACO); ok {
+		r0 = returnFunc(ctx, subjects)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*CCLFFile)
+			r0 = ret.Get(0).(*ACO)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
-		r1 = returnFunc(ctx, ID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, subjects)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetCCLFFileByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFFileByID'
-type MockRepository_GetCCLFFileByID_Call struct {
+// MockRepository_GetACOByClientCertSubjects_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOByClientCertSubjects'
+type MockRepository_GetACOByClientCertSubjects_Call struct {
 	*mock.Call
 }
 
-// GetCCLFFileByID is a helper method to define mock.On call
+// GetACOByClientCertSubjects is a helper method to define mock.On call
 //   - ctx context.Context
-//   - ID uint
-func (_e *MockRepository_Expecter) GetCCLFFileByID(ctx interface{}, ID interface{}) *MockRepository_GetCCLFFileByID_Call {
-	return &MockRepository_GetCCLFFileByID_Call{Call: _e.mock.On("GetCCLFFileByID", ctx, ID)}
+//   - subjects []string
+func (_e *MockRepository_Expecter) GetACOByClientCertSubjects(ctx interface{}, subjects interface{}) *MockRepository_GetACOByClientCertSubjects_Call {
+	return &MockRepository_GetACOByClientCertSubjects_Call{Call: _e.mock.On("GetACOByClientCertSubjects", ctx, subjects)}
 }
 
-func (_c *MockRepository_GetCCLFFileByID_Call) Run(run func(ctx context.Context, ID uint)) *MockRepository_GetCCLFFileByID_Call {
+func (_c *MockRepository_GetACOByClientCertSubjects_Call) Run(run func(ctx context.Context, subjects []string)) *MockRepository_GetACOByClientCertSubjects_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].(uint)
+			arg1 = args[1].([]string)
 		}
 		run(
 			arg0,
@@ -755,55 +834,57 @@ func (_c *MockRepository_GetCCLFFileByID_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFFileByID_Call) Return(cCLFFile *CCLFFile, err error) *MockRepository_GetCCLFFileByID_Call {
-	_c.Call.Return(cCLFFile, err)
+func (_c *MockRepository_GetACOByClientCertSubjects_Call) Return(aCO *ACO, err error) *MockRepository_GetACOByClientCertSubjects_Call {
+	_c.Call.Return(aCO, err)
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFFileByID_Call) RunAndReturn(run func(ctx context.Context, ID uint) (*CCLFFile, error)) *MockRepository_GetCCLFFileByID_Call {
+func (_c *MockRepository_GetACOByClientCertSubjects_Call) RunAndReturn(run func(context.Context, []string) (*ACO, error)) *MockRepository_GetACOByClientCertSubjects_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCCLFFileExistsByName provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetCCLFFileExistsByName(ctx context.Context, name string) (bool, error) {
-	ret := _mock.Called(ctx, name)
+// GetACOByClientID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetACOByClientID(ctx context.Context, clientID string) (*ACO, error) {
+	ret := _mock.Called(ctx, clientID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCCLFFileExistsByName")
+		panic("no return value specified for GetACOByClientID")
 	}
 
-	var r0 bool
+	var r0 *ACO
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
-		return returnFunc(ctx, name)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*ACO, error)); ok {
+		return returnFunc(ctx, clientID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
-		r0 = returnFunc(ctx, name)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *ACO); ok {
+		r0 = returnFunc(ctx, clientID)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ACO)
+		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, name)
+		r1 = returnFunc(ctx, clientID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetCCLFFileExistsByName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFFileExistsByName'
-type MockRepository_GetCCLFFileExistsByName_Call struct {
+// MockRepository_GetACOByClientID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOByClientID'
+type MockRepository_GetACOByClientID_Call struct {
 	*mock.Call
 }
 
-// GetCCLFFileExistsByName is a helper method to define mock.On call
+// GetACOByClientID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - name string
-func (_e *MockRepository_Expecter) GetCCLFFileExistsByName(ctx interface{}, name interface{}) *MockRepository_GetCCLFFileExistsByName_Call {
-	return &MockRepository_GetCCLFFileExistsByName_Call{Call: _e.mock.On("GetCCLFFileExistsByName", ctx, name)}
+//   - clientID string
+func (_e *MockRepository_Expecter) GetACOByClientID(ctx interface{}, clientID interface{}) *MockRepository_GetACOByClientID_Call {
+	return &MockRepository_GetACOByClientID_Call{Call: _e.mock.On("GetACOByClientID", ctx, clientID)}
 }
 
-func (_c *MockRepository_GetCCLFFileExistsByName_Call) Run(run func(ctx context.Context, name string)) *MockRepository_GetCCLFFileExistsByName_Call {
+func (_c *MockRepository_GetACOByClientID_Call) Run(run func(ctx context.Context, clientID string)) *MockRepository_GetACOByClientID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -821,63 +902,65 @@ func (_c *MockRepository_GetCCLFFileExistsByName_Call) Run(run func(ctx context.
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFFileExistsByName_Call) Return(b bool, err error) *MockRepository_GetCCLFFileExistsByName_Call {
-	_c.Call.Return(b, err)
+func (_c *MockRepository_GetACOByClientID_Call) Return(aCO *ACO, err error) *MockRepository_GetACOByClientID_Call {
+	_c.Call.Return(aCO, err)
 	return _c
 }
 
-func (_c *MockRepository_GetCCLFFileExistsByName_Call) RunAndReturn(run func(ctx context.Context, name string) (bool, error)) *MockRepository_GetCCLFFileExistsByName_Call {
+func (_c *MockRepository_GetACOByClientID_Call) RunAndReturn(run func(ctx context.Context, clientID string) (*ACO, error)) *MockRepository_GetACOByClientID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCMSIDByClientID provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetCMSIDByClientID(ctx context.Context, clientID string) (string, error) {
-	ret := _mock.Called(ctx, clientID)
+// GetACOByUUID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetACOByUUID(ctx context.Context, uuid1 uuid.UUID) (*ACO, error) {
+	ret := _mock.Called(ctx, uuid1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCMSIDByClientID")
+		panic("no return value specified for GetACOByUUID")
 	}
 
-	var r0 string
+	var r0 *ACO
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return returnFunc(ctx, clientID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*ACO, error)); ok {
+		return returnFunc(ctx, uuid1)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = returnFunc(ctx, clientID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *ACO); ok {
+		r0 = returnFunc(ctx, uuid1)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ACO)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, clientID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, uuid1)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetCMSIDByClientID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCMSIDByClientID'
-type MockRepository_GetCMSIDByClientID_Call struct {
+// MockRepository_GetACOByUUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOByUUID'
+type MockRepository_GetACOByUUID_Call struct {
 	*mock.Call
 }
 
-// GetCMSIDByClientID is a helper method to define mock.On call
+// GetACOByUUID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - clientID string
-func (_e *MockRepository_Expecter) GetCMSIDByClientID(ctx interface{}, clientID interface{}) *MockRepository_GetCMSIDByClientID_Call {
-	return &MockRepository_GetCMSIDByClientID_Call{Call: _e.mock.On("GetCMSIDByClientID", ctx, clientID)}
+//   - uuid1 uuid.UUID
+func (_e *MockRepository_Expecter) GetACOByUUID(ctx interface{}, uuid1 interface{}) *MockRepository_GetACOByUUID_Call {
+	return &MockRepository_GetACOByUUID_Call{Call: _e.mock.On("GetACOByUUID", ctx, uuid1)}
 }
 
-func (_c *MockRepository_GetCMSIDByClientID_Call) Run(run func(ctx context.Context, clientID string)) *MockRepository_GetCMSIDByClientID_Call {
+func (_c *MockRepository_GetACOByUUID_Call) Run(run func(ctx context.Context, uuid1 uuid.UUID)) *MockRepository_GetACOByUUID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 uuid.UUID
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(uuid.UUID)
 		}
 		run(
 			arg0,
@@ -887,207 +970,1141 @@ func (_c *MockRepository_GetCMSIDByClientID_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockRepository_GetCMSIDByClientID_Call) Return(s string, err error) *MockRepository_GetCMSIDByClientID_Call {
-	_c.Call.Return(s, err)
+func (_c *MockRepository_GetACOByUUID_Call) Return(aCO *ACO, err error) *MockRepository_GetACOByUUID_Call {
+	_c.Call.Return(aCO, err)
 	return _c
 }
 
-func (_c *MockRepository_GetCMSIDByClientID_Call) RunAndReturn(run func(ctx context.Context, clientID string) (string, error)) *MockRepository_GetCMSIDByClientID_Call {
+func (_c *MockRepository_GetACOByUUID_Call) RunAndReturn(run func(ctx context.Context, uuid1 uuid.UUID) (*ACO, error)) *MockRepository_GetACOByUUID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetJobByID provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetJobByID(ctx context.Context, jobID uint) (*Job, error) {
-	ret := _mock.Called(ctx, jobID)
+// GetACOUsage provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetACOUsage(ctx context.Context, start time.Time, end time.Time) ([]*ACOUsage, error) {
+	ret := _mock.Called(ctx, start, end)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetJobByID")
+		panic("no return value specified for GetACOUsage")
 	}
 
-	var r0 *Job
+	var r0 []*ACOUsage
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (*Job, error)); ok {
-		return returnFunc(ctx, jobID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]*ACOUsage, error)); ok {
+		return returnFunc(ctx, start, end)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) *Job); ok {
-		r0 = returnFunc(ctx, jobID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*ACOUsage); ok {
+		r0 = returnFunc(ctx, start, end)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*Job)
+			r0 = ret.Get(0).([]*ACOUsage)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
-		r1 = returnFunc(ctx, jobID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, start, end)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetJobByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobByID'
-type MockRepository_GetJobByID_Call struct {
+// MockRepository_GetACOUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetACOUsage'
+type MockRepository_GetACOUsage_Call struct {
 	*mock.Call
 }
 
-// GetJobByID is a helper method to define mock.On call
+// GetACOUsage is a helper method to define mock.On call
 //   - ctx context.Context
-//   - jobID uint
-func (_e *MockRepository_Expecter) GetJobByID(ctx interface{}, jobID interface{}) *MockRepository_GetJobByID_Call {
-	return &MockRepository_GetJobByID_Call{Call: _e.mock.On("GetJobByID", ctx, jobID)}
+//   - start time.Time
+//   - end time.Time
+func (_e *MockRepository_Expecter) GetACOUsage(ctx interface{}, start interface{}, end interface{}) *MockRepository_GetACOUsage_Call {
+	return &MockRepository_GetACOUsage_Call{Call: _e.mock.On("GetACOUsage", ctx, start, end)}
 }
 
-func (_c *MockRepository_GetJobByID_Call) Run(run func(ctx context.Context, jobID uint)) *MockRepository_GetJobByID_Call {
+func (_c *MockRepository_GetACOUsage_Call) Run(run func(ctx context.Context, start time.Time, end time.Time)) *MockRepository_GetACOUsage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint
+		var arg1 time.Time
 		if args[1] != nil {
-			arg1 = args[1].(uint)
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetJobByID_Call) Return(job *Job, err error) *MockRepository_GetJobByID_Call {
-	_c.Call.Return(job, err)
+func (_c *MockRepository_GetACOUsage_Call) Return(aCOUsages []*ACOUsage, err error) *MockRepository_GetACOUsage_Call {
+	_c.Call.Return(aCOUsages, err)
 	return _c
 }
 
-func (_c *MockRepository_GetJobByID_Call) RunAndReturn(run func(ctx context.Context, jobID uint) (*Job, error)) *MockRepository_GetJobByID_Call {
+func (_c *MockRepository_GetACOUsage_Call) RunAndReturn(run func(context.Context, time.Time, time.Time) ([]*ACOUsage, error)) *MockRepository_GetACOUsage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetJobKey provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetJobKey(ctx context.Context, jobID uint, filename string) (*JobKey, error) {
-	ret := _mock.Called(ctx, jobID, filename)
+// GetAuditEvents provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetAuditEvents(ctx context.Context, acoID uuid.UUID, since time.Time, offset int, limit int) ([]*Job, []*JobKeyDownload, int, error) {
+	ret := _mock.Called(ctx, acoID, since, offset, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetJobKey")
+		panic("no return value specified for GetAuditEvents")
 	}
 
-	var r0 *JobKey
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, string) (*JobKey, error)); ok {
-		return returnFunc(ctx, jobID, filename)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, string) *JobKey); ok {
-		r0 = returnFunc(ctx, jobID, filename)
+	var r0 []*Job
+	var r1 []*JobKeyDownload
+	var r2 int
+	var r3 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, int, int) ([]*Job, []*JobKeyDownload, int, error)); ok {
+		return returnFunc(ctx, acoID, since, offset, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, int, int) []*Job); ok {
+		r0 = returnFunc(ctx, acoID, since, offset, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*JobKey)
+			r0 = ret.Get(0).([]*Job)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
-		r1 = returnFunc(ctx, jobID, filename)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, int, int) []*JobKeyDownload); ok {
+		r1 = returnFunc(ctx, acoID, since, offset, limit)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*JobKeyDownload)
+		}
 	}
-	return r0, r1
+	if returnFunc, ok := ret.Get(2).(func(context.Context, uuid.UUID, time.Time, int, int) int); ok {
+		r2 = returnFunc(ctx, acoID, since, offset, limit)
+	} else {
+		r2 = ret.Get(2).(int)
+	}
+	if returnFunc, ok := ret.Get(3).(func(context.Context, uuid.UUID, time.Time, int, int) error); ok {
+		r3 = returnFunc(ctx, acoID, since, offset, limit)
+	} else {
+		r3 = ret.Error(3)
+	}
+	return r0, r1, r2, r3
 }
 
-// MockRepository_GetJobKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobKey'
-type MockRepository_GetJobKey_Call struct {
+// MockRepository_GetAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuditEvents'
+type MockRepository_GetAuditEvents_Call struct {
 	*mock.Call
 }
 
-// GetJobKey is a helper method to define mock.On call
+// GetAuditEvents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - jobID uint
-//   - filename string
-func (_e *MockRepository_Expecter) GetJobKey(ctx interface{}, jobID interface{}, filename interface{}) *MockRepository_GetJobKey_Call {
-	return &MockRepository_GetJobKey_Call{Call: _e.mock.On("GetJobKey", ctx, jobID, filename)}
+//   - acoID uuid.UUID
+//   - since time.Time
+//   - offset int
+//   - limit int
+func (_e *MockRepository_Expecter) GetAuditEvents(ctx interface{}, acoID interface{}, since interface{}, offset interface{}, limit interface{}) *MockRepository_GetAuditEvents_Call {
+	return &MockRepository_GetAuditEvents_Call{Call: _e.mock.On("GetAuditEvents", ctx, acoID, since, offset, limit)}
 }
 
-func (_c *MockRepository_GetJobKey_Call) Run(run func(ctx context.Context, jobID uint, filename string)) *MockRepository_GetJobKey_Call {
+func (_c *MockRepository_GetAuditEvents_Call) Run(run func(ctx context.Context, acoID uuid.UUID, since time.Time, offset int, limit int)) *MockRepository_GetAuditEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint
+		var arg1 uuid.UUID
 		if args[1] != nil {
-			arg1 = args[1].(uint)
+			arg1 = args[1].(uuid.UUID)
 		}
-		var arg2 string
+		var arg2 time.Time
 		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
+			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetJobKey_Call) Return(jobKey *JobKey, err error) *MockRepository_GetJobKey_Call {
-	_c.Call.Return(jobKey, err)
+func (_c *MockRepository_GetAuditEvents_Call) Return(jobs []*Job, jobKeyDownloads []*JobKeyDownload, n int, err error) *MockRepository_GetAuditEvents_Call {
+	_c.Call.Return(jobs, jobKeyDownloads, n, err)
 	return _c
 }
 
-func (_c *MockRepository_GetJobKey_Call) RunAndReturn(run func(ctx context.Context, jobID uint, filename string) (*JobKey, error)) *MockRepository_GetJobKey_Call {
+func (_c *MockRepository_GetAuditEvents_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, int, int) ([]*Job, []*JobKeyDownload, int, error)) *MockRepository_GetAuditEvents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetJobKeys provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetJobKeys(ctx context.Context, jobID uint) ([]*JobKey, error) {
-	ret := _mock.Called(ctx, jobID)
+// GetAuthSystemByClientID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetAuthSystemByClientID(ctx context.Context, clientID string) (*AuthSystem, error) {
+	ret := _mock.Called(ctx, clientID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetJobKeys")
+		panic("no return value specified for GetAuthSystemByClientID")
 	}
 
-	var r0 []*JobKey
+	var r0 *AuthSystem
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) ([]*JobKey, error)); ok {
-		return returnFunc(ctx, jobID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*AuthSystem, error)); ok {
+		return returnFunc(ctx, clientID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) []*JobKey); ok {
-		r0 = returnFunc(ctx, jobID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *AuthSystem); ok {
+		r0 = returnFunc(ctx, clientID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*JobKey)
+			r0 = ret.Get(0).(*AuthSystem)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
-		r1 = returnFunc(ctx, jobID)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, clientID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetJobKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobKeys'
-type MockRepository_GetJobKeys_Call struct {
+// MockRepository_GetAuthSystemByClientID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuthSystemByClientID'
+type MockRepository_GetAuthSystemByClientID_Call struct {
 	*mock.Call
 }
 
-// GetJobKeys is a helper method to define mock.On call
+// GetAuthSystemByClientID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - jobID uint
-func (_e *MockRepository_Expecter) GetJobKeys(ctx interface{}, jobID interface{}) *MockRepository_GetJobKeys_Call {
-	return &MockRepository_GetJobKeys_Call{Call: _e.mock.On("GetJobKeys", ctx, jobID)}
+//   - clientID string
+func (_e *MockRepository_Expecter) GetAuthSystemByClientID(ctx interface{}, clientID interface{}) *MockRepository_GetAuthSystemByClientID_Call {
+	return &MockRepository_GetAuthSystemByClientID_Call{Call: _e.mock.On("GetAuthSystemByClientID", ctx, clientID)}
 }
 
-func (_c *MockRepository_GetJobKeys_Call) Run(run func(ctx context.Context, jobID uint)) *MockRepository_GetJobKeys_Call {
+func (_c *MockRepository_GetAuthSystemByClientID_Call) Run(run func(ctx context.Context, clientID string)) *MockRepository_GetAuthSystemByClientID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uint
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(uint)
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetAuthSystemByClientID_Call) Return(r0 *AuthSystem, r1 error) *MockRepository_GetAuthSystemByClientID_Call {
+	_c.Call.Return(r0, r1)
+	return _c
+}
+
+func (_c *MockRepository_GetAuthSystemByClientID_Call) RunAndReturn(run func(context.Context, string) (*AuthSystem, error)) *MockRepository_GetAuthSystemByClientID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBenePrefsFileByName provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetBenePrefsFileByName(ctx context.Context, name string) (*BenePrefsFile, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBenePrefsFileByName")
+	}
+
+	var r0 *BenePrefsFile
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*BenePrefsFile, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *BenePrefsFile); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*BenePrefsFile)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetBenePrefsFileByName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBenePrefsFileByName'
+type MockRepository_GetBenePrefsFileByName_Call struct {
+	*mock.Call
+}
+
+// GetBenePrefsFileByName is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockRepository_Expecter) GetBenePrefsFileByName(ctx interface{}, name interface{}) *MockRepository_GetBenePrefsFileByName_Call {
+	return &MockRepository_GetBenePrefsFileByName_Call{Call: _e.mock.On("GetBenePrefsFileByName", ctx, name)}
+}
+
+func (_c *MockRepository_GetBenePrefsFileByName_Call) Run(run func(ctx context.Context, name string)) *MockRepository_GetBenePrefsFileByName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetBenePrefsFileByName_Call) Return(_a0 *BenePrefsFile, _a1 error) *MockRepository_GetBenePrefsFileByName_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_GetBenePrefsFileByName_Call) RunAndReturn(run func(context.Context, string) (*BenePrefsFile, error)) *MockRepository_GetBenePrefsFileByName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCCLFBeneficiaries provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCCLFBeneficiaries(ctx context.Context, cclfFileID uint, ignoredMBIs []string) ([]*CCLFBeneficiary, error) {
+	ret := _mock.Called(ctx, cclfFileID, ignoredMBIs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCCLFBeneficiaries")
+	}
+
+	var r0 []*CCLFBeneficiary
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, []string) ([]*CCLFBeneficiary, error)); ok {
+		return returnFunc(ctx, cclfFileID, ignoredMBIs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, []string) []*CCLFBeneficiary); ok {
+		r0 = returnFunc(ctx, cclfFileID, ignoredMBIs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*CCLFBeneficiary)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint, []string) error); ok {
+		r1 = returnFunc(ctx, cclfFileID, ignoredMBIs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCCLFBeneficiaries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFBeneficiaries'
+type MockRepository_GetCCLFBeneficiaries_Call struct {
+	*mock.Call
+}
+
+// GetCCLFBeneficiaries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cclfFileID uint
+//   - ignoredMBIs []string
+func (_e *MockRepository_Expecter) GetCCLFBeneficiaries(ctx interface{}, cclfFileID interface{}, ignoredMBIs interface{}) *MockRepository_GetCCLFBeneficiaries_Call {
+	return &MockRepository_GetCCLFBeneficiaries_Call{Call: _e.mock.On("GetCCLFBeneficiaries", ctx, cclfFileID, ignoredMBIs)}
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaries_Call) Run(run func(ctx context.Context, cclfFileID uint, ignoredMBIs []string)) *MockRepository_GetCCLFBeneficiaries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaries_Call) Return(cCLFBeneficiarys []*CCLFBeneficiary, err error) *MockRepository_GetCCLFBeneficiaries_Call {
+	_c.Call.Return(cCLFBeneficiarys, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaries_Call) RunAndReturn(run func(ctx context.Context, cclfFileID uint, ignoredMBIs []string) ([]*CCLFBeneficiary, error)) *MockRepository_GetCCLFBeneficiaries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCCLFBeneficiaryCount provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCCLFBeneficiaryCount(ctx context.Context, cclfFileID uint) (int, error) {
+	ret := _mock.Called(ctx, cclfFileID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCCLFBeneficiaryCount")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (int, error)); ok {
+		return returnFunc(ctx, cclfFileID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) int); ok {
+		r0 = returnFunc(ctx, cclfFileID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, cclfFileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCCLFBeneficiaryCount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFBeneficiaryCount'
+type MockRepository_GetCCLFBeneficiaryCount_Call struct {
+	*mock.Call
+}
+
+// GetCCLFBeneficiaryCount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cclfFileID uint
+func (_e *MockRepository_Expecter) GetCCLFBeneficiaryCount(ctx interface{}, cclfFileID interface{}) *MockRepository_GetCCLFBeneficiaryCount_Call {
+	return &MockRepository_GetCCLFBeneficiaryCount_Call{Call: _e.mock.On("GetCCLFBeneficiaryCount", ctx, cclfFileID)}
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryCount_Call) Run(run func(ctx context.Context, cclfFileID uint)) *MockRepository_GetCCLFBeneficiaryCount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryCount_Call) Return(count int, err error) *MockRepository_GetCCLFBeneficiaryCount_Call {
+	_c.Call.Return(count, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryCount_Call) RunAndReturn(run func(context.Context, uint) (int, error)) *MockRepository_GetCCLFBeneficiaryCount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCCLFBeneficiaryMBIs provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCCLFBeneficiaryMBIs(ctx context.Context, cclfFileID uint) ([]string, error) {
+	ret := _mock.Called(ctx, cclfFileID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCCLFBeneficiaryMBIs")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) ([]string, error)); ok {
+		return returnFunc(ctx, cclfFileID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) []string); ok {
+		r0 = returnFunc(ctx, cclfFileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, cclfFileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCCLFBeneficiaryMBIs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFBeneficiaryMBIs'
+type MockRepository_GetCCLFBeneficiaryMBIs_Call struct {
+	*mock.Call
+}
+
+// GetCCLFBeneficiaryMBIs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cclfFileID uint
+func (_e *MockRepository_Expecter) GetCCLFBeneficiaryMBIs(ctx interface{}, cclfFileID interface{}) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
+	return &MockRepository_GetCCLFBeneficiaryMBIs_Call{Call: _e.mock.On("GetCCLFBeneficiaryMBIs", ctx, cclfFileID)}
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryMBIs_Call) Run(run func(ctx context.Context, cclfFileID uint)) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryMBIs_Call) Return(strings []string, err error) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryMBIs_Call) RunAndReturn(run func(ctx context.Context, cclfFileID uint) ([]string, error)) *MockRepository_GetCCLFBeneficiaryMBIs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCCLFBeneficiaryPage provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCCLFBeneficiaryPage(ctx context.Context, cclfFileID uint, offset int, limit int) ([]*CCLFBeneficiary, error) {
+	ret := _mock.Called(ctx, cclfFileID, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCCLFBeneficiaryPage")
+	}
+
+	var r0 []*CCLFBeneficiary
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, int, int) ([]*CCLFBeneficiary, error)); ok {
+		return returnFunc(ctx, cclfFileID, offset, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, int, int) []*CCLFBeneficiary); ok {
+		r0 = returnFunc(ctx, cclfFileID, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*CCLFBeneficiary)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint, int, int) error); ok {
+		r1 = returnFunc(ctx, cclfFileID, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCCLFBeneficiaryPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFBeneficiaryPage'
+type MockRepository_GetCCLFBeneficiaryPage_Call struct {
+	*mock.Call
+}
+
+// GetCCLFBeneficiaryPage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cclfFileID uint
+//   - offset int
+//   - limit int
+func (_e *MockRepository_Expecter) GetCCLFBeneficiaryPage(ctx interface{}, cclfFileID interface{}, offset interface{}, limit interface{}) *MockRepository_GetCCLFBeneficiaryPage_Call {
+	return &MockRepository_GetCCLFBeneficiaryPage_Call{Call: _e.mock.On("GetCCLFBeneficiaryPage", ctx, cclfFileID, offset, limit)}
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryPage_Call) Run(run func(ctx context.Context, cclfFileID uint, offset int, limit int)) *MockRepository_GetCCLFBeneficiaryPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryPage_Call) Return(benes []*CCLFBeneficiary, err error) *MockRepository_GetCCLFBeneficiaryPage_Call {
+	_c.Call.Return(benes, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFBeneficiaryPage_Call) RunAndReturn(run func(context.Context, uint, int, int) ([]*CCLFBeneficiary, error)) *MockRepository_GetCCLFBeneficiaryPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCCLFFileByID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCCLFFileByID(ctx context.Context, ID uint) (*CCLFFile, error) {
+	ret := _mock.Called(ctx, ID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCCLFFileByID")
+	}
+
+	var r0 *CCLFFile
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (*CCLFFile, error)); ok {
+		return returnFunc(ctx, ID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) *CCLFFile); ok {
+		r0 = returnFunc(ctx, ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*CCLFFile)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCCLFFileByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFFileByID'
+type MockRepository_GetCCLFFileByID_Call struct {
+	*mock.Call
+}
+
+// GetCCLFFileByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ID uint
+func (_e *MockRepository_Expecter) GetCCLFFileByID(ctx interface{}, ID interface{}) *MockRepository_GetCCLFFileByID_Call {
+	return &MockRepository_GetCCLFFileByID_Call{Call: _e.mock.On("GetCCLFFileByID", ctx, ID)}
+}
+
+func (_c *MockRepository_GetCCLFFileByID_Call) Run(run func(ctx context.Context, ID uint)) *MockRepository_GetCCLFFileByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFFileByID_Call) Return(cCLFFile *CCLFFile, err error) *MockRepository_GetCCLFFileByID_Call {
+	_c.Call.Return(cCLFFile, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFFileByID_Call) RunAndReturn(run func(ctx context.Context, ID uint) (*CCLFFile, error)) *MockRepository_GetCCLFFileByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCCLFFileExistsByName provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCCLFFileExistsByName(ctx context.Context, name string) (bool, error) {
+	ret := _mock.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCCLFFileExistsByName")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, name)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCCLFFileExistsByName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCCLFFileExistsByName'
+type MockRepository_GetCCLFFileExistsByName_Call struct {
+	*mock.Call
+}
+
+// GetCCLFFileExistsByName is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *MockRepository_Expecter) GetCCLFFileExistsByName(ctx interface{}, name interface{}) *MockRepository_GetCCLFFileExistsByName_Call {
+	return &MockRepository_GetCCLFFileExistsByName_Call{Call: _e.mock.On("GetCCLFFileExistsByName", ctx, name)}
+}
+
+func (_c *MockRepository_GetCCLFFileExistsByName_Call) Run(run func(ctx context.Context, name string)) *MockRepository_GetCCLFFileExistsByName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFFileExistsByName_Call) Return(b bool, err error) *MockRepository_GetCCLFFileExistsByName_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCCLFFileExistsByName_Call) RunAndReturn(run func(ctx context.Context, name string) (bool, error)) *MockRepository_GetCCLFFileExistsByName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCMSIDByClientID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCMSIDByClientID(ctx context.Context, clientID string) (string, error) {
+	ret := _mock.Called(ctx, clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCMSIDByClientID")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return returnFunc(ctx, clientID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = returnFunc(ctx, clientID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCMSIDByClientID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCMSIDByClientID'
+type MockRepository_GetCMSIDByClientID_Call struct {
+	*mock.Call
+}
+
+// GetCMSIDByClientID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+func (_e *MockRepository_Expecter) GetCMSIDByClientID(ctx interface{}, clientID interface{}) *MockRepository_GetCMSIDByClientID_Call {
+	return &MockRepository_GetCMSIDByClientID_Call{Call: _e.mock.On("GetCMSIDByClientID", ctx, clientID)}
+}
+
+func (_c *MockRepository_GetCMSIDByClientID_Call) Run(run func(ctx context.Context, clientID string)) *MockRepository_GetCMSIDByClientID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCMSIDByClientID_Call) Return(s string, err error) *MockRepository_GetCMSIDByClientID_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockRepository_GetCMSIDByClientID_Call) RunAndReturn(run func(ctx context.Context, clientID string) (string, error)) *MockRepository_GetCMSIDByClientID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCredentialRotationByClientID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetCredentialRotationByClientID(ctx context.Context, clientID string) (*CredentialRotation, error) {
+	ret := _mock.Called(ctx, clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCredentialRotationByClientID")
+	}
+
+	var r0 *CredentialRotation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*CredentialRotation, error)); ok {
+		return returnFunc(ctx, clientID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *CredentialRotation); ok {
+		r0 = returnFunc(ctx, clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*CredentialRotation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetCredentialRotationByClientID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCredentialRotationByClientID'
+type MockRepository_GetCredentialRotationByClientID_Call struct {
+	*mock.Call
+}
+
+// GetCredentialRotationByClientID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+func (_e *MockRepository_Expecter) GetCredentialRotationByClientID(ctx interface{}, clientID interface{}) *MockRepository_GetCredentialRotationByClientID_Call {
+	return &MockRepository_GetCredentialRotationByClientID_Call{Call: _e.mock.On("GetCredentialRotationByClientID", ctx, clientID)}
+}
+
+func (_c *MockRepository_GetCredentialRotationByClientID_Call) Run(run func(ctx context.Context, clientID string)) *MockRepository_GetCredentialRotationByClientID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetCredentialRotationByClientID_Call) Return(r0 *CredentialRotation, r1 error) *MockRepository_GetCredentialRotationByClientID_Call {
+	_c.Call.Return(r0, r1)
+	return _c
+}
+
+func (_c *MockRepository_GetCredentialRotationByClientID_Call) RunAndReturn(run func(context.Context, string) (*CredentialRotation, error)) *MockRepository_GetCredentialRotationByClientID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetExpiredCredentialRotations provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetExpiredCredentialRotations(ctx context.Context, before time.Time) ([]*CredentialRotation, error) {
+	ret := _mock.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetExpiredCredentialRotations")
+	}
+
+	var r0 []*CredentialRotation
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) ([]*CredentialRotation, error)); ok {
+		return returnFunc(ctx, before)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) []*CredentialRotation); ok {
+		r0 = returnFunc(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*CredentialRotation)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetExpiredCredentialRotations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExpiredCredentialRotations'
+type MockRepository_GetExpiredCredentialRotations_Call struct {
+	*mock.Call
+}
+
+// GetExpiredCredentialRotations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockRepository_Expecter) GetExpiredCredentialRotations(ctx interface{}, before interface{}) *MockRepository_GetExpiredCredentialRotations_Call {
+	return &MockRepository_GetExpiredCredentialRotations_Call{Call: _e.mock.On("GetExpiredCredentialRotations", ctx, before)}
+}
+
+func (_c *MockRepository_GetExpiredCredentialRotations_Call) Run(run func(ctx context.Context, before time.Time)) *MockRepository_GetExpiredCredentialRotations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetExpiredCredentialRotations_Call) Return(r0 []*CredentialRotation, r1 error) *MockRepository_GetExpiredCredentialRotations_Call {
+	_c.Call.Return(r0, r1)
+	return _c
+}
+
+func (_c *MockRepository_GetExpiredCredentialRotations_Call) RunAndReturn(run func(context.Context, time.Time) ([]*CredentialRotation, error)) *MockRepository_GetExpiredCredentialRotations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobByID provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetJobByID(ctx context.Context, jobID uint) (*Job, error) {
+	ret := _mock.Called(ctx, jobID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobByID")
+	}
+
+	var r0 *Job
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (*Job, error)); ok {
+		return returnFunc(ctx, jobID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) *Job); ok {
+		r0 = returnFunc(ctx, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Job)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetJobByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobByID'
+type MockRepository_GetJobByID_Call struct {
+	*mock.Call
+}
+
+// GetJobByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobID uint
+func (_e *MockRepository_Expecter) GetJobByID(ctx interface{}, jobID interface{}) *MockRepository_GetJobByID_Call {
+	return &MockRepository_GetJobByID_Call{Call: _e.mock.On("GetJobByID", ctx, jobID)}
+}
+
+func (_c *MockRepository_GetJobByID_Call) Run(run func(ctx context.Context, jobID uint)) *MockRepository_GetJobByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetJobByID_Call) Return(job *Job, err error) *MockRepository_GetJobByID_Call {
+	_c.Call.Return(job, err)
+	return _c
+}
+
+func (_c *MockRepository_GetJobByID_Call) RunAndReturn(run func(ctx context.Context, jobID uint) (*Job, error)) *MockRepository_GetJobByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobKey provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetJobKey(ctx context.Context, jobID uint, filename string) (*JobKey, error) {
+	ret := _mock.Called(ctx, jobID, filename)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobKey")
+	}
+
+	var r0 *JobKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, string) (*JobKey, error)); ok {
+		return returnFunc(ctx, jobID, filename)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, string) *JobKey); ok {
+		r0 = returnFunc(ctx, jobID, filename)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*JobKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = returnFunc(ctx, jobID, filename)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetJobKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobKey'
+type MockRepository_GetJobKey_Call struct {
+	*mock.Call
+}
+
+// GetJobKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobID uint
+//   - filename string
+func (_e *MockRepository_Expecter) GetJobKey(ctx interface{}, jobID interface{}, filename interface{}) *MockRepository_GetJobKey_Call {
+	return &MockRepository_GetJobKey_Call{Call: _e.mock.On("GetJobKey", ctx, jobID, filename)}
+}
+
+func (_c *MockRepository_GetJobKey_Call) Run(run func(ctx context.Context, jobID uint, filename string)) *MockRepository_GetJobKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetJobKey_Call) Return(jobKey *JobKey, err error) *MockRepository_GetJobKey_Call {
+	_c.Call.Return(jobKey, err)
+	return _c
+}
+
+func (_c *MockRepository_GetJobKey_Call) RunAndReturn(run func(ctx context.Context, jobID uint, filename string) (*JobKey, error)) *MockRepository_GetJobKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobKeys provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetJobKeys(ctx context.Context, jobID uint) ([]*JobKey, error) {
+	ret := _mock.Called(ctx, jobID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobKeys")
+	}
+
+	var r0 []*JobKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) ([]*JobKey, error)); ok {
+		return returnFunc(ctx, jobID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) []*JobKey); ok {
+		r0 = returnFunc(ctx, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*JobKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetJobKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobKeys'
+type MockRepository_GetJobKeys_Call struct {
+	*mock.Call
+}
+
+// GetJobKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jobID uint
+func (_e *MockRepository_Expecter) GetJobKeys(ctx interface{}, jobID interface{}) *MockRepository_GetJobKeys_Call {
+	return &MockRepository_GetJobKeys_Call{Call: _e.mock.On("GetJobKeys", ctx, jobID)}
+}
+
+func (_c *MockRepository_GetJobKeys_Call) Run(run func(ctx context.Context, jobID uint)) *MockRepository_GetJobKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
 		}
 		run(
 			arg0,
@@ -1102,229 +2119,691 @@ func (_c *MockRepository_GetJobKeys_Call) Return(jobKeys []*JobKey, err error) *
 	return _c
 }
 
-func (_c *MockRepository_GetJobKeys_Call) RunAndReturn(run func(ctx context.Context, jobID uint) ([]*JobKey, error)) *MockRepository_GetJobKeys_Call {
+func (_c *MockRepository_GetJobKeys_Call) RunAndReturn(run func(ctx context.Context, jobID uint) ([]*JobKey, error)) *MockRepository_GetJobKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobKeysByJobIDs provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetJobKeysByJobIDs(ctx context.Context, acoID uuid.UUID, jobIDs []uint) (map[uint][]*JobKey, error) {
+	ret := _mock.Called(ctx, acoID, jobIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobKeysByJobIDs")
+	}
+
+	var r0 map[uint][]*JobKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uint) (map[uint][]*JobKey, error)); ok {
+		return returnFunc(ctx, acoID, jobIDs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uint) map[uint][]*JobKey); ok {
+		r0 = returnFunc(ctx, acoID, jobIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint][]*JobKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, []uint) error); ok {
+		r1 = returnFunc(ctx, acoID, jobIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetJobKeysByJobIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobKeysByJobIDs'
+type MockRepository_GetJobKeysByJobIDs_Call struct {
+	*mock.Call
+}
+
+// GetJobKeysByJobIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - acoID uuid.UUID
+//   - jobIDs []uint
+func (_e *MockRepository_Expecter) GetJobKeysByJobIDs(ctx interface{}, acoID interface{}, jobIDs interface{}) *MockRepository_GetJobKeysByJobIDs_Call {
+	return &MockRepository_GetJobKeysByJobIDs_Call{Call: _e.mock.On("GetJobKeysByJobIDs", ctx, acoID, jobIDs)}
+}
+
+func (_c *MockRepository_GetJobKeysByJobIDs_Call) Run(run func(ctx context.Context, acoID uuid.UUID, jobIDs []uint)) *MockRepository_GetJobKeysByJobIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 []uint
+		if args[2] != nil {
+			arg2 = args[2].([]uint)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetJobKeysByJobIDs_Call) Return(uintToJobKeys map[uint][]*JobKey, err error) *MockRepository_GetJobKeysByJobIDs_Call {
+	_c.Call.Return(uintToJobKeys, err)
+	return _c
+}
+
+func (_c *MockRepository_GetJobKeysByJobIDs_Call) RunAndReturn(run func(context.Context, uuid.UUID, []uint) (map[uint][]*JobKey, error)) *MockRepository_GetJobKeysByJobIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobs provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetJobs(ctx context.Context, acoID uuid.UUID, statuses ...JobStatus) ([]*Job, error) {
+	var tmpRet mock.Arguments
+	if len(statuses) > 0 {
+		tmpRet = _mock.Called(ctx, acoID, statuses)
+	} else {
+		tmpRet = _mock.Called(ctx, acoID)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobs")
+	}
+
+	var r0 []*Job
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, ...JobStatus) ([]*Job, error)); ok {
+		return returnFunc(ctx, acoID, statuses...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, ...JobStatus) []*Job); ok {
+		r0 = returnFunc(ctx, acoID, statuses...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Job)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, ...JobStatus) error); ok {
+		r1 = returnFunc(ctx, acoID, statuses...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobs'
+type MockRepository_GetJobs_Call struct {
+	*mock.Call
+}
+
+// GetJobs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - acoID uuid.UUID
+//   - statuses ...JobStatus
+func (_e *MockRepository_Expecter) GetJobs(ctx interface{}, acoID interface{}, statuses ...interface{}) *MockRepository_GetJobs_Call {
+	return &MockRepository_GetJobs_Call{Call: _e.mock.On("GetJobs",
+		append([]interface{}{ctx, acoID}, statuses...)...)}
+}
+
+func (_c *MockRepository_GetJobs_Call) Run(run func(ctx context.Context, acoID uuid.UUID, statuses ...JobStatus)) *MockRepository_GetJobs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 []JobStatus
+		var variadicArgs []JobStatus
+		if len(args) > 2 {
+			variadicArgs = args[2].([]JobStatus)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetJobs_Call) Return(jobs []*Job, err error) *MockRepository_GetJobs_Call {
+	_c.Call.Return(jobs, err)
+	return _c
+}
+
+func (_c *MockRepository_GetJobs_Call) RunAndReturn(run func(ctx context.Context, acoID uuid.UUID, statuses ...JobStatus) ([]*Job, error)) *MockRepository_GetJobs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobsByUpdateTimeAndStatus provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetJobsByUpdateTimeAndStatus(ctx context.Context, lowerBound time.Time, upperBound time.Time, statuses ...JobStatus) ([]*Job, error) {
+	var tmpRet mock.Arguments
+	if len(statuses) > 0 {
+		tmpRet = _mock.Called(ctx, lowerBound, upperBound, statuses)
+	} else {
+		tmpRet = _mock.Called(ctx, lowerBound, upperBound)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobsByUpdateTimeAndStatus")
+	}
+
+	var r0 []*Job
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, ...JobStatus) ([]*Job, error)); ok {
+		return returnFunc(ctx, lowerBound, upperBound, statuses...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, ...JobStatus) []*Job); ok {
+		r0 = returnFunc(ctx, lowerBound, upperBound, statuses...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Job)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, ...JobStatus) error); ok {
+		r1 = returnFunc(ctx, lowerBound, upperBound, statuses...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetJobsByUpdateTimeAndStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobsByUpdateTimeAndStatus'
+type MockRepository_GetJobsByUpdateTimeAndStatus_Call struct {
+	*mock.Call
+}
+
+// GetJobsByUpdateTimeAndStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lowerBound time.Time
+//   - upperBound time.Time
+//   - statuses ...JobStatus
+func (_e *MockRepository_Expecter) GetJobsByUpdateTimeAndStatus(ctx interface{}, lowerBound interface{}, upperBound interface{}, statuses ...interface{}) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
+	return &MockRepository_GetJobsByUpdateTimeAndStatus_Call{Call: _e.mock.On("GetJobsByUpdateTimeAndStatus",
+		append([]interface{}{ctx, lowerBound, upperBound}, statuses...)...)}
+}
+
+func (_c *MockRepository_GetJobsByUpdateTimeAndStatus_Call) Run(run func(ctx context.Context, lowerBound time.Time, upperBound time.Time, statuses ...JobStatus)) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 []JobStatus
+		var variadicArgs []JobStatus
+		if len(args) > 3 {
+			variadicArgs = args[3].([]JobStatus)
+		}
+		arg3 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetJobsByUpdateTimeAndStatus_Call) Return(jobs []*Job, err error) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
+	_c.Call.Return(jobs, err)
+	return _c
+}
+
+func (_c *MockRepository_GetJobsByUpdateTimeAndStatus_Call) RunAndReturn(run func(ctx context.Context, lowerBound time.Time, upperBound time.Time, statuses ...JobStatus) ([]*Job, error)) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLatestCCLFFile provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetLatestCCLFFile(ctx context.Context, cmsID string, cclfNum int, importStatus string, lowerBound time.Time, upperBound time.Time, fileType CCLFFileType) (*CCLFFile, error) {
+	ret := _mock.Called(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestCCLFFile")
+	}
+
+	var r0 *CCLFFile
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string, time.Time, time.Time, CCLFFileType) (*CCLFFile, error)); ok {
+		return returnFunc(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string, time.Time, time.Time, CCLFFileType) *CCLFFile); ok {
+		r0 = returnFunc(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*CCLFFile)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, string, time.Time, time.Time, CCLFFileType) error); ok {
+		r1 = returnFunc(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetLatestCCLFFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestCCLFFile'
+type MockRepository_GetLatestCCLFFile_Call struct {
+	*mock.Call
+}
+
+// GetLatestCCLFFile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmsID string
+//   - cclfNum int
+//   - importStatus string
+//   - lowerBound time.Time
+//   - upperBound time.Time
+//   - fileType CCLFFileType
+func (_e *MockRepository_Expecter) GetLatestCCLFFile(ctx interface{}, cmsID interface{}, cclfNum interface{}, importStatus interface{}, lowerBound interface{}, upperBound interface{}, fileType interface{}) *MockRepository_GetLatestCCLFFile_Call {
+	return &MockRepository_GetLatestCCLFFile_Call{Call: _e.mock.On("GetLatestCCLFFile", ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)}
+}
+
+func (_c *MockRepository_GetLatestCCLFFile_Call) Run(run func(ctx context.Context, cmsID string, cclfNum int, importStatus string, lowerBound time.Time, upperBound time.Time, fileType CCLFFileType)) *MockRepository_GetLatestCCLFFile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 time.Time
+		if args[4] != nil {
+			arg4 = args[4].(time.Time)
+		}
+		var arg5 time.Time
+		if args[5] != nil {
+			arg5 = args[5].(time.Time)
+		}
+		var arg6 CCLFFileType
+		if args[6] != nil {
+			arg6 = args[6].(CCLFFileType)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+			arg6,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetLatestCCLFFile_Call) Return(cCLFFile *CCLFFile, err error) *MockRepository_GetLatestCCLFFile_Call {
+	_c.Call.Return(cCLFFile, err)
+	return _c
+}
+
+func (_c *MockRepository_GetLatestCCLFFile_Call) RunAndReturn(run func(ctx context.Context, cmsID string, cclfNum int, importStatus string, lowerBound time.Time, upperBound time.Time, fileType CCLFFileType) (*CCLFFile, error)) *MockRepository_GetLatestCCLFFile_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetJobs provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetJobs(ctx context.Context, acoID uuid.UUID, statuses ...JobStatus) ([]*Job, error) {
-	var tmpRet mock.Arguments
-	if len(statuses) > 0 {
-		tmpRet = _mock.Called(ctx, acoID, statuses)
-	} else {
-		tmpRet = _mock.Called(ctx, acoID)
-	}
-	ret := tmpRet
+// GetSAMHSAOptOutMBIs provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetSAMHSAOptOutMBIs(ctx context.Context, mbis []string, lookbackDays int, upperBound time.Time) ([]string, error) {
+	ret := _mock.Called(ctx, mbis, lookbackDays, upperBound)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetJobs")
+		panic("no return value specified for GetSAMHSAOptOutMBIs")
 	}
 
-	var r0 []*Job
+	var r0 []string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, ...JobStatus) ([]*Job, error)); ok {
-		return returnFunc(ctx, acoID, statuses...)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, int, time.Time) ([]string, error)); ok {
+		return returnFunc(ctx, mbis, lookbackDays, upperBound)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, ...JobStatus) []*Job); ok {
-		r0 = returnFunc(ctx, acoID, statuses...)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, int, time.Time) []string); ok {
+		r0 = returnFunc(ctx, mbis, lookbackDays, upperBound)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*Job)
+			r0 = ret.Get(0).([]string)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, ...JobStatus) error); ok {
-		r1 = returnFunc(ctx, acoID, statuses...)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, int, time.Time) error); ok {
+		r1 = returnFunc(ctx, mbis, lookbackDays, upperBound)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobs'
-type MockRepository_GetJobs_Call struct {
+// MockRepository_GetSAMHSAOptOutMBIs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSAMHSAOptOutMBIs'
+type MockRepository_GetSAMHSAOptOutMBIs_Call struct {
 	*mock.Call
 }
 
-// GetJobs is a helper method to define mock.On call
+// GetSAMHSAOptOutMBIs is a helper method to define mock.On call
 //   - ctx context.Context
-//   - acoID uuid.UUID
-//   - statuses ...JobStatus
-func (_e *MockRepository_Expecter) GetJobs(ctx interface{}, acoID interface{}, statuses ...interface{}) *MockRepository_GetJobs_Call {
-	return &MockRepository_GetJobs_Call{Call: _e.mock.On("GetJobs",
-		append([]interface{}{ctx, acoID}, statuses...)...)}
+//   - mbis []string
+//   - lookbackDays int
+//   - upperBound time.Time
+func (_e *MockRepository_Expecter) GetSAMHSAOptOutMBIs(ctx interface{}, mbis interface{}, lookbackDays interface{}, upperBound interface{}) *MockRepository_GetSAMHSAOptOutMBIs_Call {
+	return &MockRepository_GetSAMHSAOptOutMBIs_Call{Call: _e.mock.On("GetSAMHSAOptOutMBIs", ctx, mbis, lookbackDays, upperBound)}
 }
 
-func (_c *MockRepository_GetJobs_Call) Run(run func(ctx context.Context, acoID uuid.UUID, statuses ...JobStatus)) *MockRepository_GetJobs_Call {
+func (_c *MockRepository_GetSAMHSAOptOutMBIs_Call) Run(run func(ctx context.Context, mbis []string, lookbackDays int, upperBound time.Time)) *MockRepository_GetSAMHSAOptOutMBIs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].([]string)
 		}
-		var arg2 []JobStatus
-		var variadicArgs []JobStatus
-		if len(args) > 2 {
-			variadicArgs = args[2].([]JobStatus)
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2...,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetJobs_Call) Return(jobs []*Job, err error) *MockRepository_GetJobs_Call {
-	_c.Call.Return(jobs, err)
+func (_c *MockRepository_GetSAMHSAOptOutMBIs_Call) Return(_a0 []string, _a1 error) *MockRepository_GetSAMHSAOptOutMBIs_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockRepository_GetJobs_Call) RunAndReturn(run func(ctx context.Context, acoID uuid.UUID, statuses ...JobStatus) ([]*Job, error)) *MockRepository_GetJobs_Call {
+func (_c *MockRepository_GetSAMHSAOptOutMBIs_Call) RunAndReturn(run func(context.Context, []string, int, time.Time) ([]string, error)) *MockRepository_GetSAMHSAOptOutMBIs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetJobsByUpdateTimeAndStatus provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetJobsByUpdateTimeAndStatus(ctx context.Context, lowerBound time.Time, upperBound time.Time, statuses ...JobStatus) ([]*Job, error) {
-	var tmpRet mock.Arguments
-	if len(statuses) > 0 {
-		tmpRet = _mock.Called(ctx, lowerBound, upperBound, statuses)
-	} else {
-		tmpRet = _mock.Called(ctx, lowerBound, upperBound)
-	}
-	ret := tmpRet
+// GetSuppressedMBIs provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetSuppressedMBIs(ctx context.Context, lookbackDays int, upperBound time.Time) ([]string, error) {
+	ret := _mock.Called(ctx, lookbackDays, upperBound)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetJobsByUpdateTimeAndStatus")
+		panic("no return value specified for GetSuppressedMBIs")
 	}
 
-	var r0 []*Job
+	var r0 []string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, ...JobStatus) ([]*Job, error)); ok {
-		return returnFunc(ctx, lowerBound, upperBound, statuses...)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) ([]string, error)); ok {
+		return returnFunc(ctx, lookbackDays, upperBound)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, ...JobStatus) []*Job); ok {
-		r0 = returnFunc(ctx, lowerBound, upperBound, statuses...)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) []string); ok {
+		r0 = returnFunc(ctx, lookbackDays, upperBound)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*Job)
+			r0 = ret.Get(0).([]string)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, ...JobStatus) error); ok {
-		r1 = returnFunc(ctx, lowerBound, upperBound, statuses...)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = returnFunc(ctx, lookbackDays, upperBound)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetJobsByUpdateTimeAndStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobsByUpdateTimeAndStatus'
-type MockRepository_GetJobsByUpdateTimeAndStatus_Call struct {
+// MockRepository_GetSuppressedMBIs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSuppressedMBIs'
+type MockRepository_GetSuppressedMBIs_Call struct {
 	*mock.Call
 }
 
-// GetJobsByUpdateTimeAndStatus is a helper method to define mock.On call
+// GetSuppressedMBIs is a helper method to define mock.On call
 //   - ctx context.Context
-//   - lowerBound time.Time
+//   - lookbackDays int
 //   - upperBound time.Time
-//   - statuses ...JobStatus
-func (_e *MockRepository_Expecter) GetJobsByUpdateTimeAndStatus(ctx interface{}, lowerBound interface{}, upperBound interface{}, statuses ...interface{}) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
-	return &MockRepository_GetJobsByUpdateTimeAndStatus_Call{Call: _e.mock.On("GetJobsByUpdateTimeAndStatus",
-		append([]interface{}{ctx, lowerBound, upperBound}, statuses...)...)}
+func (_e *MockRepository_Expecter) GetSuppressedMBIs(ctx interface{}, lookbackDays interface{}, upperBound interface{}) *MockRepository_GetSuppressedMBIs_Call {
+	return &MockRepository_GetSuppressedMBIs_Call{Call: _e.mock.On("GetSuppressedMBIs", ctx, lookbackDays, upperBound)}
 }
 
-func (_c *MockRepository_GetJobsByUpdateTimeAndStatus_Call) Run(run func(ctx context.Context, lowerBound time.Time, upperBound time.Time, statuses ...JobStatus)) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
+func (_c *MockRepository_GetSuppressedMBIs_Call) Run(run func(ctx context.Context, lookbackDays int, upperBound time.Time)) *MockRepository_GetSuppressedMBIs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 time.Time
+		var arg1 int
 		if args[1] != nil {
-			arg1 = args[1].(time.Time)
+			arg1 = args[1].(int)
 		}
 		var arg2 time.Time
 		if args[2] != nil {
 			arg2 = args[2].(time.Time)
 		}
-		var arg3 []JobStatus
-		var variadicArgs []JobStatus
-		if len(args) > 3 {
-			variadicArgs = args[3].([]JobStatus)
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetSuppressedMBIs_Call) Return(strings []string, err error) *MockRepository_GetSuppressedMBIs_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockRepository_GetSuppressedMBIs_Call) RunAndReturn(run func(ctx context.Context, lookbackDays int, upperBound time.Time) ([]string, error)) *MockRepository_GetSuppressedMBIs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWithdrawnCCLFFiles provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetWithdrawnCCLFFiles(ctx context.Context, cmsID string, cclfNum int, after time.Time, fileType CCLFFileType) ([]*CCLFFile, error) {
+	ret := _mock.Called(ctx, cmsID, cclfNum, after, fileType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawnCCLFFiles")
+	}
+
+	var r0 []*CCLFFile
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, time.Time, CCLFFileType) ([]*CCLFFile, error)); ok {
+		return returnFunc(ctx, cmsID, cclfNum, after, fileType)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, time.Time, CCLFFileType) []*CCLFFile); ok {
+		r0 = returnFunc(ctx, cmsID, cclfNum, after, fileType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*CCLFFile)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, time.Time, CCLFFileType) error); ok {
+		r1 = returnFunc(ctx, cmsID, cclfNum, after, fileType)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetWithdrawnCCLFFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawnCCLFFiles'
+type MockRepository_GetWithdrawnCCLFFiles_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawnCCLFFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmsID string
+//   - cclfNum int
+//   - after time.Time
+//   - fileType CCLFFileType
+func (_e *MockRepository_Expecter) GetWithdrawnCCLFFiles(ctx interface{}, cmsID interface{}, cclfNum interface{}, after interface{}, fileType interface{}) *MockRepository_GetWithdrawnCCLFFiles_Call {
+	return &MockRepository_GetWithdrawnCCLFFiles_Call{Call: _e.mock.On("GetWithdrawnCCLFFiles", ctx, cmsID, cclfNum, after, fileType)}
+}
+
+func (_c *MockRepository_GetWithdrawnCCLFFiles_Call) Run(run func(ctx context.Context, cmsID string, cclfNum int, after time.Time, fileType CCLFFileType)) *MockRepository_GetWithdrawnCCLFFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		var arg4 CCLFFileType
+		if args[4] != nil {
+			arg4 = args[4].(CCLFFileType)
 		}
-		arg3 = variadicArgs
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3...,
+			arg3,
+			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetJobsByUpdateTimeAndStatus_Call) Return(jobs []*Job, err error) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
-	_c.Call.Return(jobs, err)
+func (_c *MockRepository_GetWithdrawnCCLFFiles_Call) Return(cCLFFiles []*CCLFFile, err error) *MockRepository_GetWithdrawnCCLFFiles_Call {
+	_c.Call.Return(cCLFFiles, err)
 	return _c
 }
 
-func (_c *MockRepository_GetJobsByUpdateTimeAndStatus_Call) RunAndReturn(run func(ctx context.Context, lowerBound time.Time, upperBound time.Time, statuses ...JobStatus) ([]*Job, error)) *MockRepository_GetJobsByUpdateTimeAndStatus_Call {
+func (_c *MockRepository_GetWithdrawnCCLFFiles_Call) RunAndReturn(run func(context.Context, string, int, time.Time, CCLFFileType) ([]*CCLFFile, error)) *MockRepository_GetWithdrawnCCLFFiles_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetLatestCCLFFile provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetLatestCCLFFile(ctx context.Context, cmsID string, cclfNum int, importStatus string, lowerBound time.Time, upperBound time.Time, fileType CCLFFileType) (*CCLFFile, error) {
-	ret := _mock.Called(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+// InTransaction provides a mock function for the type MockRepository
+func (_mock *MockRepository) InTransaction(ctx context.Context, fn func(Repository) error) error {
+	ret := _mock.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InTransaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, func(Repository) error) error); ok {
+		r0 = returnFunc(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_InTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InTransaction'
+type MockRepository_InTransaction_Call struct {
+	*mock.Call
+}
+
+// InTransaction is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(Repository) error
+func (_e *MockRepository_Expecter) InTransaction(ctx interface{}, fn interface{}) *MockRepository_InTransaction_Call {
+	return &MockRepository_InTransaction_Call{Call: _e.mock.On("InTransaction", ctx, fn)}
+}
+
+func (_c *MockRepository_InTransaction_Call) Run(run func(ctx context.Context, fn func(Repository) error)) *MockRepository_InTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 func(Repository) error
+		if args[1] != nil {
+			arg1 = args[1].(func(Repository) error)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_InTransaction_Call) Return(_a0 error) *MockRepository_InTransaction_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRepository_InTransaction_Call) RunAndReturn(run func(context.Context, func(Repository) error) error) *MockRepository_InTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsTokenRevoked provides a mock function for the type MockRepository
+func (_mock *MockRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	ret := _mock.Called(ctx, tokenID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLatestCCLFFile")
+		panic("no return value specified for IsTokenRevoked")
 	}
 
-	var r0 *CCLFFile
+	var r0 bool
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string, time.Time, time.Time, CCLFFileType) (*CCLFFile, error)); ok {
-		return returnFunc(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return returnFunc(ctx, tokenID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, string, time.Time, time.Time, CCLFFileType) *CCLFFile); ok {
-		r0 = returnFunc(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = returnFunc(ctx, tokenID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*CCLFFile)
-		}
+		r0 = ret.Get(0).(bool)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, string, time.Time, time.Time, CCLFFileType) error); ok {
-		r1 = returnFunc(ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, tokenID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockRepository_GetLatestCCLFFile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestCCLFFile'
-type MockRepository_GetLatestCCLFFile_Call struct {
+// MockRepository_IsTokenRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsTokenRevoked'
+type MockRepository_IsTokenRevoked_Call struct {
 	*mock.Call
 }
 
-// GetLatestCCLFFile is a helper method to define mock.On call
+// IsTokenRevoked is a helper method to define mock.On call
 //   - ctx context.Context
-//   - cmsID string
-//   - cclfNum int
-//   - importStatus string
-//   - lowerBound time.Time
-//   - upperBound time.Time
-//   - fileType CCLFFileType
-func (_e *MockRepository_Expecter) GetLatestCCLFFile(ctx interface{}, cmsID interface{}, cclfNum interface{}, importStatus interface{}, lowerBound interface{}, upperBound interface{}, fileType interface{}) *MockRepository_GetLatestCCLFFile_Call {
-	return &MockRepository_GetLatestCCLFFile_Call{Call: _e.mock.On("GetLatestCCLFFile", ctx, cmsID, cclfNum, importStatus, lowerBound, upperBound, fileType)}
+//   - tokenID string
+func (_e *MockRepository_Expecter) IsTokenRevoked(ctx interface{}, tokenID interface{}) *MockRepository_IsTokenRevoked_Call {
+	return &MockRepository_IsTokenRevoked_Call{Call: _e.mock.On("IsTokenRevoked", ctx, tokenID)}
 }
 
-func (_c *MockRepository_GetLatestCCLFFile_Call) Run(run func(ctx context.Context, cmsID string, cclfNum int, importStatus string, lowerBound time.Time, upperBound time.Time, fileType CCLFFileType)) *MockRepository_GetLatestCCLFFile_Call {
+func (_c *MockRepository_IsTokenRevoked_Call) Run(run func(ctx context.Context, tokenID string)) *MockRepository_IsTokenRevoked_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1334,119 +2813,77 @@ func (_c *MockRepository_GetLatestCCLFFile_Call) Run(run func(ctx context.Contex
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
-		}
-		var arg4 time.Time
-		if args[4] != nil {
-			arg4 = args[4].(time.Time)
-		}
-		var arg5 time.Time
-		if args[5] != nil {
-			arg5 = args[5].(time.Time)
-		}
-		var arg6 CCLFFileType
-		if args[6] != nil {
-			arg6 = args[6].(CCLFFileType)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
-			arg4,
-			arg5,
-			arg6,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetLatestCCLFFile_Call) Return(cCLFFile *CCLFFile, err error) *MockRepository_GetLatestCCLFFile_Call {
-	_c.Call.Return(cCLFFile, err)
+func (_c *MockRepository_IsTokenRevoked_Call) Return(r0 bool, r1 error) *MockRepository_IsTokenRevoked_Call {
+	_c.Call.Return(r0, r1)
 	return _c
 }
 
-func (_c *MockRepository_GetLatestCCLFFile_Call) RunAndReturn(run func(ctx context.Context, cmsID string, cclfNum int, importStatus string, lowerBound time.Time, upperBound time.Time, fileType CCLFFileType) (*CCLFFile, error)) *MockRepository_GetLatestCCLFFile_Call {
+func (_c *MockRepository_IsTokenRevoked_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockRepository_IsTokenRevoked_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSuppressedMBIs provides a mock function for the type MockRepository
-func (_mock *MockRepository) GetSuppressedMBIs(ctx context.Context, lookbackDays int, upperBound time.Time) ([]string, error) {
-	ret := _mock.Called(ctx, lookbackDays, upperBound)
+// MarkCredentialRotationRevoked provides a mock function for the type MockRepository
+func (_mock *MockRepository) MarkCredentialRotationRevoked(ctx context.Context, id uint) error {
+	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSuppressedMBIs")
+		panic("no return value specified for MarkCredentialRotationRevoked")
 	}
 
-	var r0 []string
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) ([]string, error)); ok {
-		return returnFunc(ctx, lookbackDays, upperBound)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) []string); ok {
-		r0 = returnFunc(ctx, lookbackDays, upperBound)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
-		r1 = returnFunc(ctx, lookbackDays, upperBound)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = returnFunc(ctx, id)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockRepository_GetSuppressedMBIs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSuppressedMBIs'
-type MockRepository_GetSuppressedMBIs_Call struct {
+// MockRepository_MarkCredentialRotationRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkCredentialRotationRevoked'
+type MockRepository_MarkCredentialRotationRevoked_Call struct {
 	*mock.Call
 }
 
-// GetSuppressedMBIs is a helper method to define mock.On call
+// MarkCredentialRotationRevoked is a helper method to define mock.On call
 //   - ctx context.Context
-//   - lookbackDays int
-//   - upperBound time.Time
-func (_e *MockRepository_Expecter) GetSuppressedMBIs(ctx interface{}, lookbackDays interface{}, upperBound interface{}) *MockRepository_GetSuppressedMBIs_Call {
-	return &MockRepository_GetSuppressedMBIs_Call{Call: _e.mock.On("GetSuppressedMBIs", ctx, lookbackDays, upperBound)}
+//   - id uint
+func (_e *MockRepository_Expecter) MarkCredentialRotationRevoked(ctx interface{}, id interface{}) *MockRepository_MarkCredentialRotationRevoked_Call {
+	return &MockRepository_MarkCredentialRotationRevoked_Call{Call: _e.mock.On("MarkCredentialRotationRevoked", ctx, id)}
 }
 
-func (_c *MockRepository_GetSuppressedMBIs_Call) Run(run func(ctx context.Context, lookbackDays int, upperBound time.Time)) *MockRepository_GetSuppressedMBIs_Call {
+func (_c *MockRepository_MarkCredentialRotationRevoked_Call) Run(run func(ctx context.Context, id uint)) *MockRepository_MarkCredentialRotationRevoked_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 uint
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 time.Time
-		if args[2] != nil {
-			arg2 = args[2].(time.Time)
+			arg1 = args[1].(uint)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_GetSuppressedMBIs_Call) Return(strings []string, err error) *MockRepository_GetSuppressedMBIs_Call {
-	_c.Call.Return(strings, err)
+func (_c *MockRepository_MarkCredentialRotationRevoked_Call) Return(r0 error) *MockRepository_MarkCredentialRotationRevoked_Call {
+	_c.Call.Return(r0)
 	return _c
 }
 
-func (_c *MockRepository_GetSuppressedMBIs_Call) RunAndReturn(run func(ctx context.Context, lookbackDays int, upperBound time.Time) ([]string, error)) *MockRepository_GetSuppressedMBIs_Call {
+func (_c *MockRepository_MarkCredentialRotationRevoked_Call) RunAndReturn(run func(context.Context, uint) error) *MockRepository_MarkCredentialRotationRevoked_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1514,6 +2951,75 @@ func (_c *MockRepository_UpdateACO_Call) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
+// UpdateAuthSystemSecret provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateAuthSystemSecret(ctx context.Context, id uint, secretHash string, secretExpiresAt time.Time) error {
+	ret := _mock.Called(ctx, id, secretHash, secretExpiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAuthSystemSecret")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, id, secretHash, secretExpiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateAuthSystemSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAuthSystemSecret'
+type MockRepository_UpdateAuthSystemSecret_Call struct {
+	*mock.Call
+}
+
+// UpdateAuthSystemSecret is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+//   - secretHash string
+//   - secretExpiresAt time.Time
+func (_e *MockRepository_Expecter) UpdateAuthSystemSecret(ctx interface{}, id interface{}, secretHash interface{}, secretExpiresAt interface{}) *MockRepository_UpdateAuthSystemSecret_Call {
+	return &MockRepository_UpdateAuthSystemSecret_Call{Call: _e.mock.On("UpdateAuthSystemSecret", ctx, id, secretHash, secretExpiresAt)}
+}
+
+func (_c *MockRepository_UpdateAuthSystemSecret_Call) Run(run func(ctx context.Context, id uint, secretHash string, secretExpiresAt time.Time)) *MockRepository_UpdateAuthSystemSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateAuthSystemSecret_Call) Return(r0 error) *MockRepository_UpdateAuthSystemSecret_Call {
+	_c.Call.Return(r0)
+	return _c
+}
+
+func (_c *MockRepository_UpdateAuthSystemSecret_Call) RunAndReturn(run func(context.Context, uint, string, time.Time) error) *MockRepository_UpdateAuthSystemSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateBenePrefsImportStatus provides a mock function for the type MockRepository
 func (_mock *MockRepository) UpdateBenePrefsImportStatus(ctx context.Context, fileID uint, status string) error {
 	ret := _mock.Called(ctx, fileID, status)
@@ -1640,6 +3146,92 @@ func (_c *MockRepository_UpdateCCLFFileImportStatus_Call) RunAndReturn(run func(
 	return _c
 }
 
+// UpdateCCLFFileStatus provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateCCLFFileStatus(ctx context.Context, fileID uint, status string, reason string, changedBy string) (*CCLFFileStatusChange, error) {
+	ret := _mock.Called(ctx, fileID, status, reason, changedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCCLFFileStatus")
+	}
+
+	var r0 *CCLFFileStatusChange
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, string, string, string) (*CCLFFileStatusChange, error)); ok {
+		return returnFunc(ctx, fileID, status, reason, changedBy)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint, string, string, string) *CCLFFileStatusChange); ok {
+		r0 = returnFunc(ctx, fileID, status, reason, changedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*CCLFFileStatusChange)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint, string, string, string) error); ok {
+		r1 = returnFunc(ctx, fileID, status, reason, changedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_UpdateCCLFFileStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCCLFFileStatus'
+type MockRepository_UpdateCCLFFileStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateCCLFFileStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fileID uint
+//   - status string
+//   - reason string
+//   - changedBy string
+func (_e *MockRepository_Expecter) UpdateCCLFFileStatus(ctx interface{}, fileID interface{}, status interface{}, reason interface{}, changedBy interface{}) *MockRepository_UpdateCCLFFileStatus_Call {
+	return &MockRepository_UpdateCCLFFileStatus_Call{Call: _e.mock.On("UpdateCCLFFileStatus", ctx, fileID, status, reason, changedBy)}
+}
+
+func (_c *MockRepository_UpdateCCLFFileStatus_Call) Run(run func(ctx context.Context, fileID uint, status string, reason string, changedBy string)) *MockRepository_UpdateCCLFFileStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 string
+		if args[4] != nil {
+			arg4 = args[4].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateCCLFFileStatus_Call) Return(cCLFFileStatusChange *CCLFFileStatusChange, err error) *MockRepository_UpdateCCLFFileStatus_Call {
+	_c.Call.Return(cCLFFileStatusChange, err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateCCLFFileStatus_Call) RunAndReturn(run func(context.Context, uint, string, string, string) (*CCLFFileStatusChange, error)) *MockRepository_UpdateCCLFFileStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateJob provides a mock function for the type MockRepository
 func (_mock *MockRepository) UpdateJob(ctx context.Context, j Job) error {
 	ret := _mock.Called(ctx, j)
@@ -1696,3 +3288,69 @@ func (_c *MockRepository_UpdateJob_Call) RunAndReturn(run func(ctx context.Conte
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpsertMBILinks provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpsertMBILinks(ctx context.Context, fileID uint) (int64, error) {
+	ret := _mock.Called(ctx, fileID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertMBILinks")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) (int64, error)); ok {
+		return returnFunc(ctx, fileID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint) int64); ok {
+		r0 = returnFunc(ctx, fileID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = returnFunc(ctx, fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_UpsertMBILinks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertMBILinks'
+type MockRepository_UpsertMBILinks_Call struct {
+	*mock.Call
+}
+
+// UpsertMBILinks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fileID uint
+func (_e *MockRepository_Expecter) UpsertMBILinks(ctx interface{}, fileID interface{}) *MockRepository_UpsertMBILinks_Call {
+	return &MockRepository_UpsertMBILinks_Call{Call: _e.mock.On("UpsertMBILinks", ctx, fileID)}
+}
+
+func (_c *MockRepository_UpsertMBILinks_Call) Run(run func(ctx context.Context, fileID uint)) *MockRepository_UpsertMBILinks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint
+		if args[1] != nil {
+			arg1 = args[1].(uint)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpsertMBILinks_Call) Return(_a0 int64, _a1 error) *MockRepository_UpsertMBILinks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_UpsertMBILinks_Call) RunAndReturn(run func(context.Context, uint) (int64, error)) *MockRepository_UpsertMBILinks_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -73,6 +73,60 @@ func (j *JobKey) IsError() bool {
 	return strings.Contains(j.FileName, "-error.ndjson")
 }
 
+// JobKeyDownload records a single download of a job's output file.
+type JobKeyDownload struct {
+	ID           uint
+	JobID        uint
+	ACOID        uuid.UUID
+	FileName     string
+	ResourceType string
+	ClientID     string
+	RequestID    string
+	Bytes        int64 // size of the response, zero for downloads recorded before sizes were kept
+	CreatedAt    time.Time
+}
+
+// ACOUsage summarizes the exports an ACO ran and the files it downloaded over a reporting period.
+type ACOUsage struct {
+	CMSID string
+	// Jobs counts the jobs created in the period by status. Completed jobs that have since been archived or
+	// expired are counted as completed, and failed jobs whose data was cleaned up as failed.
+	Jobs map[JobStatus]int
+	// ResourceTypes counts the jobs that produced a data file for each resource type.
+	ResourceTypes   map[string]int
+	Downloads       int
+	BytesDownloaded int64
+}
+
+// AuthSystem is a client registered with the local auth provider. SecretHash is a bcrypt hash; the secret
+// itself is only returned when the system is created or its secret is reset.
+type AuthSystem struct {
+	ID              uint
+	ACOID           uuid.UUID
+	ClientID        string
+	ClientName      string
+	SecretHash      string
+	PublicKey       string
+	IPs             []string
+	SecretExpiresAt time.Time
+	CreatedAt       time.Time
+}
+
+// CredentialRotation records that an ACO's client credentials were replaced. The previous credentials
+// remain valid until GraceExpiresAt, after which they are revoked.
+type CredentialRotation struct {
+	ID               uint
+	ACOID            uuid.UUID
+	PreviousClientID string
+	PreviousSystemID string
+	ClientID         string
+	SystemID         string
+	GraceExpiresAt   time.Time
+	SecretExpiresAt  *time.Time
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+}
+
 // ACO represents an Accountable Care Organization.
 type ACO struct {
 	ID                 uint
@@ -124,6 +178,18 @@ type CCLFFile struct {
 	CreatedAt time.Time
 }
 
+// CCLFFileStatusChange records an operator changing the import status of an attribution file,
+// e.g. to withdraw a bad roster.
+type CCLFFileStatusChange struct {
+	ID             uint
+	FileID         uint
+	PreviousStatus string
+	Status         string
+	Reason         string
+	ChangedBy      string
+	CreatedAt      time.Time
+}
+
 // "The MBI has 11 characters, like the Health Insurance Claim Number (HICN), which can have up to 11."
 // https://www.cms.gov/Medicare/New-Medicare-Card/Understanding-the-MBI-with-Format.pdf
 type CCLFBeneficiary struct {
@@ -131,6 +197,23 @@ type CCLFBeneficiary struct {
 	FileID       uint
 	MBI          string
 	BlueButtonID string
+	// The period the beneficiary was aligned to the entity. Either bound is zero when the attribution file
+	// did not provide it, in which case the beneficiary is attributed for the whole file.
+	AttributionStart time.Time
+	AttributionEnd   time.Time
+	// Every period the beneficiary was aligned to the entity in the attribution file, since a beneficiary can
+	// leave and rejoin within a file. Populated by the worker and not stored with the beneficiary.
+	AttributionPeriods []AttributionPeriod
+	// Other MBIs issued to the beneficiary, most recently seen first. Populated by the worker from the MBI
+	// crosswalk and not stored with the beneficiary.
+	LinkedMBIs []string
+}
+
+// AttributionPeriod is a period a beneficiary was aligned to an entity. Either bound is zero when the attribution
+// file did not provide it.
+type AttributionPeriod struct {
+	Start time.Time
+	End   time.Time
 }
 
 // A BenePrefsFile is a basic file representation that can be stored in a database.
@@ -139,6 +222,8 @@ type BenePrefsFile struct {
 	Name         string
 	Timestamp    time.Time
 	ImportStatus string
+	// SHA-256 of the file's records, empty for files imported before checksums were recorded
+	Checksum string
 }
 
 // BenePrefsFilenameMetadata is metadata information parsed from the filename.
@@ -149,6 +234,9 @@ type BenePrefsFilenameMetadata struct {
 	Imported     bool
 	DeliveryDate time.Time
 	FileID       uint
+	Checksum     string
+	// Set once a file that failed validation or import has been moved out of the import path
+	Quarantined bool
 }
 
 func (m BenePrefsFilenameMetadata) String() string {
@@ -10,6 +10,7 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
@@ -68,9 +69,18 @@ func DeleteACO(t *testing.T, db *sql.DB, acoID uuid.UUID) {
 }
 
 func CreateCCLFBeneficiary(t *testing.T, db *sql.DB, bene *models.CCLFBeneficiary) {
+	// Unset attribution dates are stored as NULL
+	var start, end interface{}
+	if !bene.AttributionStart.IsZero() {
+		start = bene.AttributionStart
+	}
+	if !bene.AttributionEnd.IsZero() {
+		end = bene.AttributionEnd
+	}
+
 	ib := sqlFlavor.NewInsertBuilder().InsertInto("cclf_beneficiaries")
-	ib.Cols("file_id", "mbi", "blue_button_id").
-		Values(bene.FileID, bene.MBI, bene.BlueButtonID)
+	ib.Cols("file_id", "mbi", "blue_button_id", "attribution_start", "attribution_end").
+		Values(bene.FileID, bene.MBI, bene.BlueButtonID, start, end)
 	query, args := ib.Build()
 	// Append the RETURNING id to retrieve the auto-generated ID value associated with the bene
 	query = fmt.Sprintf("%s RETURNING id", query)
@@ -247,7 +257,7 @@ func GetSuppressionFileByName(t *testing.T, db *sql.DB, names ...string) []model
 		nameArgs[i] = name
 	}
 
-	sb := sqlFlavor.NewSelectBuilder().Select("id", "name", "timestamp", "import_status").From("suppression_files")
+	sb := sqlFlavor.NewSelectBuilder().Select("id", "name", "timestamp", "import_status", "checksum").From("suppression_files")
 	sb.Where(sb.In("name", nameArgs...))
 
 	query, args := sb.Build()
@@ -258,9 +268,13 @@ func GetSuppressionFileByName(t *testing.T, db *sql.DB, names ...string) []model
 
 	var files []models.BenePrefsFile
 	for rows.Next() {
-		var sf models.BenePrefsFile
-		err = rows.Scan(&sf.ID, &sf.Name, &sf.Timestamp, &sf.ImportStatus)
+		var (
+			sf       models.BenePrefsFile
+			checksum sql.NullString
+		)
+		err = rows.Scan(&sf.ID, &sf.Name, &sf.Timestamp, &sf.ImportStatus, &checksum)
 		assert.NoError(t, err)
+		sf.Checksum = checksum.String
 		files = append(files, sf)
 	}
 
@@ -312,6 +326,38 @@ func GetSuppressionsByFileID(t *testing.T, db *sql.DB, fileID uint) []models.Ben
 	return suppressions
 }
 
+// GetMBILinks returns the MBIs linked to the beneficiary link key along with when each was last seen.
+func GetMBILinks(t *testing.T, db *sql.DB, linkKey int) map[string]time.Time {
+	sb := sqlFlavor.NewSelectBuilder().Select("mbi", "last_seen").From("mbi_links")
+	sb.Where(sb.Equal("beneficiary_link_key", linkKey))
+
+	query, args := sb.Build()
+	rows, err := db.Query(query, args...)
+	assert.NoError(t, err)
+	defer rows.Close()
+
+	links := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			mbi      string
+			lastSeen time.Time
+		)
+		assert.NoError(t, rows.Scan(&mbi, &lastSeen))
+		links[mbi] = lastSeen
+	}
+	assert.NoError(t, rows.Err())
+
+	return links
+}
+
+func DeleteMBILinks(t *testing.T, db *sql.DB, linkKey int) {
+	delete := sqlFlavor.NewDeleteBuilder().DeleteFrom("mbi_links")
+	delete.Where(delete.Equal("beneficiary_link_key", linkKey))
+	query, args := delete.Build()
+	_, err := db.Exec(query, args...)
+	assert.NoError(t, err)
+}
+
 func getFileIDsForCMSID(db *sql.DB, cmsID string) ([]interface{}, error) {
 	sb := sqlFlavor.NewSelectBuilder().Select("id").From("cclf_files")
 	sb.Where(sb.Equal("aco_cms_id", cmsID))
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -37,6 +38,28 @@ func NewRepositoryTx(tx *sql.Tx) *Repository {
 	return &Repository{&database.Tx{Tx: tx}, &database.Tx{Tx: tx}}
 }
 
+func (r *Repository) InTransaction(ctx context.Context, fn func(models.Repository) error) error {
+	db, ok := r.Executable.(*database.DB)
+	if !ok {
+		return fn(r)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	if err = fn(NewRepositoryTx(tx)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %s)", err, rollbackErr)
+		}
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (r *Repository) CreateACO(ctx context.Context, aco models.ACO) error {
 	ib := sqlFlavor.NewInsertBuilder().InsertInto("acos")
 	ib.Cols("uuid", "cms_id", "client_id", "name", "termination_details")
@@ -60,6 +83,44 @@ func (r *Repository) GetACOByCMSID(ctx context.Context, cmsID string) (*models.A
 	return r.getACO(ctx, "cms_id", cmsID)
 }
 
+func (r *Repository) GetACOByClientCertSubjects(ctx context.Context, subjects []string) (*models.ACO, error) {
+	values := make([]interface{}, len(subjects))
+	for i, v := range subjects {
+		values[i] = v
+	}
+
+	sb := sqlFlavor.NewSelectBuilder().Select(append(slices.Clone(acoColumns), "client_cert_subject")...).From("acos")
+	sb.Where(sb.In("client_cert_subject", values...))
+
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// The subject column is unique, so there is at most one ACO per subject
+	acos := make(map[string]*models.ACO)
+	for rows.Next() {
+		var subject string
+		aco, err := scanACO(rows, &subject)
+		if err != nil {
+			return nil, err
+		}
+		acos[subject] = aco
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, subject := range subjects {
+		if aco, ok := acos[subject]; ok {
+			return aco, nil
+		}
+	}
+	return nil, fmt.Errorf("no ACO record found for client certificate subjects %v: %w", subjects, sql.ErrNoRows)
+}
+
 func (r *Repository) UpdateACO(ctx context.Context, acoUUID uuid.UUID, fieldsAndValues map[string]interface{}) error {
 	ub := sqlFlavor.NewUpdateBuilder().Update("acos")
 	for field, value := range fieldsAndValues {
@@ -91,6 +152,119 @@ func (r *Repository) UpdateACO(ctx context.Context, acoUUID uuid.UUID, fieldsAnd
 	return nil
 }
 
+func (r *Repository) CreateAuthSystem(ctx context.Context, system models.AuthSystem) (uint, error) {
+	ib := sqlFlavor.NewInsertBuilder().InsertInto("auth_systems")
+	ib.Cols("aco_id", "client_id", "client_name", "secret_hash", "public_key", "ips", "secret_expires_at")
+	ib.Values(system.ACOID, system.ClientID, system.ClientName, system.SecretHash, system.PublicKey,
+		strings.Join(system.IPs, ","), system.SecretExpiresAt)
+	query, args := ib.Build()
+	// Append the RETURNING id to retrieve the auto-generated ID value associated with the system
+	query = fmt.Sprintf(constants.CCLFFileRetID, query)
+
+	var id uint
+	if err := r.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *Repository) GetAuthSystemByClientID(ctx context.Context, clientID string) (*models.AuthSystem, error) {
+	sb := sqlFlavor.NewSelectBuilder()
+	sb.Select("id", "aco_id", "client_id", "client_name", "secret_hash", "public_key", "ips", "secret_expires_at", "created_at")
+	sb.From("auth_systems")
+	sb.Where(sb.Equal("client_id", clientID), sb.IsNull("deleted_at"))
+
+	query, args := sb.Build()
+	var (
+		system         models.AuthSystem
+		publicKey, ips sql.NullString
+	)
+	err := r.QueryRowContext(ctx, query, args...).Scan(&system.ID, &system.ACOID, &system.ClientID, &system.ClientName,
+		&system.SecretHash, &publicKey, &ips, &system.SecretExpiresAt, &system.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no system found for client %s: %w", clientID, err)
+		}
+		return nil, err
+	}
+	system.PublicKey = publicKey.String
+	if ips.String != "" {
+		system.IPs = strings.Split(ips.String, ",")
+	}
+	return &system, nil
+}
+
+func (r *Repository) UpdateAuthSystemSecret(ctx context.Context, id uint, secretHash string, secretExpiresAt time.Time) error {
+	ub := sqlFlavor.NewUpdateBuilder().Update("auth_systems")
+	ub.Set(
+		ub.Assign("secret_hash", secretHash),
+		ub.Assign("secret_expires_at", secretExpiresAt),
+		ub.Assign("updated_at", sqlbuilder.Raw("NOW()")),
+	)
+	ub.Where(ub.Equal("id", id), ub.IsNull("deleted_at"))
+
+	return r.updateAuthSystem(ctx, ub, id)
+}
+
+func (r *Repository) DeleteAuthSystem(ctx context.Context, id uint) error {
+	ub := sqlFlavor.NewUpdateBuilder().Update("auth_systems")
+	ub.Set(ub.Assign("deleted_at", sqlbuilder.Raw("NOW()")))
+	ub.Where(ub.Equal("id", id), ub.IsNull("deleted_at"))
+
+	return r.updateAuthSystem(ctx, ub, id)
+}
+
+func (r *Repository) updateAuthSystem(ctx context.Context, ub *sqlbuilder.UpdateBuilder, id uint) error {
+	query, args := ub.Build()
+	result, err := r.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("system %d not updated, no active row found", id)
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateRevokedToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ib := sqlFlavor.NewInsertBuilder().InsertInto("auth_revoked_tokens")
+	ib.Cols("token_id", "expires_at")
+	ib.Values(tokenID, expiresAt)
+	query, args := ib.Build()
+	// Revoking the same token twice is not an error
+	query = fmt.Sprintf("%s ON CONFLICT (token_id) DO NOTHING", query)
+
+	if _, err := r.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	// Expired tokens are rejected on expiry alone, so their revocations no longer need to be kept
+	del := sqlFlavor.NewDeleteBuilder().DeleteFrom("auth_revoked_tokens")
+	del.Where(del.LessThan("expires_at", time.Now()))
+	query, args = del.Build()
+	_, err := r.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *Repository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	sb := sqlFlavor.NewSelectBuilder().Select("COUNT(1)").From("auth_revoked_tokens")
+	sb.Where(sb.Equal("token_id", tokenID))
+
+	query, args := sb.Build()
+	var count int
+	if err := r.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *Repository) GetCCLFFileByID(ctx context.Context, ID uint) (*models.CCLFFile, error) {
 	sb := sqlFlavor.NewSelectBuilder()
 	sb.Select("id", "name", "timestamp", "performance_year", "created_at")
@@ -217,10 +391,74 @@ func (r *Repository) UpdateCCLFFileImportStatus(ctx context.Context, fileID uint
 	return nil
 }
 
+func (r *Repository) UpdateCCLFFileStatus(ctx context.Context, fileID uint, status, reason, changedBy string) (*models.CCLFFileStatusChange, error) {
+	// Lock the file so its status and the change history are updated together
+	prev := sqlFlavor.NewSelectBuilder()
+	prev.Select("id", "import_status").From("cclf_files")
+	prev.Where(
+		prev.Equal("id", fileID),
+		prev.In("import_status", constants.ImportComplete, constants.ImportSuperseded, constants.ImportInvalid),
+		prev.NotEqual("import_status", status),
+	)
+	prev.ForUpdate()
+
+	query, args := sqlbuilder.Buildf(
+		"WITH prev AS (%v), "+
+			"upd AS (UPDATE cclf_files SET import_status = %v FROM prev WHERE cclf_files.id = prev.id) "+
+			"INSERT INTO cclf_file_status_changes (file_id, previous_status, status, reason, changed_by) "+
+			"SELECT id, import_status, %v, %v, %v FROM prev RETURNING id, previous_status, created_at",
+		prev, status, status, reason, changedBy,
+	).BuildWithFlavor(sqlFlavor)
+
+	change := models.CCLFFileStatusChange{FileID: fileID, Status: status, Reason: reason, ChangedBy: changedBy}
+	if err := r.QueryRowContext(ctx, query, args...).Scan(&change.ID, &change.PreviousStatus, &change.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no cclf file %d that can be moved to %s: %w", fileID, status, err)
+		}
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+func (r *Repository) GetWithdrawnCCLFFiles(ctx context.Context, cmsID string, cclfNum int, after time.Time, fileType models.CCLFFileType) ([]*models.CCLFFile, error) {
+	sb := sqlFlavor.NewSelectBuilder()
+	sb.Select("id", "name", "timestamp", "performance_year", "import_status", "created_at")
+	sb.From("cclf_files")
+	sb.Where(
+		sb.Equal("aco_cms_id", cmsID),
+		sb.Equal("cclf_num", cclfNum),
+		sb.In("import_status", constants.ImportSuperseded, constants.ImportInvalid),
+		sb.Equal("type", fileType),
+	)
+	if !after.IsZero() {
+		sb.Where(sb.GreaterThan("timestamp", after))
+	}
+	sb.OrderBy("timestamp").Desc()
+
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []*models.CCLFFile
+	for rows.Next() {
+		f := models.CCLFFile{ACOCMSID: cmsID, CCLFNum: cclfNum, Type: fileType}
+		if err := rows.Scan(&f.ID, &f.Name, &f.Timestamp, &f.PerformanceYear, &f.ImportStatus, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		files = append(files, &f)
+	}
+
+	return files, rows.Err()
+}
+
 func (r *Repository) GetCCLFBeneficiaryMBIs(ctx context.Context, cclfFileID uint) ([]string, error) {
 	var mbis []string
 
-	sb := sqlFlavor.NewSelectBuilder().Select("mbi").From("cclf_beneficiaries")
+	sb := sqlFlavor.NewSelectBuilder().Distinct().Select("mbi").From("cclf_beneficiaries")
 	sb.Where(sb.Equal("file_id", cclfFileID))
 
 	query, args := sb.Build()
@@ -244,31 +482,82 @@ func (r *Repository) GetCCLFBeneficiaryMBIs(ctx context.Context, cclfFileID uint
 	return mbis, nil
 }
 
-func (r *Repository) GetCCLFBeneficiaries(ctx context.Context, cclfFileID uint, ignoredMBIs []string) ([]*models.CCLFBeneficiary, error) {
-	var beneficiaries []*models.CCLFBeneficiary
+func (r *Repository) GetCCLFBeneficiaryCount(ctx context.Context, cclfFileID uint) (int, error) {
+	sb := sqlFlavor.NewSelectBuilder().Select("COUNT(DISTINCT mbi)").From("cclf_beneficiaries")
+	sb.Where(sb.Equal("file_id", cclfFileID))
+
+	query, args := sb.Build()
+	var count int
+	if err := r.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
 
-	// Subquery to deal with duplicate MBIs found within a single CCLF file.
-	// NOTE: We no longer have duplicate MBIs after this PR: https://github.com/CMSgov/bcda-app/pull/583
-	// We have to remove duplicates on older files, but once that's done, we can remove the subquery
-	// and query for the benes by file_id directly.
+// selectCCLFBeneficiaries selects a single beneficiary for each MBI in the file. Files list a beneficiary once
+// for each period they were aligned to the entity; the newest row is returned with an attribution period
+// spanning all of them, left open at either end if any of the periods are.
+func selectCCLFBeneficiaries(cclfFileID uint) *sqlbuilder.SelectBuilder {
+	// Older files may also contain duplicate MBIs, see https://github.com/CMSgov/bcda-app/pull/583
 	subSB := sqlFlavor.NewSelectBuilder()
-	subSB.Select("MAX(id)").From("cclf_beneficiaries").Where(
+	subSB.Select(
+		"MAX(id) AS id",
+		"CASE WHEN COUNT(attribution_start) = COUNT(*) THEN MIN(attribution_start) END AS attribution_start",
+		"CASE WHEN COUNT(attribution_end) = COUNT(*) THEN MAX(attribution_end) END AS attribution_end",
+	).From("cclf_beneficiaries").Where(
 		subSB.Equal("file_id", cclfFileID),
 	).GroupBy("mbi")
 
 	sb := sqlFlavor.NewSelectBuilder()
-	sb.Select("id", "file_id", "mbi", "blue_button_id")
-	sb.From("cclf_beneficiaries").Where(sb.In("id", subSB))
+	sb.Select("b.id", "b.file_id", "b.mbi", "b.blue_button_id", "p.attribution_start", "p.attribution_end")
+	sb.From(sb.BuilderAs(subSB, "p")).Join("cclf_beneficiaries b", "b.id = p.id")
+	return sb
+}
+
+func (r *Repository) GetCCLFBeneficiaryPage(ctx context.Context, cclfFileID uint, offset, limit int) ([]*models.CCLFBeneficiary, error) {
+	// Same de-duplication as GetCCLFBeneficiaries
+	sb := selectCCLFBeneficiaries(cclfFileID)
+	sb.OrderBy("b.mbi").Offset(offset).Limit(limit)
+
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var beneficiaries []*models.CCLFBeneficiary
+	for rows.Next() {
+		var (
+			bene       models.CCLFBeneficiary
+			bbID       sql.NullString
+			start, end sql.NullTime
+		)
+		if err := rows.Scan(&bene.ID, &bene.FileID, &bene.MBI, &bbID, &start, &end); err != nil {
+			return nil, err
+		}
+		bene.BlueButtonID = bbID.String
+		bene.AttributionStart, bene.AttributionEnd = start.Time, end.Time
+		beneficiaries = append(beneficiaries, &bene)
+	}
+
+	return beneficiaries, rows.Err()
+}
+
+func (r *Repository) GetCCLFBeneficiaries(ctx context.Context, cclfFileID uint, ignoredMBIs []string) ([]*models.CCLFBeneficiary, error) {
+	var beneficiaries []*models.CCLFBeneficiary
+
+	sb := selectCCLFBeneficiaries(cclfFileID)
 
 	if len(ignoredMBIs) != 0 {
 		ignored := make([]interface{}, len(ignoredMBIs))
 		for i, v := range ignoredMBIs {
 			ignored[i] = v
 		}
-		sb.Where(sb.NotIn("mbi", ignored...))
+		sb.Where(sb.NotIn("b.mbi", ignored...))
 	}
 
-	sb.OrderBy("blue_button_id ASC NULLS LAST")
+	sb.OrderBy("b.blue_button_id ASC NULLS LAST")
 
 	query, args := sb.Build()
 	rows, err := r.QueryContext(ctx, query, args...)
@@ -280,13 +569,15 @@ func (r *Repository) GetCCLFBeneficiaries(ctx context.Context, cclfFileID uint,
 
 	for rows.Next() {
 		var (
-			bene models.CCLFBeneficiary
-			bbID sql.NullString
+			bene       models.CCLFBeneficiary
+			bbID       sql.NullString
+			start, end sql.NullTime
 		)
-		if err := rows.Scan(&bene.ID, &bene.FileID, &bene.MBI, &bbID); err != nil {
+		if err := rows.Scan(&bene.ID, &bene.FileID, &bene.MBI, &bbID, &start, &end); err != nil {
 			return nil, err
 		}
 		bene.BlueButtonID = bbID.String
+		bene.AttributionStart, bene.AttributionEnd = start.Time, end.Time
 		beneficiaries = append(beneficiaries, &bene)
 	}
 	if err = rows.Err(); err != nil {
@@ -298,7 +589,7 @@ func (r *Repository) GetCCLFBeneficiaries(ctx context.Context, cclfFileID uint,
 
 func (r *Repository) CreateBenePrefsFile(ctx context.Context, file models.BenePrefsFile) (uint, error) {
 	ib := sqlFlavor.NewInsertBuilder().InsertInto("suppression_files")
-	ib.Cols("name", "timestamp", "import_status").Values(file.Name, file.Timestamp, file.ImportStatus)
+	ib.Cols("name", "timestamp", "import_status", "checksum").Values(file.Name, file.Timestamp, file.ImportStatus, file.Checksum)
 	query, args := ib.Build()
 
 	// Append the RETURNING id to retrieve the auto-generated ID value associated with the suppression file
@@ -364,22 +655,133 @@ func (r *Repository) UpdateBenePrefsImportStatus(ctx context.Context, fileID uin
 	return nil
 }
 
+func (r *Repository) GetBenePrefsFileByName(ctx context.Context, name string) (*models.BenePrefsFile, error) {
+	sb := sqlFlavor.NewSelectBuilder()
+	sb.Select("id", "name", "timestamp", "import_status", "checksum")
+	sb.From("suppression_files")
+	sb.Where(sb.Equal("name", name))
+
+	var (
+		file                   models.BenePrefsFile
+		importStatus, checksum sql.NullString
+	)
+	query, args := sb.Build()
+	row := r.QueryRowContext(ctx, query, args...)
+	if err := row.Scan(&file.ID, &file.Name, &file.Timestamp, &importStatus, &checksum); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	file.ImportStatus, file.Checksum = importStatus.String, checksum.String
+
+	return &file, nil
+}
+
+func (r *Repository) DeleteBenePrefsFile(ctx context.Context, fileID uint) error {
+	del := sqlFlavor.NewDeleteBuilder().DeleteFrom("suppressions")
+	del.Where(del.Equal("file_id", fileID))
+	query, args := del.Build()
+	if _, err := r.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	del = sqlFlavor.NewDeleteBuilder().DeleteFrom("suppression_files")
+	del.Where(del.Equal("id", fileID))
+	query, args = del.Build()
+	result, err := r.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("SuppressionFile %d not deleted, no row found", fileID)
+	}
+
+	return nil
+}
+
+func (r *Repository) UpsertMBILinks(ctx context.Context, fileID uint) (int64, error) {
+	sb := sqlFlavor.NewSelectBuilder()
+	sb.Select("beneficiary_link_key", "mbi", "MAX(COALESCE(effective_date, created_at, now()))")
+	sb.From("suppressions")
+	// A link key of 0 means the file did not identify the beneficiary
+	sb.Where(sb.Equal("file_id", fileID), sb.GreaterThan("beneficiary_link_key", 0), sb.IsNotNull("mbi"))
+	sb.GroupBy("beneficiary_link_key", "mbi")
+
+	query, args := sqlbuilder.Buildf(
+		"INSERT INTO mbi_links (beneficiary_link_key, mbi, last_seen) %v "+
+			"ON CONFLICT (beneficiary_link_key, mbi) DO UPDATE "+
+			"SET last_seen = GREATEST(mbi_links.last_seen, EXCLUDED.last_seen), updated_at = NOW()",
+		sb,
+	).BuildWithFlavor(sqlFlavor)
+
+	result, err := r.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (r *Repository) GetSuppressedMBIs(ctx context.Context, lookbackDays int, upperBound time.Time) ([]string, error) {
+	return r.getOptedOutMBIs(ctx, "effective_date", "preference_indicator", nil, lookbackDays, upperBound)
+}
+
+// GetSAMHSAOptOutMBIs returns which of mbis have a most recent SAMHSA (42 CFR Part 2) preference within the
+// lookback window that opts out of sharing substance use disorder data.
+func (r *Repository) GetSAMHSAOptOutMBIs(ctx context.Context, mbis []string, lookbackDays int, upperBound time.Time) ([]string, error) {
+	if len(mbis) == 0 {
+		return nil, nil
+	}
+	return r.getOptedOutMBIs(ctx, "samhsa_effective_date", "samhsa_preference_indicator", mbis, lookbackDays, upperBound)
+}
+
+// getOptedOutMBIs returns the MBIs whose latest preference, as identified by the dateCol and indicatorCol
+// columns of the suppressions table, is an opt out. Preferences recorded under any MBI linked to the same
+// beneficiary in mbi_links count towards each of their MBIs, so an opt out follows a reissued MBI. When mbis is
+// non-nil, only those MBIs are considered.
+func (r *Repository) getOptedOutMBIs(ctx context.Context, dateCol, indicatorCol string, mbis []string, lookbackDays int, upperBound time.Time) ([]string, error) {
 	var suppressedMBIs []string
 
 	lookbackDuration := time.Duration(-1*lookbackDays*24) * time.Hour
 	lowerBound := upperBound.Add(lookbackDuration)
 
-	subSB := sqlFlavor.NewSelectBuilder()
-	subSB.Select("mbi", "MAX(effective_date) as max_date").From("suppressions")
-	subSB.Where(
-		subSB.GreaterEqualThan("effective_date", lowerBound), subSB.LessEqualThan("effective_date", upperBound),
-		subSB.NotEqual("preference_indicator", ""),
-	).GroupBy("mbi")
+	// Preferences recorded under the MBI itself
+	ownSB := sqlFlavor.NewSelectBuilder()
+	ownSB.Select("mbi", fmt.Sprintf("%s AS pref_date", dateCol), fmt.Sprintf("%s AS indicator", indicatorCol)).From("suppressions")
+	ownSB.Where(
+		ownSB.GreaterEqualThan(dateCol, lowerBound), ownSB.LessEqualThan(dateCol, upperBound),
+		ownSB.NotEqual(indicatorCol, ""),
+	)
 
-	sb := sqlFlavor.NewSelectBuilder().Distinct().Select("s.mbi")
-	sb.From(sb.BuilderAs(subSB, "h")).Join("suppressions s", "s.mbi = h.mbi", "s.effective_date = h.max_date")
-	sb.Where(sb.Equal("preference_indicator", "N"))
+	// Preferences recorded under the beneficiary's other MBIs
+	linkedSB := sqlFlavor.NewSelectBuilder()
+	linkedSB.Select("known.mbi", "s."+dateCol, "s."+indicatorCol).From("suppressions s")
+	linkedSB.Join("mbi_links linked", "linked.mbi = s.mbi")
+	linkedSB.Join("mbi_links known", "known.beneficiary_link_key = linked.beneficiary_link_key", "known.mbi <> s.mbi")
+	linkedSB.Where(
+		linkedSB.GreaterEqualThan("s."+dateCol, lowerBound), linkedSB.LessEqualThan("s."+dateCol, upperBound),
+		linkedSB.NotEqual("s."+indicatorCol, ""),
+	)
+
+	if mbis != nil {
+		// Passed as a single array parameter so large attribution lists stay under the bind parameter limit
+		ownSB.Where(fmt.Sprintf("mbi = ANY(%s)", ownSB.Var(mbis)))
+		linkedSB.Where(fmt.Sprintf("known.mbi = ANY(%s)", linkedSB.Var(mbis)))
+	}
+
+	prefSB := sqlFlavor.NewSelectBuilder()
+	prefSB.Select("mbi", "pref_date", "indicator", "MAX(pref_date) OVER (PARTITION BY mbi) AS max_date")
+	prefSB.From(prefSB.BuilderAs(sqlFlavor.NewUnionBuilder().UnionAll(ownSB, linkedSB), "p"))
+
+	sb := sqlFlavor.NewSelectBuilder().Distinct().Select("h.mbi")
+	sb.From(sb.BuilderAs(prefSB, "h"))
+	sb.Where("h.pref_date = h.max_date", sb.Equal("h.indicator", "N"))
 
 	query, args := sb.Build()
 	rows, err := r.QueryContext(ctx, query, args...)
@@ -414,6 +816,124 @@ var jobColumns []string = []string{
 	"benes_attributed_to_aco",
 }
 
+func (r *Repository) CreateCredentialRotation(ctx context.Context, rotation models.CredentialRotation) error {
+	ib := sqlFlavor.NewInsertBuilder().InsertInto("credential_rotations").Cols(
+		"aco_id",
+		"previous_client_id",
+		"previous_system_id",
+		"client_id",
+		"system_id",
+		"grace_expires_at",
+		"secret_expires_at",
+		"created_at",
+	).Values(
+		rotation.ACOID,
+		rotation.PreviousClientID,
+		rotation.PreviousSystemID,
+		rotation.ClientID,
+		rotation.SystemID,
+		rotation.GraceExpiresAt,
+		rotation.SecretExpiresAt,
+		sqlbuilder.Raw("NOW()"),
+	)
+	query, args := ib.Build()
+
+	_, err := r.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *Repository) GetCredentialRotationByClientID(ctx context.Context, clientID string) (*models.CredentialRotation, error) {
+	sb := newCredentialRotationSelect()
+	sb.Where(sb.Or(sb.Equal("client_id", clientID), sb.Equal("previous_client_id", clientID)))
+	sb.OrderBy("created_at").Desc().Limit(1)
+
+	rotations, err := r.getCredentialRotations(ctx, sb)
+	if err != nil {
+		return nil, err
+	}
+	if len(rotations) == 0 {
+		return nil, fmt.Errorf("no credential rotation found for client %s: %w", clientID, sql.ErrNoRows)
+	}
+	return rotations[0], nil
+}
+
+func (r *Repository) GetExpiredCredentialRotations(ctx context.Context, before time.Time) ([]*models.CredentialRotation, error) {
+	sb := newCredentialRotationSelect()
+	sb.Where(sb.LessThan("grace_expires_at", before), sb.IsNull("revoked_at"))
+	sb.OrderBy("grace_expires_at")
+
+	return r.getCredentialRotations(ctx, sb)
+}
+
+func (r *Repository) MarkCredentialRotationRevoked(ctx context.Context, id uint) error {
+	ub := sqlFlavor.NewUpdateBuilder().Update("credential_rotations")
+	ub.Set(ub.Assign("revoked_at", sqlbuilder.Raw("NOW()")))
+	ub.Where(ub.Equal("id", id))
+
+	query, args := ub.Build()
+	result, err := r.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("credential rotation %d not updated, no row found", id)
+	}
+
+	return nil
+}
+
+func newCredentialRotationSelect() *sqlbuilder.SelectBuilder {
+	return sqlFlavor.NewSelectBuilder().Select(
+		"id",
+		"aco_id",
+		"previous_client_id",
+		"previous_system_id",
+		"client_id",
+		"system_id",
+		"grace_expires_at",
+		"secret_expires_at",
+		"revoked_at",
+		"created_at",
+	).From("credential_rotations")
+}
+
+func (r *Repository) getCredentialRotations(ctx context.Context, sb *sqlbuilder.SelectBuilder) ([]*models.CredentialRotation, error) {
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rotations []*models.CredentialRotation
+	for rows.Next() {
+		var (
+			rotation                   models.CredentialRotation
+			secretExpiresAt, revokedAt sql.NullTime
+		)
+		if err = rows.Scan(&rotation.ID, &rotation.ACOID, &rotation.PreviousClientID, &rotation.PreviousSystemID,
+			&rotation.ClientID, &rotation.SystemID, &rotation.GraceExpiresAt, &secretExpiresAt, &revokedAt,
+			&rotation.CreatedAt); err != nil {
+			return nil, err
+		}
+		if secretExpiresAt.Valid {
+			rotation.SecretExpiresAt = &secretExpiresAt.Time
+		}
+		if revokedAt.Valid {
+			rotation.RevokedAt = &revokedAt.Time
+		}
+		rotations = append(rotations, &rotation)
+	}
+
+	return rotations, rows.Err()
+}
+
 func (r *Repository) GetJobs(ctx context.Context, acoID uuid.UUID, statuses ...models.JobStatus) ([]*models.Job, error) {
 	s := make([]interface{}, len(statuses))
 	for i, v := range statuses {
@@ -435,6 +955,103 @@ func (r *Repository) GetJobs(ctx context.Context, acoID uuid.UUID, statuses ...m
 
 }
 
+func (r *Repository) GetAuditEvents(ctx context.Context, acoID uuid.UUID, since time.Time, offset, limit int) (
+	[]*models.Job, []*models.JobKeyDownload, int, error) {
+	countSB := sqlFlavor.NewSelectBuilder()
+	countSB.Select("COUNT(*)").From(countSB.BuilderAs(auditEvents(acoID, since), "e"))
+	query, args := countSB.Build()
+	var total int
+	if err := r.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return nil, nil, 0, err
+	}
+
+	// The kind breaks ties between a job and a download sharing a creation time and ID so pages never overlap
+	pageSB := sqlFlavor.NewSelectBuilder()
+	pageSB.Select("e.kind", "e.id").From(pageSB.BuilderAs(auditEvents(acoID, since), "e"))
+	pageSB.OrderBy("e.created_at DESC", "e.id DESC", "e.kind DESC").Offset(offset).Limit(limit)
+
+	var jobIDs, downloadIDs []interface{}
+	err := r.queryRows(ctx, pageSB, func(rows database.Rows) error {
+		var (
+			kind string
+			id   uint
+		)
+		if err := rows.Scan(&kind, &id); err != nil {
+			return err
+		}
+		if kind == "job" {
+			jobIDs = append(jobIDs, id)
+		} else {
+			downloadIDs = append(downloadIDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var jobs []*models.Job
+	if len(jobIDs) > 0 {
+		sb := sqlFlavor.NewSelectBuilder()
+		sb.Select(jobColumns...).From("jobs").Where(sb.In("id", jobIDs...))
+		sb.OrderBy("created_at DESC", "id DESC")
+		query, args = sb.Build()
+		if jobs, err = r.getJobs(ctx, query, args...); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	var downloads []*models.JobKeyDownload
+	if len(downloadIDs) > 0 {
+		sb := sqlFlavor.NewSelectBuilder().Select(
+			"id",
+			"job_id",
+			"aco_id",
+			"file_name",
+			"resource_type",
+			"client_id",
+			"request_id",
+			"COALESCE(bytes, 0)",
+			"created_at",
+		).From("job_key_downloads")
+		sb.Where(sb.In("id", downloadIDs...))
+		sb.OrderBy("created_at DESC", "id DESC")
+		err = r.queryRows(ctx, sb, func(rows database.Rows) error {
+			var (
+				d                      models.JobKeyDownload
+				resourceType, clientID sql.NullString
+				requestID              sql.NullString
+			)
+			if err := rows.Scan(&d.ID, &d.JobID, &d.ACOID, &d.FileName, &resourceType, &clientID, &requestID, &d.Bytes, &d.CreatedAt); err != nil {
+				return err
+			}
+			d.ResourceType = resourceType.String
+			d.ClientID = clientID.String
+			d.RequestID = requestID.String
+			downloads = append(downloads, &d)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	return jobs, downloads, total, nil
+}
+
+// auditEvents selects the kind, ID, and creation time of the ACO's jobs and downloads created at or after since.
+func auditEvents(acoID uuid.UUID, since time.Time) *sqlbuilder.UnionBuilder {
+	jobsSB := sqlFlavor.NewSelectBuilder()
+	jobsSB.Select("'job' AS kind", "id", "created_at").From("jobs").Where(jobsSB.Equal("aco_id", acoID))
+	downloadsSB := sqlFlavor.NewSelectBuilder()
+	downloadsSB.Select("'download' AS kind", "id", "created_at").From("job_key_downloads").Where(downloadsSB.Equal("aco_id", acoID))
+	if !since.IsZero() {
+		jobsSB.Where(jobsSB.GreaterEqualThan("created_at", since))
+		downloadsSB.Where(downloadsSB.GreaterEqualThan("created_at", since))
+	}
+	return sqlFlavor.NewUnionBuilder().UnionAll(jobsSB, downloadsSB)
+}
+
 func (r *Repository) GetJobsByUpdateTimeAndStatus(ctx context.Context, lowerBound, upperBound time.Time, statuses ...models.JobStatus) ([]*models.Job, error) {
 	s := make([]interface{}, len(statuses))
 	for i, v := range statuses {
@@ -594,6 +1211,58 @@ func (r *Repository) GetJobKeys(ctx context.Context, jobID uint) ([]*models.JobK
 	return keys, nil
 }
 
+func (r *Repository) GetJobKeysByJobIDs(ctx context.Context, acoID uuid.UUID, jobIDs []uint) (map[uint][]*models.JobKey, error) {
+	keys := make(map[uint][]*models.JobKey)
+	if len(jobIDs) == 0 {
+		return keys, nil
+	}
+
+	ids := make([]interface{}, len(jobIDs))
+	for i, id := range jobIDs {
+		ids[i] = id
+	}
+
+	sb := sqlFlavor.NewSelectBuilder().Select(
+		"jk.id",
+		"jk.job_id",
+		"jk.file_name",
+		"jk.resource_type",
+		"jk.benes_with_data",
+		"jk.benes_retrieved_percent",
+	).From("job_keys jk").Join("jobs j", "j.id = jk.job_id")
+	sb.Where(sb.Equal("j.aco_id", acoID), sb.In("jk.job_id", ids...))
+	sb.OrderBy("jk.id")
+
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jk models.JobKey
+		if err = rows.Scan(
+			&jk.ID,
+			&jk.JobID,
+			&jk.FileName,
+			&jk.ResourceType,
+			&jk.BenesWithData,
+			&jk.BenesRetrievedPercent,
+		); err != nil {
+			return nil, err
+		}
+		jk.FileName = strings.TrimSpace(jk.FileName)
+		keys[jk.JobID] = append(keys[jk.JobID], &jk)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
 func (r *Repository) GetJobKey(ctx context.Context, jobID uint, fileName string) (*models.JobKey, error) {
 	sb := sqlFlavor.NewSelectBuilder().Select(
 		"id",
@@ -623,6 +1292,143 @@ func (r *Repository) GetJobKey(ctx context.Context, jobID uint, fileName string)
 	return jk, nil
 }
 
+func (r *Repository) CreateJobKeyDownload(ctx context.Context, download models.JobKeyDownload) error {
+	ib := sqlFlavor.NewInsertBuilder().InsertInto("job_key_downloads").Cols(
+		"job_id",
+		"aco_id",
+		"file_name",
+		"resource_type",
+		"client_id",
+		"request_id",
+		"bytes",
+		"created_at",
+	).Values(
+		download.JobID,
+		download.ACOID,
+		download.FileName,
+		download.ResourceType,
+		download.ClientID,
+		download.RequestID,
+		download.Bytes,
+		sqlbuilder.Raw("NOW()"),
+	)
+	query, args := ib.Build()
+
+	_, err := r.ExecContext(ctx, query, args...)
+	return err
+}
+
+// usageJobStatus groups the statuses jobs move to after they finish with the status they finished in.
+var usageJobStatus = map[models.JobStatus]models.JobStatus{
+	models.JobStatusArchived:         models.JobStatusCompleted,
+	models.JobStatusExpired:          models.JobStatusCompleted,
+	models.JobStatusFailedExpired:    models.JobStatusFailed,
+	models.JobStatusCancelledExpired: models.JobStatusCancelled,
+}
+
+func (r *Repository) GetACOUsage(ctx context.Context, start, end time.Time) ([]*models.ACOUsage, error) {
+	usage := make(map[string]*models.ACOUsage)
+	forACO := func(cmsID string) *models.ACOUsage {
+		u, ok := usage[cmsID]
+		if !ok {
+			u = &models.ACOUsage{CMSID: cmsID, Jobs: make(map[models.JobStatus]int), ResourceTypes: make(map[string]int)}
+			usage[cmsID] = u
+		}
+		return u
+	}
+
+	jobs := sqlFlavor.NewSelectBuilder()
+	jobs.Select("a.cms_id", "j.status", "COUNT(*)").
+		From("jobs j").Join("acos a", "a.uuid = j.aco_id").
+		Where(jobs.GreaterEqualThan("j.created_at", start), jobs.LessThan("j.created_at", end), jobs.IsNotNull("a.cms_id")).
+		GroupBy("a.cms_id", "j.status")
+	err := r.queryRows(ctx, jobs, func(rows database.Rows) error {
+		var (
+			cmsID  string
+			status models.JobStatus
+			count  int
+		)
+		if err := rows.Scan(&cmsID, &status, &count); err != nil {
+			return err
+		}
+		if s, ok := usageJobStatus[status]; ok {
+			status = s
+		}
+		forACO(cmsID).Jobs[status] += count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := sqlFlavor.NewSelectBuilder()
+	resources.Select("a.cms_id", "k.resource_type", "COUNT(DISTINCT k.job_id)").
+		From("job_keys k").Join("jobs j", "j.id = k.job_id").Join("acos a", "a.uuid = j.aco_id").
+		Where(resources.GreaterEqualThan("j.created_at", start), resources.LessThan("j.created_at", end),
+			resources.NotLike("k.file_name", "%-error.ndjson"), resources.IsNotNull("k.resource_type"), resources.IsNotNull("a.cms_id")).
+		GroupBy("a.cms_id", "k.resource_type")
+	err = r.queryRows(ctx, resources, func(rows database.Rows) error {
+		var (
+			cmsID, resourceType string
+			count               int
+		)
+		if err := rows.Scan(&cmsID, &resourceType, &count); err != nil {
+			return err
+		}
+		forACO(cmsID).ResourceTypes[resourceType] = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	downloads := sqlFlavor.NewSelectBuilder()
+	downloads.Select("a.cms_id", "COUNT(*)", "COALESCE(SUM(d.bytes), 0)").
+		From("job_key_downloads d").Join("acos a", "a.uuid = d.aco_id").
+		Where(downloads.GreaterEqualThan("d.created_at", start), downloads.LessThan("d.created_at", end), downloads.IsNotNull("a.cms_id")).
+		GroupBy("a.cms_id")
+	err = r.queryRows(ctx, downloads, func(rows database.Rows) error {
+		var (
+			cmsID string
+			count int
+			bytes int64
+		)
+		if err := rows.Scan(&cmsID, &count, &bytes); err != nil {
+			return err
+		}
+		u := forACO(cmsID)
+		u.Downloads, u.BytesDownloaded = count, bytes
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.ACOUsage, 0, len(usage))
+	for _, u := range usage {
+		result = append(result, u)
+	}
+	slices.SortFunc(result, func(a, b *models.ACOUsage) int { return strings.Compare(a.CMSID, b.CMSID) })
+	return result, nil
+}
+
+// queryRows runs the query built by sb and calls scan for each row.
+func (r *Repository) queryRows(ctx context.Context, sb *sqlbuilder.SelectBuilder, scan func(database.Rows) error) error {
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (r *Repository) getJobs(ctx context.Context, query string, args ...interface{}) ([]*models.Job, error) {
 	rows, err := r.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -630,27 +1436,13 @@ func (r *Repository) getJobs(ctx context.Context, query string, args ...interfac
 	}
 	defer rows.Close()
 
-	var (
-		jobs                                  []*models.Job
-		transactionTime, createdAt, updatedAt sql.NullTime
-	)
+	var jobs []*models.Job
 	for rows.Next() {
-		var j models.Job
-		if err = rows.Scan(
-			&j.ID,
-			&j.ACOID,
-			&j.RequestURL,
-			&j.Status,
-			&transactionTime,
-			&j.JobCount,
-			&createdAt,
-			&updatedAt,
-			&j.BenesAttributedToACO,
-		); err != nil {
+		j, err := scanJob(rows)
+		if err != nil {
 			return nil, err
 		}
-		j.TransactionTime, j.CreatedAt, j.UpdatedAt = transactionTime.Time, createdAt.Time, updatedAt.Time
-		jobs = append(jobs, &j)
+		jobs = append(jobs, j)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -660,25 +1452,59 @@ func (r *Repository) getJobs(ctx context.Context, query string, args ...interfac
 	return jobs, nil
 }
 
+// scanJob scans a row selected with jobColumns, followed by any extra columns into extra.
+func scanJob(rows database.Rows, extra ...interface{}) (*models.Job, error) {
+	var (
+		j                                     models.Job
+		transactionTime, createdAt, updatedAt sql.NullTime
+	)
+	dest := append([]interface{}{
+		&j.ID,
+		&j.ACOID,
+		&j.RequestURL,
+		&j.Status,
+		&transactionTime,
+		&j.JobCount,
+		&createdAt,
+		&updatedAt,
+		&j.BenesAttributedToACO,
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	j.TransactionTime, j.CreatedAt, j.UpdatedAt = transactionTime.Time, createdAt.Time, updatedAt.Time
+	return &j, nil
+}
+
+var acoColumns = []string{"id", "uuid", "cms_id", "name",
+	"client_id", "group_id", "system_id",
+	"termination_details"}
+
 func (r *Repository) getACO(ctx context.Context, field string, value interface{}) (*models.ACO, error) {
-	sb := sqlFlavor.NewSelectBuilder().Select("id", "uuid", "cms_id", "name",
-		"client_id", "group_id", "system_id",
-		"termination_details").From("acos")
+	sb := sqlFlavor.NewSelectBuilder().Select(acoColumns...).From("acos")
 	sb.Where(sb.Equal(field, value))
 
 	query, args := sb.Build()
-	row := r.QueryRowContext(ctx, query, args...)
+	aco, err := scanACO(r.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no ACO record found for %s", value)
+		}
+		return nil, err
+	}
+	return aco, nil
+}
+
+// scanACO scans a row selected with acoColumns, followed by any extra columns into extra.
+func scanACO(row database.Row, extra ...interface{}) (*models.ACO, error) {
 	var (
 		aco                                      models.ACO
 		termination                              termination
 		name, cmsID, clientID, groupID, systemID sql.NullString
 	)
-	err := row.Scan(&aco.ID, &aco.UUID, &cmsID, &name,
-		&clientID, &groupID, &systemID, &termination)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("no ACO record found for %s", value)
-		}
+	dest := append([]interface{}{&aco.ID, &aco.UUID, &cmsID, &name,
+		&clientID, &groupID, &systemID, &termination}, extra...)
+	if err := row.Scan(dest...); err != nil {
 		return nil, err
 	}
 	aco.Name, aco.ClientID = name.String, clientID.String
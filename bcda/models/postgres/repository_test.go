@@ -234,6 +234,58 @@ func (r *RepositoryTestSuite) TestGetCCLFFileByID_SQLError() {
 	assert.Nil(r.T(), cclfFile)
 }
 
+func (r *RepositoryTestSuite) TestUpdateCCLFFileStatus() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(r.T(), err)
+	defer func() {
+		assert.NoError(r.T(), mock.ExpectationsWereMet())
+		db.Close()
+	}()
+	repository := postgres.NewRepository(db)
+
+	expQuery := regexp.QuoteMeta(`WITH prev AS (SELECT id, import_status FROM cclf_files WHERE id = $1 AND import_status IN ($2, $3, $4) AND import_status <> $5 FOR UPDATE), ` +
+		`upd AS (UPDATE cclf_files SET import_status = $6 FROM prev WHERE cclf_files.id = prev.id) ` +
+		`INSERT INTO cclf_file_status_changes (file_id, previous_status, status, reason, changed_by) ` +
+		`SELECT id, import_status, $7, $8, $9 FROM prev RETURNING id, previous_status, created_at`)
+	createdAt := time.Now()
+	mock.ExpectQuery(expQuery).
+		WithArgs(uint(4), constants.ImportComplete, constants.ImportSuperseded, constants.ImportInvalid, constants.ImportInvalid,
+			constants.ImportInvalid, constants.ImportInvalid, "truncated roster", "jdoe").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "previous_status", "created_at"}).AddRow(2, constants.ImportComplete, createdAt))
+	mock.ExpectQuery(expQuery).WillReturnRows(sqlmock.NewRows([]string{"id", "previous_status", "created_at"}))
+
+	change, err := repository.UpdateCCLFFileStatus(context.Background(), 4, constants.ImportInvalid, "truncated roster", "jdoe")
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), &models.CCLFFileStatusChange{ID: 2, FileID: 4, PreviousStatus: constants.ImportComplete, Status: constants.ImportInvalid,
+		Reason: "truncated roster", ChangedBy: "jdoe", CreatedAt: createdAt}, change)
+
+	change, err = repository.UpdateCCLFFileStatus(context.Background(), 5, constants.ImportInvalid, "truncated roster", "jdoe")
+	assert.ErrorIs(r.T(), err, sql.ErrNoRows)
+	assert.Nil(r.T(), change)
+}
+
+func (r *RepositoryTestSuite) TestGetWithdrawnCCLFFiles() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(r.T(), err)
+	defer func() {
+		assert.NoError(r.T(), mock.ExpectationsWereMet())
+		db.Close()
+	}()
+	repository := postgres.NewRepository(db)
+
+	after := time.Now().Add(-24 * time.Hour)
+	timestamp := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, timestamp, performance_year, import_status, created_at FROM cclf_files WHERE aco_cms_id = $1 AND cclf_num = $2 AND import_status IN ($3, $4) AND type = $5 AND timestamp > $6 ORDER BY timestamp DESC`)).
+		WithArgs("A9994", 8, constants.ImportSuperseded, constants.ImportInvalid, models.FileTypeDefault, after).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "timestamp", "performance_year", "import_status", "created_at"}).
+			AddRow(3, "T.BCD.A9994.ZC8Y24.D240201.T0000000", timestamp, 24, constants.ImportSuperseded, timestamp))
+
+	files, err := repository.GetWithdrawnCCLFFiles(context.Background(), "A9994", 8, after, models.FileTypeDefault)
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), []*models.CCLFFile{{ID: 3, CCLFNum: 8, Name: "T.BCD.A9994.ZC8Y24.D240201.T0000000", ACOCMSID: "A9994", Timestamp: timestamp,
+		PerformanceYear: 24, ImportStatus: constants.ImportSuperseded, Type: models.FileTypeDefault, CreatedAt: timestamp}}, files)
+}
+
 func (r *RepositoryTestSuite) TestGetCCLFBeneficiaryMBIs() {
 	tests := []struct {
 		name          string
@@ -242,12 +294,12 @@ func (r *RepositoryTestSuite) TestGetCCLFBeneficiaryMBIs() {
 	}{
 		{
 			"HappyPath",
-			`SELECT mbi FROM cclf_beneficiaries WHERE file_id = $1`,
+			`SELECT DISTINCT mbi FROM cclf_beneficiaries WHERE file_id = $1`,
 			nil,
 		},
 		{
 			"ErrorOnQuery",
-			`SELECT mbi FROM cclf_beneficiaries WHERE file_id = $1`,
+			`SELECT DISTINCT mbi FROM cclf_beneficiaries WHERE file_id = $1`,
 			fmt.Errorf(constants.SQLErr),
 		},
 	}
@@ -300,7 +352,7 @@ func (r *RepositoryTestSuite) TestGetCCLFBeneficiaries() {
 	}{
 		{
 			"NoIgnoreMBIs",
-			`SELECT id, file_id, mbi, blue_button_id FROM cclf_beneficiaries WHERE id IN (SELECT MAX(id) FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) ORDER BY blue_button_id ASC NULLS LAST`,
+			`SELECT b.id, b.file_id, b.mbi, b.blue_button_id, p.attribution_start, p.attribution_end FROM (SELECT MAX(id) AS id, CASE WHEN COUNT(attribution_start) = COUNT(*) THEN MIN(attribution_start) END AS attribution_start, CASE WHEN COUNT(attribution_end) = COUNT(*) THEN MAX(attribution_end) END AS attribution_end FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) AS p JOIN cclf_beneficiaries b ON b.id = p.id ORDER BY b.blue_button_id ASC NULLS LAST`,
 			nil,
 			[]*models.CCLFBeneficiary{
 				getCCLFBeneficiary(),
@@ -310,9 +362,24 @@ func (r *RepositoryTestSuite) TestGetCCLFBeneficiaries() {
 			},
 			nil,
 		},
+		{
+			"AttributionPeriod",
+			`SELECT b.id, b.file_id, b.mbi, b.blue_button_id, p.attribution_start, p.attribution_end FROM (SELECT MAX(id) AS id, CASE WHEN COUNT(attribution_start) = COUNT(*) THEN MIN(attribution_start) END AS attribution_start, CASE WHEN COUNT(attribution_end) = COUNT(*) THEN MAX(attribution_end) END AS attribution_end FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) AS p JOIN cclf_beneficiaries b ON b.id = p.id ORDER BY b.blue_button_id ASC NULLS LAST`,
+			nil,
+			[]*models.CCLFBeneficiary{
+				func() *models.CCLFBeneficiary {
+					bene := getCCLFBeneficiary()
+					bene.AttributionStart = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+					bene.AttributionEnd = time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)
+					return bene
+				}(),
+				getCCLFBeneficiary(),
+			},
+			nil,
+		},
 		{
 			"IgnoredMBIs",
-			`SELECT id, file_id, mbi, blue_button_id FROM cclf_beneficiaries WHERE id IN (SELECT MAX(id) FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) AND mbi NOT IN ($2, $3) ORDER BY blue_button_id ASC NULLS LAST`,
+			`SELECT b.id, b.file_id, b.mbi, b.blue_button_id, p.attribution_start, p.attribution_end FROM (SELECT MAX(id) AS id, CASE WHEN COUNT(attribution_start) = COUNT(*) THEN MIN(attribution_start) END AS attribution_start, CASE WHEN COUNT(attribution_end) = COUNT(*) THEN MAX(attribution_end) END AS attribution_end FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) AS p JOIN cclf_beneficiaries b ON b.id = p.id WHERE b.mbi NOT IN ($2, $3) ORDER BY b.blue_button_id ASC NULLS LAST`,
 			[]string{"123", "456"},
 			[]*models.CCLFBeneficiary{
 				getCCLFBeneficiary(),
@@ -321,7 +388,7 @@ func (r *RepositoryTestSuite) TestGetCCLFBeneficiaries() {
 		},
 		{
 			"ErrorOnQuery",
-			`SELECT id, file_id, mbi, blue_button_id FROM cclf_beneficiaries WHERE id IN (SELECT MAX(id) FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) ORDER BY blue_button_id ASC NULLS LAST`,
+			`SELECT b.id, b.file_id, b.mbi, b.blue_button_id, p.attribution_start, p.attribution_end FROM (SELECT MAX(id) AS id, CASE WHEN COUNT(attribution_start) = COUNT(*) THEN MIN(attribution_start) END AS attribution_start, CASE WHEN COUNT(attribution_end) = COUNT(*) THEN MAX(attribution_end) END AS attribution_end FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) AS p JOIN cclf_beneficiaries b ON b.id = p.id ORDER BY b.blue_button_id ASC NULLS LAST`,
 			nil,
 			nil,
 			fmt.Errorf(constants.SQLErr),
@@ -353,9 +420,9 @@ func (r *RepositoryTestSuite) TestGetCCLFBeneficiaries() {
 					WithArgs(args...)
 			}
 			if tt.errToReturn == nil {
-				rows := sqlmock.NewRows([]string{"id", "file_id", "mbi", "blue_button_id"})
+				rows := sqlmock.NewRows([]string{"id", "file_id", "mbi", "blue_button_id", "attribution_start", "attribution_end"})
 				for _, bene := range tt.expectedResults {
-					rows.AddRow(bene.ID, bene.FileID, bene.MBI, bene.BlueButtonID)
+					rows.AddRow(bene.ID, bene.FileID, bene.MBI, bene.BlueButtonID, nullTime(bene.AttributionStart), nullTime(bene.AttributionEnd))
 				}
 				query.WillReturnRows(rows)
 			} else {
@@ -374,6 +441,100 @@ func (r *RepositoryTestSuite) TestGetCCLFBeneficiaries() {
 	}
 }
 
+func (r *RepositoryTestSuite) TestGetCCLFBeneficiaryCount() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(r.T(), err)
+	defer func() {
+		assert.NoError(r.T(), mock.ExpectationsWereMet())
+		db.Close()
+	}()
+	repository := postgres.NewRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT mbi) FROM cclf_beneficiaries WHERE file_id = $1`)).
+		WithArgs(uint(7)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repository.GetCCLFBeneficiaryCount(context.Background(), 7)
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), 42, count)
+}
+
+func (r *RepositoryTestSuite) TestGetCCLFBeneficiaryPage() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(r.T(), err)
+	defer func() {
+		assert.NoError(r.T(), mock.ExpectationsWereMet())
+		db.Close()
+	}()
+	repository := postgres.NewRepository(db)
+
+	bene := getCCLFBeneficiary()
+	bene.AttributionStart = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	query := `SELECT b.id, b.file_id, b.mbi, b.blue_button_id, p.attribution_start, p.attribution_end FROM (SELECT MAX(id) AS id, CASE WHEN COUNT(attribution_start) = COUNT(*) THEN MIN(attribution_start) END AS attribution_start, CASE WHEN COUNT(attribution_end) = COUNT(*) THEN MAX(attribution_end) END AS attribution_end FROM cclf_beneficiaries WHERE file_id = $1 GROUP BY mbi) AS p JOIN cclf_beneficiaries b ON b.id = p.id ORDER BY b.mbi LIMIT 50 OFFSET 100`
+	mock.ExpectQuery(fmt.Sprintf("^%s$", regexp.QuoteMeta(query))).WithArgs(bene.FileID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "file_id", "mbi", "blue_button_id", "attribution_start", "attribution_end"}).
+			AddRow(bene.ID, bene.FileID, bene.MBI, bene.BlueButtonID, bene.AttributionStart, nil))
+
+	benes, err := repository.GetCCLFBeneficiaryPage(context.Background(), bene.FileID, 100, 50)
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), []*models.CCLFBeneficiary{bene}, benes)
+}
+
+func (r *RepositoryTestSuite) TestInTransaction() {
+	deleteFile := regexp.QuoteMeta(`DELETE FROM suppressions WHERE file_id = $1`)
+	deleteRecords := func(repo models.Repository) error {
+		// Joins the transaction already started
+		return repo.InTransaction(context.Background(), func(repo models.Repository) error {
+			return repo.DeleteBenePrefsFile(context.Background(), 1)
+		})
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(mock sqlmock.Sqlmock)
+		expErr string
+	}{
+		{"Committed", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteFile).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM suppression_files WHERE id = $1`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}, ""},
+		{"Rolled back", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteFile).WithArgs(1).WillReturnError(fmt.Errorf(constants.SQLErr))
+			mock.ExpectRollback()
+		}, constants.SQLErr},
+		{"Commit fails", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin()
+			mock.ExpectExec(deleteFile).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM suppression_files WHERE id = $1`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit().WillReturnError(fmt.Errorf(constants.SQLErr))
+		}, "failed to commit transaction"},
+		{"Begin fails", func(mock sqlmock.Sqlmock) {
+			mock.ExpectBegin().WillReturnError(fmt.Errorf(constants.SQLErr))
+		}, "failed to start transaction"},
+	}
+
+	for _, tt := range tests {
+		r.T().Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer func() {
+				assert.NoError(t, mock.ExpectationsWereMet())
+				db.Close()
+			}()
+
+			tt.setup(mock)
+			err = postgres.NewRepository(db).InTransaction(context.Background(), deleteRecords)
+			if tt.expErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expErr)
+			}
+		})
+	}
+}
+
 func (r *RepositoryTestSuite) TestGetSuppressedMBIs() {
 	lookbackDays := 10
 	upperBound := time.Now().Round(time.Millisecond).UTC()
@@ -388,14 +549,14 @@ func (r *RepositoryTestSuite) TestGetSuppressedMBIs() {
 	}{
 		{
 			"HappyPath",
-			`SELECT DISTINCT s.mbi FROM (SELECT mbi, MAX(effective_date) as max_date FROM suppressions WHERE effective_date >= $1 AND effective_date <= $2 AND preference_indicator <> $3 GROUP BY mbi) AS h JOIN suppressions s ON s.mbi = h.mbi AND s.effective_date = h.max_date WHERE preference_indicator = $4`,
+			`SELECT DISTINCT h.mbi FROM (SELECT mbi, pref_date, indicator, MAX(pref_date) OVER (PARTITION BY mbi) AS max_date FROM ((SELECT mbi, effective_date AS pref_date, preference_indicator AS indicator FROM suppressions WHERE effective_date >= $1 AND effective_date <= $2 AND preference_indicator <> $3) UNION ALL (SELECT known.mbi, s.effective_date, s.preference_indicator FROM suppressions s JOIN mbi_links linked ON linked.mbi = s.mbi JOIN mbi_links known ON known.beneficiary_link_key = linked.beneficiary_link_key AND known.mbi <> s.mbi WHERE s.effective_date >= $4 AND s.effective_date <= $5 AND s.preference_indicator <> $6)) AS p) AS h WHERE h.pref_date = h.max_date AND h.indicator = $7`,
 			lowerBound,
 			upperBound,
 			nil,
 		},
 		{
 			"ErrorOnQuery",
-			`SELECT DISTINCT s.mbi FROM (SELECT mbi, MAX(effective_date) as max_date FROM suppressions WHERE effective_date >= $1 AND effective_date <= $2 AND preference_indicator <> $3 GROUP BY mbi) AS h JOIN suppressions s ON s.mbi = h.mbi AND s.effective_date = h.max_date WHERE preference_indicator = $4`,
+			`SELECT DISTINCT h.mbi FROM (SELECT mbi, pref_date, indicator, MAX(pref_date) OVER (PARTITION BY mbi) AS max_date FROM ((SELECT mbi, effective_date AS pref_date, preference_indicator AS indicator FROM suppressions WHERE effective_date >= $1 AND effective_date <= $2 AND preference_indicator <> $3) UNION ALL (SELECT known.mbi, s.effective_date, s.preference_indicator FROM suppressions s JOIN mbi_links linked ON linked.mbi = s.mbi JOIN mbi_links known ON known.beneficiary_link_key = linked.beneficiary_link_key AND known.mbi <> s.mbi WHERE s.effective_date >= $4 AND s.effective_date <= $5 AND s.preference_indicator <> $6)) AS p) AS h WHERE h.pref_date = h.max_date AND h.indicator = $7`,
 			time.Time{}.Add(-1 * 10 * 24 * time.Hour),
 			time.Time{},
 			fmt.Errorf(constants.SQLErr),
@@ -413,7 +574,7 @@ func (r *RepositoryTestSuite) TestGetSuppressedMBIs() {
 			}()
 			repository := postgres.NewRepository(db)
 
-			args := []driver.Value{tt.lowerBound, tt.upperBound, "", "N"}
+			args := []driver.Value{tt.lowerBound, tt.upperBound, "", tt.lowerBound, tt.upperBound, "", "N"}
 			query := mock.ExpectQuery(fmt.Sprintf("^%s$", regexp.QuoteMeta(tt.expQueryRegex))).
 				WithArgs(args...)
 			if tt.errToReturn == nil {
@@ -438,6 +599,46 @@ func (r *RepositoryTestSuite) TestGetSuppressedMBIs() {
 	}
 }
 
+func (r *RepositoryTestSuite) TestGetSAMHSAOptOutMBIs() {
+	lookbackDays := 10
+	upperBound := time.Now().Round(time.Millisecond).UTC()
+	lowerBound := upperBound.Add(time.Duration(-1*lookbackDays*24) * time.Hour)
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	assert.NoError(r.T(), err)
+	defer func() {
+		assert.NoError(r.T(), mock.ExpectationsWereMet())
+		db.Close()
+	}()
+	repository := postgres.NewRepository(db)
+
+	mbis := []string{"0", "1", "2"}
+	// Preferences recorded under linked MBIs count towards each of the beneficiary's MBIs
+	expQuery := `SELECT DISTINCT h.mbi FROM (SELECT mbi, pref_date, indicator, MAX(pref_date) OVER (PARTITION BY mbi) AS max_date FROM ((SELECT mbi, samhsa_effective_date AS pref_date, samhsa_preference_indicator AS indicator FROM suppressions WHERE samhsa_effective_date >= $1 AND samhsa_effective_date <= $2 AND samhsa_preference_indicator <> $3 AND mbi = ANY($4)) UNION ALL (SELECT known.mbi, s.samhsa_effective_date, s.samhsa_preference_indicator FROM suppressions s JOIN mbi_links linked ON linked.mbi = s.mbi JOIN mbi_links known ON known.beneficiary_link_key = linked.beneficiary_link_key AND known.mbi <> s.mbi WHERE s.samhsa_effective_date >= $5 AND s.samhsa_effective_date <= $6 AND s.samhsa_preference_indicator <> $7 AND known.mbi = ANY($8))) AS p) AS h WHERE h.pref_date = h.max_date AND h.indicator = $9`
+	mock.ExpectQuery(fmt.Sprintf("^%s$", regexp.QuoteMeta(expQuery))).
+		WithArgs(lowerBound, upperBound, "", mbis, lowerBound, upperBound, "", mbis, "N").
+		WillReturnRows(sqlmock.NewRows([]string{"mbi"}).AddRow("0").AddRow("1"))
+
+	result, err := repository.GetSAMHSAOptOutMBIs(context.Background(), mbis, lookbackDays, upperBound)
+	assert.NoError(r.T(), err)
+	assert.Equal(r.T(), []string{"0", "1"}, result)
+
+	// No beneficiaries means no lookup
+	result, err = repository.GetSAMHSAOptOutMBIs(context.Background(), nil, lookbackDays, upperBound)
+	assert.NoError(r.T(), err)
+	assert.Nil(r.T(), result)
+}
+
+// arrayConverter passes []string arguments through to sqlmock unchanged, as the pgx driver accepts them as arrays
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if s, ok := v.([]string); ok {
+		return s, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
 func (r *RepositoryTestSuite) TestGetCMSIDByClientID_Success() {
 	db, mock, err := sqlmock.New()
 	assert.NoError(r.T(), err)
@@ -605,6 +806,13 @@ func (r *RepositoryTestSuite) TestACOMethods() {
 	assert.NoError(r.repository.UpdateACO(ctx, aco.UUID,
 		map[string]interface{}{"termination_details": aco.TerminationDetails}))
 
+	certSubject := fmt.Sprintf("urn:bcda:aco:%s", cmsID)
+	assert.NoError(r.repository.UpdateACO(ctx, aco.UUID,
+		map[string]interface{}{"client_cert_subject": certSubject}))
+	res, err = r.repository.GetACOByClientCertSubjects(ctx, []string{"urn:bcda:aco:unmapped", certSubject})
+	assert.NoError(err)
+	assert.Equal(aco, *res)
+
 	res, err = r.repository.GetACOByCMSID(ctx, terminatedCMSID)
 	assert.NoError(err)
 	assert.Equal(terminatedACO, *res)
@@ -633,6 +841,10 @@ func (r *RepositoryTestSuite) TestACOMethods() {
 	assert.EqualError(err, constants.NoACORecord+aco.ClientID)
 	assert.Nil(res)
 
+	res, err = r.repository.GetACOByClientCertSubjects(ctx, []string{aco.ClientID})
+	assert.ErrorIs(err, sql.ErrNoRows)
+	assert.Nil(res)
+
 	assert.Contains(
 		r.repository.UpdateACO(ctx, aco.UUID,
 			map[string]interface{}{"some_unknown_column": uuid.New()}).Error(),
@@ -727,6 +939,25 @@ func (r *RepositoryTestSuite) TestCCLFBeneficiariesMethods() {
 	assert.Contains(benes, bene1)
 	assert.Contains(benes, bene2)
 
+	// A bene aligned for more than one period is returned once, spanning all of them
+	jan, jul := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	bene3 := &models.CCLFBeneficiary{FileID: cclfFile.ID, MBI: testUtils.RandomMBI(r.T()), AttributionStart: jan, AttributionEnd: jan.AddDate(0, 3, -1)}
+	postgrestest.CreateCCLFBeneficiary(r.T(), r.db, bene3)
+	bene3.AttributionStart, bene3.AttributionEnd = jul, time.Time{}
+	postgrestest.CreateCCLFBeneficiary(r.T(), r.db, bene3)
+
+	benes, err = r.repository.GetCCLFBeneficiaryPage(ctx, cclfFile.ID, 0, 10)
+	assert.NoError(err)
+	assert.Len(benes, 3)
+	for _, bene := range benes {
+		if bene.MBI == bene3.MBI {
+			assert.Equal(bene3.ID, bene.ID)
+			assert.True(jan.Equal(bene.AttributionStart))
+			assert.True(bene.AttributionEnd.IsZero())
+		}
+	}
+	mbis = append(mbis, bene3.MBI)
+
 	// All benes excluded
 	benes, err = r.repository.GetCCLFBeneficiaries(ctx, cclfFile.ID, mbis)
 	assert.NoError(err)
@@ -797,6 +1028,7 @@ func (r *RepositoryTestSuite) TestBenePrefsFileMethods() {
 		Name:         uuid.New(),
 		Timestamp:    now,
 		ImportStatus: constants.ImportInprog,
+		Checksum:     "abc123",
 	}
 	failed := models.BenePrefsFile{
 		Name:         uuid.New(),
@@ -823,8 +1055,115 @@ func (r *RepositoryTestSuite) TestBenePrefsFileMethods() {
 	assertEqualBenePrefsFile(assert, failed, postgrestest.GetSuppressionFileByName(r.T(), r.db, failed.Name)[0])
 	assertEqualBenePrefsFile(assert, other, postgrestest.GetSuppressionFileByName(r.T(), r.db, other.Name)[0])
 
+	file, err := r.repository.GetBenePrefsFileByName(ctx, inProgress.Name)
+	assert.NoError(err)
+	assertEqualBenePrefsFile(assert, inProgress, *file)
+
+	file, err = r.repository.GetBenePrefsFileByName(ctx, uuid.New())
+	assert.NoError(err)
+	assert.Nil(file)
+
+	assert.NoError(r.repository.DeleteBenePrefsFile(ctx, other.ID))
+	assert.Empty(postgrestest.GetSuppressionFileByName(r.T(), r.db, other.Name))
+
 	// Negative cases
 	assert.EqualError(r.repository.UpdateBenePrefsImportStatus(ctx, 0, "SomeOtherStatus"), "SuppressionFile 0 not updated, no row found")
+	assert.EqualError(r.repository.DeleteBenePrefsFile(ctx, 0), "SuppressionFile 0 not deleted, no row found")
+}
+
+func (r *RepositoryTestSuite) TestUpsertMBILinks() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	linkKey := int(time.Now().UnixNano() % 1000000000)
+	oldMBI, newMBI := testUtils.RandomMBI(r.T()), testUtils.RandomMBI(r.T())
+	defer postgrestest.DeleteMBILinks(r.T(), r.db, linkKey)
+
+	first := time.Now().Add(-48 * time.Hour).Round(time.Millisecond)
+	second := time.Now().Add(-24 * time.Hour).Round(time.Millisecond)
+
+	createFile := func(records ...models.BenePrefsRecord) uint {
+		fileID, err := r.repository.CreateBenePrefsFile(ctx, models.BenePrefsFile{Name: uuid.New(), Timestamp: time.Now(), ImportStatus: constants.ImportComplete})
+		assert.NoError(err)
+		for _, rec := range records {
+			rec.FileID = fileID
+			assert.NoError(r.repository.CreateBenePrefsRecord(ctx, rec))
+		}
+		return fileID
+	}
+
+	firstFile := createFile(
+		models.BenePrefsRecord{MBI: oldMBI, EffectiveDt: first, BeneficiaryLinkKey: linkKey},
+		// No link key, so it cannot be tied to another MBI
+		models.BenePrefsRecord{MBI: testUtils.RandomMBI(r.T()), EffectiveDt: first},
+	)
+	defer postgrestest.DeleteSuppressionFileByID(r.T(), r.db, firstFile)
+
+	count, err := r.repository.UpsertMBILinks(ctx, firstFile)
+	assert.NoError(err)
+	assert.EqualValues(1, count)
+	links := postgrestest.GetMBILinks(r.T(), r.db, linkKey)
+	assert.Len(links, 1)
+	assert.True(first.Equal(links[oldMBI]))
+
+	secondFile := createFile(
+		models.BenePrefsRecord{MBI: oldMBI, EffectiveDt: second, BeneficiaryLinkKey: linkKey},
+		models.BenePrefsRecord{MBI: newMBI, EffectiveDt: second, BeneficiaryLinkKey: linkKey},
+	)
+	defer postgrestest.DeleteSuppressionFileByID(r.T(), r.db, secondFile)
+
+	count, err = r.repository.UpsertMBILinks(ctx, secondFile)
+	assert.NoError(err)
+	assert.EqualValues(2, count)
+	links = postgrestest.GetMBILinks(r.T(), r.db, linkKey)
+	assert.Len(links, 2)
+	assert.True(second.Equal(links[oldMBI]))
+	assert.True(second.Equal(links[newMBI]))
+
+	// Re-importing an older file does not move last seen backwards
+	_, err = r.repository.UpsertMBILinks(ctx, firstFile)
+	assert.NoError(err)
+	assert.True(second.Equal(postgrestest.GetMBILinks(r.T(), r.db, linkKey)[oldMBI]))
+}
+
+// TestOptedOutLinkedMBIs validates that an opt out recorded under one of a beneficiary's MBIs applies to the others
+func (r *RepositoryTestSuite) TestOptedOutLinkedMBIs() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	linkKey := int(time.Now().UnixNano() % 1000000000)
+	oldMBI, newMBI, optedIn := testUtils.RandomMBI(r.T()), testUtils.RandomMBI(r.T()), testUtils.RandomMBI(r.T())
+	defer postgrestest.DeleteMBILinks(r.T(), r.db, linkKey)
+	defer postgrestest.DeleteMBILinks(r.T(), r.db, linkKey+1)
+
+	fileID, err := r.repository.CreateBenePrefsFile(ctx, models.BenePrefsFile{Name: uuid.New(), Timestamp: time.Now(), ImportStatus: constants.ImportComplete})
+	assert.NoError(err)
+	defer postgrestest.DeleteSuppressionFileByID(r.T(), r.db, fileID)
+
+	twoHoursAgo, oneHourAgo := time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)
+	for _, rec := range []models.BenePrefsRecord{
+		// The beneficiary opted out under their old MBI and has no preference under the reissued one
+		{MBI: oldMBI, EffectiveDt: oneHourAgo, PrefIndicator: "N", SAMHSAEffectiveDt: oneHourAgo, SAMHSAPrefIndicator: "N", BeneficiaryLinkKey: linkKey},
+		{MBI: newMBI, EffectiveDt: twoHoursAgo, BeneficiaryLinkKey: linkKey},
+		// A later opt in under a linked MBI replaces an earlier opt out
+		{MBI: optedIn, EffectiveDt: twoHoursAgo, PrefIndicator: "N", BeneficiaryLinkKey: linkKey + 1},
+		{MBI: testUtils.RandomMBI(r.T()), EffectiveDt: oneHourAgo, PrefIndicator: "Y", BeneficiaryLinkKey: linkKey + 1},
+	} {
+		rec.FileID = fileID
+		assert.NoError(r.repository.CreateBenePrefsRecord(ctx, rec))
+	}
+	_, err = r.repository.UpsertMBILinks(ctx, fileID)
+	assert.NoError(err)
+
+	mbis, err := r.repository.GetSuppressedMBIs(ctx, 10, time.Now())
+	assert.NoError(err)
+	assert.Contains(mbis, oldMBI)
+	assert.Contains(mbis, newMBI)
+	assert.NotContains(mbis, optedIn)
+
+	mbis, err = r.repository.GetSAMHSAOptOutMBIs(ctx, []string{newMBI, optedIn}, 10, time.Now())
+	assert.NoError(err)
+	assert.Equal([]string{newMBI}, mbis)
 }
 
 // TestJobsMethods validates the CRUD operations associated with the jobs table
@@ -984,6 +1323,345 @@ func (r *RepositoryTestSuite) TestJobKeysMethods() {
 	assert.Equal(100, jobKey.BenesRetrievedPercent)
 }
 
+func (r *RepositoryTestSuite) TestGetJobKeysByJobIDs() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	cmsID := testUtils.RandomHexID()[0:4]
+	aco := models.ACO{UUID: uuid.NewRandom(), Name: uuid.New(), CMSID: &cmsID}
+	postgrestest.CreateACO(r.T(), r.db, aco)
+	defer postgrestest.DeleteACO(r.T(), r.db, aco.UUID)
+
+	job1 := models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/1", Status: models.JobStatusCompleted}
+	job2 := models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/2", Status: models.JobStatusCompleted}
+	var err error
+	job1.ID, err = r.repository.CreateJob(ctx, job1)
+	assert.NoError(err)
+	job2.ID, err = r.repository.CreateJob(ctx, job2)
+	assert.NoError(err)
+
+	jk1 := models.JobKey{JobID: job1.ID, FileName: uuid.New(), ResourceType: "Patient"}
+	jk2 := models.JobKey{JobID: job1.ID, FileName: uuid.New(), ResourceType: "Coverage"}
+	jk3 := models.JobKey{JobID: job2.ID, FileName: uuid.New(), ResourceType: "Patient"}
+	assert.NoError(bcdaworkerpostgres.NewRepository(r.db).CreateJobKeys(ctx, []models.JobKey{jk1, jk2, jk3}))
+
+	keys, err := r.repository.GetJobKeysByJobIDs(ctx, aco.UUID, []uint{job1.ID, job2.ID})
+	assert.NoError(err)
+	assert.Len(keys, 2)
+	assertContainsFile(assert, keys[job1.ID], jk1.FileName)
+	assertContainsFile(assert, keys[job1.ID], jk2.FileName)
+	assertContainsFile(assert, keys[job2.ID], jk3.FileName)
+	assertDoesNotContainsFile(assert, keys[job2.ID], jk1.FileName)
+
+	// Jobs belonging to another ACO are left out
+	keys, err = r.repository.GetJobKeysByJobIDs(ctx, uuid.NewRandom(), []uint{job1.ID, job2.ID})
+	assert.NoError(err)
+	assert.Empty(keys)
+
+	keys, err = r.repository.GetJobKeysByJobIDs(ctx, aco.UUID, nil)
+	assert.NoError(err)
+	assert.Empty(keys)
+}
+
+func (r *RepositoryTestSuite) TestJobKeyDownloadsMethods() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	acoID := uuid.NewRandom()
+	otherACOID := uuid.NewRandom()
+	jobID, _ := safecast.ToUint(testUtils.CryptoRandInt31())
+	defer func() {
+		_, err := r.db.Exec("DELETE FROM job_key_downloads WHERE aco_id IN ($1, $2)", acoID, otherACOID)
+		assert.NoError(err)
+	}()
+
+	d1 := models.JobKeyDownload{JobID: jobID, ACOID: acoID, FileName: uuid.New(), ResourceType: "Patient", ClientID: "client-1", RequestID: "req-1", Bytes: 2048}
+	d2 := models.JobKeyDownload{JobID: jobID, ACOID: otherACOID, FileName: uuid.New(), ResourceType: "Coverage"}
+	d3 := models.JobKeyDownload{JobID: jobID, ACOID: acoID, FileName: uuid.New(), ResourceType: "Coverage"}
+	assert.NoError(r.repository.CreateJobKeyDownload(ctx, d1))
+	assert.NoError(r.repository.CreateJobKeyDownload(ctx, d2))
+	assert.NoError(r.repository.CreateJobKeyDownload(ctx, d3))
+
+	// Newest first, limited to the page while still counting every download
+	jobs, downloads, total, err := r.repository.GetAuditEvents(ctx, acoID, time.Time{}, 0, 1)
+	assert.NoError(err)
+	assert.Equal(2, total)
+	assert.Empty(jobs)
+	assert.Len(downloads, 1)
+	assert.Equal(d3.FileName, downloads[0].FileName)
+
+	_, downloads, total, err = r.repository.GetAuditEvents(ctx, acoID, time.Time{}, 1, 10)
+	assert.NoError(err)
+	assert.Equal(2, total)
+	assert.Len(downloads, 1)
+	assert.Equal(jobID, downloads[0].JobID)
+	assert.Equal(d1.FileName, downloads[0].FileName)
+	assert.Equal("Patient", downloads[0].ResourceType)
+	assert.Equal("client-1", downloads[0].ClientID)
+	assert.Equal("req-1", downloads[0].RequestID)
+	assert.Equal(int64(2048), downloads[0].Bytes)
+	assert.False(downloads[0].CreatedAt.IsZero())
+
+	_, downloads, total, err = r.repository.GetAuditEvents(ctx, acoID, time.Now().Add(time.Hour), 0, 10)
+	assert.NoError(err)
+	assert.Equal(0, total)
+	assert.Empty(downloads)
+}
+
+func (r *RepositoryTestSuite) TestGetAuditEvents() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	cmsID := testUtils.RandomHexID()[0:4]
+	aco := models.ACO{UUID: uuid.NewRandom(), Name: uuid.New(), CMSID: &cmsID}
+	postgrestest.CreateACO(r.T(), r.db, aco)
+	defer postgrestest.DeleteACO(r.T(), r.db, aco.UUID)
+	defer func() {
+		_, err := r.db.Exec("DELETE FROM job_key_downloads WHERE aco_id = $1", aco.UUID)
+		assert.NoError(err)
+	}()
+
+	now := time.Now().Round(time.Millisecond)
+	oldJob := models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/old", Status: models.JobStatusCompleted}
+	newJob := models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/new", Status: models.JobStatusPending}
+	for _, j := range []*models.Job{&oldJob, &newJob} {
+		id, err := r.repository.CreateJob(ctx, *j)
+		assert.NoError(err)
+		j.ID = id
+	}
+	_, err := r.db.Exec("UPDATE jobs SET created_at = $1 WHERE id = $2", now.Add(-3*time.Hour), oldJob.ID)
+	assert.NoError(err)
+	_, err = r.db.Exec("UPDATE jobs SET created_at = $1 WHERE id = $2", now.Add(-time.Hour), newJob.ID)
+	assert.NoError(err)
+
+	oldDownload := models.JobKeyDownload{JobID: oldJob.ID, ACOID: aco.UUID, FileName: uuid.New()}
+	newDownload := models.JobKeyDownload{JobID: oldJob.ID, ACOID: aco.UUID, FileName: uuid.New()}
+	assert.NoError(r.repository.CreateJobKeyDownload(ctx, oldDownload))
+	assert.NoError(r.repository.CreateJobKeyDownload(ctx, newDownload))
+	_, err = r.db.Exec("UPDATE job_key_downloads SET created_at = $1 WHERE file_name = $2", now.Add(-2*time.Hour), oldDownload.FileName)
+	assert.NoError(err)
+	_, err = r.db.Exec("UPDATE job_key_downloads SET created_at = $1 WHERE file_name = $2", now, newDownload.FileName)
+	assert.NoError(err)
+
+	// Events interleave by creation time: newDownload, newJob, oldDownload, oldJob
+	jobs, downloads, total, err := r.repository.GetAuditEvents(ctx, aco.UUID, time.Time{}, 0, 2)
+	assert.NoError(err)
+	assert.Equal(4, total)
+	assert.Len(jobs, 1)
+	assert.Equal(newJob.ID, jobs[0].ID)
+	assert.Len(downloads, 1)
+	assert.Equal(newDownload.FileName, downloads[0].FileName)
+
+	jobs, downloads, total, err = r.repository.GetAuditEvents(ctx, aco.UUID, time.Time{}, 2, 2)
+	assert.NoError(err)
+	assert.Equal(4, total)
+	assert.Len(jobs, 1)
+	assert.Equal(oldJob.ID, jobs[0].ID)
+	assert.Len(downloads, 1)
+	assert.Equal(oldDownload.FileName, downloads[0].FileName)
+
+	jobs, downloads, total, err = r.repository.GetAuditEvents(ctx, aco.UUID, time.Time{}, 1, 2)
+	assert.NoError(err)
+	assert.Equal(4, total)
+	assert.Len(jobs, 1)
+	assert.Equal(newJob.ID, jobs[0].ID)
+	assert.Len(downloads, 1)
+	assert.Equal(oldDownload.FileName, downloads[0].FileName)
+
+	// Events before since are neither returned nor counted
+	jobs, downloads, total, err = r.repository.GetAuditEvents(ctx, aco.UUID, now.Add(-90*time.Minute), 0, 10)
+	assert.NoError(err)
+	assert.Equal(2, total)
+	assert.Len(jobs, 1)
+	assert.Len(downloads, 1)
+
+	// Offsets past the last event return an empty page with the full total
+	for _, offset := range []int{4, 10000000} {
+		jobs, downloads, total, err = r.repository.GetAuditEvents(ctx, aco.UUID, time.Time{}, offset, 5000)
+		assert.NoError(err)
+		assert.Equal(4, total)
+		assert.Empty(jobs)
+		assert.Empty(downloads)
+	}
+}
+
+func (r *RepositoryTestSuite) TestGetACOUsage() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	cmsID := testUtils.RandomHexID()[0:4]
+	aco := models.ACO{UUID: uuid.NewRandom(), Name: uuid.New(), CMSID: &cmsID}
+	postgrestest.CreateACO(r.T(), r.db, aco)
+	defer postgrestest.DeleteACO(r.T(), r.db, aco.UUID)
+
+	start := time.Now().Add(-time.Hour)
+	end := time.Now().Add(time.Hour)
+	completed := &models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/api/v2/Patient/$export", Status: models.JobStatusCompleted}
+	archived := &models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/api/v2/Patient/$export", Status: models.JobStatusArchived}
+	failed := &models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/api/v2/Patient/$export", Status: models.JobStatusFailed}
+	outOfRange := &models.Job{ACOID: aco.UUID, RequestURL: "http://bcda.cms.gov/api/v2/Patient/$export", Status: models.JobStatusFailed,
+		CreatedAt: start.Add(-24 * time.Hour)}
+	postgrestest.CreateJobs(r.T(), r.db, completed, archived, failed, outOfRange)
+	defer postgrestest.DeleteJobKeysByJobIDs(r.T(), r.db, completed.ID, archived.ID)
+
+	postgrestest.CreateJobKeys(r.T(), r.db,
+		models.JobKey{JobID: completed.ID, FileName: uuid.New() + ".ndjson", ResourceType: "Patient"},
+		models.JobKey{JobID: completed.ID, FileName: uuid.New() + ".ndjson", ResourceType: "Patient"},
+		models.JobKey{JobID: completed.ID, FileName: uuid.New() + "-error.ndjson", ResourceType: "Coverage"},
+		models.JobKey{JobID: archived.ID, FileName: uuid.New() + ".ndjson", ResourceType: "Patient"},
+	)
+
+	defer func() {
+		_, err := r.db.Exec("DELETE FROM job_key_downloads WHERE aco_id = $1", aco.UUID)
+		assert.NoError(err)
+	}()
+	assert.NoError(r.repository.CreateJobKeyDownload(ctx, models.JobKeyDownload{JobID: completed.ID, ACOID: aco.UUID, FileName: uuid.New(), Bytes: 100}))
+	assert.NoError(r.repository.CreateJobKeyDownload(ctx, models.JobKeyDownload{JobID: completed.ID, ACOID: aco.UUID, FileName: uuid.New(), Bytes: 250}))
+
+	// Jobs for an ACO without a CMS ID can't be reported on, but don't fail the report
+	noCMSID := models.ACO{UUID: uuid.NewRandom(), Name: uuid.New()}
+	postgrestest.CreateACO(r.T(), r.db, noCMSID)
+	defer postgrestest.DeleteACO(r.T(), r.db, noCMSID.UUID)
+	postgrestest.CreateJobs(r.T(), r.db, &models.Job{ACOID: noCMSID.UUID, RequestURL: "http://bcda.cms.gov/api/v2/Patient/$export", Status: models.JobStatusCompleted})
+
+	usage, err := r.repository.GetACOUsage(ctx, start, end)
+	assert.NoError(err)
+	var found *models.ACOUsage
+	for _, u := range usage {
+		if u.CMSID == cmsID {
+			found = u
+		}
+	}
+	if !assert.NotNil(found) {
+		return
+	}
+	assert.Equal(map[models.JobStatus]int{models.JobStatusCompleted: 2, models.JobStatusFailed: 1}, found.Jobs)
+	assert.Equal(map[string]int{"Patient": 2}, found.ResourceTypes, "error files are not counted")
+	assert.Equal(2, found.Downloads)
+	assert.Equal(int64(350), found.BytesDownloaded)
+}
+
+func (r *RepositoryTestSuite) TestCredentialRotationMethods() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	acoID := uuid.NewRandom()
+	previousClientID, clientID := uuid.New(), uuid.New()
+	defer func() {
+		_, err := r.db.Exec("DELETE FROM credential_rotations WHERE aco_id = $1", acoID)
+		assert.NoError(err)
+	}()
+
+	secretExpiresAt := time.Now().Add(90 * 24 * time.Hour).UTC().Round(time.Millisecond)
+	rotation := models.CredentialRotation{ACOID: acoID, PreviousClientID: previousClientID, PreviousSystemID: "1",
+		ClientID: clientID, SystemID: "2", GraceExpiresAt: time.Now().Add(-time.Minute), SecretExpiresAt: &secretExpiresAt}
+	assert.NoError(r.repository.CreateCredentialRotation(ctx, rotation))
+
+	for _, id := range []string{previousClientID, clientID} {
+		res, err := r.repository.GetCredentialRotationByClientID(ctx, id)
+		assert.NoError(err)
+		assert.Equal(previousClientID, res.PreviousClientID)
+		assert.Equal(clientID, res.ClientID)
+		assert.True(secretExpiresAt.Equal(*res.SecretExpiresAt))
+		assert.Nil(res.RevokedAt)
+	}
+
+	_, err := r.repository.GetCredentialRotationByClientID(ctx, uuid.New())
+	assert.ErrorIs(err, sql.ErrNoRows)
+
+	expired, err := r.repository.GetExpiredCredentialRotations(ctx, time.Now())
+	assert.NoError(err)
+	var found *models.CredentialRotation
+	for _, e := range expired {
+		if uuid.Equal(e.ACOID, acoID) {
+			found = e
+		}
+	}
+	if assert.NotNil(found) {
+		assert.NoError(r.repository.MarkCredentialRotationRevoked(ctx, found.ID))
+	}
+
+	expired, err = r.repository.GetExpiredCredentialRotations(ctx, time.Now())
+	assert.NoError(err)
+	for _, e := range expired {
+		assert.False(uuid.Equal(e.ACOID, acoID))
+	}
+
+	assert.EqualError(r.repository.MarkCredentialRotationRevoked(ctx, 0), "credential rotation 0 not updated, no row found")
+}
+
+func (r *RepositoryTestSuite) TestAuthSystemMethods() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	system := models.AuthSystem{ACOID: uuid.NewRandom(), ClientID: uuid.New(), ClientName: "Local Auth System",
+		SecretHash: "hash", IPs: []string{"127.0.0.1", "10.0.0.1"}, SecretExpiresAt: time.Now().Add(time.Hour).UTC().Round(time.Millisecond)}
+	defer func() {
+		_, err := r.db.Exec("DELETE FROM auth_systems WHERE client_id = $1", system.ClientID)
+		assert.NoError(err)
+	}()
+
+	id, err := r.repository.CreateAuthSystem(ctx, system)
+	assert.NoError(err)
+
+	res, err := r.repository.GetAuthSystemByClientID(ctx, system.ClientID)
+	assert.NoError(err)
+	assert.Equal(id, res.ID)
+	assert.Equal(system.IPs, res.IPs)
+	assert.Equal("", res.PublicKey)
+	assert.True(system.SecretExpiresAt.Equal(res.SecretExpiresAt))
+
+	newExpiry := system.SecretExpiresAt.Add(24 * time.Hour)
+	assert.NoError(r.repository.UpdateAuthSystemSecret(ctx, id, "newHash", newExpiry))
+	res, err = r.repository.GetAuthSystemByClientID(ctx, system.ClientID)
+	assert.NoError(err)
+	assert.Equal("newHash", res.SecretHash)
+	assert.True(newExpiry.Equal(res.SecretExpiresAt))
+
+	assert.NoError(r.repository.DeleteAuthSystem(ctx, id))
+	_, err = r.repository.GetAuthSystemByClientID(ctx, system.ClientID)
+	assert.ErrorIs(err, sql.ErrNoRows)
+	assert.EqualError(r.repository.DeleteAuthSystem(ctx, id), fmt.Sprintf("system %d not updated, no active row found", id))
+}
+
+func (r *RepositoryTestSuite) TestRevokedTokenMethods() {
+	ctx := context.Background()
+	assert := r.Assert()
+
+	tokenID := uuid.New()
+	defer func() {
+		_, err := r.db.Exec("DELETE FROM auth_revoked_tokens WHERE token_id = $1", tokenID)
+		assert.NoError(err)
+	}()
+
+	revoked, err := r.repository.IsTokenRevoked(ctx, tokenID)
+	assert.NoError(err)
+	assert.False(revoked)
+
+	assert.NoError(r.repository.CreateRevokedToken(ctx, tokenID, time.Now().Add(time.Hour)))
+	assert.NoError(r.repository.CreateRevokedToken(ctx, tokenID, time.Now().Add(time.Hour)))
+
+	revoked, err = r.repository.IsTokenRevoked(ctx, tokenID)
+	assert.NoError(err)
+	assert.True(revoked)
+
+	// Revoking another token prunes the revocations of expired tokens
+	expiredID, otherID := uuid.New(), uuid.New()
+	defer func() {
+		_, err := r.db.Exec("DELETE FROM auth_revoked_tokens WHERE token_id IN ($1, $2)", expiredID, otherID)
+		assert.NoError(err)
+	}()
+	assert.NoError(r.repository.CreateRevokedToken(ctx, expiredID, time.Now().Add(-time.Minute)))
+	assert.NoError(r.repository.CreateRevokedToken(ctx, otherID, time.Now().Add(time.Hour)))
+
+	revoked, err = r.repository.IsTokenRevoked(ctx, expiredID)
+	assert.NoError(err)
+	assert.False(revoked)
+	revoked, err = r.repository.IsTokenRevoked(ctx, tokenID)
+	assert.NoError(err)
+	assert.True(revoked)
+}
+
 // TestCMSID verifies that we can store and retrieve the CMS_ID as expected
 // i.e. the value is not padded with any extra characters
 func (r *RepositoryTestSuite) TestCMSID() {
@@ -1093,6 +1771,14 @@ func getCCLFBeneficiary() *models.CCLFBeneficiary {
 	}
 }
 
+// nullTime returns the value a nullable date column holds for t, which is NULL when t is zero
+func nullTime(t time.Time) driver.Value {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
 func assertEqualCCLFFile(assert *assert.Assertions, expected, actual models.CCLFFile) {
 	// normalize timestamps so we can use equality checks
 	expected.Timestamp = expected.Timestamp.UTC()
@@ -10,17 +10,27 @@ import (
 // Repository contains all of the CRUD methods represented in the models package from the storage layer
 type Repository interface {
 	acoRepository
+	authSystemRepository
 	benePrefsRepository
 	cclfFileRepository
 	cclfBeneficiaryRepository
+	credentialRotationRepository
 	jobRepository
 	JobKeyRepository
+
+	// InTransaction calls fn with a Repository whose methods all run in a single transaction, which is committed
+	// if fn returns nil and rolled back otherwise. Calls made on a Repository that is already in a transaction
+	// join it.
+	InTransaction(ctx context.Context, fn func(Repository) error) error
 }
 
 type acoRepository interface {
 	CreateACO(ctx context.Context, aco ACO) error
 	GetACOByClientID(ctx context.Context, clientID string) (*ACO, error)
 	GetACOByCMSID(ctx context.Context, cmsID string) (*ACO, error)
+	// GetACOByClientCertSubjects returns the ACO mapped to the first of a mutual TLS client certificate's
+	// identities that has one. The error wraps sql.ErrNoRows when none of them are mapped.
+	GetACOByClientCertSubjects(ctx context.Context, subjects []string) (*ACO, error)
 	GetACOByUUID(ctx context.Context, uuid uuid.UUID) (*ACO, error)
 	GetCMSIDByClientID(ctx context.Context, clientID string) (string, error)
 	// UpdateACO updates the ACO (found by the acoUUID field) with the fields and values indicated by the fieldsAndValues map.
@@ -29,11 +39,29 @@ type acoRepository interface {
 	UpdateACO(ctx context.Context, acoUUID uuid.UUID, fieldsAndValues map[string]interface{}) error
 }
 
+// authSystemRepository backs the local auth provider.
+type authSystemRepository interface {
+	CreateAuthSystem(ctx context.Context, system AuthSystem) (uint, error)
+	// GetAuthSystemByClientID returns the active (not deleted) system registered with the client ID.
+	GetAuthSystemByClientID(ctx context.Context, clientID string) (*AuthSystem, error)
+	UpdateAuthSystemSecret(ctx context.Context, id uint, secretHash string, secretExpiresAt time.Time) error
+	DeleteAuthSystem(ctx context.Context, id uint) error
+	CreateRevokedToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+}
+
 type benePrefsRepository interface {
 	CreateBenePrefsFile(ctx context.Context, file BenePrefsFile) (uint, error)
 	CreateBenePrefsRecord(ctx context.Context, record BenePrefsRecord) error
 	GetSuppressedMBIs(ctx context.Context, lookbackDays int, upperBound time.Time) ([]string, error)
+	GetSAMHSAOptOutMBIs(ctx context.Context, mbis []string, lookbackDays int, upperBound time.Time) ([]string, error)
 	UpdateBenePrefsImportStatus(ctx context.Context, fileID uint, status string) error
+	GetBenePrefsFileByName(ctx context.Context, name string) (*BenePrefsFile, error)
+	// DeleteBenePrefsFile removes the file and the suppression records imported from it.
+	DeleteBenePrefsFile(ctx context.Context, fileID uint) error
+	// UpsertMBILinks adds the MBI of each beneficiary in the bene-prefs file to the MBI crosswalk, keyed by the
+	// beneficiary link key. It returns the number of links added or refreshed.
+	UpsertMBILinks(ctx context.Context, fileID uint) (int64, error)
 }
 
 type cclfFileRepository interface {
@@ -49,23 +77,60 @@ type cclfFileRepository interface {
 	CreateCCLFFile(ctx context.Context, cclfFile CCLFFile) (uint, error)
 
 	UpdateCCLFFileImportStatus(ctx context.Context, fileID uint, importStatus string) error
+
+	// UpdateCCLFFileStatus moves a completed, superseded or invalid CCLF file to status and records the
+	// change. Files in any other status are treated as not found.
+	UpdateCCLFFileStatus(ctx context.Context, fileID uint, status, reason, changedBy string) (*CCLFFileStatusChange, error)
+
+	// GetWithdrawnCCLFFiles returns the superseded or invalid CCLF files with a timestamp after the given time,
+	// most recent first.
+	GetWithdrawnCCLFFiles(ctx context.Context, cmsID string, cclfNum int, after time.Time, fileType CCLFFileType) ([]*CCLFFile, error)
 }
 
 // CCLFBeneficiaryRepository contains methods need to interact with CCLF Beneficiary data.
 type cclfBeneficiaryRepository interface {
 	GetCCLFBeneficiaries(ctx context.Context, cclfFileID uint, ignoredMBIs []string) ([]*CCLFBeneficiary, error)
 	GetCCLFBeneficiaryMBIs(ctx context.Context, cclfFileID uint) ([]string, error)
+
+	// GetCCLFBeneficiaryCount returns the number of distinct MBIs attributed by the CCLF file.
+	GetCCLFBeneficiaryCount(ctx context.Context, cclfFileID uint) (int, error)
+	// GetCCLFBeneficiaryPage returns up to limit beneficiaries of the CCLF file, skipping the first offset.
+	// Beneficiaries are ordered by MBI so pages remain stable while the file is in use.
+	GetCCLFBeneficiaryPage(ctx context.Context, cclfFileID uint, offset, limit int) ([]*CCLFBeneficiary, error)
+}
+
+type credentialRotationRepository interface {
+	CreateCredentialRotation(ctx context.Context, rotation CredentialRotation) error
+	// GetCredentialRotationByClientID returns the most recent rotation in which clientID was either
+	// issued or replaced.
+	GetCredentialRotationByClientID(ctx context.Context, clientID string) (*CredentialRotation, error)
+	// GetExpiredCredentialRotations returns rotations whose grace window ended before the given time
+	// and whose previous credentials have not yet been revoked.
+	GetExpiredCredentialRotations(ctx context.Context, before time.Time) ([]*CredentialRotation, error)
+	MarkCredentialRotationRevoked(ctx context.Context, id uint) error
 }
 
 type jobRepository interface {
 	CreateJob(ctx context.Context, j Job) (jobID uint, err error)
 	GetJobByID(ctx context.Context, jobID uint) (*Job, error)
 	GetJobs(ctx context.Context, acoID uuid.UUID, statuses ...JobStatus) ([]*Job, error)
+	// GetAuditEvents returns a page of the ACO's jobs and downloads created at or after since, along with the
+	// number of them created since then. Events are ordered from most to least recent; the page skips the first
+	// offset events and holds up to limit of them. If since equals time.Time (default value), then all events are considered.
+	GetAuditEvents(ctx context.Context, acoID uuid.UUID, since time.Time, offset, limit int) ([]*Job, []*JobKeyDownload, int, error)
 	GetJobsByUpdateTimeAndStatus(ctx context.Context, lowerBound, upperBound time.Time, statuses ...JobStatus) ([]*Job, error)
 	UpdateJob(ctx context.Context, j Job) error
+	// GetACOUsage returns the usage of each ACO that created a job or downloaded a file at or after start
+	// and before end, ordered by CMS ID.
+	GetACOUsage(ctx context.Context, start, end time.Time) ([]*ACOUsage, error)
 }
 
 type JobKeyRepository interface {
 	GetJobKey(ctx context.Context, jobID uint, filename string) (*JobKey, error)
 	GetJobKeys(ctx context.Context, jobID uint) ([]*JobKey, error)
+	// GetJobKeysByJobIDs returns the job keys of each of the ACO's jobs in jobIDs, by job ID.
+	GetJobKeysByJobIDs(ctx context.Context, acoID uuid.UUID, jobIDs []uint) (map[uint][]*JobKey, error)
+
+	// CreateJobKeyDownload records that a job's output file was downloaded.
+	CreateJobKeyDownload(ctx context.Context, download JobKeyDownload) error
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	"github.com/CMSgov/bcda-app/bcda/models"
@@ -99,6 +101,212 @@ func (r FhirResponseWriter) CreateJobsBundleEntry(job *models.Job, host string)
 	}
 }
 
+func (r FhirResponseWriter) AuditEventBundle(w http.ResponseWriter, cmsID string, total int, jobs []*models.Job, jobKeys map[uint][]*models.JobKey, downloads []*models.JobKeyDownload, host string) {
+	ab := r.CreateAuditEventBundle(cmsID, total, jobs, jobKeys, downloads, host)
+	r.WriteBundleResponse(ab, w)
+}
+
+// CreateAuditEventBundle renders an ACO's export jobs (along with the files each job produced) and file downloads
+// as a searchset of AuditEvents, ordered from most to least recent. Total is the number of events matching the
+// search, so the bundle may hold fewer entries than it.
+func (r FhirResponseWriter) CreateAuditEventBundle(cmsID string, total int, jobs []*models.Job, jobKeys map[uint][]*models.JobKey, downloads []*models.JobKeyDownload, host string) *r4.Bundle {
+	type recordedEvent struct {
+		recorded time.Time
+		event    *r4.AuditEvent
+	}
+
+	events := make([]recordedEvent, 0, len(jobs)+len(downloads))
+	for _, job := range jobs {
+		events = append(events, recordedEvent{job.CreatedAt, r.CreateJobAuditEvent(cmsID, job, jobKeys[job.ID], host)})
+	}
+	for _, download := range downloads {
+		events = append(events, recordedEvent{download.CreatedAt, r.CreateDownloadAuditEvent(cmsID, download, host)})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].recorded.After(events[j].recorded)
+	})
+
+	entries := make([]r4.BundleEntry, 0, len(events))
+	for _, e := range events {
+		entries = append(entries, r4.BundleEntry{Resource: e.event})
+	}
+
+	bundleTotal, err := safecast.ToUint32(total)
+	if err != nil {
+		log.API.Errorln(err)
+	}
+
+	return &r4.Bundle{
+		ResourceType: "Bundle",
+		Type:         "searchset",
+		Total:        bundleTotal,
+		Entry:        entries,
+	}
+}
+
+// CreateJobAuditEvent renders a bulk export request as an AuditEvent. Each file produced by the job is listed as an entity.
+func (r FhirResponseWriter) CreateJobAuditEvent(cmsID string, job *models.Job, jobKeys []*models.JobKey, host string) *r4.AuditEvent {
+	entities := []r4.AuditEventEntity{
+		{
+			What: &r4.Reference{
+				Identifier: &r4.Identifier{
+					Use:    "official",
+					System: host + "/api/v3/jobs",
+					Value:  fmt.Sprint(job.ID),
+				},
+			},
+			Description: "GET " + job.RequestURL,
+		},
+	}
+	for _, jk := range jobKeys {
+		entities = append(entities, fileEntity(job.ID, jk.FileName, jk.ResourceType, host))
+	}
+
+	return &r4.AuditEvent{
+		ResourceType: "AuditEvent",
+		ID:           fmt.Sprintf("job-%d", job.ID),
+		Type: r4.Coding{
+			System:  constants.AuditEventTypeSystem,
+			Code:    "rest",
+			Display: "RESTful Operation",
+		},
+		Subtype: []r4.Coding{
+			{
+				System:  constants.RestfulInteractionSystem,
+				Code:    "operation",
+				Display: "$export",
+			},
+		},
+		Action: r4.AuditEventActionExecute,
+		Period: &r4.Period{
+			Start: job.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+			End:   job.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		},
+		Recorded: job.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		Outcome:  r.GetAuditEventOutcome(job.Status),
+		Agent:    []r4.AuditEventAgent{{Who: &r4.Reference{Display: cmsID}, Requestor: true}},
+		Source:   auditEventSource(host),
+		Entity:   entities,
+	}
+}
+
+// CreateDownloadAuditEvent renders the download of a single job file as an AuditEvent.
+func (r FhirResponseWriter) CreateDownloadAuditEvent(cmsID string, download *models.JobKeyDownload, host string) *r4.AuditEvent {
+	who := &r4.Reference{Display: cmsID}
+	if download.ClientID != "" {
+		who.Identifier = &r4.Identifier{Value: download.ClientID}
+	}
+
+	return &r4.AuditEvent{
+		ResourceType: "AuditEvent",
+		ID:           fmt.Sprintf("download-%d", download.ID),
+		Type: r4.Coding{
+			System:  constants.AuditEventDICOMSystem,
+			Code:    "110106",
+			Display: "Export",
+		},
+		Action:   r4.AuditEventActionRead,
+		Recorded: download.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		Outcome:  r4.AuditEventOutcomeSuccess,
+		Agent:    []r4.AuditEventAgent{{Who: who, Requestor: true}},
+		Source:   auditEventSource(host),
+		Entity:   []r4.AuditEventEntity{fileEntity(download.JobID, download.FileName, download.ResourceType, host)},
+	}
+}
+
+// GetAuditEventOutcome maps a job status to an AuditEvent outcome. Jobs that have not finished have no outcome.
+func (r FhirResponseWriter) GetAuditEventOutcome(status models.JobStatus) r4.AuditEventOutcome {
+	switch status {
+	case models.JobStatusCompleted, models.JobStatusArchived, models.JobStatusExpired:
+		return r4.AuditEventOutcomeSuccess
+	case models.JobStatusCancelled, models.JobStatusCancelledExpired:
+		return r4.AuditEventOutcomeMinorFailure
+	case models.JobStatusFailed, models.JobStatusFailedExpired:
+		return r4.AuditEventOutcomeSeriousFailure
+	}
+	return ""
+}
+
+func fileEntity(jobID uint, fileName, resourceType, host string) r4.AuditEventEntity {
+	entity := r4.AuditEventEntity{
+		What: &r4.Reference{Reference: fmt.Sprintf("%s/data/%d/%s", host, jobID, fileName)},
+		Name: fileName,
+	}
+	if resourceType != "" {
+		entity.Type = &r4.Coding{System: constants.FHIRResourceTypesSystem, Code: resourceType}
+	}
+	return entity
+}
+
+func auditEventSource(host string) r4.AuditEventSource {
+	return r4.AuditEventSource{
+		Site:     host,
+		Observer: r4.Reference{Display: constants.SoftwareName},
+	}
+}
+
+// CreateGroup renders a page of the beneficiaries attributed to an ACO by file as a Group. Quantity is the size of
+// the whole roster, so members may hold fewer entries than it. Beneficiaries whose MBI is in suppressed are marked inactive.
+func (r FhirResponseWriter) CreateGroup(groupID, cmsID string, file *models.CCLFFile, total int, benes []*models.CCLFBeneficiary, suppressed map[string]struct{}) *r4.Group {
+	quantity, err := safecast.ToUint32(total)
+	if err != nil {
+		log.API.Errorln(err)
+	}
+
+	members := make([]r4.GroupMember, 0, len(benes))
+	for _, bene := range benes {
+		member := r4.GroupMember{
+			Entity: r4.Reference{Identifier: &r4.Identifier{System: constants.MBISystem, Value: bene.MBI}},
+		}
+		if !bene.AttributionStart.IsZero() || !bene.AttributionEnd.IsZero() {
+			member.Period = &r4.Period{Start: formatDate(bene.AttributionStart), End: formatDate(bene.AttributionEnd)}
+		}
+		if _, ok := suppressed[bene.MBI]; ok {
+			member.Inactive = true
+		}
+		members = append(members, member)
+	}
+
+	return &r4.Group{
+		ResourceType:   "Group",
+		ID:             groupID,
+		Meta:           &r4.Meta{LastUpdated: file.Timestamp.UTC().Format("2006-01-02T15:04:05Z")},
+		Type:           r4.GroupTypePerson,
+		Actual:         true,
+		Name:           fmt.Sprintf("Beneficiaries attributed to %s (%s)", cmsID, groupID),
+		Quantity:       quantity,
+		ManagingEntity: &r4.Reference{Display: cmsID},
+		Member:         members,
+	}
+}
+
+// CreateGroupBundle renders groups as a searchset.
+func (r FhirResponseWriter) CreateGroupBundle(groups []*r4.Group) *r4.Bundle {
+	entries := make([]r4.BundleEntry, 0, len(groups))
+	for _, g := range groups {
+		entries = append(entries, r4.BundleEntry{Resource: g})
+	}
+
+	total, err := safecast.ToUint32(len(entries))
+	if err != nil {
+		log.API.Errorln(err)
+	}
+
+	return &r4.Bundle{
+		ResourceType: "Bundle",
+		Type:         "searchset",
+		Total:        total,
+		Entry:        entries,
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
 func (r FhirResponseWriter) GetFhirStatusCode(status models.JobStatus) r4.TaskStatus {
 	switch status {
 	case models.JobStatusFailed, models.JobStatusFailedExpired:
@@ -153,6 +361,24 @@ func (r FhirResponseWriter) WriteOperationOutcome(w io.Writer, outcome *r4.Opera
 	return w.Write(outcomeJSON)
 }
 
+func (r FhirResponseWriter) WriteGroupResponse(group *r4.Group, w http.ResponseWriter) {
+	groupJSON, err := json.Marshal(group)
+	if err != nil {
+		log.API.WithField("resp_status", http.StatusInternalServerError).Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(constants.ContentType, constants.JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(groupJSON)
+	if err != nil {
+		log.API.WithField("resp_status", http.StatusInternalServerError).Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (r FhirResponseWriter) WriteBundleResponse(bundle *r4.Bundle, w http.ResponseWriter) {
 	bundleJSON, err := json.Marshal(bundle)
 	if err != nil {
@@ -229,6 +229,148 @@ func (s *ResponseUtilsWriterTestSuite) TestGetFhirStatusCode() {
 	}
 }
 
+func (s *ResponseUtilsWriterTestSuite) TestWriteAuditEventBundle() {
+	rw := NewFhirResponseWriter()
+	now := time.Now().Truncate(time.Second)
+	jobs := []*models.Job{
+		{
+			ID:         1,
+			ACOID:      uuid.NewUUID(),
+			RequestURL: "https://www.requesturl.com",
+			Status:     models.JobStatusCompleted,
+			CreatedAt:  now.Add(-24 * time.Hour),
+			UpdatedAt:  now.Add(-23 * time.Hour),
+		},
+	}
+	jobKeys := map[uint][]*models.JobKey{
+		1: {{ID: 10, JobID: 1, FileName: "patient.ndjson", ResourceType: "Patient"}},
+	}
+	downloads := []*models.JobKeyDownload{
+		{ID: 5, JobID: 1, FileName: "patient.ndjson", ResourceType: "Patient", ClientID: "client-id", CreatedAt: now},
+	}
+
+	rw.AuditEventBundle(s.rr, "A9999", 3, jobs, jobKeys, downloads, constants.TestAPIUrl)
+
+	var bundle r4.Bundle
+	assert.NoError(s.T(), json.Unmarshal(s.rr.Body.Bytes(), &bundle))
+	assert.Equal(s.T(), http.StatusOK, s.rr.Code)
+	// Total covers every matching event, not just the ones on this page
+	assert.Equal(s.T(), uint32(3), bundle.Total)
+	assert.Len(s.T(), bundle.Entry, 2)
+	assert.Equal(s.T(), "searchset", bundle.Type)
+
+	events := make([]r4.AuditEvent, len(bundle.Entry))
+	for i, entry := range bundle.Entry {
+		b, err := json.Marshal(entry.Resource)
+		assert.NoError(s.T(), err)
+		assert.NoError(s.T(), json.Unmarshal(b, &events[i]))
+	}
+
+	// Most recent event comes first
+	download := events[0]
+	assert.Equal(s.T(), "AuditEvent", download.ResourceType)
+	assert.Equal(s.T(), "download-5", download.ID)
+	assert.Equal(s.T(), r4.AuditEventActionRead, download.Action)
+	assert.Equal(s.T(), r4.AuditEventOutcomeSuccess, download.Outcome)
+	assert.Equal(s.T(), now.UTC().Format("2006-01-02T15:04:05Z"), download.Recorded)
+	assert.Equal(s.T(), "client-id", download.Agent[0].Who.Identifier.Value)
+	assert.Equal(s.T(), "A9999", download.Agent[0].Who.Display)
+	assert.Equal(s.T(), "https://www.api.com/data/1/patient.ndjson", download.Entity[0].What.Reference)
+	assert.Equal(s.T(), "Patient", download.Entity[0].Type.Code)
+
+	job := events[1]
+	assert.Equal(s.T(), "job-1", job.ID)
+	assert.Equal(s.T(), r4.AuditEventActionExecute, job.Action)
+	assert.Equal(s.T(), r4.AuditEventOutcomeSuccess, job.Outcome)
+	assert.Equal(s.T(), jobs[0].CreatedAt.UTC().Format("2006-01-02T15:04:05Z"), job.Period.Start)
+	assert.Equal(s.T(), jobs[0].UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"), job.Period.End)
+	assert.Len(s.T(), job.Entity, 2)
+	assert.Equal(s.T(), "https://www.api.com/api/v3/jobs", job.Entity[0].What.Identifier.System)
+	assert.Equal(s.T(), "1", job.Entity[0].What.Identifier.Value)
+	assert.Equal(s.T(), "GET "+jobs[0].RequestURL, job.Entity[0].Description)
+	assert.Equal(s.T(), "patient.ndjson", job.Entity[1].Name)
+}
+
+func (s *ResponseUtilsWriterTestSuite) TestCreateAuditEventBundleEmpty() {
+	rw := NewFhirResponseWriter()
+	ab := rw.CreateAuditEventBundle("A9999", 0, nil, nil, nil, constants.TestAPIUrl)
+
+	assert.Equal(s.T(), uint32(0), ab.Total)
+	assert.Equal(s.T(), "searchset", ab.Type)
+	assert.Empty(s.T(), ab.Entry)
+}
+
+func (s *ResponseUtilsWriterTestSuite) TestGetAuditEventOutcome() {
+	rw := NewFhirResponseWriter()
+	tests := []struct {
+		status  models.JobStatus
+		outcome r4.AuditEventOutcome
+	}{
+		{models.JobStatusPending, ""},
+		{models.JobStatusInProgress, ""},
+		{models.JobStatusCompleted, r4.AuditEventOutcomeSuccess},
+		{models.JobStatusArchived, r4.AuditEventOutcomeSuccess},
+		{models.JobStatusExpired, r4.AuditEventOutcomeSuccess},
+		{models.JobStatusCancelled, r4.AuditEventOutcomeMinorFailure},
+		{models.JobStatusCancelledExpired, r4.AuditEventOutcomeMinorFailure},
+		{models.JobStatusFailed, r4.AuditEventOutcomeSeriousFailure},
+		{models.JobStatusFailedExpired, r4.AuditEventOutcomeSeriousFailure},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.outcome, rw.GetAuditEventOutcome(tt.status))
+		})
+	}
+}
+
+func (s *ResponseUtilsWriterTestSuite) TestWriteGroupResponse() {
+	rw := NewFhirResponseWriter()
+	file := &models.CCLFFile{ID: 1, Timestamp: time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)}
+	benes := []*models.CCLFBeneficiary{
+		{ID: 1, MBI: "1SJ0A00AA00", AttributionStart: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), AttributionEnd: time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, MBI: "1SJ0A00AA01"},
+	}
+	suppressed := map[string]struct{}{"1SJ0A00AA01": {}}
+
+	rw.WriteGroupResponse(rw.CreateGroup("all", "A9999", file, 10, benes, suppressed), s.rr)
+
+	var group r4.Group
+	assert.NoError(s.T(), json.Unmarshal(s.rr.Body.Bytes(), &group))
+	assert.Equal(s.T(), http.StatusOK, s.rr.Code)
+	assert.Equal(s.T(), "Group", group.ResourceType)
+	assert.Equal(s.T(), "all", group.ID)
+	assert.Equal(s.T(), r4.GroupTypePerson, group.Type)
+	assert.True(s.T(), group.Actual)
+	assert.Equal(s.T(), uint32(10), group.Quantity)
+	assert.Equal(s.T(), "2026-01-15T10:30:00Z", group.Meta.LastUpdated)
+	assert.Equal(s.T(), "A9999", group.ManagingEntity.Display)
+	assert.Len(s.T(), group.Member, 2)
+
+	assert.Equal(s.T(), constants.MBISystem, group.Member[0].Entity.Identifier.System)
+	assert.Equal(s.T(), "1SJ0A00AA00", group.Member[0].Entity.Identifier.Value)
+	assert.Equal(s.T(), &r4.Period{Start: "2026-01-01", End: "2026-06-30"}, group.Member[0].Period)
+	assert.False(s.T(), group.Member[0].Inactive)
+
+	assert.Equal(s.T(), "1SJ0A00AA01", group.Member[1].Entity.Identifier.Value)
+	assert.Nil(s.T(), group.Member[1].Period)
+	assert.True(s.T(), group.Member[1].Inactive)
+}
+
+func (s *ResponseUtilsWriterTestSuite) TestCreateGroupBundle() {
+	rw := NewFhirResponseWriter()
+	file := &models.CCLFFile{ID: 1, Timestamp: time.Now()}
+	gb := rw.CreateGroupBundle([]*r4.Group{
+		rw.CreateGroup("all", "A9999", file, 10, nil, nil),
+		rw.CreateGroup("runout", "A9999", file, 8, nil, nil),
+	})
+
+	assert.Equal(s.T(), uint32(2), gb.Total)
+	assert.Equal(s.T(), "searchset", gb.Type)
+	assert.Equal(s.T(), "runout", gb.Entry[1].Resource.(*r4.Group).ID)
+	assert.Empty(s.T(), gb.Entry[1].Resource.(*r4.Group).Member)
+}
+
 func MakeTestStructuredLoggerEntry(logFields logrus.Fields) *log.StructuredLoggerEntry {
 	var lggr logrus.Logger
 	newLogEntry := &log.StructuredLoggerEntry{Logger: lggr.WithFields(logFields)}
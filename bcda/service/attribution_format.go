@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultAttributionDateLayout is used to parse effective and termination dates when a layout does not specify one.
+const DefaultAttributionDateLayout = "2006-01-02"
+
+// AttributionLayout describes the records contained in an attribution file. Records are either delimited
+// (Delimiter is set) or fixed-width, in which case fields are located by byte offsets.
+type AttributionLayout struct {
+	Delimiter string `conf:"delimiter"`
+	// Number of lines before the first record
+	HeaderLines int `conf:"header_lines"`
+	// Lines starting with TrailerPrefix are skipped, e.g. record count trailers
+	TrailerPrefix string `conf:"trailer_prefix"`
+
+	MBI             AttributionField `conf:"mbi"`
+	EffectiveDate   AttributionField `conf:"effective_date"`
+	TerminationDate AttributionField `conf:"termination_date"`
+	DateLayout      string           `conf:"date_layout"`
+}
+
+// AttributionField locates a value within a record. Delimited layouts use the 1-based Column while
+// fixed-width layouts use the [Start, End) byte offsets. A field with neither is not present in the file.
+type AttributionField struct {
+	Column int `conf:"column"`
+	Start  int `conf:"start"`
+	End    int `conf:"end"`
+}
+
+func (f AttributionField) IsSet() bool {
+	return f.Column > 0 || f.End > f.Start
+}
+
+// IsDelimited reports whether records are split on a delimiter rather than read at fixed offsets.
+func (l AttributionLayout) IsDelimited() bool {
+	return l.Delimiter != ""
+}
+
+// Comma returns the delimiter as a rune for use with encoding/csv.
+func (l AttributionLayout) Comma() rune {
+	r, _ := utf8.DecodeRuneInString(l.Delimiter)
+	return r
+}
+
+func (l AttributionLayout) validate() error {
+	if utf8.RuneCountInString(l.Delimiter) > 1 {
+		return fmt.Errorf("delimiter '%s' must be a single character", l.Delimiter)
+	}
+	if !l.MBI.IsSet() {
+		return fmt.Errorf("an MBI field is required")
+	}
+	for name, field := range map[string]AttributionField{"mbi": l.MBI, "effective_date": l.EffectiveDate, "termination_date": l.TerminationDate} {
+		if l.IsDelimited() && field.End > field.Start {
+			return fmt.Errorf("%s uses byte offsets but the layout is delimited", name)
+		}
+		if !l.IsDelimited() && field.Column > 0 {
+			return fmt.Errorf("%s uses a column but the layout is fixed-width", name)
+		}
+	}
+	return nil
+}
+
+// CCLFFormat is the format of CCLF8 records. Models that receive CCLF archives use it unless their
+// attribution_file names another format.
+const CCLFFormat = "cclf8"
+
+// Built-in attribution formats. Models reference them by name with attribution_file.format and may override
+// individual settings with attribution_file.layout.
+var attributionFormats = map[string]AttributionLayout{
+	// Single column of MBIs with a header row
+	"csv": {Delimiter: ",", HeaderLines: 1, MBI: AttributionField{Column: 1}},
+	// CCLF8 beneficiary demographics records
+	CCLFFormat: {MBI: AttributionField{Start: 0, End: 11}},
+}
+
+// AttributionFormat returns the layout registered under name.
+func AttributionFormat(name string) (AttributionLayout, bool) {
+	layout, ok := attributionFormats[strings.ToLower(name)]
+	if ok && layout.DateLayout == "" {
+		layout.DateLayout = DefaultAttributionDateLayout
+	}
+	return layout, ok
+}
+
+// RecordLayout resolves the layout of the records in the attribution file. The named format (which defaults
+// to the file type) is used as a base and any settings declared in layout take precedence over it.
+func (a AttributionFile) RecordLayout() (AttributionLayout, error) {
+	name := a.Format
+	if name == "" {
+		name = a.FileType
+	}
+
+	layout, ok := attributionFormats[strings.ToLower(name)]
+	if !ok {
+		return AttributionLayout{}, fmt.Errorf("unknown attribution format '%s'", name)
+	}
+
+	o := a.Layout
+	if o.Delimiter != "" {
+		layout.Delimiter = o.Delimiter
+	}
+	if o.HeaderLines != 0 {
+		layout.HeaderLines = o.HeaderLines
+	}
+	if o.TrailerPrefix != "" {
+		layout.TrailerPrefix = o.TrailerPrefix
+	}
+	if o.MBI.IsSet() {
+		layout.MBI = o.MBI
+	}
+	if o.EffectiveDate.IsSet() {
+		layout.EffectiveDate = o.EffectiveDate
+	}
+	if o.TerminationDate.IsSet() {
+		layout.TerminationDate = o.TerminationDate
+	}
+	if o.DateLayout != "" {
+		layout.DateLayout = o.DateLayout
+	}
+	if layout.DateLayout == "" {
+		layout.DateLayout = DefaultAttributionDateLayout
+	}
+
+	if err := layout.validate(); err != nil {
+		return AttributionLayout{}, err
+	}
+	return layout, nil
+}
+
+// CCLFRecordLayout returns the layout of the CCLF8 records delivered for cmsID. The layout declared in the
+// attribution_file of the ACO's model, if any, is applied to the CCLF format.
+func (cfg *Config) CCLFRecordLayout(cmsID string) (AttributionLayout, error) {
+	for _, aco := range cfg.ACOConfigs {
+		if aco.patternExp == nil || !aco.patternExp.MatchString(cmsID) {
+			continue
+		}
+		return aco.AttributionFile.cclfRecordLayout()
+	}
+	layout, _ := AttributionFormat(CCLFFormat)
+	return layout, nil
+}
+
+func (a AttributionFile) cclfRecordLayout() (AttributionLayout, error) {
+	if a.Format == "" {
+		a.Format = CCLFFormat
+	}
+	return a.RecordLayout()
+}
+
+func (a AttributionFile) modelIdentifierMatch() int {
+	if a.ModelIdentifierMatch == 0 {
+		return 2
+	}
+	return a.ModelIdentifierMatch
+}
+
+// MatchesModel reports whether the submatches of the file's name pattern carry its model identifier.
+// Similarly named models can match each other's patterns, so the identifier is checked as well.
+func (a AttributionFile) MatchesModel(subMatches []string) bool {
+	idx := a.modelIdentifierMatch()
+	return idx < len(subMatches) && subMatches[idx] == a.ModelIdentifier
+}
+
+// CMSIDFromMatches builds the CMS ID of the entity an attribution file belongs to from the submatches of
+// its name pattern.
+func (a AttributionFile) CMSIDFromMatches(subMatches []string) (string, error) {
+	if a.CMSID != "" {
+		return a.CMSID, nil
+	}
+
+	var sb strings.Builder
+	for _, idx := range a.CMSIDMatches {
+		if idx <= 0 || idx >= len(subMatches) {
+			return "", fmt.Errorf("cms_id_matches group %d is not in the file name", idx)
+		}
+		sb.WriteString(subMatches[idx])
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("failed to get aco ID for attribution file")
+	}
+	return sb.String(), nil
+}
+
+// validate checks that the file name pattern and record layout are usable so misconfigured models are
+// caught when the config is loaded rather than when a file arrives.
+func (a AttributionFile) validate() error {
+	exp, err := regexp.Compile(a.NamePattern)
+	if err != nil {
+		return fmt.Errorf("invalid name pattern: %w", err)
+	}
+
+	groups := exp.NumSubexp()
+	for name, idx := range map[string]int{"file_performance_year": a.PerformanceYear, "file_date": a.FileDate,
+		"model_identifier_match": a.modelIdentifierMatch()} {
+		if idx <= 0 || idx > groups {
+			return fmt.Errorf("%s group %d is not in the name pattern", name, idx)
+		}
+	}
+	if a.CMSID == "" && len(a.CMSIDMatches) == 0 {
+		return fmt.Errorf("one of cms_id or cms_id_matches is required")
+	}
+	for _, idx := range a.CMSIDMatches {
+		if idx <= 0 || idx > groups {
+			return fmt.Errorf("cms_id_matches group %d is not in the name pattern", idx)
+		}
+	}
+
+	if _, err := a.RecordLayout(); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributionFileRecordLayout(t *testing.T) {
+	layout, err := AttributionFile{FileType: "csv"}.RecordLayout()
+	require.NoError(t, err)
+	assert.Equal(t, AttributionLayout{Delimiter: ",", HeaderLines: 1, MBI: AttributionField{Column: 1}, DateLayout: DefaultAttributionDateLayout}, layout)
+
+	// Settings declared in the layout override the named format
+	layout, err = AttributionFile{FileType: "csv", Layout: AttributionLayout{
+		Delimiter:     "|",
+		MBI:           AttributionField{Column: 2},
+		EffectiveDate: AttributionField{Column: 3},
+		DateLayout:    "20060102",
+	}}.RecordLayout()
+	require.NoError(t, err)
+	assert.Equal(t, AttributionLayout{Delimiter: "|", HeaderLines: 1, MBI: AttributionField{Column: 2},
+		EffectiveDate: AttributionField{Column: 3}, DateLayout: "20060102"}, layout)
+
+	layout, err = AttributionFile{FileType: "csv", Format: "CCLF8"}.RecordLayout()
+	require.NoError(t, err)
+	assert.False(t, layout.IsDelimited())
+	assert.Equal(t, AttributionField{Start: 0, End: 11}, layout.MBI)
+
+	_, err = AttributionFile{FileType: "csv", Format: "xml"}.RecordLayout()
+	assert.EqualError(t, err, "unknown attribution format 'xml'")
+
+	_, err = AttributionFile{FileType: "cclf8", Layout: AttributionLayout{EffectiveDate: AttributionField{Column: 2}}}.RecordLayout()
+	assert.EqualError(t, err, "effective_date uses a column but the layout is fixed-width")
+}
+
+func TestCCLFRecordLayout(t *testing.T) {
+	cfg := &Config{RunoutConfig: RunoutConfig{ClaimThruDate: "2020-12-31"}, ACOConfigs: []ACOConfig{
+		{Model: "SSP", Pattern: `^A\d{4}$`},
+		{Model: "KCC", Pattern: `^K\d{4}$`, AttributionFile: AttributionFile{Layout: AttributionLayout{
+			EffectiveDate: AttributionField{Start: 11, End: 19}, DateLayout: "20060102",
+		}}},
+	}}
+	require.NoError(t, cfg.ComputeFields())
+
+	cclf, _ := AttributionFormat(CCLFFormat)
+	layout, err := cfg.CCLFRecordLayout("A0001")
+	require.NoError(t, err)
+	assert.Equal(t, cclf, layout)
+
+	layout, err = cfg.CCLFRecordLayout("K0001")
+	require.NoError(t, err)
+	assert.Equal(t, AttributionLayout{MBI: AttributionField{Start: 0, End: 11}, EffectiveDate: AttributionField{Start: 11, End: 19},
+		DateLayout: "20060102"}, layout)
+
+	// ACOs without a model still use the CCLF format
+	layout, err = cfg.CCLFRecordLayout("Z0001")
+	require.NoError(t, err)
+	assert.Equal(t, cclf, layout)
+
+	// Invalid layouts are rejected when the config is loaded
+	cfg.ACOConfigs[1].AttributionFile.Layout.EffectiveDate = AttributionField{Column: 2}
+	assert.ErrorContains(t, cfg.ComputeFields(), "invalid ACO model KCC attribution file: effective_date uses a column but the layout is fixed-width")
+}
+
+func TestAttributionFileMatchesModel(t *testing.T) {
+	matches := []string{"P.GUIDE.GUIDE-0001.Y25", "P", "GUIDE", "GUIDE-", "0001"}
+
+	assert.True(t, AttributionFile{ModelIdentifier: "GUIDE"}.MatchesModel(matches))
+	assert.False(t, AttributionFile{ModelIdentifier: "GUIDE-"}.MatchesModel(matches))
+	assert.True(t, AttributionFile{ModelIdentifier: "GUIDE-", ModelIdentifierMatch: 3}.MatchesModel(matches))
+	assert.False(t, AttributionFile{ModelIdentifier: "GUIDE", ModelIdentifierMatch: 5}.MatchesModel(matches))
+	assert.False(t, AttributionFile{ModelIdentifier: "GUIDE"}.MatchesModel(nil))
+}
+
+func TestAttributionFileCMSIDFromMatches(t *testing.T) {
+	matches := []string{"P.GUIDE.GUIDE-0001.Y25", "P", "GUIDE", "GUIDE-", "0001"}
+
+	cmsID, err := AttributionFile{CMSIDMatches: []int{3, 4}}.CMSIDFromMatches(matches)
+	require.NoError(t, err)
+	assert.Equal(t, "GUIDE-0001", cmsID)
+
+	cmsID, err = AttributionFile{CMSID: "CT000000", CMSIDMatches: []int{3}}.CMSIDFromMatches(matches)
+	require.NoError(t, err)
+	assert.Equal(t, "CT000000", cmsID)
+
+	_, err = AttributionFile{CMSIDMatches: []int{5}}.CMSIDFromMatches(matches)
+	assert.EqualError(t, err, "cms_id_matches group 5 is not in the file name")
+
+	_, err = AttributionFile{}.CMSIDFromMatches(matches)
+	assert.EqualError(t, err, "failed to get aco ID for attribution file")
+}
+
+func TestAttributionFileValidate(t *testing.T) {
+	valid := AttributionFile{
+		FileType:        "csv",
+		NamePattern:     `(P|T)\.(BCD)\.(DA\d{4}).(MBIY)(\d{2})\.(D\d{6}\.T\d{6})\d`,
+		PerformanceYear: 5,
+		FileDate:        6,
+		CMSIDMatches:    []int{3},
+	}
+	assert.NoError(t, valid.validate())
+
+	tests := []struct {
+		name   string
+		modify func(a *AttributionFile)
+		err    string
+	}{
+		{"InvalidPattern", func(a *AttributionFile) { a.NamePattern = "(" }, "invalid name pattern"},
+		{"FileDateOutOfRange", func(a *AttributionFile) { a.FileDate = 7 }, "file_date group 7 is not in the name pattern"},
+		{"ModelIdentifierOutOfRange", func(a *AttributionFile) { a.ModelIdentifierMatch = 7 }, "model_identifier_match group 7 is not in the name pattern"},
+		{"MissingCMSID", func(a *AttributionFile) { a.CMSIDMatches = nil }, "one of cms_id or cms_id_matches is required"},
+		{"CMSIDOutOfRange", func(a *AttributionFile) { a.CMSIDMatches = []int{0} }, "cms_id_matches group 0 is not in the name pattern"},
+		{"UnknownFormat", func(a *AttributionFile) { a.Format = "xml" }, "unknown attribution format 'xml'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid
+			tt.modify(&a)
+			assert.ErrorContains(t, a.validate(), tt.err)
+		})
+	}
+}
@@ -72,8 +72,17 @@ type AttributionFile struct {
 	NamePattern     string `conf:"name_pattern" `
 	MetadataMatches int    `conf:"metadata_matches" `
 	ModelIdentifier string `conf:"model_identifier"`
-	PerformanceYear int    `conf:"file_performance_year"`
-	FileDate        int    `conf:"file_date"`
+	// ModelIdentifierMatch is the name_pattern group holding the model identifier, 2 when not set
+	ModelIdentifierMatch int `conf:"model_identifier_match"`
+	PerformanceYear      int `conf:"file_performance_year"`
+	FileDate             int `conf:"file_date"`
+	// CMSIDMatches are the name_pattern groups joined to form the CMS ID of the entity the file belongs to.
+	// CMSID is used instead for models that receive a single file covering every entity.
+	CMSIDMatches []int  `conf:"cms_id_matches"`
+	CMSID        string `conf:"cms_id"`
+	// Format names a registered record layout, defaulting to the file type. Layout overrides its settings.
+	Format string            `conf:"format"`
+	Layout AttributionLayout `conf:"layout"`
 }
 
 type RateLimitConfig struct {
@@ -114,6 +123,14 @@ func (cfg *Config) ComputeFields() (err error) {
 				return fmt.Errorf("failed to parse perf year: %w", err)
 			}
 		}
+		if cfg.ACOConfigs[idx].AttributionFile.FileType != "" {
+			if err = cfg.ACOConfigs[idx].AttributionFile.validate(); err != nil {
+				return fmt.Errorf("invalid ACO model %s attribution file: %w", cfg.ACOConfigs[idx].Model, err)
+			}
+		} else if _, err = cfg.ACOConfigs[idx].AttributionFile.cclfRecordLayout(); err != nil {
+			// Models that receive CCLF archives may only override the record layout
+			return fmt.Errorf("invalid ACO model %s attribution file: %w", cfg.ACOConfigs[idx].Model, err)
+		}
 	}
 
 	return nil
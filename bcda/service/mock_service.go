@@ -248,6 +248,96 @@ func (_c *MockService_GetACOConfigForID_Call) RunAndReturn(run func(cmsID string
 	return _c
 }
 
+// GetAttributionRoster provides a mock function for the type MockService
+func (_mock *MockService) GetAttributionRoster(ctx context.Context, cmsID string, cclfFileID uint, optOutDate time.Time, offset int, count int) (AttributionRoster, error) {
+	ret := _mock.Called(ctx, cmsID, cclfFileID, optOutDate, offset, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttributionRoster")
+	}
+
+	var r0 AttributionRoster
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uint, time.Time, int, int) (AttributionRoster, error)); ok {
+		return returnFunc(ctx, cmsID, cclfFileID, optOutDate, offset, count)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uint, time.Time, int, int) AttributionRoster); ok {
+		r0 = returnFunc(ctx, cmsID, cclfFileID, optOutDate, offset, count)
+	} else {
+		r0 = ret.Get(0).(AttributionRoster)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uint, time.Time, int, int) error); ok {
+		r1 = returnFunc(ctx, cmsID, cclfFileID, optOutDate, offset, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetAttributionRoster_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttributionRoster'
+type MockService_GetAttributionRoster_Call struct {
+	*mock.Call
+}
+
+// GetAttributionRoster is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmsID string
+//   - cclfFileID uint
+//   - optOutDate time.Time
+//   - offset int
+//   - count int
+func (_e *MockService_Expecter) GetAttributionRoster(ctx interface{}, cmsID interface{}, cclfFileID interface{}, optOutDate interface{}, offset interface{}, count interface{}) *MockService_GetAttributionRoster_Call {
+	return &MockService_GetAttributionRoster_Call{Call: _e.mock.On("GetAttributionRoster", ctx, cmsID, cclfFileID, optOutDate, offset, count)}
+}
+
+func (_c *MockService_GetAttributionRoster_Call) Run(run func(ctx context.Context, cmsID string, cclfFileID uint, optOutDate time.Time, offset int, count int)) *MockService_GetAttributionRoster_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uint
+		if args[2] != nil {
+			arg2 = args[2].(uint)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		var arg5 int
+		if args[5] != nil {
+			arg5 = args[5].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetAttributionRoster_Call) Return(roster AttributionRoster, err error) *MockService_GetAttributionRoster_Call {
+	_c.Call.Return(roster, err)
+	return _c
+}
+
+func (_c *MockService_GetAttributionRoster_Call) RunAndReturn(run func(context.Context, string, uint, time.Time, int, int) (AttributionRoster, error)) *MockService_GetAttributionRoster_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCutoffTime provides a mock function for the type MockService
 func (_mock *MockService) GetCutoffTime(ctx context.Context, reqType constants.DataRequestType, since time.Time, timeConstraints TimeConstraints, fileType models.CCLFFileType) (time.Time, string) {
 	ret := _mock.Called(ctx, reqType, since, timeConstraints, fileType)
@@ -854,6 +944,86 @@ func (_c *MockService_GetTimeConstraints_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// GetWithdrawnCCLFFiles provides a mock function for the type MockService
+func (_mock *MockService) GetWithdrawnCCLFFiles(ctx context.Context, cmsID string, after time.Time, fileType models.CCLFFileType) ([]*models.CCLFFile, error) {
+	ret := _mock.Called(ctx, cmsID, after, fileType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWithdrawnCCLFFiles")
+	}
+
+	var r0 []*models.CCLFFile
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time, models.CCLFFileType) ([]*models.CCLFFile, error)); ok {
+		return returnFunc(ctx, cmsID, after, fileType)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time, models.CCLFFileType) []*models.CCLFFile); ok {
+		r0 = returnFunc(ctx, cmsID, after, fileType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.CCLFFile)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Time, models.CCLFFileType) error); ok {
+		r1 = returnFunc(ctx, cmsID, after, fileType)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetWithdrawnCCLFFiles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWithdrawnCCLFFiles'
+type MockService_GetWithdrawnCCLFFiles_Call struct {
+	*mock.Call
+}
+
+// GetWithdrawnCCLFFiles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmsID string
+//   - after time.Time
+//   - fileType models.CCLFFileType
+func (_e *MockService_Expecter) GetWithdrawnCCLFFiles(ctx interface{}, cmsID interface{}, after interface{}, fileType interface{}) *MockService_GetWithdrawnCCLFFiles_Call {
+	return &MockService_GetWithdrawnCCLFFiles_Call{Call: _e.mock.On("GetWithdrawnCCLFFiles", ctx, cmsID, after, fileType)}
+}
+
+func (_c *MockService_GetWithdrawnCCLFFiles_Call) Run(run func(ctx context.Context, cmsID string, after time.Time, fileType models.CCLFFileType)) *MockService_GetWithdrawnCCLFFiles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 models.CCLFFileType
+		if args[3] != nil {
+			arg3 = args[3].(models.CCLFFileType)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetWithdrawnCCLFFiles_Call) Return(cCLFFiles []*models.CCLFFile, err error) *MockService_GetWithdrawnCCLFFiles_Call {
+	_c.Call.Return(cCLFFiles, err)
+	return _c
+}
+
+func (_c *MockService_GetWithdrawnCCLFFiles_Call) RunAndReturn(run func(context.Context, string, time.Time, models.CCLFFileType) ([]*models.CCLFFile, error)) *MockService_GetWithdrawnCCLFFiles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsV3NoPartialClaimsModel provides a mock function for the type MockService
 func (_mock *MockService) IsV3NoPartialClaimsModel(model string) bool {
 	ret := _mock.Called(model)
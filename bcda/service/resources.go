@@ -17,6 +17,9 @@ var fhirResourceTypeMap = map[string]ClaimType{
 	"ClaimResponse":        {PartiallyAdjudicated: true},
 }
 
+// samhsaResourceTypes are the resources that carry diagnoses and may therefore contain substance use disorder data
+var samhsaResourceTypes = []string{"ExplanationOfBenefit", "Claim"}
+
 // SupportsClaimType checks if the dataType is supported by the instanced DataType object
 func (r ClaimType) SupportsClaimType(claimType string) bool {
 	switch claimType {
@@ -17,6 +17,7 @@ import (
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	bcdaerrors "github.com/CMSgov/bcda-app/bcda/errors"
 	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/bcda/utils"
 	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
 	"github.com/CMSgov/bcda-app/conf"
@@ -38,6 +39,8 @@ type Service interface {
 	CancelJob(ctx context.Context, jobID uint) (uint, error)
 	GetJobPriority(acoID string, resourceType string, sinceParam bool) int16
 	GetLatestCCLFFile(ctx context.Context, cmsID string, lowerBound time.Time, upperBound time.Time, fileType models.CCLFFileType) (*models.CCLFFile, error)
+	GetWithdrawnCCLFFiles(ctx context.Context, cmsID string, after time.Time, fileType models.CCLFFileType) ([]*models.CCLFFile, error)
+	GetAttributionRoster(ctx context.Context, cmsID string, cclfFileID uint, optOutDate time.Time, offset, count int) (AttributionRoster, error)
 	GetACOConfigForID(cmsID string) (*ACOConfig, bool)
 	GetTimeConstraints(ctx context.Context, cmsID string) (TimeConstraints, error)
 	IsV3NoPartialClaimsModel(model string) bool
@@ -191,6 +194,12 @@ func (s *service) GetQueJobs(ctx context.Context, args worker_types.PrepareJobAr
 		return nil, 0, err
 	}
 
+	queJobs = append(queJobs, jobs...)
+
+	if err = s.setSAMHSAOptOuts(ctx, args, queJobs, append(newBeneficiaries, beneficiaries...)); err != nil {
+		return nil, 0, err
+	}
+
 	benesAttributed = len(newBeneficiaries) + len(beneficiaries)
 	args.Job.BenesAttributedToACO = benesAttributed
 	err = s.repository.UpdateJob(ctx, args.Job)
@@ -198,11 +207,68 @@ func (s *service) GetQueJobs(ctx context.Context, args worker_types.PrepareJobAr
 		return nil, 0, fmt.Errorf("failed to update job with benesAttributed: %w", err)
 	}
 
-	queJobs = append(queJobs, jobs...)
-
 	return queJobs, benesAttributed, nil
 }
 
+// setSAMHSAOptOuts records, on each job for a resource type that carries claims, which of its beneficiaries
+// have opted out of sharing substance use disorder data as of the job's opt out date.
+func (s *service) setSAMHSAOptOuts(ctx context.Context, args worker_types.PrepareJobArgs, jobs []*worker_types.JobEnqueueArgs, beneficiaries []*models.CCLFBeneficiary) error {
+	var claimsJobs []*worker_types.JobEnqueueArgs
+	for _, job := range jobs {
+		if slices.Contains(samhsaResourceTypes, job.ResourceType) {
+			claimsJobs = append(claimsJobs, job)
+		}
+	}
+	if len(claimsJobs) == 0 || len(beneficiaries) == 0 || s.sp.includeSuppressedBeneficiaries {
+		return nil
+	}
+
+	cfg, ok := s.GetACOConfigForID(args.CMSID)
+	if !ok {
+		return &bcdaerrors.InvalidACOConfigError{CMSID: args.CMSID}
+	}
+	if cfg.IgnoreSuppressions {
+		return nil
+	}
+
+	upperBound := args.OptOutDate
+	if upperBound.IsZero() {
+		upperBound = time.Now()
+	}
+	beneMBIs := make([]string, 0, len(beneficiaries))
+	for _, bene := range beneficiaries {
+		beneMBIs = append(beneMBIs, bene.MBI)
+	}
+	mbis, err := s.repository.GetSAMHSAOptOutMBIs(ctx, beneMBIs, s.sp.lookbackDays, upperBound)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve SAMHSA opt out MBIs %s", err.Error())
+	}
+	if len(mbis) == 0 {
+		return nil
+	}
+
+	optedOutMBIs := make(map[string]struct{}, len(mbis))
+	for _, mbi := range mbis {
+		optedOutMBIs[mbi] = struct{}{}
+	}
+	optedOutIDs := make(map[string]struct{})
+	for _, bene := range beneficiaries {
+		if _, ok := optedOutMBIs[bene.MBI]; ok {
+			optedOutIDs[fmt.Sprint(bene.ID)] = struct{}{}
+		}
+	}
+
+	for _, job := range claimsJobs {
+		for _, id := range job.BeneficiaryIDs {
+			if _, ok := optedOutIDs[id]; ok {
+				job.SAMHSAOptOutBeneficiaryIDs = append(job.SAMHSAOptOutBeneficiaryIDs, id)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (s *service) GetJobAndKeys(ctx context.Context, jobID uint) (*models.Job, []*models.JobKey, error) {
 	j, err := s.repository.GetJobByID(ctx, jobID)
 	if err != nil {
@@ -403,6 +469,7 @@ func (s *service) buildQueueJobArgs(ctx context.Context, args worker_types.Prepa
 		TransactionTime: getQueueJobTransactionTime(args, dataType),
 		BBBasePath:      args.BFDPath,
 		DataType:        dataType,
+		TraceContext:    tracing.Inject(ctx),
 	}
 
 	if !s.setClaimsDate(&enqueueArgs, args) {
@@ -546,7 +613,8 @@ func (s *service) getBenesByFileID(ctx context.Context, cclfFileID uint, args wo
 	return benes, nil
 }
 
-// setClaimsDate computes the claims window to apply on the args
+// setClaimsDate computes the claims window to apply on the args. The worker narrows it further for
+// beneficiaries whose attribution file included the period they were attributed to the entity.
 func (s *service) setClaimsDate(args *worker_types.JobEnqueueArgs, prepareArgs worker_types.PrepareJobArgs) bool {
 	// If the caller made a request for runout data
 	// it takes precedence over any other claims date
@@ -654,6 +722,72 @@ func (s *service) GetLatestCCLFFile(ctx context.Context, cmsID string, earlierTi
 	return cclfFile, nil
 }
 
+// GetWithdrawnCCLFFiles returns the CCLF8 files for the ACO that were superseded or invalidated after being
+// imported. Only files newer than after are returned, i.e. the ones that would otherwise be in use.
+func (s *service) GetWithdrawnCCLFFiles(ctx context.Context, cmsID string, after time.Time, fileType models.CCLFFileType) ([]*models.CCLFFile, error) {
+	return s.repository.GetWithdrawnCCLFFiles(ctx, cmsID, constants.CCLF8FileNum, after, fileType)
+}
+
+// AttributionRoster is a page of the beneficiaries attributed to an ACO by a CCLF file.
+type AttributionRoster struct {
+	// Number of beneficiaries attributed by the file, across all pages
+	Total         int
+	Beneficiaries []*models.CCLFBeneficiary
+	// MBIs of Beneficiaries that are excluded from exports by their data sharing preferences
+	SuppressedMBIs map[string]struct{}
+}
+
+// GetAttributionRoster returns a page of the beneficiaries attributed by the CCLF file. Unlike the beneficiaries
+// used for an export, suppressed beneficiaries are included and flagged rather than removed.
+func (s *service) GetAttributionRoster(ctx context.Context, cmsID string, cclfFileID uint, optOutDate time.Time, offset, count int) (AttributionRoster, error) {
+	var roster AttributionRoster
+
+	total, err := s.repository.GetCCLFBeneficiaryCount(ctx, cclfFileID)
+	if err != nil {
+		return roster, fmt.Errorf("failed to count beneficiaries %s", err.Error())
+	}
+	roster.Total = total
+
+	benes, err := s.repository.GetCCLFBeneficiaryPage(ctx, cclfFileID, offset, count)
+	if err != nil {
+		return roster, fmt.Errorf("failed to get beneficiaries %s", err.Error())
+	}
+	roster.Beneficiaries = benes
+
+	if len(benes) == 0 || s.sp.includeSuppressedBeneficiaries {
+		return roster, nil
+	}
+	cfg, ok := s.GetACOConfigForID(cmsID)
+	if !ok {
+		return roster, &bcdaerrors.InvalidACOConfigError{CMSID: cmsID}
+	}
+	if cfg.IgnoreSuppressions {
+		return roster, nil
+	}
+
+	upperBound := optOutDate
+	if upperBound.IsZero() {
+		upperBound = time.Now()
+	}
+	suppressed, err := s.repository.GetSuppressedMBIs(ctx, s.sp.lookbackDays, upperBound)
+	if err != nil {
+		return roster, fmt.Errorf("failed to retreive suppressedMBIs %s", err.Error())
+	}
+
+	onPage := make(map[string]struct{}, len(benes))
+	for _, bene := range benes {
+		onPage[bene.MBI] = struct{}{}
+	}
+	roster.SuppressedMBIs = make(map[string]struct{})
+	for _, mbi := range suppressed {
+		if _, ok := onPage[mbi]; ok {
+			roster.SuppressedMBIs[mbi] = struct{}{}
+		}
+	}
+
+	return roster, nil
+}
+
 // GetTimeConstraints searches for any time bounds that we should apply on the associated ACO
 func (s *service) GetTimeConstraints(ctx context.Context, cmsID string) (TimeConstraints, error) {
 	var constraint TimeConstraints
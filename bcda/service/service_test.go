@@ -758,6 +758,7 @@ func (s *ServiceTestSuite) TestGetQueJobs_Integration() {
 				repository.On("GetCCLFFileByID", testUtils.CtxMatcher, mock.Anything).Return(getCCLFFile(1, false, false), nil)
 			}
 			repository.On("GetSuppressedMBIs", testUtils.CtxMatcher, mock.Anything, mock.Anything).Return(nil, nil)
+			repository.On("GetSAMHSAOptOutMBIs", testUtils.CtxMatcher, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 			repository.On("GetCCLFBeneficiaries", testUtils.CtxMatcher, mock.Anything, mock.Anything).Return(tt.expBenes, nil)
 			// use benes1 as the "old" benes. Allows us to verify the since parameter is populated as expected
 			repository.On("GetCCLFBeneficiaryMBIs", testUtils.CtxMatcher, mock.Anything).Return(benes1MBI, nil)
@@ -995,6 +996,7 @@ func (s *ServiceTestSuite) TestGetQueJobsByDataType_Integration() {
 				Return(&models.ACO{UUID: args.ACOID, TerminationDetails: tt.terminationDetails}, nil)
 			repository.On("GetCCLFFileByID", testUtils.CtxMatcher, mock.Anything).Return(getCCLFFile(1, false, false), nil)
 			repository.On("GetSuppressedMBIs", testUtils.CtxMatcher, mock.Anything, mock.Anything).Return(nil, nil)
+			repository.On("GetSAMHSAOptOutMBIs", testUtils.CtxMatcher, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 			repository.On("GetCCLFBeneficiaries", testUtils.CtxMatcher, mock.Anything, mock.Anything).Return(tt.expBenes, nil)
 			// use benes1 as the "old" benes. Allows us to verify the since parameter is populated as expected
 			repository.On("GetCCLFBeneficiaryMBIs", testUtils.CtxMatcher, mock.Anything).Return(benes1MBI, nil)
@@ -1780,6 +1782,99 @@ func TestCreateQueueJobs(t *testing.T) {
 	})
 }
 
+func TestSetSAMHSAOptOuts(t *testing.T) {
+	optOutDate := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	benes := []*models.CCLFBeneficiary{getCCLFBeneficiary(1, "MBI1"), getCCLFBeneficiary(2, "MBI2"), getCCLFBeneficiary(3, "MBI3")}
+
+	t.Run("flags opted out beneficiaries on claims jobs only", func(t *testing.T) {
+		cfg := newQueueJobTestConfig(t, []ACOConfig{
+			newQueueJobTestACO("SSP", `^A\d{4}`, []string{constants.Adjudicated}),
+		}, nil)
+		svc := newQueueJobTestService(t, cfg)
+		svc.repository.(*models.MockRepository).On("GetSAMHSAOptOutMBIs", mock.Anything, []string{"MBI1", "MBI2", "MBI3"}, 30, optOutDate).Return([]string{"MBI2"}, nil)
+
+		args := newQueueJobTestArgs("A1234", nil)
+		args.OptOutDate = optOutDate
+		eob := &worker_types.JobEnqueueArgs{ResourceType: "ExplanationOfBenefit", BeneficiaryIDs: []string{"1", "2"}}
+		otherEOB := &worker_types.JobEnqueueArgs{ResourceType: "ExplanationOfBenefit", BeneficiaryIDs: []string{"3"}}
+		patient := &worker_types.JobEnqueueArgs{ResourceType: "Patient", BeneficiaryIDs: []string{"1", "2"}}
+
+		assert.NoError(t, svc.setSAMHSAOptOuts(context.Background(), args, []*worker_types.JobEnqueueArgs{eob, otherEOB, patient}, benes))
+		assert.Equal(t, []string{"2"}, eob.SAMHSAOptOutBeneficiaryIDs)
+		assert.Empty(t, otherEOB.SAMHSAOptOutBeneficiaryIDs)
+		assert.Empty(t, patient.SAMHSAOptOutBeneficiaryIDs)
+	})
+
+	t.Run("skips lookup when no claims jobs are queued", func(t *testing.T) {
+		svc := newQueueJobTestService(t, newQueueJobTestConfig(t, nil, nil))
+		patient := &worker_types.JobEnqueueArgs{ResourceType: "Patient", BeneficiaryIDs: []string{"1"}}
+
+		assert.NoError(t, svc.setSAMHSAOptOuts(context.Background(), newQueueJobTestArgs("A1234", nil), []*worker_types.JobEnqueueArgs{patient}, benes))
+	})
+
+	t.Run("skips lookup when ACO ignores suppressions", func(t *testing.T) {
+		aco := newQueueJobTestACO("SSP", `^A\d{4}`, []string{constants.Adjudicated})
+		aco.IgnoreSuppressions = true
+		svc := newQueueJobTestService(t, newQueueJobTestConfig(t, []ACOConfig{aco}, nil))
+		eob := &worker_types.JobEnqueueArgs{ResourceType: "ExplanationOfBenefit", BeneficiaryIDs: []string{"1"}}
+
+		assert.NoError(t, svc.setSAMHSAOptOuts(context.Background(), newQueueJobTestArgs("A1234", nil), []*worker_types.JobEnqueueArgs{eob}, benes))
+		assert.Empty(t, eob.SAMHSAOptOutBeneficiaryIDs)
+	})
+
+	t.Run("returns lookup errors", func(t *testing.T) {
+		svc := newQueueJobTestService(t, newQueueJobTestConfig(t, []ACOConfig{
+			newQueueJobTestACO("SSP", `^A\d{4}`, []string{constants.Adjudicated}),
+		}, nil))
+		svc.repository.(*models.MockRepository).On("GetSAMHSAOptOutMBIs", mock.Anything, mock.Anything, 30, mock.Anything).Return(nil, errors.New("forced failure"))
+		claim := &worker_types.JobEnqueueArgs{ResourceType: "Claim", BeneficiaryIDs: []string{"1"}}
+
+		err := svc.setSAMHSAOptOuts(context.Background(), newQueueJobTestArgs("A1234", nil), []*worker_types.JobEnqueueArgs{claim}, benes)
+		assert.ErrorContains(t, err, "failed to retrieve SAMHSA opt out MBIs")
+	})
+}
+
+func TestGetAttributionRoster(t *testing.T) {
+	optOutDate := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	benes := []*models.CCLFBeneficiary{getCCLFBeneficiary(1, "MBI1"), getCCLFBeneficiary(2, "MBI2")}
+
+	t.Run("flags suppressed beneficiaries on the page", func(t *testing.T) {
+		svc := newQueueJobTestService(t, newQueueJobTestConfig(t, []ACOConfig{
+			newQueueJobTestACO("SSP", `^A\d{4}`, []string{constants.Adjudicated}),
+		}, nil))
+		repository := svc.repository.(*models.MockRepository)
+		repository.On("GetCCLFBeneficiaryCount", mock.Anything, uint(9)).Return(5, nil)
+		repository.On("GetCCLFBeneficiaryPage", mock.Anything, uint(9), 2, 2).Return(benes, nil)
+		repository.On("GetSuppressedMBIs", mock.Anything, 30, optOutDate).Return([]string{"MBI2", "MBI7"}, nil)
+
+		roster, err := svc.GetAttributionRoster(context.Background(), "A1234", 9, optOutDate, 2, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, AttributionRoster{Total: 5, Beneficiaries: benes, SuppressedMBIs: map[string]struct{}{"MBI2": {}}}, roster)
+	})
+
+	t.Run("skips suppression when ACO ignores it", func(t *testing.T) {
+		aco := newQueueJobTestACO("SSP", `^A\d{4}`, []string{constants.Adjudicated})
+		aco.IgnoreSuppressions = true
+		svc := newQueueJobTestService(t, newQueueJobTestConfig(t, []ACOConfig{aco}, nil))
+		repository := svc.repository.(*models.MockRepository)
+		repository.On("GetCCLFBeneficiaryCount", mock.Anything, uint(9)).Return(2, nil)
+		repository.On("GetCCLFBeneficiaryPage", mock.Anything, uint(9), 0, 10).Return(benes, nil)
+
+		roster, err := svc.GetAttributionRoster(context.Background(), "A1234", 9, time.Time{}, 0, 10)
+		assert.NoError(t, err)
+		assert.Nil(t, roster.SuppressedMBIs)
+		repository.AssertNotCalled(t, "GetSuppressedMBIs", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns repository errors", func(t *testing.T) {
+		svc := newQueueJobTestService(t, newQueueJobTestConfig(t, nil, nil))
+		svc.repository.(*models.MockRepository).On("GetCCLFBeneficiaryCount", mock.Anything, uint(9)).Return(0, errors.New("forced failure"))
+
+		_, err := svc.GetAttributionRoster(context.Background(), "A1234", 9, time.Time{}, 0, 10)
+		assert.ErrorContains(t, err, "failed to count beneficiaries")
+	})
+}
+
 func TestFormatSinceArg(t *testing.T) {
 	assert.Equal(t, "", formatSinceArg(time.Time{}))
 
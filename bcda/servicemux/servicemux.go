@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -127,11 +130,43 @@ func (sm *ServiceMux) serveHTTPS(tlsCertPath, tlsKeyPath string) {
 		MinVersion: tls.VersionTLS12,
 	}
 
+	if clientCAPath := conf.GetEnv("BCDA_MTLS_CLIENT_CA"); clientCAPath != "" {
+		clientCAs, err := loadClientCAs(clientCAPath)
+		if err != nil {
+			log.API.Panic(err)
+		}
+		sm.TLSConfig.ClientCAs = clientCAs
+		sm.TLSConfig.ClientAuth = clientAuthType()
+	}
+
 	sm.Listener = tls.NewListener(sm.Listener, &sm.TLSConfig)
 
 	sm.serveHTTP()
 }
 
+// loadClientCAs reads the PEM encoded CA bundle that client certificates are verified against.
+func loadClientCAs(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in client CA bundle %s", path)
+	}
+	return pool, nil
+}
+
+// clientAuthType verifies client certificates when they are presented. Bearer tokens remain an alternative
+// unless BCDA_MTLS_REQUIRED is true, in which case every connection must present a valid certificate.
+func clientAuthType() tls.ClientAuthType {
+	if conf.GetEnv("BCDA_MTLS_REQUIRED") == "true" {
+		return tls.RequireAndVerifyClientCert
+	}
+	return tls.VerifyClientCertIfGiven
+}
+
 func (sm *ServiceMux) serveHTTP() {
 	m := cmux.New(sm.Listener)
 
@@ -135,6 +135,29 @@ func (s *ServiceMuxTestSuite) TestServeHTTPS() {
 	assert.Equal(s.T(), "Test", string(body))
 }
 
+func (s *ServiceMuxTestSuite) TestLoadClientCAs() {
+	pool, err := loadClientCAs("../../shared_files/localhost.crt")
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), pool)
+
+	_, err = loadClientCAs("../../shared_files/localhost.key")
+	assert.ErrorContains(s.T(), err, "no certificates found")
+
+	_, err = loadClientCAs("foo.crt")
+	assert.Error(s.T(), err)
+}
+
+func (s *ServiceMuxTestSuite) TestClientAuthType() {
+	origRequired := conf.GetEnv("BCDA_MTLS_REQUIRED")
+	defer conf.SetEnv(s.T(), "BCDA_MTLS_REQUIRED", origRequired)
+
+	conf.SetEnv(s.T(), "BCDA_MTLS_REQUIRED", "")
+	assert.Equal(s.T(), tls.VerifyClientCertIfGiven, clientAuthType())
+
+	conf.SetEnv(s.T(), "BCDA_MTLS_REQUIRED", "true")
+	assert.Equal(s.T(), tls.RequireAndVerifyClientCert, clientAuthType())
+}
+
 func (s *ServiceMuxTestSuite) TestServeHTTPSBadKeypair() {
 	srv := &http.Server{
 		Handler:           testHandler,
@@ -0,0 +1,156 @@
+// Package tracing exports OpenTelemetry spans for API requests, the export jobs they create, and the calls
+// made to BFD and SSAS along the way, so a single export can be followed from the API to the worker.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	gcmw "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/CMSgov/bcda-app/conf"
+	"github.com/CMSgov/bcda-app/middleware"
+)
+
+const tracerName = "github.com/CMSgov/bcda-app"
+
+func init() {
+	// Propagate trace context even when this process doesn't export spans, so a request's trace still reaches
+	// the worker, BFD and SSAS. Baggage is not propagated since it would pass client-supplied values downstream.
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// Init exports spans for serviceName to the OTLP/HTTP endpoint in OTEL_EXPORTER_OTLP_ENDPOINT. The other
+// OTEL_EXPORTER_OTLP_* and OTEL_TRACES_SAMPLER* variables are honored as well. When no endpoint is set, spans
+// are not recorded. The returned func flushes any buffered spans and should be called before the process exits.
+func Init(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	endpoint := strings.TrimSuffix(conf.GetEnv("OTEL_EXPORTER_OTLP_ENDPOINT"), "/")
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		attribute.String("deployment.environment.name", conf.GetEnv("DEPLOYMENT_TARGET")),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if there is one, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject returns the trace context of ctx in a form that can be saved in job args.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx with the trace context saved by Inject, so spans started from it continue that trace.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// Middleware starts a server span for each request. Requests come from outside the system, so each starts a new
+// trace that is only linked to any traceparent the client sent, and the client's sampling decision is ignored.
+// Spans are named by their chi route pattern, and carry the request's transaction ID when it runs after
+// NewTransactionID.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts := []trace.SpanStartOption{trace.WithNewRoot(), trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPRequestMethodKey.String(r.Method), semconv.URLPath(r.URL.Path))}
+		remote := trace.SpanContextFromContext(otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(r.Header)))
+		if remote.IsValid() {
+			opts = append(opts, trace.WithLinks(trace.Link{SpanContext: remote}))
+		}
+		ctx, span := otel.Tracer(tracerName).Start(r.Context(), r.Method, opts...)
+		defer span.End()
+		if tid, ok := r.Context().Value(middleware.CtxTransactionKey).(string); ok {
+			span.SetAttributes(attribute.String("transaction_id", tid))
+		}
+
+		ww := gcmw.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// Transport wraps rt to start a client span for each request and send its traceparent header. Only the
+// host and path are recorded, since query strings sent to BFD can identify beneficiaries.
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return transport{rt}
+}
+
+type transport struct {
+	rt http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), req.Method+" "+req.URL.Host, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(req.Method), semconv.ServerAddress(req.URL.Hostname()), semconv.URLPath(req.URL.Path)))
+	defer span.End()
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,171 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/CMSgov/bcda-app/middleware"
+)
+
+// recordSpans sends spans to an in-memory exporter for the rest of the test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func attrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestInjectExtract(t *testing.T) {
+	assert.Nil(t, Inject(context.Background()), "no trace context without a span")
+
+	recordSpans(t)
+	ctx, span := Start(context.Background(), "PrepareJob")
+	defer span.End()
+
+	carrier := Inject(ctx)
+	assert.Contains(t, carrier, "traceparent")
+
+	extracted := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+	assert.True(t, extracted.IsRemote())
+}
+
+func TestEnd(t *testing.T) {
+	exporter := recordSpans(t)
+
+	_, span := Start(context.Background(), "ok")
+	End(span, nil)
+	_, span = Start(context.Background(), "failed")
+	End(span, assert.AnError)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, assert.AnError.Error(), spans[1].Status.Description)
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := recordSpans(t)
+
+	parentCtx, parent := Start(context.Background(), "client")
+	parent.End()
+
+	var handlerSpan trace.SpanContext
+	r := chi.NewRouter()
+	r.Use(middleware.NewTransactionID, Middleware)
+	r.Get("/api/v2/jobs/{jobID}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/jobs/1234", nil)
+	for k, v := range Inject(parentCtx) {
+		req.Header.Set(k, v)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	span := spans[1]
+	assert.Equal(t, "GET /api/v2/jobs/{jobID}", span.Name)
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+	assert.NotEqual(t, parent.SpanContext().TraceID(), span.SpanContext.TraceID(), "starts a new trace")
+	assert.False(t, span.Parent.IsValid())
+	require.Len(t, span.Links, 1)
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Links[0].SpanContext.SpanID(), "links to the caller's span")
+	assert.Equal(t, span.SpanContext.SpanID(), handlerSpan.SpanID(), "handler runs in the server span")
+	assert.Equal(t, codes.Error, span.Status.Code)
+
+	a := attrs(span)
+	assert.Equal(t, "/api/v2/jobs/{jobID}", a["http.route"].AsString())
+	assert.Equal(t, int64(http.StatusInternalServerError), a["http.response.status_code"].AsInt64())
+	assert.NotEmpty(t, a["transaction_id"].AsString())
+}
+
+func TestMiddlewareIgnoresClientTraceState(t *testing.T) {
+	exporter := recordSpans(t)
+
+	var handlerCtx context.Context
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCtx = r.Context()
+	}))
+
+	// A client can't stop the request from being sampled or pass baggage on to BFD and SSAS
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/Patient/$export", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	req.Header.Set("baggage", "user=alice")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.True(t, spans[0].SpanContext.IsSampled())
+	assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	require.Len(t, spans[0].Links, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].Links[0].SpanContext.TraceID().String())
+
+	carrier := Inject(handlerCtx)
+	assert.Contains(t, carrier, "traceparent")
+	assert.NotContains(t, carrier, "baggage")
+}
+
+func TestTransport(t *testing.T) {
+	exporter := recordSpans(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	ctx, parent := Start(context.Background(), "ProcessJob")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v2/fhir/Patient?identifier=mbi", nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: Transport(http.DefaultTransport)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	assert.Empty(t, req.Header.Get("traceparent"), "caller's request is not modified")
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	assert.Contains(t, traceparent, span.SpanContext.TraceID().String())
+	assert.Contains(t, traceparent, span.SpanContext.SpanID().String())
+
+	a := attrs(span)
+	assert.Equal(t, "/v2/fhir/Patient", a["url.path"].AsString())
+	assert.Equal(t, int64(http.StatusOK), a["http.response.status_code"].AsInt64())
+	for _, kv := range span.Attributes {
+		assert.NotContains(t, kv.Value.Emit(), "mbi", "query string is not recorded")
+	}
+}
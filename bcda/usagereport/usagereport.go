@@ -0,0 +1,170 @@
+// Package usagereport summarizes each ACO's use of the API over a period: the exports they ran, the resource
+// types they pulled, the files they downloaded, and how often their exports failed.
+package usagereport
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+
+	dateLayout = "2006-01-02"
+)
+
+// Report is the usage of every ACO that created a job or downloaded a file from Start up to, but not
+// including, End.
+type Report struct {
+	Start time.Time  `json:"start"`
+	End   time.Time  `json:"end"`
+	ACOs  []ACOUsage `json:"acos"`
+}
+
+// ACOUsage is one ACO's line in the report.
+type ACOUsage struct {
+	CMSID         string `json:"cms_id"`
+	Model         string `json:"model"`
+	Jobs          int    `json:"jobs"`
+	CompletedJobs int    `json:"completed_jobs"`
+	FailedJobs    int    `json:"failed_jobs"`
+	// FailureRate is the share of finished jobs that failed.
+	FailureRate float64 `json:"failure_rate"`
+	// ResourceTypes counts the jobs that returned data for each resource type.
+	ResourceTypes   map[string]int `json:"resource_types"`
+	Downloads       int            `json:"downloads"`
+	BytesDownloaded int64          `json:"bytes_downloaded"`
+}
+
+// ParsePeriod parses the first and last days (YYYY-MM-DD, UTC) of a report and returns the period they
+// cover. When neither is given, the period is the calendar month before now.
+func ParsePeriod(startDate, endDate string, now time.Time) (start, end time.Time, err error) {
+	if startDate == "" && endDate == "" {
+		now = now.UTC()
+		end = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return end.AddDate(0, -1, 0), end, nil
+	}
+	if startDate == "" || endDate == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("both start and end dates are required when either is given")
+	}
+
+	if start, err = time.Parse(dateLayout, startDate); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q, expected YYYY-MM-DD", startDate)
+	}
+	if end, err = time.Parse(dateLayout, endDate); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q, expected YYYY-MM-DD", endDate)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", endDate, startDate)
+	}
+	// The end date is inclusive
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// ValidFormat reports whether the report can be written in format.
+func ValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatJSON
+}
+
+// Generate builds the report for the period from start up to end. Each ACO's model comes from the ACO
+// config that svc has for its CMS ID.
+func Generate(ctx context.Context, r models.Repository, svc service.Service, start, end time.Time) (*Report, error) {
+	usage, err := r.GetACOUsage(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ACO usage: %w", err)
+	}
+
+	report := &Report{Start: start, End: end, ACOs: make([]ACOUsage, 0, len(usage))}
+	for _, u := range usage {
+		line := ACOUsage{
+			CMSID:           u.CMSID,
+			CompletedJobs:   u.Jobs[models.JobStatusCompleted],
+			FailedJobs:      u.Jobs[models.JobStatusFailed],
+			ResourceTypes:   u.ResourceTypes,
+			Downloads:       u.Downloads,
+			BytesDownloaded: u.BytesDownloaded,
+		}
+		if cfg, ok := svc.GetACOConfigForID(u.CMSID); ok {
+			line.Model = cfg.Model
+		}
+		for _, count := range u.Jobs {
+			line.Jobs += count
+		}
+		if finished := line.CompletedJobs + line.FailedJobs; finished > 0 {
+			line.FailureRate = float64(line.FailedJobs) / float64(finished)
+		}
+		report.ACOs = append(report.ACOs, line)
+	}
+
+	return report, nil
+}
+
+// Write writes the report to w as CSV, one row per ACO, or as JSON.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatCSV:
+		return r.writeCSV(w)
+	default:
+		return fmt.Errorf("unsupported report format %q, must be one of %s, %s", format, FormatCSV, FormatJSON)
+	}
+}
+
+func (r *Report) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"cms_id", "model", "jobs", "completed_jobs", "failed_jobs", "failure_rate",
+		"resource_types", "downloads", "bytes_downloaded"})
+	if err != nil {
+		return err
+	}
+
+	for _, u := range r.ACOs {
+		err := cw.Write([]string{
+			u.CMSID,
+			u.Model,
+			strconv.Itoa(u.Jobs),
+			strconv.Itoa(u.CompletedJobs),
+			strconv.Itoa(u.FailedJobs),
+			strconv.FormatFloat(u.FailureRate, 'f', 4, 64),
+			formatResourceTypes(u.ResourceTypes),
+			strconv.Itoa(u.Downloads),
+			strconv.FormatInt(u.BytesDownloaded, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatResourceTypes writes the job count for each resource type in a single CSV field, e.g.
+// "Coverage:3;Patient:5".
+func formatResourceTypes(counts map[string]int) string {
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+
+	fields := make([]string, len(types))
+	for i, t := range types {
+		fields[i] = fmt.Sprintf("%s:%d", t, counts[t])
+	}
+	return strings.Join(fields, ";")
+}
@@ -0,0 +1,114 @@
+package usagereport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/models"
+	"github.com/CMSgov/bcda-app/bcda/service"
+)
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name               string
+		startDate, endDate string
+		start, end         time.Time
+		errMsg             string
+	}{
+		{"previous month by default", "", "", time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"end date is inclusive", "2026-01-01", "2026-01-31", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"single day", "2026-01-05", "2026-01-05", time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC), time.Date(2026, time.January, 6, 0, 0, 0, 0, time.UTC), ""},
+		{"missing end", "2026-01-01", "", time.Time{}, time.Time{}, "both start and end dates are required"},
+		{"invalid start", "01/01/2026", "2026-01-31", time.Time{}, time.Time{}, "invalid start date"},
+		{"invalid end", "2026-01-01", "2026-02-30", time.Time{}, time.Time{}, "invalid end date"},
+		{"end before start", "2026-02-01", "2026-01-31", time.Time{}, time.Time{}, "is before start date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParsePeriod(tt.startDate, tt.endDate, now)
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	repository := models.NewMockRepository(t)
+	repository.On("GetACOUsage", ctx, start, end).Return([]*models.ACOUsage{
+		{
+			CMSID:           "A9994",
+			Jobs:            map[models.JobStatus]int{models.JobStatusCompleted: 3, models.JobStatusFailed: 1, models.JobStatusPending: 1},
+			ResourceTypes:   map[string]int{"Patient": 3, "Coverage": 2},
+			Downloads:       5,
+			BytesDownloaded: 4096,
+		},
+		{CMSID: "Z9999", Jobs: map[models.JobStatus]int{models.JobStatusPending: 1}},
+	}, nil)
+	svc := service.NewMockService(t)
+	svc.On("GetACOConfigForID", "A9994").Return(&service.ACOConfig{Model: "SSP"}, true)
+	svc.On("GetACOConfigForID", "Z9999").Return(nil, false)
+
+	report, err := Generate(ctx, repository, svc, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, start, report.Start)
+	assert.Equal(t, end, report.End)
+	assert.Equal(t, []ACOUsage{
+		{CMSID: "A9994", Model: "SSP", Jobs: 5, CompletedJobs: 3, FailedJobs: 1, FailureRate: 0.25,
+			ResourceTypes: map[string]int{"Patient": 3, "Coverage": 2}, Downloads: 5, BytesDownloaded: 4096},
+		{CMSID: "Z9999", Jobs: 1},
+	}, report.ACOs)
+}
+
+func TestGenerateError(t *testing.T) {
+	repository := models.NewMockRepository(t)
+	repository.On("GetACOUsage", context.Background(), time.Time{}, time.Time{}).Return(nil, errors.New("connection refused"))
+
+	_, err := Generate(context.Background(), repository, service.NewMockService(t), time.Time{}, time.Time{})
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+func TestWrite(t *testing.T) {
+	report := &Report{
+		Start: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		ACOs: []ACOUsage{
+			{CMSID: "A9994", Model: "SSP", Jobs: 5, CompletedJobs: 3, FailedJobs: 1, FailureRate: 0.25,
+				ResourceTypes: map[string]int{"Patient": 3, "Coverage": 2}, Downloads: 5, BytesDownloaded: 4096},
+			{CMSID: "Z9999", Jobs: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, FormatCSV))
+	assert.Equal(t, "cms_id,model,jobs,completed_jobs,failed_jobs,failure_rate,resource_types,downloads,bytes_downloaded\n"+
+		"A9994,SSP,5,3,1,0.2500,Coverage:2;Patient:3,5,4096\n"+
+		"Z9999,,1,0,0,0.0000,,0,0\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, report.Write(&buf, FormatJSON))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, *report, decoded)
+
+	assert.ErrorContains(t, report.Write(&buf, "xml"), "unsupported report format")
+	assert.True(t, ValidFormat(FormatCSV))
+	assert.False(t, ValidFormat("xml"))
+}
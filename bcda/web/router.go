@@ -12,8 +12,10 @@ import (
 	"github.com/CMSgov/bcda-app/bcda/auth"
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	"github.com/CMSgov/bcda-app/bcda/logging"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
 	"github.com/CMSgov/bcda-app/bcda/service"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/bcda/utils"
 	"github.com/CMSgov/bcda-app/bcda/web/middleware"
 	"github.com/CMSgov/bcda-app/conf"
@@ -31,7 +33,7 @@ var commonAuth = []func(http.Handler) http.Handler{
 func NewAPIRouter(db *sql.DB, pool *pgxv5Pool.Pool, provider auth.Provider) http.Handler {
 	r := chi.NewRouter()
 	am := auth.NewAuthMiddleware(provider)
-	r.Use(gcmw.RequestID, appMiddleware.NewTransactionID, am.ParseToken, logging.NewStructuredLogger(), middleware.SecurityHeader, middleware.ConnectionClose, logging.NewCtxLogger)
+	r.Use(metrics.Middleware, gcmw.RequestID, appMiddleware.NewTransactionID, tracing.Middleware, am.ParseToken, logging.NewStructuredLogger(), middleware.SecurityHeader, middleware.ConnectionClose, logging.NewCtxLogger)
 
 	// Serve up the swagger ui folder
 	FileServer(r, "/api/v1/swagger", http.Dir("./swaggerui/v1"))
@@ -93,6 +95,9 @@ func NewAPIRouter(db *sql.DB, pool *pgxv5Pool.Pool, provider auth.Provider) http
 			r.With(append(commonAuth, v3NonExportRequestValidators...)...).Get("/jobs", apiV3.JobsStatus)
 			r.With(append(commonAuth, am.RequireTokenJobMatch(db))...).Delete(constants.JOBIDPath, apiV3.DeleteJob)
 			r.With(commonAuth...).Get("/attribution_status", apiV3.AttributionStatus)
+			r.With(append(commonAuth, v3NonExportRequestValidators...)...).Get("/AuditEvent", apiV3.AuditEvent)
+			r.With(append(commonAuth, v3NonExportRequestValidators...)...).Get("/Group", apiV3.GroupSearch)
+			r.With(append(commonAuth, v3NonExportRequestValidators...)...).Get("/Group/{groupId}", apiV3.Group)
 			r.Get("/metadata", apiV3.Metadata)
 		})
 	}
@@ -104,7 +109,7 @@ func NewAPIRouter(db *sql.DB, pool *pgxv5Pool.Pool, provider auth.Provider) http
 }
 
 func NewAuthRouter(provider auth.Provider) http.Handler {
-	return auth.NewAuthRouter(provider, gcmw.RequestID, appMiddleware.NewTransactionID, logging.NewStructuredLogger(), middleware.SecurityHeader, middleware.ConnectionClose, logging.NewCtxLogger)
+	return auth.NewAuthRouter(provider, metrics.Middleware, gcmw.RequestID, appMiddleware.NewTransactionID, tracing.Middleware, logging.NewStructuredLogger(), middleware.SecurityHeader, middleware.ConnectionClose, logging.NewCtxLogger)
 }
 
 func NewDataRouter(db *sql.DB, provider auth.Provider) http.Handler {
@@ -113,12 +118,12 @@ func NewDataRouter(db *sql.DB, provider auth.Provider) http.Handler {
 	resourceTypeLogger := &logging.ResourceTypeLogger{
 		Repository: postgres.NewRepository(db),
 	}
-	r.Use(am.ParseToken, gcmw.RequestID, appMiddleware.NewTransactionID, logging.NewStructuredLogger(), middleware.SecurityHeader, middleware.ConnectionClose, logging.NewCtxLogger)
-	r.With(append(
-		commonAuth,
-		am.RequireTokenJobMatch(db),
+	r.Use(metrics.Middleware, tracing.Middleware, am.ParseToken, gcmw.RequestID, appMiddleware.NewTransactionID, logging.NewStructuredLogger(), middleware.SecurityHeader, middleware.ConnectionClose, logging.NewCtxLogger)
+	tokenAuth := chi.Chain(append(commonAuth, am.RequireTokenJobMatch(db))...).Handler
+	r.With(
+		auth.RequireSignedURLOrToken(db, tokenAuth),
 		resourceTypeLogger.LogJobResourceType,
-	)...).Get("/data/{jobID}/{fileName}", v1.ServeData)
+	).Get("/data/{jobID}/{fileName}", v1.ServeData)
 	return r
 }
 
@@ -42,7 +42,9 @@ func (s *RouterTestSuite) SetupTest() {
 	conf.SetEnv(s.T(), "DEBUG", "true")
 	conf.SetEnv(s.T(), "BB_SERVER_LOCATION", "v1-server-location")
 	s.db = database.Connect()
-	s.provider = auth.NewProvider(s.db)
+	var err error
+	s.provider, err = auth.NewProvider(s.db)
+	s.Require().NoError(err)
 	s.apiRouter = NewAPIRouter(s.db, s.pool, s.provider)
 	s.dataRouter = NewDataRouter(s.db, s.provider)
 }
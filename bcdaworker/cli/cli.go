@@ -3,15 +3,26 @@ package cli
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	bcdaaws "github.com/CMSgov/bcda-app/bcda/aws"
 	"github.com/CMSgov/bcda-app/bcda/client"
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	"github.com/CMSgov/bcda-app/bcda/database"
 	"github.com/CMSgov/bcda-app/bcda/health"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
+	"github.com/CMSgov/bcda-app/bcda/models/postgres"
+	"github.com/CMSgov/bcda-app/bcda/service"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/bcda/utils"
 	"github.com/CMSgov/bcda-app/bcdaworker/queueing"
 	"github.com/CMSgov/bcda-app/conf"
@@ -91,18 +102,133 @@ func startWorker() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	shutdownTracing, err := tracing.Init(ctx, "worker")
+	if err != nil {
+		log.Worker.Warn(err)
+	} else {
+		// Use a fresh context, since ctx is done by the time spans are flushed
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				log.Worker.Warn(err)
+			}
+		}()
+	}
+
 	riverClient := queueing.CreateRiverClient(logger, db, utils.GetEnvInt("WORKER_POOL_SIZE", 4))
 	if err := riverClient.Start(ctx); err != nil {
 		logger.Error("failed to start river client", "error", err)
 		panic(err)
 	}
 
+	go reportBlueButtonStats(ctx)
+
+	if port := conf.GetEnv("WORKER_HEALTH_PORT"); port != "" {
+		go serveHealth(ctx, ":"+port)
+	}
+
 	<-ctx.Done()
 	stop()
 	logger.Info("Received exit signal; initiating soft stop (waiting for cancelled jobs to finish)")
 	<-riverClient.Stopped()
 }
 
+// serveHealth serves the worker's health check and Prometheus metrics until ctx is done.
+func serveHealth(ctx context.Context, addr string) {
+	metrics.SetACOModelLookup(acoModelLookup(ctx))
+	if err := metrics.Register(metrics.NewDBCollector(db)); err != nil {
+		log.Worker.Errorf("Failed to register database metrics: %s", err)
+	}
+
+	healthChecker := health.NewHealthChecker(db)
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+	mux.HandleFunc("GET /_health", func(w http.ResponseWriter, r *http.Request) {
+		status, ok := healthChecker.IsWorkerDatabaseOK()
+		w.Header().Set(constants.ContentType, constants.JsonContentType)
+		if !ok {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+		if err := json.NewEncoder(w).Encode(map[string]string{"database": status}); err != nil {
+			log.Worker.Error(err)
+		}
+	})
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadTimeout:       5 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		if err := srv.Close(); err != nil {
+			log.Worker.Error(err)
+		}
+	}()
+
+	log.Worker.Infof("Serving health check and metrics on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Worker.Errorf("Health server stopped: %s", err)
+	}
+}
+
+// acoModelLookup finds the model of an ACO from its UUID so finished jobs can be counted by model.
+func acoModelLookup(ctx context.Context) func(acoID string) string {
+	cfg, err := service.LoadConfig()
+	if err != nil {
+		log.Worker.Errorf("Failed to load service config for metrics: %s", err)
+		return nil
+	}
+	repository := postgres.NewRepository(db)
+	svc := service.NewService(repository, cfg, "")
+
+	return func(acoID string) string {
+		aco, err := repository.GetACOByUUID(ctx, uuid.Parse(acoID))
+		if err != nil || aco.CMSID == nil {
+			return ""
+		}
+		if acoConfig, ok := svc.GetACOConfigForID(*aco.CMSID); ok {
+			return acoConfig.Model
+		}
+		return ""
+	}
+}
+
+// reportBlueButtonStats periodically saves this worker's BFD request stats for the API health check and
+// publishes them as CloudWatch metrics in deployed environments.
+func reportBlueButtonStats(ctx context.Context) {
+	instance, err := os.Hostname()
+	if err != nil {
+		log.Worker.Warnf("Failed to get hostname for BFD stats: %s", err)
+		instance = uuid.NewRandom().String()
+	}
+
+	var cw bcdaaws.CustomCloudwatchClient
+	environment := conf.GetEnv("DEPLOYMENT_TARGET")
+	if environment != "" {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(constants.DefaultRegion))
+		if err != nil {
+			log.Worker.Errorf("error configuring cloudwatch client: %+v", err)
+		} else {
+			cw = cloudwatch.NewFromConfig(cfg)
+		}
+	}
+
+	ticker := time.NewTicker(time.Duration(max(utils.GetEnvInt("BFD_STATS_REPORT_INTERVAL_SEC", 60), 1)) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := health.ReportBlueButtonStats(ctx, db, cw, instance, environment); err != nil {
+				log.Worker.Errorf("Failed to report BFD stats: %s", err)
+			}
+		}
+	}
+}
+
 func createWorkerDirs() {
 	staging := conf.GetEnv("FHIR_STAGING_DIR")
 	err := os.MkdirAll(staging, 0744)
@@ -7,6 +7,7 @@ Job Processing is handled by RiverQueue and consists of three main components:
 3. CleanupJob: Handles cleaning up old/archived bulk export job files.
 
 There are three workers for each step above; they are assigned a "kind" of work and do that work only.
+When alerting rules are enabled, an AlertJob also evaluates them periodically, see package alerting.
 
 When a request comes in, the PrepareWorker will divide the steps into multiple pieces to be worked,
 depending on the number of beneficiaries and resources requested. Each of those pieces will enqueue a new Job which will be picked up by a jobProcessWorker.
@@ -21,6 +22,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/CMSgov/bcda-app/bcda/alerting"
 	"github.com/CMSgov/bcda-app/bcda/database"
 	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
 	"github.com/jackc/pgx/v5"
@@ -61,6 +63,28 @@ func CreateRiverClient(logger *slog.Logger, db *sql.DB, numWorkers int) *river.C
 		),
 	}
 
+	alertingCfg, err := alerting.LoadConfig()
+	if err != nil {
+		panic(err)
+	}
+	if alertingCfg.Enabled() {
+		alertWorker, err := NewAlertJobWorker(db, alertingCfg)
+		if err != nil {
+			panic(err)
+		}
+		river.AddWorker(workers, alertWorker)
+
+		// The schedule was validated when the config was loaded
+		alertSchedule, _ := cron.ParseStandard(alertingCfg.Schedule)
+		periodicJobs = append(periodicJobs, river.NewPeriodicJob(
+			alertSchedule,
+			func() (river.JobArgs, *river.InsertOpts) {
+				return worker_types.AlertJobArgs{}, &river.InsertOpts{}
+			},
+			&river.PeriodicJobOpts{},
+		))
+	}
+
 	riverClient, err := river.NewClient(riverpgxv5.New(pool), &river.Config{
 		Queues: map[string]river.QueueConfig{
 			river.QueueDefault: {MaxWorkers: numWorkers},
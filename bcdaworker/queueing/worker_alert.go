@@ -0,0 +1,46 @@
+package queueing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"github.com/CMSgov/bcda-app/bcda/alerting"
+	"github.com/CMSgov/bcda-app/bcda/service"
+	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+// AlertJobWorker evaluates the alerting rules on the schedule in the alerting config.
+type AlertJobWorker struct {
+	river.WorkerDefaults[worker_types.AlertJobArgs]
+	run func(context.Context, time.Time) error
+}
+
+func NewAlertJobWorker(db *sql.DB, cfg *alerting.Config) (*AlertJobWorker, error) {
+	svcCfg, err := service.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load service config: %w", err)
+	}
+	rules, err := alerting.NewRules(cfg, alerting.NewSource(db), svcCfg.ACOConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alerting rules: %w", err)
+	}
+
+	engine := alerting.NewEngine(rules, alerting.NewNotifiers(cfg.Notifiers), alerting.NewState(db), cfg.Silences, cfg.RepeatInterval)
+	return &AlertJobWorker{run: engine.Run}, nil
+}
+
+func (w *AlertJobWorker) Work(ctx context.Context, rjob *river.Job[worker_types.AlertJobArgs]) error {
+	ctx = log.NewStructuredLoggerEntry(log.Worker, ctx)
+	logger := log.GetCtxLogger(ctx)
+
+	// Don't return the error: River would retry a stale evaluation while the next scheduled one is on its way
+	if err := w.run(ctx, time.Now()); err != nil {
+		logger.Errorf("Failed to evaluate alerting rules for job ID %d: %s", rjob.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package queueing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
+)
+
+func TestAlertJobWorker_Work(t *testing.T) {
+	var runs int
+	w := &AlertJobWorker{run: func(_ context.Context, now time.Time) error {
+		runs++
+		assert.WithinDuration(t, time.Now(), now, time.Minute)
+		return errors.New("failed to evaluate rule queue_age")
+	}}
+
+	err := w.Work(context.Background(), &river.Job[worker_types.AlertJobArgs]{JobRow: &rivertype.JobRow{ID: 1}})
+	assert.NoError(t, err, "evaluation errors are left for the next scheduled run")
+	assert.Equal(t, 1, runs)
+}
@@ -14,13 +14,16 @@ import (
 	"net/url"
 	"os"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/CMSgov/bcda-app/bcda/client"
 	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/CMSgov/bcda-app/bcda/models/postgres"
 	"github.com/CMSgov/bcda-app/bcda/service"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/bcda/web/middleware"
 	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
 	"github.com/CMSgov/bcda-app/conf"
@@ -30,6 +33,7 @@ import (
 	pgxv5 "github.com/jackc/pgx/v5"
 	pgxv5Pool "github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // PrepareJobWorker has two BFD clients because it depends on a configuration variable that is not available until Work() is called.
@@ -81,7 +85,12 @@ func NewPrepareJobWorker(db *sql.DB, pool *pgxv5Pool.Pool) (*PrepareJobWorker, e
 
 }
 
-func (w *PrepareJobWorker) Work(ctx context.Context, rjob *river.Job[worker_types.PrepareJobArgs]) error {
+func (w *PrepareJobWorker) Work(ctx context.Context, rjob *river.Job[worker_types.PrepareJobArgs]) (err error) {
+	ctx, span := tracing.Start(tracing.Extract(ctx, rjob.Args.TraceContext), "PrepareJob",
+		attribute.String("job_id", strconv.FormatUint(uint64(rjob.Args.Job.ID), 10)), attribute.String("cms_id", rjob.Args.CMSID),
+		attribute.String("transaction_id", rjob.Args.TransactionID))
+	defer func() { tracing.End(span, err) }()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -153,6 +162,8 @@ func (p *PrepareJobWorker) prepareExportJobs(ctx context.Context, args worker_ty
 		dberr := p.r.UpdateJob(ctx, args.Job)
 		if dberr != nil {
 			err = fmt.Errorf("%w: %w", err, dberr)
+		} else if args.Job.Status == models.JobStatusFailed {
+			metrics.JobFinished(args.Job.ACOID.String(), string(models.JobStatusFailed))
 		}
 	}()
 
@@ -169,9 +180,10 @@ func (p *PrepareJobWorker) prepareExportJobs(ctx context.Context, args worker_ty
 		TypeFilter:      args.TypeFilter,
 		TransactionTime: time.Now(),
 		CMSID:           args.CMSID,
+		TraceContext:    tracing.Inject(ctx),
 	}
 
-	args.Job.TransactionTime, err = p.GetBundleLastUpdated(args.BFDPath, jobData)
+	args.Job.TransactionTime, err = p.GetBundleLastUpdated(ctx, args.BFDPath, jobData)
 	if err != nil {
 		return exports, args.Since, err
 	}
@@ -193,13 +205,13 @@ func (p *PrepareJobWorker) prepareExportJobs(ctx context.Context, args worker_ty
 }
 
 // GetBundleLastUpdated requests a fake patient in order to acquire the bundle's lastUpdated metadata.
-func (p *PrepareJobWorker) GetBundleLastUpdated(basepath string, jobData worker_types.JobEnqueueArgs) (time.Time, error) {
+func (p *PrepareJobWorker) GetBundleLastUpdated(ctx context.Context, basepath string, jobData worker_types.JobEnqueueArgs) (time.Time, error) {
 	switch basepath {
 	case constants.BFDV1Path:
-		b, err := p.v1Client.GetPatient(jobData, "0")
+		b, err := p.v1Client.GetPatient(ctx, jobData, "0")
 		return b.Meta.LastUpdated, err
 	case constants.BFDV2Path:
-		b, err := p.v2Client.GetPatient(jobData, "0")
+		b, err := p.v2Client.GetPatient(ctx, jobData, "0")
 		return b.Meta.LastUpdated, err
 	case constants.BFDV3Path:
 		return jobData.TransactionTime, nil // TODO: see https://jira.cms.gov/browse/BCDA-10317
@@ -375,7 +375,7 @@ func (s *PrepareWorkerIntegrationTestSuite) TestGetBundleLastUpdated() {
 	c := new(client.MockBlueButtonClient)
 	c.On("GetPatient", mock.Anything, "0").Return(&fhirModels.Bundle{}, nil)
 	worker := &PrepareJobWorker{svc: svc, v1Client: c, v2Client: c, r: s.r, pool: s.pool}
-	_, err := worker.GetBundleLastUpdated(basepath, worker_types.JobEnqueueArgs{})
+	_, err := worker.GetBundleLastUpdated(context.Background(), basepath, worker_types.JobEnqueueArgs{})
 	assert.Nil(s.T(), err)
 }
 
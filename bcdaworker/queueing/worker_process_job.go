@@ -3,8 +3,12 @@ package queueing
 import (
 	"context"
 	"database/sql"
+	goerrors "errors"
 	"time"
 
+	"github.com/CMSgov/bcda-app/bcda/client"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
+	"github.com/CMSgov/bcda-app/bcda/tracing"
 	"github.com/CMSgov/bcda-app/bcda/utils"
 	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
 	"github.com/CMSgov/bcda-app/bcdaworker/repository/postgres"
@@ -14,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/riverqueue/river"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type JobWorker struct {
@@ -28,7 +33,25 @@ func (w *JobWorker) Timeout(*river.Job[worker_types.JobEnqueueArgs]) time.Durati
 	return time.Duration(minutes) * time.Minute
 }
 
-func (w *JobWorker) Work(ctx context.Context, rjob *river.Job[worker_types.JobEnqueueArgs]) error {
+func (w *JobWorker) Work(ctx context.Context, rjob *river.Job[worker_types.JobEnqueueArgs]) (err error) {
+	start := time.Now()
+	ctx, span := tracing.Start(tracing.Extract(ctx, rjob.Args.TraceContext), "ProcessJob",
+		attribute.Int("job_id", rjob.Args.ID), attribute.Int64("subjob_id", rjob.ID),
+		attribute.String("resource_type", rjob.Args.ResourceType), attribute.Int("beneficiaries", len(rjob.Args.BeneficiaryIDs)),
+		attribute.String("transaction_id", rjob.Args.TransactionID))
+	defer func() {
+		result := subJobResult(err)
+		metrics.SubJobProcessed(rjob.Args.ResourceType, result, time.Since(start))
+		span.SetAttributes(attribute.String("result", result))
+		if result == "snoozed" {
+			tracing.End(span, nil)
+		} else {
+			tracing.End(span, err)
+		}
+	}()
+	// BFD requests are made with the sub-job's args rather than its context, so they carry the trace
+	rjob.Args.TraceContext = tracing.Inject(ctx)
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -74,10 +97,20 @@ func (w *JobWorker) Work(ctx context.Context, rjob *river.Job[worker_types.JobEn
 				return err
 			}
 
+			// Don't spend a retry on a job that would fail because BFD is unavailable
+			if wait, open := client.CircuitOpen(client.NewConfig(rjob.Args.BBBasePath)); open {
+				logger.Warnf("BFD circuit breaker is open, snoozing job for %s", wait)
+				return river.JobSnooze(wait)
+			}
+
 			// start a goroutine that will periodically check the status of the parent job
 			go checkIfCancelled(ctx, repo, cancel, jobID, 15)
 
 			if err := workerInstance.ProcessJob(ctx, rjob.ID, *exportJob, rjob.Args); err != nil {
+				if circuitErr, ok := goerrors.AsType[*client.CircuitOpenError](err); ok {
+					logger.Warnf("BFD circuit breaker opened while processing job, snoozing job for %s", circuitErr.RetryAfter)
+					return river.JobSnooze(circuitErr.RetryAfter)
+				}
 				err := errors.Wrap(err, "failed to process job")
 				logger.Error(err)
 				return err
@@ -87,3 +120,14 @@ func (w *JobWorker) Work(ctx context.Context, rjob *river.Job[worker_types.JobEn
 		}
 	}
 }
+
+// subJobResult describes the outcome of a sub-job for metrics.
+func subJobResult(err error) string {
+	if err == nil {
+		return "success"
+	}
+	if _, ok := goerrors.AsType[*river.JobSnoozeError](err); ok {
+		return "snoozed"
+	}
+	return "error"
+}
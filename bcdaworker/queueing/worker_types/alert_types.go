@@ -0,0 +1,10 @@
+package worker_types
+
+const AlertJobKind = "AlertJob"
+
+type AlertJobArgs struct {
+}
+
+func (args AlertJobArgs) Kind() string {
+	return AlertJobKind
+}
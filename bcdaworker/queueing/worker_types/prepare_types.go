@@ -27,6 +27,8 @@ type PrepareJobArgs struct {
 	ClaimsDate             time.Time
 	OptOutDate             time.Time
 	TransactionID          string
+	// TraceContext carries the trace of the API request that created the job, see tracing.Inject
+	TraceContext map[string]string
 }
 
 func (args PrepareJobArgs) Kind() string {
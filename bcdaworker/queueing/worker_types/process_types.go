@@ -24,6 +24,12 @@ type JobEnqueueArgs struct {
 		UpperBound time.Time
 	}
 	DataType string
+	// SAMHSAOptOutBeneficiaryIDs lists the entries of BeneficiaryIDs who have opted out of sharing
+	// substance use disorder data (42 CFR Part 2); their SUD-related claims are withheld from the export.
+	SAMHSAOptOutBeneficiaryIDs []string
+	// TraceContext carries the trace of the job that enqueued this sub-job, and of the sub-job itself once
+	// it is being processed, so requests to BFD join the same trace. See tracing.Inject.
+	TraceContext map[string]string
 }
 
 // Needed by River (queue library)
@@ -8,6 +8,8 @@ import (
 	models "github.com/CMSgov/bcda-app/bcda/models"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/pborman/uuid"
 )
 
@@ -52,6 +54,24 @@ func (_m *MockRepository) CreateJobKeys(ctx context.Context, jobKeys []models.Jo
 	return r0
 }
 
+// DeleteBFDPatientID provides a mock function with given fields: ctx, mbi, basePath
+func (_m *MockRepository) DeleteBFDPatientID(ctx context.Context, mbi string, basePath string) error {
+	ret := _m.Called(ctx, mbi, basePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBFDPatientID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, mbi, basePath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetACOByUUID provides a mock function with given fields: ctx, _a1
 func (_m *MockRepository) GetACOByUUID(ctx context.Context, _a1 uuid.UUID) (*models.ACO, error) {
 	ret := _m.Called(ctx, _a1)
@@ -82,6 +102,34 @@ func (_m *MockRepository) GetACOByUUID(ctx context.Context, _a1 uuid.UUID) (*mod
 	return r0, r1
 }
 
+// GetBFDPatientID provides a mock function with given fields: ctx, mbi, basePath, maxAge
+func (_m *MockRepository) GetBFDPatientID(ctx context.Context, mbi string, basePath string, maxAge time.Duration) (string, error) {
+	ret := _m.Called(ctx, mbi, basePath, maxAge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBFDPatientID")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (string, error)); ok {
+		return rf(ctx, mbi, basePath, maxAge)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) string); ok {
+		r0 = rf(ctx, mbi, basePath, maxAge)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, mbi, basePath, maxAge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCCLFBeneficiaryByID provides a mock function with given fields: ctx, id
 func (_m *MockRepository) GetCCLFBeneficiaryByID(ctx context.Context, id uint) (*models.CCLFBeneficiary, error) {
 	ret := _m.Called(ctx, id)
@@ -200,6 +248,54 @@ func (_m *MockRepository) GetJobKeyCount(ctx context.Context, jobID uint) (int,
 	return r0, r1
 }
 
+// GetLinkedMBIs provides a mock function with given fields: ctx, mbi
+func (_m *MockRepository) GetLinkedMBIs(ctx context.Context, mbi string) ([]string, error) {
+	ret := _m.Called(ctx, mbi)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLinkedMBIs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, mbi)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, mbi)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, mbi)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetBFDPatientID provides a mock function with given fields: ctx, mbi, basePath, patientID
+func (_m *MockRepository) SetBFDPatientID(ctx context.Context, mbi string, basePath string, patientID string) error {
+	ret := _m.Called(ctx, mbi, basePath, patientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBFDPatientID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, mbi, basePath, patientID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateCCLFBeneficiaryBlueButtonID provides a mock function with given fields: ctx, id, blueButtonID
 func (_m *MockRepository) UpdateCCLFBeneficiaryBlueButtonID(ctx context.Context, id uint, blueButtonID string) error {
 	ret := _m.Called(ctx, id, blueButtonID)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/CMSgov/bcda-app/bcda/constants"
 	"github.com/CMSgov/bcda-app/bcda/models"
@@ -66,25 +67,59 @@ func (r *Repository) GetACOByUUID(ctx context.Context, uuid uuid.UUID) (*models.
 
 func (r *Repository) GetCCLFBeneficiaryByID(ctx context.Context, id uint) (*models.CCLFBeneficiary, error) {
 	sb := sqlFlavor.NewSelectBuilder()
-	sb.Select("id", "file_id", "mbi", "blue_button_id")
+	sb.Select("id", "file_id", "mbi", "blue_button_id", "attribution_start", "attribution_end")
 	sb.From("cclf_beneficiaries").Where(sb.Equal("id", id))
 
 	query, args := sb.Build()
 	row := r.QueryRowContext(ctx, query, args...)
 
 	var (
-		bene models.CCLFBeneficiary
-		bbID sql.NullString
+		bene       models.CCLFBeneficiary
+		bbID       sql.NullString
+		start, end sql.NullTime
 	)
 
-	if err := row.Scan(&bene.ID, &bene.FileID, &bene.MBI, &bbID); err != nil {
+	if err := row.Scan(&bene.ID, &bene.FileID, &bene.MBI, &bbID, &start, &end); err != nil {
 		return nil, err
 	}
 	bene.BlueButtonID = bbID.String
+	bene.AttributionStart, bene.AttributionEnd = start.Time, end.Time
+
+	periods, err := r.getAttributionPeriods(ctx, bene.FileID, bene.MBI)
+	if err != nil {
+		return nil, err
+	}
+	bene.AttributionPeriods = periods
 
 	return &bene, nil
 }
 
+// getAttributionPeriods returns every period the beneficiary identified by mbi is aligned for in the file,
+// earliest first. The API enqueues one row per MBI, so the other periods are only found by looking them up.
+func (r *Repository) getAttributionPeriods(ctx context.Context, fileID uint, mbi string) ([]models.AttributionPeriod, error) {
+	sb := sqlFlavor.NewSelectBuilder()
+	sb.Select("attribution_start", "attribution_end").From("cclf_beneficiaries")
+	sb.Where(sb.Equal("file_id", fileID), sb.Equal("mbi", mbi))
+	sb.OrderBy("attribution_start ASC NULLS FIRST", "attribution_end ASC NULLS LAST")
+
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []models.AttributionPeriod
+	for rows.Next() {
+		var start, end sql.NullTime
+		if err := rows.Scan(&start, &end); err != nil {
+			return nil, err
+		}
+		periods = append(periods, models.AttributionPeriod{Start: start.Time, End: end.Time})
+	}
+	return periods, rows.Err()
+}
+
 func (r *Repository) UpdateCCLFBeneficiaryBlueButtonID(ctx context.Context, id uint, blueButtonID string) error {
 	ub := sqlFlavor.NewUpdateBuilder().Update("cclf_beneficiaries")
 	ub.Set(ub.Assign("blue_button_id", blueButtonID))
@@ -96,6 +131,70 @@ func (r *Repository) UpdateCCLFBeneficiaryBlueButtonID(ctx context.Context, id u
 	return err
 }
 
+func (r *Repository) GetLinkedMBIs(ctx context.Context, mbi string) ([]string, error) {
+	sb := sqlFlavor.NewSelectBuilder()
+	sb.Select("linked.mbi").From("mbi_links known")
+	sb.Join("mbi_links linked", "known.beneficiary_link_key = linked.beneficiary_link_key")
+	sb.Where(sb.Equal("known.mbi", mbi), sb.NotEqual("linked.mbi", mbi))
+	sb.GroupBy("linked.mbi").OrderBy("MAX(linked.last_seen)").Desc()
+
+	query, args := sb.Build()
+	rows, err := r.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mbis []string
+	for rows.Next() {
+		var linked string
+		if err = rows.Scan(&linked); err != nil {
+			return nil, err
+		}
+		mbis = append(mbis, linked)
+	}
+	return mbis, rows.Err()
+}
+
+func (r *Repository) GetBFDPatientID(ctx context.Context, mbi, basePath string, maxAge time.Duration) (string, error) {
+	sb := sqlFlavor.NewSelectBuilder().Select("patient_id").From("bfd_patient_ids")
+	sb.Where(
+		sb.Equal("mbi", mbi),
+		sb.Equal("bfd_base_path", basePath),
+		sb.GreaterThan("updated_at", time.Now().Add(-maxAge)),
+	)
+
+	query, args := sb.Build()
+	var patientID string
+	if err := r.QueryRowContext(ctx, query, args...).Scan(&patientID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return patientID, nil
+}
+
+func (r *Repository) SetBFDPatientID(ctx context.Context, mbi, basePath, patientID string) error {
+	ib := sqlFlavor.NewInsertBuilder().InsertInto("bfd_patient_ids")
+	ib.Cols("mbi", "bfd_base_path", "patient_id")
+	ib.Values(mbi, basePath, patientID)
+	query, args := ib.Build()
+	query = fmt.Sprintf("%s ON CONFLICT (mbi, bfd_base_path) DO UPDATE SET patient_id = EXCLUDED.patient_id, updated_at = NOW()", query)
+
+	_, err := r.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *Repository) DeleteBFDPatientID(ctx context.Context, mbi, basePath string) error {
+	db := sqlFlavor.NewDeleteBuilder().DeleteFrom("bfd_patient_ids")
+	db.Where(db.Equal("mbi", mbi), db.Equal("bfd_base_path", basePath))
+
+	query, args := db.Build()
+	_, err := r.ExecContext(ctx, query, args...)
+	return err
+}
+
 func (r *Repository) GetJobByID(ctx context.Context, jobID uint) (*models.Job, error) {
 	sb := sqlFlavor.NewSelectBuilder()
 	sb.Select("id", "aco_id", "request_url", "status", "transaction_time", "job_count", "created_at", "updated_at", "benes_attributed_to_aco")
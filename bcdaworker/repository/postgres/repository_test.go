@@ -96,7 +96,21 @@ func (r *RepositoryTestSuite) TestCCLFBeneficiariesMethods() {
 
 	bene1, err := r.repository.GetCCLFBeneficiaryByID(ctx, bene.ID)
 	assert.NoError(err)
+	bene.AttributionPeriods = []models.AttributionPeriod{{}}
 	assert.Equal(bene, *bene1)
+	bene.AttributionPeriods = nil
+
+	// Every period the beneficiary is aligned for in the file is loaded, whichever row is requested
+	jan, mar := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+	sep, dec := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	mbi := testUtils.RandomMBI(r.T())
+	later := models.CCLFBeneficiary{FileID: cclfFile.ID, MBI: mbi, AttributionStart: sep, AttributionEnd: dec}
+	earlier := models.CCLFBeneficiary{FileID: cclfFile.ID, MBI: mbi, AttributionStart: jan, AttributionEnd: mar}
+	postgrestest.CreateCCLFBeneficiary(r.T(), r.db, &later)
+	postgrestest.CreateCCLFBeneficiary(r.T(), r.db, &earlier)
+	aligned, err := r.repository.GetCCLFBeneficiaryByID(ctx, earlier.ID)
+	assert.NoError(err)
+	assert.Equal([]models.AttributionPeriod{{Start: jan, End: mar}, {Start: sep, End: dec}}, aligned.AttributionPeriods)
 
 	newBlueButtonID := testUtils.RandomHexID()
 	err = r.repository.UpdateCCLFBeneficiaryBlueButtonID(ctx, bene.ID, newBlueButtonID)
@@ -114,6 +128,79 @@ func (r *RepositoryTestSuite) TestCCLFBeneficiariesMethods() {
 	assert.EqualError(err, "sql: no rows in result set")
 }
 
+func (r *RepositoryTestSuite) TestGetLinkedMBIs() {
+	assert := r.Assert()
+	ctx := context.Background()
+	bcdaRepo := bcdaPostgres.NewRepository(r.db)
+
+	linkKey := int(testUtils.CryptoRandInt31())
+	defer postgrestest.DeleteMBILinks(r.T(), r.db, linkKey)
+	current, previous, original := testUtils.RandomMBI(r.T()), testUtils.RandomMBI(r.T()), testUtils.RandomMBI(r.T())
+
+	fileID, err := bcdaRepo.CreateBenePrefsFile(ctx, models.BenePrefsFile{Name: uuid.New(), Timestamp: time.Now(), ImportStatus: constants.ImportComplete})
+	assert.NoError(err)
+	defer postgrestest.DeleteSuppressionFileByID(r.T(), r.db, fileID)
+	for i, mbi := range []string{current, previous, original} {
+		rec := models.BenePrefsRecord{FileID: fileID, MBI: mbi, BeneficiaryLinkKey: linkKey, EffectiveDt: time.Now().AddDate(0, 0, -i)}
+		assert.NoError(bcdaRepo.CreateBenePrefsRecord(ctx, rec))
+	}
+	_, err = bcdaRepo.UpsertMBILinks(ctx, fileID)
+	assert.NoError(err)
+
+	mbis, err := r.repository.GetLinkedMBIs(ctx, previous)
+	assert.NoError(err)
+	assert.Equal([]string{current, original}, mbis)
+
+	mbis, err = r.repository.GetLinkedMBIs(ctx, original)
+	assert.NoError(err)
+	assert.Equal([]string{current, previous}, mbis)
+
+	mbis, err = r.repository.GetLinkedMBIs(ctx, testUtils.RandomMBI(r.T()))
+	assert.NoError(err)
+	assert.Empty(mbis)
+}
+
+func (r *RepositoryTestSuite) TestBFDPatientIDMethods() {
+	assert := r.Assert()
+	ctx := context.Background()
+
+	mbi := testUtils.RandomMBI(r.T())
+	defer func() {
+		assert.NoError(r.repository.DeleteBFDPatientID(ctx, mbi, constants.BFDV2Path))
+		assert.NoError(r.repository.DeleteBFDPatientID(ctx, mbi, constants.BFDV3Path))
+	}()
+
+	patientID, err := r.repository.GetBFDPatientID(ctx, mbi, constants.BFDV2Path, time.Hour)
+	assert.NoError(err)
+	assert.Empty(patientID)
+
+	assert.NoError(r.repository.SetBFDPatientID(ctx, mbi, constants.BFDV2Path, "-1"))
+	assert.NoError(r.repository.SetBFDPatientID(ctx, mbi, constants.BFDV3Path, "-2"))
+
+	patientID, err = r.repository.GetBFDPatientID(ctx, mbi, constants.BFDV2Path, time.Hour)
+	assert.NoError(err)
+	assert.Equal("-1", patientID)
+	patientID, err = r.repository.GetBFDPatientID(ctx, mbi, constants.BFDV3Path, time.Hour)
+	assert.NoError(err)
+	assert.Equal("-2", patientID)
+
+	// Entries older than the max age are ignored
+	time.Sleep(10 * time.Millisecond)
+	patientID, err = r.repository.GetBFDPatientID(ctx, mbi, constants.BFDV2Path, time.Millisecond)
+	assert.NoError(err)
+	assert.Empty(patientID)
+
+	assert.NoError(r.repository.SetBFDPatientID(ctx, mbi, constants.BFDV2Path, "-3"))
+	patientID, err = r.repository.GetBFDPatientID(ctx, mbi, constants.BFDV2Path, time.Hour)
+	assert.NoError(err)
+	assert.Equal("-3", patientID)
+
+	assert.NoError(r.repository.DeleteBFDPatientID(ctx, mbi, constants.BFDV2Path))
+	patientID, err = r.repository.GetBFDPatientID(ctx, mbi, constants.BFDV2Path, time.Hour)
+	assert.NoError(err)
+	assert.Empty(patientID)
+}
+
 // TestJobsMethods validates the CRUD operations associated with the jobs table
 func (r *RepositoryTestSuite) TestJobsMethods() {
 	var err error
@@ -4,6 +4,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/CMSgov/bcda-app/bcda/models"
 	"github.com/pborman/uuid"
@@ -12,6 +13,7 @@ import (
 type Repository interface {
 	acoRepository
 	cclfBeneficiaryRepository
+	bfdPatientIDRepository
 	jobRepository
 	jobKeyRepository
 }
@@ -23,6 +25,19 @@ type acoRepository interface {
 type cclfBeneficiaryRepository interface {
 	GetCCLFBeneficiaryByID(ctx context.Context, id uint) (*models.CCLFBeneficiary, error)
 	UpdateCCLFBeneficiaryBlueButtonID(ctx context.Context, id uint, blueButtonID string) error
+	// GetLinkedMBIs returns the other MBIs that have been issued to the beneficiary identified by mbi,
+	// most recently seen first.
+	GetLinkedMBIs(ctx context.Context, mbi string) ([]string, error)
+}
+
+// bfdPatientIDRepository caches the BFD patient ID each MBI resolves to. Entries are kept per BFD base path
+// since IDs are not guaranteed to match across BFD versions.
+type bfdPatientIDRepository interface {
+	// GetBFDPatientID returns the cached patient ID for the MBI, or an empty string if there is none that has
+	// been refreshed within maxAge.
+	GetBFDPatientID(ctx context.Context, mbi, basePath string, maxAge time.Duration) (string, error)
+	SetBFDPatientID(ctx context.Context, mbi, basePath, patientID string) error
+	DeleteBFDPatientID(ctx context.Context, mbi, basePath string) error
 }
 type jobRepository interface {
 	GetJobByID(ctx context.Context, jobID uint) (*models.Job, error)
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	goerrors "errors"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/client"
+	bcdaErrs "github.com/CMSgov/bcda-app/bcda/errors"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcda/utils"
+	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
+	"github.com/CMSgov/bcda-app/bcdaworker/repository"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+// Default number of hours a cached BFD patient ID is used before it is looked up again
+const defaultBFDPatientIDTTLHours = 168
+
+// bfdPatientIDTTL returns how long cached BFD patient IDs are used. Caching is disabled when it is not positive.
+func bfdPatientIDTTL() time.Duration {
+	return time.Duration(utils.GetEnvInt("BFD_PATIENT_ID_CACHE_TTL_HOURS", defaultBFDPatientIDTTLHours)) * time.Hour
+}
+
+// lookupBlueButtonID returns the BlueButton ID for mbi from the BFD patient ID cache, falling back to
+// resolveBlueButtonID on a miss. Successful lookups are cached and an MBI that BFD no longer knows is removed
+// from the cache. The cache is an optimization, so failing to read or update it does not fail the lookup.
+func lookupBlueButtonID(ctx context.Context, r repository.Repository, bb client.APIClient, mbi string, jobData worker_types.JobEnqueueArgs) (string, []string, error) {
+	ttl := bfdPatientIDTTL()
+	if ttl <= 0 {
+		return resolveBlueButtonID(ctx, r, bb, mbi, jobData)
+	}
+
+	logger := log.GetCtxLogger(ctx)
+	bbID, err := r.GetBFDPatientID(ctx, mbi, jobData.BBBasePath, ttl)
+	if err != nil {
+		logger.Warnf("failed to read cached BFD patient ID: %s", err)
+	} else if bbID != "" {
+		if jobData.ResourceType != "Patient" {
+			return bbID, nil, nil
+		}
+		linked, err := r.GetLinkedMBIs(ctx, mbi)
+		if err != nil {
+			logger.Warnf("failed to retrieve linked MBIs: %s", err)
+		}
+		return bbID, linked, nil
+	}
+
+	bbID, linked, err := resolveBlueButtonID(ctx, r, bb, mbi, jobData)
+	if err == nil {
+		if cacheErr := r.SetBFDPatientID(ctx, mbi, jobData.BBBasePath, bbID); cacheErr != nil {
+			logger.Warnf("failed to cache BFD patient ID: %s", cacheErr)
+		}
+	} else if _, ok := goerrors.AsType[*bcdaErrs.RequestedBeneficiaryNotFoundError](err); ok {
+		invalidateBFDPatientID(ctx, r, mbi, jobData)
+	}
+	return bbID, linked, err
+}
+
+// streamByPatientID runs a search scoped to the beneficiary's BFD patient ID, passing each page to fn. The
+// patient ID may have come from the cache, so when the search finds nothing it is invalidated and the next job
+// looks it up again.
+func streamByPatientID(ctx context.Context, r repository.Repository, jobData worker_types.JobEnqueueArgs, bene models.CCLFBeneficiary,
+	fn client.PageHandler, search func(client.PageHandler) error) error {
+	entries := 0
+	err := search(func(b *fhirmodels.Bundle) error {
+		entries += len(b.Entries)
+		return fn(b)
+	})
+	if err == nil && entries == 0 {
+		invalidateBFDPatientID(ctx, r, bene.MBI, jobData)
+	}
+	return err
+}
+
+// invalidateBFDPatientID removes the cached BFD patient ID for mbi so the next job looks it up again.
+func invalidateBFDPatientID(ctx context.Context, r repository.Repository, mbi string, jobData worker_types.JobEnqueueArgs) {
+	if bfdPatientIDTTL() <= 0 {
+		return
+	}
+	if err := r.DeleteBFDPatientID(ctx, mbi, jobData.BBBasePath); err != nil {
+		log.GetCtxLogger(ctx).Warnf("failed to remove cached BFD patient ID: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/CMSgov/bcda-app/bcda/client"
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
+	"github.com/CMSgov/bcda-app/bcdaworker/repository"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+func TestLookupBlueButtonID(t *testing.T) {
+	const mbi = "1S00E00AA00"
+	ttl := time.Duration(defaultBFDPatientIDTTLHours) * time.Hour
+	jobArgs := worker_types.JobEnqueueArgs{ResourceType: "Coverage", BBBasePath: constants.TestFHIRPath}
+
+	tests := []struct {
+		name       string
+		cached     string
+		cacheErr   error
+		patient    string
+		expectedID string
+		setup      func(r *repository.MockRepository)
+		lookups    int
+	}{
+		{name: "cache hit", cached: "-1", expectedID: "-1"},
+		{name: "cache miss", patient: patientByMBI("-2", mbi), expectedID: "-2", lookups: 1,
+			setup: func(r *repository.MockRepository) {
+				r.On("SetBFDPatientID", mock.Anything, mbi, constants.TestFHIRPath, "-2").Return(nil)
+			}},
+		{name: "cache read error", cacheErr: errors.New("db error"), patient: patientByMBI("-2", mbi), expectedID: "-2", lookups: 1,
+			setup: func(r *repository.MockRepository) {
+				r.On("SetBFDPatientID", mock.Anything, mbi, constants.TestFHIRPath, "-2").Return(errors.New("db error"))
+			}},
+		{name: "not found invalidates", patient: `{"entry":[]}`, lookups: 1,
+			setup: func(r *repository.MockRepository) {
+				r.On("GetLinkedMBIs", mock.Anything, mbi).Return(nil, nil)
+				r.On("DeleteBFDPatientID", mock.Anything, mbi, constants.TestFHIRPath).Return(nil)
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bbc := &client.MockBlueButtonClient{}
+			bbc.On("GetPatientByMbi", mbi).Return(tt.patient, nil)
+			r := &repository.MockRepository{}
+			r.On("GetBFDPatientID", mock.Anything, mbi, constants.TestFHIRPath, ttl).Return(tt.cached, tt.cacheErr)
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+
+			bbID, _, err := lookupBlueButtonID(context.Background(), r, bbc, mbi, jobArgs)
+			if tt.expectedID == "" {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedID, bbID)
+			bbc.AssertNumberOfCalls(t, "GetPatientByMbi", tt.lookups)
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLookupBlueButtonIDCacheDisabled(t *testing.T) {
+	const mbi = "1S00E00AA00"
+	orig := conf.GetEnv("BFD_PATIENT_ID_CACHE_TTL_HOURS")
+	defer conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", orig)
+	conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", "0")
+
+	bbc := &client.MockBlueButtonClient{}
+	bbc.On("GetPatientByMbi", mbi).Return(patientByMBI("-1", mbi), nil)
+	r := &repository.MockRepository{}
+
+	jobArgs := worker_types.JobEnqueueArgs{ResourceType: "Coverage", BBBasePath: constants.TestFHIRPath}
+	bbID, _, err := lookupBlueButtonID(context.Background(), r, bbc, mbi, jobArgs)
+	assert.NoError(t, err)
+	assert.Equal(t, "-1", bbID)
+	r.AssertExpectations(t)
+}
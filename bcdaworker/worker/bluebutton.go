@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
@@ -13,8 +14,8 @@ import (
 
 // This method will ensure that a valid BlueButton ID is returned.
 // If you use cclfBeneficiary.BlueButtonID you will not be guaranteed a valid value
-func getBlueButtonID(bb client.APIClient, mbi string, jobData worker_types.JobEnqueueArgs) (blueButtonID string, err error) {
-	jsonData, err := bb.GetPatientByMbi(jobData, mbi)
+func getBlueButtonID(ctx context.Context, bb client.APIClient, mbi string, jobData worker_types.JobEnqueueArgs) (blueButtonID string, err error) {
+	jsonData, err := bb.GetPatientByMbi(ctx, jobData, mbi)
 	if err != nil {
 		return "", err
 	}
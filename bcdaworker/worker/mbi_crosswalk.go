@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	goerrors "errors"
+
+	"github.com/CMSgov/bcda-app/bcda/client"
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	bcdaErrs "github.com/CMSgov/bcda-app/bcda/errors"
+	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
+	"github.com/CMSgov/bcda-app/bcdaworker/repository"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+// resolveBlueButtonID looks up the BlueButton ID of the beneficiary identified by mbi. CMS reissues MBIs
+// (e.g. after identity theft) and attribution files may still carry a beneficiary's previous MBI, so when BFD
+// does not know mbi the lookup is retried with the other MBIs linked to it in the bene-prefs crosswalk.
+//
+// The linked MBIs are returned so they can be included in the beneficiary's identifier history. They are
+// only loaded for Patient jobs or when the lookup by mbi fails.
+func resolveBlueButtonID(ctx context.Context, r repository.Repository, bb client.APIClient, mbi string, jobData worker_types.JobEnqueueArgs) (string, []string, error) {
+	bbID, err := getBlueButtonID(ctx, bb, mbi, jobData)
+	_, notFound := goerrors.AsType[*bcdaErrs.RequestedBeneficiaryNotFoundError](err)
+	if err != nil && !notFound {
+		return "", nil, err
+	}
+	if err == nil && jobData.ResourceType != "Patient" {
+		return bbID, nil, nil
+	}
+
+	logger := log.GetCtxLogger(ctx)
+	linked, linkErr := r.GetLinkedMBIs(ctx, mbi)
+	if linkErr != nil {
+		logger.Warnf("failed to retrieve linked MBIs: %s", linkErr)
+		return bbID, nil, err
+	}
+	if err == nil {
+		return bbID, linked, nil
+	}
+
+	for _, linkedMBI := range linked {
+		id, linkedErr := getBlueButtonID(ctx, bb, linkedMBI, jobData)
+		if linkedErr == nil {
+			logger.Infof("Found beneficiary using a linked MBI (%d linked)", len(linked))
+			return id, linked, nil
+		}
+		if _, ok := goerrors.AsType[*bcdaErrs.RequestedBeneficiaryNotFoundError](linkedErr); !ok {
+			return "", linked, linkedErr
+		}
+	}
+
+	// Report the failure against the MBI the entity knows the beneficiary by
+	return "", linked, err
+}
+
+// addMBIHistory adds each of mbis that is not already one of a Patient's identifiers as an old MBI and returns
+// the number of identifiers added.
+func addMBIHistory(b *fhirmodels.Bundle, mbis []string) (added int) {
+	for _, entry := range b.Entries {
+		resource, ok := entry["resource"].(map[string]interface{})
+		if !ok || resource["resourceType"] != "Patient" {
+			continue
+		}
+
+		identifiers := asSlice(resource["identifier"])
+		known := make(map[string]struct{}, len(identifiers))
+		for _, i := range identifiers {
+			identifier, _ := i.(map[string]interface{})
+			if value, ok := identifier["value"].(string); ok {
+				known[value] = struct{}{}
+			}
+		}
+
+		for _, mbi := range mbis {
+			if _, ok := known[mbi]; ok || mbi == "" {
+				continue
+			}
+			known[mbi] = struct{}{}
+			identifiers = append(identifiers, map[string]interface{}{
+				"system": constants.MBISystem,
+				"value":  mbi,
+				"use":    "old",
+			})
+			added++
+		}
+		resource["identifier"] = identifiers
+	}
+	return added
+}
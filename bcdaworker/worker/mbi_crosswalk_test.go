@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/client"
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
+	"github.com/CMSgov/bcda-app/bcdaworker/repository"
+)
+
+func patientByMBI(bbID, mbi string) string {
+	return fmt.Sprintf(`{"entry":[{"resource":{"id":"%[1]s","identifier":[
+		{"system":"https://bluebutton.cms.gov/resources/variables/bene_id","value":"%[1]s"},
+		{"system":"http://hl7.org/fhir/sid/us-mbi","value":"%[2]s"}]}}]}`, bbID, mbi)
+}
+
+func TestResolveBlueButtonID(t *testing.T) {
+	const (
+		oldMBI = "1S00E00AA00"
+		newMBI = "1S00E00AA01"
+	)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		linked       []string
+		linkErr      error
+		byMBI        map[string]string
+		bbErr        error
+		expectedID   string
+		expectedErr  string
+		noLinkLookup bool
+	}{
+		{name: "found by MBI", resourceType: "ExplanationOfBenefit", byMBI: map[string]string{oldMBI: patientByMBI("-1", oldMBI)},
+			expectedID: "-1", noLinkLookup: true},
+		{name: "found by MBI loads links for Patient", resourceType: "Patient", linked: []string{newMBI},
+			byMBI: map[string]string{oldMBI: patientByMBI("-1", oldMBI)}, expectedID: "-1"},
+		{name: "found by linked MBI", resourceType: "Coverage", linked: []string{newMBI},
+			byMBI: map[string]string{newMBI: patientByMBI("-2", newMBI)}, expectedID: "-2"},
+		{name: "not found by any MBI", resourceType: "Coverage", linked: []string{newMBI},
+			expectedErr: "requested beneficiary not found, err: patient identifier not found for MBI"},
+		{name: "no linked MBIs", resourceType: "Coverage",
+			expectedErr: "requested beneficiary not found, err: patient identifier not found for MBI"},
+		{name: "failed to load linked MBIs", resourceType: "Coverage", linkErr: errors.New("db error"),
+			expectedErr: "requested beneficiary not found, err: patient identifier not found for MBI"},
+		{name: "BFD error", resourceType: "Coverage", bbErr: errors.New("bfd unavailable"),
+			expectedErr: "bfd unavailable", noLinkLookup: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bbc := &client.MockBlueButtonClient{}
+			for _, mbi := range []string{oldMBI, newMBI} {
+				if body, ok := tt.byMBI[mbi]; ok {
+					bbc.On("GetPatientByMbi", mbi).Return(body, nil)
+				} else if tt.bbErr != nil {
+					bbc.On("GetPatientByMbi", mbi).Return("", tt.bbErr)
+				} else {
+					bbc.On("GetPatientByMbi", mbi).Return(`{"entry":[]}`, nil)
+				}
+			}
+			r := &repository.MockRepository{}
+			if !tt.noLinkLookup {
+				r.On("GetLinkedMBIs", mock.Anything, oldMBI).Return(tt.linked, tt.linkErr)
+			}
+
+			jobArgs := worker_types.JobEnqueueArgs{ResourceType: tt.resourceType, BBBasePath: constants.TestFHIRPath}
+			bbID, linked, err := resolveBlueButtonID(context.Background(), r, bbc, oldMBI, jobArgs)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Empty(t, bbID)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedID, bbID)
+				assert.Equal(t, tt.linked, linked)
+			}
+			r.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAddMBIHistory(t *testing.T) {
+	var b fhirmodels.Bundle
+	require.NoError(t, json.Unmarshal([]byte(`{"resourceType": "Bundle", "entry": [
+		{"resource": {"resourceType": "Patient", "id": "-1", "identifier": [
+			{"system": "https://bluebutton.cms.gov/resources/variables/bene_id", "value": "-1"},
+			{"system": "http://hl7.org/fhir/sid/us-mbi", "value": "1S00E00AA01"}]}},
+		{"resource": {"resourceType": "Coverage", "id": "part-a--1"}}
+	]}`), &b))
+
+	assert.Equal(t, 2, addMBIHistory(&b, []string{"1S00E00AA01", "1S00E00AA00", "", "1S00E00AA02", "1S00E00AA00"}))
+
+	identifiers := b.Entries[0]["resource"].(map[string]interface{})["identifier"].([]interface{})
+	require.Len(t, identifiers, 4)
+	assert.Equal(t, map[string]interface{}{"system": constants.MBISystem, "value": "1S00E00AA00", "use": "old"}, identifiers[2])
+	assert.Equal(t, map[string]interface{}{"system": constants.MBISystem, "value": "1S00E00AA02", "use": "old"}, identifiers[3])
+	assert.NotContains(t, b.Entries[1]["resource"], "identifier")
+}
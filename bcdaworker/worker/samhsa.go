@@ -0,0 +1,171 @@
+package worker
+
+import (
+	"slices"
+	"strings"
+
+	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+)
+
+// Security labels BFD applies to claims containing data protected under 42 CFR Part 2
+var samhsaSecurityCodes = []string{"42CFRPart2", "ETH"}
+
+// ICD-10-CM substance use disorder categories. F17 (nicotine dependence) is not covered by 42 CFR Part 2.
+var sudICD10Prefixes = []string{"F10", "F11", "F12", "F13", "F14", "F15", "F16", "F18", "F19"}
+
+// ICD-9-CM substance use disorder categories. 305.1 (tobacco use disorder) is not covered by 42 CFR Part 2.
+var sudICD9Prefixes = []string{"291", "292", "303", "304", "305"}
+
+// ICD-10-PCS section HZ covers substance abuse treatment.
+var sudICD10ProcedurePrefixes = []string{"HZ"}
+
+// ICD-9-CM procedures for drug and alcohol counseling (94.45, 94.46) and rehabilitation and detoxification (94.6x).
+var sudICD9ProcedurePrefixes = []string{"9445", "9446", "946"}
+
+// HCPCS and CPT codes billed for substance use disorder assessment, counseling, detoxification and
+// medication-assisted treatment.
+var sudServiceCodes = []string{
+	"H0001", "H0003", "H0005", "H0006", "H0007", "H0008", "H0009", "H0010", "H0011", "H0012", "H0013",
+	"H0014", "H0015", "H0016", "H0020", "H0022", "H0047", "H0050", "H2034", "H2035", "H2036",
+	"G0396", "G0397", "G1028", "G2067", "G2068", "G2069", "G2070", "G2071", "G2072", "G2073", "G2074",
+	"G2075", "G2076", "G2077", "G2078", "G2079", "G2080", "G2086", "G2087", "G2088", "G2215", "G2216",
+	"J0570", "J0571", "J0572", "J0573", "J0574", "J0575", "J2315", "Q9991", "Q9992", "S9475",
+	"T1006", "T1007", "99408", "99409",
+}
+
+// Revenue center codes for detoxification room and board (0116-0156), chemical dependency intensive
+// outpatient (0906), drug and alcohol rehabilitation (0944, 0945) and chemical dependency residential
+// treatment (1002).
+var sudRevenueCodes = []string{"0116", "0126", "0136", "0146", "0156", "0906", "0944", "0945", "1002"}
+
+// removeSUDEntries drops the resources in b that contain substance use disorder data and returns how many
+// were removed. A resource is considered SUD-related when it carries a 42 CFR Part 2 security label, or any of
+// its diagnoses, procedures, line item services or revenue centers is a substance use disorder code.
+func removeSUDEntries(b *fhirmodels.Bundle) (removed int) {
+	entries := b.Entries[:0]
+	for _, entry := range b.Entries {
+		resource, ok := entry["resource"].(map[string]interface{})
+		if ok && (hasSAMHSASecurityLabel(resource) || hasSUDDiagnosis(resource) || hasSUDProcedure(resource) || hasSUDItem(resource)) {
+			removed++
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	b.Entries = entries
+	return removed
+}
+
+func hasSAMHSASecurityLabel(resource map[string]interface{}) bool {
+	meta, _ := resource["meta"].(map[string]interface{})
+	for _, label := range asSlice(meta["security"]) {
+		coding, _ := label.(map[string]interface{})
+		if code, _ := coding["code"].(string); slices.Contains(samhsaSecurityCodes, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSUDDiagnosis(resource map[string]interface{}) bool {
+	for _, d := range asSlice(resource["diagnosis"]) {
+		diagnosis, _ := d.(map[string]interface{})
+		for _, coding := range codings(diagnosis["diagnosisCodeableConcept"]) {
+			system, _ := coding["system"].(string)
+			code, _ := coding["code"].(string)
+			if isSUDCode(system, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasSUDProcedure(resource map[string]interface{}) bool {
+	for _, p := range asSlice(resource["procedure"]) {
+		procedure, _ := p.(map[string]interface{})
+		for _, coding := range codings(procedure["procedureCodeableConcept"]) {
+			system, _ := coding["system"].(string)
+			code, _ := coding["code"].(string)
+			if isSUDProcedureCode(system, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasSUDItem reports whether any line item bills a substance use disorder service or revenue center.
+// R4 resources name the service productOrService, STU3 resources name it service.
+func hasSUDItem(resource map[string]interface{}) bool {
+	for _, i := range asSlice(resource["item"]) {
+		item, _ := i.(map[string]interface{})
+		for _, field := range []string{"productOrService", "service"} {
+			for _, coding := range codings(item[field]) {
+				if code, _ := coding["code"].(string); slices.Contains(sudServiceCodes, strings.ToUpper(code)) {
+					return true
+				}
+			}
+		}
+		for _, coding := range codings(item["revenue"]) {
+			if code, _ := coding["code"].(string); slices.Contains(sudRevenueCodes, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSUDCode reports whether code is a substance use disorder diagnosis in the ICD-9 or ICD-10 code system.
+// Codes are compared without the decimal point since BFD does not always include it.
+func isSUDCode(system, code string) bool {
+	code = strings.ToUpper(strings.ReplaceAll(code, ".", ""))
+	switch {
+	case strings.Contains(system, "icd-10"):
+		return hasAnyPrefix(code, sudICD10Prefixes)
+	case strings.Contains(system, "icd-9"):
+		return hasAnyPrefix(code, sudICD9Prefixes) && !strings.HasPrefix(code, "3051")
+	default:
+		return false
+	}
+}
+
+// isSUDProcedureCode reports whether code is a substance use disorder procedure in the ICD-9 or ICD-10 code
+// system. BFD identifies procedure code systems both as icd-10 and as ICD10.
+func isSUDProcedureCode(system, code string) bool {
+	system = strings.ToLower(strings.ReplaceAll(system, "-", ""))
+	code = strings.ToUpper(strings.ReplaceAll(code, ".", ""))
+	switch {
+	case strings.Contains(system, "icd10"):
+		return hasAnyPrefix(code, sudICD10ProcedurePrefixes)
+	case strings.Contains(system, "icd9"):
+		return hasAnyPrefix(code, sudICD9ProcedurePrefixes)
+	default:
+		return false
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+// codings returns the codings of a CodeableConcept
+func codings(concept interface{}) []map[string]interface{} {
+	c, _ := concept.(map[string]interface{})
+	var result []map[string]interface{}
+	for _, v := range asSlice(c["coding"]) {
+		if coding, ok := v.(map[string]interface{}); ok {
+			result = append(result, coding)
+		}
+	}
+	return result
+}
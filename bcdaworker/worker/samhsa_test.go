@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+)
+
+const syntheticSAMHSABundle = `{
+	"resourceType": "Bundle",
+	"entry": [
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "carrier--1",
+			"diagnosis": [{"diagnosisCodeableConcept": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-10", "code": "I10"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "carrier--2",
+			"diagnosis": [{"diagnosisCodeableConcept": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-10-cm", "code": "F10.20"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "inpatient--3",
+			"meta": {"security": [{"system": "http://terminology.hl7.org/CodeSystem/v3-ActCode", "code": "42CFRPart2"}]}}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "carrier--4",
+			"diagnosis": [{"diagnosisCodeableConcept": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-10", "code": "F17210"}]}}]}},
+		{"resource": {"resourceType": "Claim", "id": "fiss--5",
+			"diagnosis": [{"diagnosisCodeableConcept": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-9-cm", "code": "3040"}]}}]}},
+		{"resource": {"resourceType": "Claim", "id": "fiss--6",
+			"diagnosis": [{"diagnosisCodeableConcept": {"coding": [{"system": "http://hl7.org/fhir/sid/icd-9-cm", "code": "305.1"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "inpatient--7",
+			"procedure": [{"procedureCodeableConcept": {"coding": [{"system": "http://www.cms.gov/Medicare/Coding/ICD10", "code": "HZ2ZZZZ"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "inpatient--8",
+			"procedure": [{"procedureCodeableConcept": {"coding": [{"system": "http://www.cms.gov/Medicare/Coding/ICD10", "code": "0DTJ4ZZ"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "carrier--9",
+			"item": [{"sequence": 1, "productOrService": {"coding": [{"system": "https://bluebutton.cms.gov/resources/codesystem/hcpcs", "code": "G2067"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "carrier--10",
+			"item": [{"sequence": 1, "service": {"coding": [{"system": "https://bluebutton.cms.gov/resources/codesystem/hcpcs", "code": "H0020"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "outpatient--11",
+			"item": [{"sequence": 1, "revenue": {"coding": [{"system": "https://bluebutton.cms.gov/resources/variables/rev_cntr", "code": "0944"}]}}]}},
+		{"resource": {"resourceType": "ExplanationOfBenefit", "id": "outpatient--12",
+			"item": [{"sequence": 1, "revenue": {"coding": [{"system": "https://bluebutton.cms.gov/resources/variables/rev_cntr", "code": "0450"}]},
+				"productOrService": {"coding": [{"system": "https://bluebutton.cms.gov/resources/codesystem/hcpcs", "code": "99283"}]}}]}}
+	]
+}`
+
+func TestRemoveSUDEntries(t *testing.T) {
+	var b fhirmodels.Bundle
+	require.NoError(t, json.Unmarshal([]byte(syntheticSAMHSABundle), &b))
+
+	assert.Equal(t, 7, removeSUDEntries(&b))
+
+	var ids []string
+	for _, entry := range b.Entries {
+		ids = append(ids, entry["resource"].(map[string]interface{})["id"].(string))
+	}
+	assert.Equal(t, []string{"carrier--1", "carrier--4", "fiss--6", "inpatient--8", "outpatient--12"}, ids)
+}
+
+func TestIsSUDCode(t *testing.T) {
+	tests := []struct {
+		system string
+		code   string
+		exp    bool
+	}{
+		{"http://hl7.org/fhir/sid/icd-10", "F1120", true},
+		{"http://hl7.org/fhir/sid/icd-10-cm", "f19.10", true},
+		{"http://hl7.org/fhir/sid/icd-10", "F17200", false},
+		{"http://hl7.org/fhir/sid/icd-10", "E119", false},
+		{"http://hl7.org/fhir/sid/icd-9-cm", "291.0", true},
+		{"http://hl7.org/fhir/sid/icd-9-cm", "30510", false},
+		{"http://hl7.org/fhir/sid/icd-9-cm", "30500", true},
+		{"http://www.ama-assn.org/go/cpt", "F1020", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.exp, isSUDCode(tt.system, tt.code), "%s|%s", tt.system, tt.code)
+	}
+}
+
+func TestIsSUDProcedureCode(t *testing.T) {
+	tests := []struct {
+		system string
+		code   string
+		exp    bool
+	}{
+		{"http://www.cms.gov/Medicare/Coding/ICD10", "HZ2ZZZZ", true},
+		{"http://hl7.org/fhir/sid/icd-10", "hz81zzz", true},
+		{"http://www.cms.gov/Medicare/Coding/ICD10", "0DTJ4ZZ", false},
+		{"http://www.cms.gov/Medicare/Coding/ICD9", "94.61", true},
+		{"http://hl7.org/fhir/sid/icd-9-cm", "9445", true},
+		{"http://www.cms.gov/Medicare/Coding/ICD9", "94.44", false},
+		{"http://www.ama-assn.org/go/cpt", "HZ2ZZZZ", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.exp, isSUDProcedureCode(tt.system, tt.code), "%s|%s", tt.system, tt.code)
+	}
+}
@@ -0,0 +1,142 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcda/models/fhir/stu3"
+	"github.com/CMSgov/bcda-app/bcda/responseutils"
+	"github.com/CMSgov/bcda-app/bcda/utils"
+	"github.com/CMSgov/bcda-app/log"
+)
+
+// Elements the FHIR R4 profiles require for each resource type we export, checked when
+// BFD_PROFILE_VALIDATION is enabled
+var requiredElements = map[string][]string{
+	"Patient":              {"identifier"},
+	"Coverage":             {"status", "beneficiary", "payor"},
+	"ExplanationOfBenefit": {"status", "type", "use", "patient", "created", "insurer", "provider", "outcome", "insurance"},
+	"Claim":                {"status", "type", "use", "patient", "created", "provider", "priority", "insurance"},
+	"ClaimResponse":        {"status", "type", "use", "patient", "created", "insurer", "outcome"},
+}
+
+// invalidResourceError describes why a resource returned by BFD can't be exported.
+type invalidResourceError struct {
+	code   stu3.IssueTypeCode
+	reason string
+}
+
+func (e *invalidResourceError) Error() string {
+	return e.reason
+}
+
+// rejectInvalidEntries removes the resources in b that fail validateResource, recording each in the error file,
+// and returns how many were removed. Resources for another beneficiary or of the wrong type would otherwise be
+// shipped to the entity without anyone noticing.
+func rejectInvalidEntries(ctx context.Context, b *fhirmodels.Bundle, resourceType string, bene models.CCLFBeneficiary, fileUUID, tmpDir string) (rejected int) {
+	checkProfile := utils.GetEnvBool("BFD_PROFILE_VALIDATION", false)
+	entries := b.Entries[:0]
+	for _, entry := range b.Entries {
+		if entry["resource"] == nil {
+			entries = append(entries, entry)
+			continue
+		}
+		if err := validateResource(entry["resource"], resourceType, bene, checkProfile); err != nil {
+			rejected++
+			// MBI is appended inside file, not printed out to system logs
+			appendErrorToFile(ctx, fileUUID, err.code, responseutils.BbErr,
+				fmt.Sprintf("Invalid %s returned for beneficiary MBI %s: %s", resourceType, bene.MBI, err.reason), tmpDir)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	b.Entries = entries
+
+	if rejected > 0 {
+		log.GetCtxLogger(ctx).Warnf("Rejected %d invalid %s resources for cclfBeneficiaryId %d", rejected, resourceType, bene.ID)
+	}
+	return rejected
+}
+
+// validateResource checks that a resource is a well-formed resourceType belonging to bene.
+func validateResource(r interface{}, resourceType string, bene models.CCLFBeneficiary, checkProfile bool) *invalidResourceError {
+	resource, ok := r.(map[string]interface{})
+	if !ok {
+		return &invalidResourceError{stu3.IssueTypeCodeStructure, "resource is not a JSON object"}
+	}
+	if actual, _ := resource["resourceType"].(string); actual != resourceType {
+		return &invalidResourceError{stu3.IssueTypeCodeStructure, fmt.Sprintf("unexpected resourceType %q", actual)}
+	}
+	id, _ := resource["id"].(string)
+	if id == "" {
+		return &invalidResourceError{stu3.IssueTypeCodeStructure, "resource has no id"}
+	}
+
+	if !belongsTo(resource, resourceType, bene) {
+		return &invalidResourceError{stu3.IssueTypeCodeProcessing, fmt.Sprintf("%s/%s is for a different beneficiary", resourceType, id)}
+	}
+
+	if checkProfile {
+		for _, element := range requiredElements[resourceType] {
+			if isEmpty(resource[element]) {
+				return &invalidResourceError{stu3.IssueTypeCodeStructure, fmt.Sprintf("%s/%s is missing required element %s", resourceType, id, element)}
+			}
+		}
+	}
+	return nil
+}
+
+// belongsTo reports whether the resource references bene. Adjudicated resources reference the beneficiary by
+// their BFD patient ID. Partially adjudicated resources are searched by MBI and are only checked when they
+// identify the beneficiary by one, which may be any of the beneficiary's linked MBIs.
+func belongsTo(resource map[string]interface{}, resourceType string, bene models.CCLFBeneficiary) bool {
+	patientRef := "Patient/" + bene.BlueButtonID
+	mbis := append([]string{bene.MBI}, bene.LinkedMBIs...)
+	switch resourceType {
+	case "Patient":
+		if id, _ := resource["id"].(string); bene.BlueButtonID != "" && id == bene.BlueButtonID {
+			return true
+		}
+		return slices.ContainsFunc(asSlice(resource["identifier"]), func(i interface{}) bool {
+			identifier, _ := i.(map[string]interface{})
+			value, _ := identifier["value"].(string)
+			return identifier["system"] == constants.MBISystem && slices.Contains(mbis, value)
+		})
+	case "Coverage":
+		return bene.BlueButtonID == "" || reference(resource["beneficiary"]) == patientRef
+	case "ExplanationOfBenefit":
+		return bene.BlueButtonID == "" || reference(resource["patient"]) == patientRef
+	case "Claim", "ClaimResponse":
+		patient, _ := resource["patient"].(map[string]interface{})
+		identifier, _ := patient["identifier"].(map[string]interface{})
+		value, _ := identifier["value"].(string)
+		return identifier["system"] != constants.MBISystem || slices.Contains(mbis, value)
+	default:
+		return true
+	}
+}
+
+func reference(v interface{}) string {
+	ref, _ := v.(map[string]interface{})
+	s, _ := ref["reference"].(string)
+	return s
+}
+
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
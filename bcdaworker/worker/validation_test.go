@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/CMSgov/bcda-app/bcda/models"
+	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcda/models/fhir/stu3"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+func TestValidateResource(t *testing.T) {
+	bene := models.CCLFBeneficiary{ID: 1, MBI: "1S00E00AA01", BlueButtonID: "-1", LinkedMBIs: []string{"1S00E00AA00"}}
+	mbiIdentifier := func(mbi string) map[string]interface{} {
+		return map[string]interface{}{"system": constants.MBISystem, "value": mbi}
+	}
+
+	tests := []struct {
+		name         string
+		resourceType string
+		resource     interface{}
+		checkProfile bool
+		expectedCode stu3.IssueTypeCode
+		expectedErr  string
+	}{
+		{name: "not an object", resourceType: "Coverage", resource: "Coverage/part-a--1",
+			expectedCode: stu3.IssueTypeCodeStructure, expectedErr: "resource is not a JSON object"},
+		{name: "wrong resourceType", resourceType: "Coverage",
+			resource:     map[string]interface{}{"resourceType": "Patient", "id": "-1"},
+			expectedCode: stu3.IssueTypeCodeStructure, expectedErr: `unexpected resourceType "Patient"`},
+		{name: "missing id", resourceType: "Coverage",
+			resource:     map[string]interface{}{"resourceType": "Coverage"},
+			expectedCode: stu3.IssueTypeCodeStructure, expectedErr: "resource has no id"},
+		{name: "Coverage", resourceType: "Coverage",
+			resource: map[string]interface{}{"resourceType": "Coverage", "id": "part-a--1", "beneficiary": map[string]interface{}{"reference": "Patient/-1"}}},
+		{name: "Coverage for another beneficiary", resourceType: "Coverage",
+			resource:     map[string]interface{}{"resourceType": "Coverage", "id": "part-a--2", "beneficiary": map[string]interface{}{"reference": "Patient/-2"}},
+			expectedCode: stu3.IssueTypeCodeProcessing, expectedErr: "Coverage/part-a--2 is for a different beneficiary"},
+		{name: "EOB", resourceType: "ExplanationOfBenefit",
+			resource: map[string]interface{}{"resourceType": "ExplanationOfBenefit", "id": "carrier-1", "patient": map[string]interface{}{"reference": "Patient/-1"}}},
+		{name: "EOB without patient", resourceType: "ExplanationOfBenefit",
+			resource:     map[string]interface{}{"resourceType": "ExplanationOfBenefit", "id": "carrier-1"},
+			expectedCode: stu3.IssueTypeCodeProcessing, expectedErr: "ExplanationOfBenefit/carrier-1 is for a different beneficiary"},
+		{name: "Patient by ID", resourceType: "Patient",
+			resource: map[string]interface{}{"resourceType": "Patient", "id": "-1"}},
+		{name: "Patient by linked MBI", resourceType: "Patient",
+			resource: map[string]interface{}{"resourceType": "Patient", "id": "-3", "identifier": []interface{}{mbiIdentifier("1S00E00AA00")}}},
+		{name: "Patient for another beneficiary", resourceType: "Patient",
+			resource:     map[string]interface{}{"resourceType": "Patient", "id": "-2", "identifier": []interface{}{mbiIdentifier("1S00E00AA02")}},
+			expectedCode: stu3.IssueTypeCodeProcessing, expectedErr: "Patient/-2 is for a different beneficiary"},
+		{name: "Claim", resourceType: "Claim",
+			resource: map[string]interface{}{"resourceType": "Claim", "id": "f-1", "patient": map[string]interface{}{"identifier": mbiIdentifier("1S00E00AA01")}}},
+		{name: "Claim by linked MBI", resourceType: "Claim",
+			resource: map[string]interface{}{"resourceType": "Claim", "id": "f-2", "patient": map[string]interface{}{"identifier": mbiIdentifier("1S00E00AA00")}}},
+		{name: "ClaimResponse by linked MBI", resourceType: "ClaimResponse",
+			resource: map[string]interface{}{"resourceType": "ClaimResponse", "id": "f-2", "patient": map[string]interface{}{"identifier": mbiIdentifier("1S00E00AA00")}}},
+		{name: "Claim without MBI", resourceType: "Claim",
+			resource: map[string]interface{}{"resourceType": "Claim", "id": "f-1"}},
+		{name: "ClaimResponse for another beneficiary", resourceType: "ClaimResponse",
+			resource:     map[string]interface{}{"resourceType": "ClaimResponse", "id": "f-1", "patient": map[string]interface{}{"identifier": mbiIdentifier("1S00E00AA02")}},
+			expectedCode: stu3.IssueTypeCodeProcessing, expectedErr: "ClaimResponse/f-1 is for a different beneficiary"},
+		{name: "missing required element", resourceType: "Coverage", checkProfile: true,
+			resource:     map[string]interface{}{"resourceType": "Coverage", "id": "part-a--1", "status": "active", "beneficiary": map[string]interface{}{"reference": "Patient/-1"}, "payor": []interface{}{}},
+			expectedCode: stu3.IssueTypeCodeStructure, expectedErr: "Coverage/part-a--1 is missing required element payor"},
+		{name: "required elements", resourceType: "Coverage", checkProfile: true,
+			resource: map[string]interface{}{"resourceType": "Coverage", "id": "part-a--1", "status": "active", "beneficiary": map[string]interface{}{"reference": "Patient/-1"},
+				"payor": []interface{}{map[string]interface{}{"identifier": map[string]interface{}{"value": "Centers for Medicare and Medicaid Services"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateResource(tt.resource, tt.resourceType, bene, tt.checkProfile)
+			if tt.expectedErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.expectedCode, err.code)
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestRejectInvalidEntries(t *testing.T) {
+	orig := conf.GetEnv("BFD_PROFILE_VALIDATION")
+	defer conf.SetEnv(t, "BFD_PROFILE_VALIDATION", orig)
+	conf.SetEnv(t, "BFD_PROFILE_VALIDATION", "false")
+
+	eob := func(id, patient string) fhirmodels.BundleEntry {
+		return fhirmodels.BundleEntry{"resource": map[string]interface{}{
+			"resourceType": "ExplanationOfBenefit", "id": id, "patient": map[string]interface{}{"reference": "Patient/" + patient},
+		}}
+	}
+	b := &fhirmodels.Bundle{Entries: []fhirmodels.BundleEntry{
+		eob("carrier-1", "-1"),
+		eob("carrier-2", "-2"),
+		{"resource": map[string]interface{}{"resourceType": "Coverage", "id": "part-a--1"}},
+		eob("carrier-3", "-1"),
+	}}
+
+	tmpDir := t.TempDir()
+	bene := models.CCLFBeneficiary{ID: 1, MBI: "1S00E00AA01", BlueButtonID: "-1"}
+	assert.Equal(t, 2, rejectInvalidEntries(context.Background(), b, "ExplanationOfBenefit", bene, "file", tmpDir))
+	require.Len(t, b.Entries, 2)
+	assert.Equal(t, "carrier-1", b.Entries[0]["resource"].(map[string]interface{})["id"])
+	assert.Equal(t, "carrier-3", b.Entries[1]["resource"].(map[string]interface{})["id"])
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "file-error.ndjson"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "Invalid ExplanationOfBenefit returned for beneficiary MBI 1S00E00AA01: ExplanationOfBenefit/carrier-2 is for a different beneficiary")
+	assert.Contains(t, lines[1], `unexpected resourceType \"Coverage\"`)
+}
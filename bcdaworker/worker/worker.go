@@ -12,10 +12,12 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 
 	"github.com/CMSgov/bcda-app/bcda/client"
 	bcdaErrs "github.com/CMSgov/bcda-app/bcda/errors"
+	"github.com/CMSgov/bcda-app/bcda/metrics"
 	"github.com/CMSgov/bcda-app/bcda/models"
 	fhirmodels "github.com/CMSgov/bcda-app/bcda/models/fhir"
 	"github.com/CMSgov/bcda-app/bcda/models/fhir/stu3"
@@ -138,6 +140,12 @@ func (w *worker) ProcessJob(ctx context.Context, queJobID int64, job models.Job,
 
 	jobKeys, err := writeBBDataToFile(ctx, w.r, bb, *aco.CMSID, queJobID, jobArgs, tempJobPath)
 
+	// BFD is unavailable, so leave the job in progress to be picked up again once the circuit closes
+	if circuitErr, ok := goerrors.AsType[*client.CircuitOpenError](err); ok {
+		logger.Warn(circuitErr)
+		return circuitErr
+	}
+
 	// This is only run AFTER completion of all the collection
 	if err != nil {
 		logger.Error(errors.Wrap(err, "ProcessJob: Error occurred when writing BFD Data to file"))
@@ -153,6 +161,7 @@ func (w *worker) ProcessJob(ctx context.Context, queJobID int64, job models.Job,
 			logger.Error(err)
 			return err
 		} else {
+			metrics.JobFinished(job.ACOID.String(), string(models.JobStatusFailed))
 			logger.Error("Job failed. Job ID: ", job.ID)
 		}
 	}
@@ -245,42 +254,55 @@ func writeBBDataToFile(ctx context.Context, r repository.Repository, bb client.A
 
 	logger := log.GetCtxLogger(ctx)
 
-	var bundleFunc func(bene models.CCLFBeneficiary) (*fhirmodels.Bundle, error)
+	// bundleFunc passes each page of the beneficiary's resources to fn as it is retrieved from BFD
+	var bundleFunc func(bene models.CCLFBeneficiary, fn client.PageHandler) error
 	// NOTE: Currently all Coverage/EOB/Patient requests are for adjudicated data and
 	// Claim/ClaimResponse are partially-adjudicated, future work may require checking what
 	// kind of backing data to pull from if there is overlap (one or more FHIR resource
 	// used for representing both adjudicated and partially-adjudicated data)
 	switch jobArgs.ResourceType {
 	case "Coverage":
-		bundleFunc = func(bene models.CCLFBeneficiary) (*fhirmodels.Bundle, error) {
-			return bb.GetCoverage(jobArgs, bene.BlueButtonID)
+		bundleFunc = func(bene models.CCLFBeneficiary, fn client.PageHandler) error {
+			return streamByPatientID(ctx, r, jobArgs, bene, fn, func(fn client.PageHandler) error {
+				return bb.StreamCoverage(ctx, jobArgs, bene.BlueButtonID, fn)
+			})
 		}
 	case "ExplanationOfBenefit":
-		bundleFunc = func(bene models.CCLFBeneficiary) (*fhirmodels.Bundle, error) {
-			cw := client.ClaimsWindow{
-				LowerBound: jobArgs.ClaimsWindow.LowerBound,
-				UpperBound: jobArgs.ClaimsWindow.UpperBound}
-			return bb.GetExplanationOfBenefit(jobArgs, bene.BlueButtonID, cw)
+		bundleFunc = func(bene models.CCLFBeneficiary, fn client.PageHandler) error {
+			windows := beneficiaryClaimsWindows(ctx, jobArgs, bene)
+			if len(windows) == 0 {
+				return nil
+			}
+			return streamByPatientID(ctx, r, jobArgs, bene, fn, func(fn client.PageHandler) error {
+				return streamClaimsWindows(windows, func(cw client.ClaimsWindow) error {
+					return bb.StreamExplanationOfBenefit(ctx, jobArgs, bene.BlueButtonID, cw, fn)
+				})
+			})
 		}
 	case "Patient":
-		bundleFunc = func(bene models.CCLFBeneficiary) (*fhirmodels.Bundle, error) {
-			return bb.GetPatient(jobArgs, bene.BlueButtonID)
+		bundleFunc = func(bene models.CCLFBeneficiary, fn client.PageHandler) error {
+			return streamByPatientID(ctx, r, jobArgs, bene, fn, func(fn client.PageHandler) error {
+				return bb.StreamPatient(ctx, jobArgs, bene.BlueButtonID, func(b *fhirmodels.Bundle) error {
+					if len(bene.LinkedMBIs) > 0 {
+						addMBIHistory(b, append([]string{bene.MBI}, bene.LinkedMBIs...))
+					}
+					return fn(b)
+				})
+			})
 		}
 		//NOTE: The assumption is Claim/ClaimResponse is always partially-adjudicated, future work may require checking what
 		//kind of backing data to pull from
 	case "Claim":
-		bundleFunc = func(bene models.CCLFBeneficiary) (*fhirmodels.Bundle, error) {
-			cw := client.ClaimsWindow{
-				LowerBound: jobArgs.ClaimsWindow.LowerBound,
-				UpperBound: jobArgs.ClaimsWindow.UpperBound}
-			return bb.GetClaim(jobArgs, bene.MBI, cw)
+		bundleFunc = func(bene models.CCLFBeneficiary, fn client.PageHandler) error {
+			return streamClaimsWindows(beneficiaryClaimsWindows(ctx, jobArgs, bene), func(cw client.ClaimsWindow) error {
+				return bb.StreamClaim(ctx, jobArgs, bene.MBI, cw, fn)
+			})
 		}
 	case "ClaimResponse":
-		bundleFunc = func(bene models.CCLFBeneficiary) (*fhirmodels.Bundle, error) {
-			cw := client.ClaimsWindow{
-				LowerBound: jobArgs.ClaimsWindow.LowerBound,
-				UpperBound: jobArgs.ClaimsWindow.UpperBound}
-			return bb.GetClaimResponse(jobArgs, bene.MBI, cw)
+		bundleFunc = func(bene models.CCLFBeneficiary, fn client.PageHandler) error {
+			return streamClaimsWindows(beneficiaryClaimsWindows(ctx, jobArgs, bene), func(cw client.ClaimsWindow) error {
+				return bb.StreamClaimResponse(ctx, jobArgs, bene.MBI, cw, fn)
+			})
 		}
 	default:
 		return jobKeys, fmt.Errorf("unsupported resource type requested: %s", jobArgs.ResourceType)
@@ -305,6 +327,11 @@ func writeBBDataToFile(ctx context.Context, r repository.Repository, bb client.A
 	failThreshold := utils.GetEnvFloat("EXPORT_FAIL_PCT", 100)
 	failed := false
 
+	samhsaOptOuts := make(map[string]struct{}, len(jobArgs.SAMHSAOptOutBeneficiaryIDs))
+	for _, beneID := range jobArgs.SAMHSAOptOutBeneficiaryIDs {
+		samhsaOptOuts[beneID] = struct{}{}
+	}
+
 	for _, beneID := range jobArgs.BeneficiaryIDs {
 		// if the parent job was cancelled, stop processing beneIDs and fail the job
 		if ctx.Err() == context.Canceled {
@@ -329,12 +356,37 @@ func writeBBDataToFile(ctx context.Context, r repository.Repository, bb client.A
 				return fmt.Sprintf("Error retrieving BlueButton ID for cclfBeneficiary MBI %s", bene.MBI), stu3.IssueTypeCodeNotFound, err
 			}
 
-			b, err := bundleFunc(bene)
+			// Pages are written as they arrive, so remember where this beneficiary's resources start in case a
+			// later page fails
+			start, err := f.Seek(0, io.SeekCurrent)
 			if err != nil {
+				return fmt.Sprintf("Error writing %s for beneficiary MBI %s in ACO %s", jobArgs.ResourceType, bene.MBI, jobArgs.ACOID), stu3.IssueTypeCodeException, err
+			}
+			start += int64(w.Buffered())
+
+			_, optedOut := samhsaOptOuts[beneID]
+			hadData, withheld := false, 0
+			err = bundleFunc(bene, func(b *fhirmodels.Bundle) error {
+				rejectInvalidEntries(ctx, b, jobArgs.ResourceType, bene, fileUUID, tmpDir)
+				// Withhold substance use disorder claims for beneficiaries who opted out of sharing them (42 CFR Part 2)
+				if optedOut {
+					withheld += removeSUDEntries(b)
+				}
+				if fhirBundleToResourceNDJSON(ctx, w, b, jobArgs.ResourceType, beneID, cmsID, fileUUID, tmpDir) {
+					hadData = true
+				}
+				return nil
+			})
+			if err != nil {
+				if truncErr := truncateFile(f, w, start); truncErr != nil {
+					logger.Errorf("Failed to discard partial %s data for cclfBeneficiaryId %s: %s", jobArgs.ResourceType, beneID, truncErr)
+				}
 				//MBI is appended inside file, not printed out to system logs
 				return fmt.Sprintf("Error retrieving %s for beneficiary MBI %s in ACO %s", jobArgs.ResourceType, bene.MBI, jobArgs.ACOID), stu3.IssueTypeCodeNotFound, err
 			}
-			hadData := fhirBundleToResourceNDJSON(ctx, w, b, jobArgs.ResourceType, beneID, cmsID, fileUUID, tmpDir)
+			if withheld > 0 {
+				logger.Infof("Withheld %d %s resources for cclfBeneficiaryId %s due to SAMHSA preferences", withheld, jobArgs.ResourceType, beneID)
+			}
 			if hadData {
 				benesWithDataCount++
 			}
@@ -342,6 +394,10 @@ func writeBBDataToFile(ctx context.Context, r repository.Repository, bb client.A
 			return "", stu3.IssueTypeCode(""), nil
 		}()
 
+		if circuitErr, ok := goerrors.AsType[*client.CircuitOpenError](err); ok {
+			// Every remaining beneficiary would fail the same way, so stop and let the job be retried later
+			return jobKeys, circuitErr
+		}
 		if err != nil {
 			if reqErr, ok := goerrors.AsType[*bcdaErrs.RequestedBeneficiaryNotFoundError](err); ok {
 				logger.Warn(reqErr)
@@ -400,6 +456,69 @@ func writeBBDataToFile(ctx context.Context, r repository.Repository, bb client.A
 	return jobKeys, nil
 }
 
+// beneficiaryClaimsWindows narrows the job's claims window to each period the beneficiary was attributed to the
+// entity, so claims from the gaps between periods are not requested. Periods that overlap or adjoin are
+// requested as one window. No windows are returned when no period overlaps the job's claims window.
+func beneficiaryClaimsWindows(ctx context.Context, jobArgs worker_types.JobEnqueueArgs, bene models.CCLFBeneficiary) []client.ClaimsWindow {
+	jobWindow := client.ClaimsWindow{
+		LowerBound: jobArgs.ClaimsWindow.LowerBound,
+		UpperBound: jobArgs.ClaimsWindow.UpperBound,
+	}
+
+	periods := bene.AttributionPeriods
+	if len(periods) == 0 {
+		periods = []models.AttributionPeriod{{Start: bene.AttributionStart, End: bene.AttributionEnd}}
+	}
+
+	var windows []client.ClaimsWindow
+	for _, p := range mergeAttributionPeriods(periods) {
+		if cw := jobWindow.ForAttributionPeriod(p.Start, p.End); !cw.IsEmpty() {
+			windows = append(windows, cw)
+		}
+	}
+	if len(windows) == 0 {
+		log.GetCtxLogger(ctx).Infof("Skipping %s request for cclfBeneficiaryId %d; attribution period is outside of the claims window", jobArgs.ResourceType, bene.ID)
+	}
+	return windows
+}
+
+// mergeAttributionPeriods returns periods sorted by start with those that overlap or adjoin combined. Attribution
+// dates are inclusive, so a period starting the day after another ends continues it.
+func mergeAttributionPeriods(periods []models.AttributionPeriod) []models.AttributionPeriod {
+	sorted := slices.Clone(periods)
+	slices.SortFunc(sorted, func(a, b models.AttributionPeriod) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	var merged []models.AttributionPeriod
+	for _, p := range sorted {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if last.End.IsZero() {
+				continue
+			}
+			if !p.Start.After(last.End.AddDate(0, 0, 1)) {
+				if p.End.IsZero() || p.End.After(last.End) {
+					last.End = p.End
+				}
+				continue
+			}
+		}
+		merged = append(merged, p)
+	}
+	return merged
+}
+
+// streamClaimsWindows runs search for each of windows in turn.
+func streamClaimsWindows(windows []client.ClaimsWindow, search func(client.ClaimsWindow) error) error {
+	for _, cw := range windows {
+		if err := search(cw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // getBeneficiary returns the beneficiary. The bb ID value is retrieved and set in the model.
 func getBeneficiary(ctx context.Context, r repository.Repository, beneID uint, bb client.APIClient, fetchBBId bool, jobData worker_types.JobEnqueueArgs) (models.CCLFBeneficiary, error) {
 	bene, err := r.GetCCLFBeneficiaryByID(ctx, beneID)
@@ -411,10 +530,11 @@ func getBeneficiary(ctx context.Context, r repository.Repository, beneID uint, b
 	cclfBeneficiary := *bene
 
 	if fetchBBId {
-		bbID, err := getBlueButtonID(bb, cclfBeneficiary.MBI, jobData)
+		bbID, linkedMBIs, err := lookupBlueButtonID(ctx, r, bb, cclfBeneficiary.MBI, jobData)
 		if err != nil {
 			return cclfBeneficiary, err
 		}
+		cclfBeneficiary.LinkedMBIs = linkedMBIs
 
 		if cclfBeneficiary.BlueButtonID != bbID {
 			err = r.UpdateCCLFBeneficiaryBlueButtonID(ctx, beneID, bbID)
@@ -423,6 +543,13 @@ func getBeneficiary(ctx context.Context, r repository.Repository, beneID uint, b
 			}
 			cclfBeneficiary.BlueButtonID = bbID
 		}
+	} else {
+		// Partially adjudicated claims may identify the beneficiary by any of their linked MBIs
+		linkedMBIs, err := r.GetLinkedMBIs(ctx, cclfBeneficiary.MBI)
+		if err != nil {
+			log.GetCtxLogger(ctx).Warnf("failed to retrieve linked MBIs for cclfBeneficiaryId %d: %s", beneID, err)
+		}
+		cclfBeneficiary.LinkedMBIs = linkedMBIs
 	}
 
 	return cclfBeneficiary, nil
@@ -490,6 +617,18 @@ func fhirBundleToResourceNDJSON(ctx context.Context, w *bufio.Writer, b *fhirmod
 	return hasAtLeastOneEntry
 }
 
+// truncateFile discards everything written to f through w after offset.
+func truncateFile(f *os.File, w *bufio.Writer, offset int64) error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := f.Seek(offset, io.SeekStart)
+	return err
+}
+
 func CheckJobCompleteAndCleanup(ctx context.Context, r repository.Repository, jobID uint) (jobCompleted bool, err error) {
 	logger := log.GetCtxLogger(ctx)
 	j, err := r.GetJobByID(ctx, jobID)
@@ -545,6 +684,7 @@ func CheckJobCompleteAndCleanup(ctx context.Context, r repository.Repository, jo
 			err = errors.Wrap(err, fmt.Sprintf("Error updating the job status to %s for job id %d", models.JobStatusCompleted, j.ID))
 			return false, err
 		}
+		metrics.JobFinished(j.ACOID.String(), string(models.JobStatusCompleted))
 		// Able to mark job as completed
 		return true, nil
 
@@ -7,12 +7,14 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"math"
 	"math/big"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -23,6 +25,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/CMSgov/bcda-app/bcda/client"
@@ -61,6 +64,8 @@ type WorkerTestSuite struct {
 	w  Worker
 
 	logctx context.Context
+
+	origPatientIDCacheTTL string
 }
 
 func (s *WorkerTestSuite) SetupSuite() {
@@ -89,6 +94,9 @@ func (s *WorkerTestSuite) SetupSuite() {
 	conf.SetEnv(s.T(), "BB_CLIENT_CERT_FILE", "../../shared_files/decrypted/bfd-dev-test-cert.pem")
 	conf.SetEnv(s.T(), "BB_CLIENT_KEY_FILE", "../../shared_files/decrypted/bfd-dev-test-key.pem")
 	conf.SetEnv(s.T(), "BB_CLIENT_CA_FILE", "../../shared_files/localhost.crt")
+	// Tests mock BFD lookups per MBI, so cached patient IDs must not carry over between them
+	s.origPatientIDCacheTTL = conf.GetEnv("BFD_PATIENT_ID_CACHE_TTL_HOURS")
+	conf.SetEnv(s.T(), "BFD_PATIENT_ID_CACHE_TTL_HOURS", "0")
 
 	// Set up the logger since we're using the real client
 	client.SetLogger(log.BFDWorker)
@@ -138,6 +146,7 @@ func (s *WorkerTestSuite) TearDownSuite() {
 	os.RemoveAll(conf.GetEnv("FHIR_STAGING_DIR"))
 	os.RemoveAll(conf.GetEnv("FHIR_PAYLOAD_DIR"))
 	os.RemoveAll(conf.GetEnv("FHIR_TEMP_DIR"))
+	conf.SetEnv(s.T(), "BFD_PATIENT_ID_CACHE_TTL_HOURS", s.origPatientIDCacheTTL)
 
 	// Reset worker logger to original logger
 	log.Worker = oldLogger
@@ -197,7 +206,7 @@ func (s *WorkerTestSuite) TestGetBlueButtonID_NonHappyPaths() {
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
 			mockCall := bbc.On("GetPatientByMbi", cclfBeneficiary.MBI).Return(tt.patientJSON, nil)
-			bbID, err := getBlueButtonID(bbc, beneficiaryID, jobArgs)
+			bbID, err := getBlueButtonID(context.Background(), bbc, beneficiaryID, jobArgs)
 			if tt.err != nil {
 				assert.Error(s.T(), err)
 				assert.Equal(s.T(), fmt.Sprint(tt.err), fmt.Sprint(err))
@@ -1140,3 +1149,256 @@ func cryptoRandInt64() (int64, error) {
 	}
 	return n.Int64(), nil
 }
+
+func TestBeneficiaryClaimsWindows(t *testing.T) {
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC) }
+	jobArgs := worker_types.JobEnqueueArgs{ResourceType: "ExplanationOfBenefit"}
+	jobArgs.ClaimsWindow.LowerBound = date(time.January, 1)
+	jobArgs.ClaimsWindow.UpperBound = date(time.December, 31)
+	jobWindow := client.ClaimsWindow{LowerBound: jobArgs.ClaimsWindow.LowerBound, UpperBound: jobArgs.ClaimsWindow.UpperBound}
+
+	tests := []struct {
+		name     string
+		bene     models.CCLFBeneficiary
+		expected []client.ClaimsWindow
+	}{
+		{"no attribution period", models.CCLFBeneficiary{}, []client.ClaimsWindow{jobWindow}},
+		{"row's own period", models.CCLFBeneficiary{AttributionStart: date(time.April, 1)},
+			[]client.ClaimsWindow{{LowerBound: date(time.April, 1), UpperBound: jobWindow.UpperBound}}},
+		{"attribution ended before the claims window", models.CCLFBeneficiary{AttributionEnd: time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC)}, nil},
+		{"separate periods", models.CCLFBeneficiary{AttributionPeriods: []models.AttributionPeriod{
+			{Start: date(time.September, 1), End: date(time.October, 31)},
+			{Start: date(time.February, 1), End: date(time.March, 31)},
+		}}, []client.ClaimsWindow{
+			{LowerBound: date(time.February, 1), UpperBound: date(time.March, 31)},
+			{LowerBound: date(time.September, 1), UpperBound: date(time.October, 31)},
+		}},
+		{"overlapping and adjoining periods", models.CCLFBeneficiary{AttributionPeriods: []models.AttributionPeriod{
+			{Start: date(time.February, 1), End: date(time.March, 31)},
+			{Start: date(time.March, 1), End: date(time.April, 30)},
+			{Start: date(time.May, 1), End: date(time.May, 31)},
+		}}, []client.ClaimsWindow{{LowerBound: date(time.February, 1), UpperBound: date(time.May, 31)}}},
+		{"open-ended period", models.CCLFBeneficiary{AttributionPeriods: []models.AttributionPeriod{
+			{Start: date(time.February, 1)},
+			{Start: date(time.June, 1), End: date(time.June, 30)},
+		}}, []client.ClaimsWindow{{LowerBound: date(time.February, 1), UpperBound: jobWindow.UpperBound}}},
+		{"only periods inside the claims window", models.CCLFBeneficiary{AttributionPeriods: []models.AttributionPeriod{
+			{Start: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)},
+			{Start: date(time.June, 1), End: time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)},
+		}}, []client.ClaimsWindow{{LowerBound: date(time.June, 1), UpperBound: jobWindow.UpperBound}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, beneficiaryClaimsWindows(context.Background(), jobArgs, tt.bene))
+		})
+	}
+}
+
+func TestWriteBBDataToFileAttributionPeriods(t *testing.T) {
+	date := func(month time.Month, day int) time.Time { return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC) }
+	claim := func(id string) *fhirmodels.Bundle {
+		return &fhirmodels.Bundle{Entries: []fhirmodels.BundleEntry{
+			{"resource": map[string]interface{}{"resourceType": "Claim", "id": id}},
+		}}
+	}
+	// The last inserted row is the one enqueued, but the beneficiary was also aligned earlier in the year
+	bene := models.CCLFBeneficiary{ID: 2, MBI: "1S00E00AA01", AttributionStart: date(time.September, 1), AttributionEnd: date(time.October, 31),
+		AttributionPeriods: []models.AttributionPeriod{
+			{Start: date(time.February, 1), End: date(time.March, 31)},
+			{Start: date(time.September, 1), End: date(time.October, 31)},
+		}}
+	early := client.ClaimsWindow{LowerBound: date(time.February, 1), UpperBound: date(time.March, 31)}
+	late := client.ClaimsWindow{LowerBound: date(time.September, 1), UpperBound: date(time.October, 31)}
+
+	r := &repository.MockRepository{}
+	r.On("GetCCLFBeneficiaryByID", mock.Anything, bene.ID).Return(&bene, nil)
+	r.On("GetLinkedMBIs", mock.Anything, bene.MBI).Return(nil, nil)
+	bbc := &client.MockBlueButtonClient{}
+	bbc.On("GetClaim", mock.Anything, bene.MBI, early).Return(claim("f-1"), nil).Once()
+	bbc.On("GetClaim", mock.Anything, bene.MBI, late).Return(claim("f-2"), nil).Once()
+
+	tmpDir := t.TempDir()
+	jobArgs := worker_types.JobEnqueueArgs{ID: 1, ResourceType: "Claim", BeneficiaryIDs: []string{"2"}, BBBasePath: constants.TestFHIRPath}
+	jobArgs.ClaimsWindow.LowerBound = date(time.January, 1)
+	jobArgs.ClaimsWindow.UpperBound = date(time.December, 31)
+	jobKeys, err := writeBBDataToFile(context.Background(), r, bbc, "A0000", 1, jobArgs, tmpDir)
+	require.NoError(t, err)
+	require.Len(t, jobKeys, 1)
+
+	// Each period is requested on its own, so claims from the gap between them are not
+	bbc.AssertExpectations(t)
+	bbc.AssertNumberOfCalls(t, "GetClaim", 2)
+	data, err := os.ReadFile(filepath.Join(tmpDir, jobKeys[0].FileName))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "f-1")
+	assert.Contains(t, lines[1], "f-2")
+}
+
+// pagedBlueButtonClient streams each patient's EOB pages in turn and then fails for the patients in fail.
+type pagedBlueButtonClient struct {
+	*client.MockBlueButtonClient
+	pages map[string][]*fhirmodels.Bundle
+	fail  map[string]error
+}
+
+func (c *pagedBlueButtonClient) StreamExplanationOfBenefit(ctx context.Context, jobData worker_types.JobEnqueueArgs, patientID string, cw client.ClaimsWindow, fn client.PageHandler) error {
+	for _, page := range c.pages[patientID] {
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+	return c.fail[patientID]
+}
+
+func TestWriteBBDataToFilePages(t *testing.T) {
+	orig := conf.GetEnv("BFD_PATIENT_ID_CACHE_TTL_HOURS")
+	defer conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", orig)
+	conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", "0")
+
+	eob := func(patientID, id string) *fhirmodels.Bundle {
+		return &fhirmodels.Bundle{Entries: []fhirmodels.BundleEntry{
+			{"resource": map[string]interface{}{"resourceType": "ExplanationOfBenefit", "id": id,
+				"patient": map[string]interface{}{"reference": "Patient/" + patientID}}},
+		}}
+	}
+	benes := []models.CCLFBeneficiary{
+		{ID: 1, MBI: "1S00E00AA01", BlueButtonID: "-1"},
+		{ID: 2, MBI: "1S00E00AA02", BlueButtonID: "-2"},
+	}
+
+	r := &repository.MockRepository{}
+	bbc := &client.MockBlueButtonClient{}
+	for _, bene := range benes {
+		r.On("GetCCLFBeneficiaryByID", mock.Anything, bene.ID).Return(&bene, nil)
+		bbc.On("GetPatientByMbi", bene.MBI).Return(patientByMBI(bene.BlueButtonID, bene.MBI), nil)
+	}
+	paged := &pagedBlueButtonClient{
+		MockBlueButtonClient: bbc,
+		pages: map[string][]*fhirmodels.Bundle{
+			"-1": {eob("-1", "carrier-1"), eob("-1", "carrier-2")},
+			"-2": {eob("-2", "carrier-3")},
+		},
+		fail: map[string]error{"-2": errors.New("blue button request failed 3 time(s)")},
+	}
+
+	tmpDir := t.TempDir()
+	jobArgs := worker_types.JobEnqueueArgs{ID: 1, ResourceType: "ExplanationOfBenefit", BeneficiaryIDs: []string{"1", "2"}, BBBasePath: constants.TestFHIRPath}
+	jobKeys, err := writeBBDataToFile(context.Background(), r, paged, "A0000", 1, jobArgs, tmpDir)
+	require.NoError(t, err)
+	require.Len(t, jobKeys, 2)
+	assert.Contains(t, jobKeys[1].FileName, "-error.ndjson")
+
+	// Every page of the first beneficiary is written and the partial data for the failed one is discarded
+	data, err := os.ReadFile(filepath.Join(tmpDir, jobKeys[0].FileName))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "carrier-1")
+	assert.Contains(t, lines[1], "carrier-2")
+
+	errData, err := os.ReadFile(filepath.Join(tmpDir, jobKeys[1].FileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(errData), "Error retrieving ExplanationOfBenefit for beneficiary MBI 1S00E00AA02")
+}
+
+func TestWriteBBDataToFilePatientIDCache(t *testing.T) {
+	orig := conf.GetEnv("BFD_PATIENT_ID_CACHE_TTL_HOURS")
+	defer conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", orig)
+	conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", "24")
+
+	eob := &fhirmodels.Bundle{Entries: []fhirmodels.BundleEntry{
+		{"resource": map[string]interface{}{"resourceType": "ExplanationOfBenefit", "id": "carrier-1",
+			"patient": map[string]interface{}{"reference": "Patient/-1"}}},
+	}}
+	benes := []models.CCLFBeneficiary{
+		{ID: 1, MBI: "1S00E00AA01", BlueButtonID: "-1"},
+		{ID: 2, MBI: "1S00E00AA02", BlueButtonID: "-2"},
+	}
+
+	r := &repository.MockRepository{}
+	for _, bene := range benes {
+		r.On("GetCCLFBeneficiaryByID", mock.Anything, bene.ID).Return(&bene, nil)
+		// Both patient IDs come from the cache, so BFD is not searched by MBI
+		r.On("GetBFDPatientID", mock.Anything, bene.MBI, constants.TestFHIRPath, 24*time.Hour).Return(bene.BlueButtonID, nil)
+	}
+	// The second patient ID finds nothing, so it is removed for the next job to look up again
+	r.On("DeleteBFDPatientID", mock.Anything, benes[1].MBI, constants.TestFHIRPath).Return(nil)
+
+	bbc := &client.MockBlueButtonClient{}
+	paged := &pagedBlueButtonClient{
+		MockBlueButtonClient: bbc,
+		pages:                map[string][]*fhirmodels.Bundle{"-1": {eob}},
+	}
+
+	jobArgs := worker_types.JobEnqueueArgs{ID: 1, ResourceType: "ExplanationOfBenefit", BeneficiaryIDs: []string{"1", "2"}, BBBasePath: constants.TestFHIRPath}
+	jobKeys, err := writeBBDataToFile(context.Background(), r, paged, "A0000", 1, jobArgs, t.TempDir())
+	require.NoError(t, err)
+	assert.Len(t, jobKeys, 1)
+	bbc.AssertNotCalled(t, "GetPatientByMbi", mock.Anything)
+	r.AssertExpectations(t)
+	r.AssertNotCalled(t, "DeleteBFDPatientID", mock.Anything, benes[0].MBI, mock.Anything)
+}
+
+func TestWriteBBDataToFileClaimLinkedMBIs(t *testing.T) {
+	claim := func(id, mbi string) fhirmodels.BundleEntry {
+		return fhirmodels.BundleEntry{"resource": map[string]interface{}{"resourceType": "Claim", "id": id,
+			"patient": map[string]interface{}{"identifier": map[string]interface{}{"system": constants.MBISystem, "value": mbi}}}}
+	}
+	bene := models.CCLFBeneficiary{ID: 1, MBI: "1S00E00AA01"}
+
+	r := &repository.MockRepository{}
+	r.On("GetCCLFBeneficiaryByID", mock.Anything, bene.ID).Return(&bene, nil)
+	r.On("GetLinkedMBIs", mock.Anything, bene.MBI).Return([]string{"1S00E00AA00"}, nil)
+	bbc := &client.MockBlueButtonClient{}
+	bbc.On("GetClaim", mock.Anything, bene.MBI, mock.Anything).Return(&fhirmodels.Bundle{Entries: []fhirmodels.BundleEntry{
+		claim("f-1", bene.MBI), claim("f-2", "1S00E00AA00"), claim("f-3", "1S00E00AA02"),
+	}}, nil)
+
+	// Claims under a linked MBI are kept and only the claim for another beneficiary is rejected
+	tmpDir := t.TempDir()
+	jobArgs := worker_types.JobEnqueueArgs{ID: 1, ResourceType: "Claim", BeneficiaryIDs: []string{"1"}, BBBasePath: constants.TestFHIRPath}
+	jobKeys, err := writeBBDataToFile(context.Background(), r, bbc, "A0000", 1, jobArgs, tmpDir)
+	require.NoError(t, err)
+	require.Len(t, jobKeys, 1)
+	data, err := os.ReadFile(filepath.Join(tmpDir, jobKeys[0].FileName))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "f-1")
+	assert.Contains(t, lines[1], "f-2")
+
+	errData, err := os.ReadFile(filepath.Join(tmpDir, strings.TrimSuffix(jobKeys[0].FileName, ".ndjson")+"-error.ndjson"))
+	require.NoError(t, err)
+	assert.Contains(t, string(errData), "Claim/f-3 is for a different beneficiary")
+}
+
+func TestWriteBBDataToFileCircuitOpen(t *testing.T) {
+	orig := conf.GetEnv("BFD_PATIENT_ID_CACHE_TTL_HOURS")
+	defer conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", orig)
+	conf.SetEnv(t, "BFD_PATIENT_ID_CACHE_TTL_HOURS", "0")
+
+	bene := models.CCLFBeneficiary{ID: 1, MBI: "1S00E00AA01", BlueButtonID: "-1"}
+	r := &repository.MockRepository{}
+	r.On("GetCCLFBeneficiaryByID", mock.Anything, bene.ID).Return(&bene, nil)
+	bbc := &client.MockBlueButtonClient{}
+	bbc.On("GetPatientByMbi", bene.MBI).Return(patientByMBI(bene.BlueButtonID, bene.MBI), nil)
+	paged := &pagedBlueButtonClient{
+		MockBlueButtonClient: bbc,
+		fail:                 map[string]error{"-1": &client.CircuitOpenError{RetryAfter: time.Minute}},
+	}
+
+	// The remaining beneficiaries are not requested and no errors are recorded against them
+	tmpDir := t.TempDir()
+	jobArgs := worker_types.JobEnqueueArgs{ID: 1, ResourceType: "ExplanationOfBenefit", BeneficiaryIDs: []string{"1", "2"}, BBBasePath: constants.TestFHIRPath}
+	_, err := writeBBDataToFile(context.Background(), r, paged, "A0000", 1, jobArgs, tmpDir)
+	circuitErr, ok := goerrors.AsType[*client.CircuitOpenError](err)
+	require.True(t, ok, "expected circuit open error, got %v", err)
+	assert.Equal(t, time.Minute, circuitErr.RetryAfter)
+	r.AssertNotCalled(t, "GetCCLFBeneficiaryByID", mock.Anything, uint(2))
+
+	files, err := filepath.Glob(filepath.Join(tmpDir, "*-error.ndjson"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -0,0 +1,8 @@
+-- Drop job key download records
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_job_key_downloads_aco_id_created_at;
+DROP TABLE IF EXISTS public.job_key_downloads;
+
+COMMIT;
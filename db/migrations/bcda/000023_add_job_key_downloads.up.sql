@@ -0,0 +1,18 @@
+-- Record each download of a job's output file so ACOs can audit their own API activity
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.job_key_downloads (
+    id serial PRIMARY KEY,
+    job_id integer NOT NULL,
+    aco_id uuid NOT NULL,
+    file_name text NOT NULL,
+    resource_type text,
+    client_id text,
+    request_id text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_key_downloads_aco_id_created_at ON public.job_key_downloads USING btree (aco_id, created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Remove the client certificate identity from acos
+BEGIN;
+
+DROP INDEX IF EXISTS idx_acos_client_cert_subject;
+ALTER TABLE public.acos DROP COLUMN IF EXISTS client_cert_subject;
+
+COMMIT;
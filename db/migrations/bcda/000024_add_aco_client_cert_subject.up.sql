@@ -0,0 +1,7 @@
+-- Add the client certificate identity used to map mutual TLS connections to an ACO
+BEGIN;
+
+ALTER TABLE public.acos ADD COLUMN client_cert_subject text;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_acos_client_cert_subject ON public.acos USING btree (client_cert_subject);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Remove credential rotation tracking
+
+BEGIN;
+
+DROP TABLE IF EXISTS public.credential_rotations;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- Track rotated client credentials so the previous credentials remain valid for a grace window
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.credential_rotations (
+    id serial PRIMARY KEY,
+    aco_id uuid NOT NULL,
+    previous_client_id text NOT NULL,
+    previous_system_id text NOT NULL,
+    client_id text NOT NULL,
+    system_id text NOT NULL,
+    grace_expires_at timestamp with time zone NOT NULL,
+    secret_expires_at timestamp with time zone,
+    revoked_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_credential_rotations_client_id ON public.credential_rotations USING btree (client_id);
+CREATE INDEX IF NOT EXISTS idx_credential_rotations_previous_client_id ON public.credential_rotations USING btree (previous_client_id);
+
+COMMIT;
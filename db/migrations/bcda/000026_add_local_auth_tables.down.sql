@@ -0,0 +1,8 @@
+-- Remove the local auth provider tables
+
+BEGIN;
+
+DROP TABLE IF EXISTS public.auth_revoked_tokens;
+DROP TABLE IF EXISTS public.auth_systems;
+
+COMMIT;
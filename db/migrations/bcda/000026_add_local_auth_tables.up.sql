@@ -0,0 +1,27 @@
+-- Store systems and revoked tokens for the local auth provider, used when BCDA runs without SSAS
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.auth_systems (
+    id serial PRIMARY KEY,
+    aco_id uuid NOT NULL,
+    client_id text NOT NULL,
+    client_name text NOT NULL,
+    secret_hash text NOT NULL,
+    public_key text,
+    ips text,
+    secret_expires_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp with time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_systems_client_id ON public.auth_systems USING btree (client_id);
+
+CREATE TABLE IF NOT EXISTS public.auth_revoked_tokens (
+    token_id text PRIMARY KEY,
+    expires_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMIT;
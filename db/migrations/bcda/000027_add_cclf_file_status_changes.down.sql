@@ -0,0 +1,7 @@
+-- Remove the attribution file status change history
+
+BEGIN;
+
+DROP TABLE IF EXISTS public.cclf_file_status_changes;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Record operators superseding, invalidating or restoring imported attribution files
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.cclf_file_status_changes (
+    id serial PRIMARY KEY,
+    file_id integer NOT NULL REFERENCES public.cclf_files(id) ON DELETE CASCADE,
+    previous_status text NOT NULL,
+    status text NOT NULL,
+    reason text NOT NULL,
+    changed_by text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_cclf_file_status_changes_file_id ON public.cclf_file_status_changes USING btree (file_id);
+
+COMMIT;
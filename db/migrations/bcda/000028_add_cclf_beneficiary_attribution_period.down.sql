@@ -0,0 +1,9 @@
+-- Remove the beneficiary attribution period
+
+BEGIN;
+
+ALTER TABLE public.cclf_beneficiaries
+    DROP COLUMN IF EXISTS attribution_start,
+    DROP COLUMN IF EXISTS attribution_end;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Store the period each beneficiary was aligned to the entity when the attribution file includes it
+
+BEGIN;
+
+ALTER TABLE public.cclf_beneficiaries
+    ADD COLUMN IF NOT EXISTS attribution_start date,
+    ADD COLUMN IF NOT EXISTS attribution_end date;
+
+COMMIT;
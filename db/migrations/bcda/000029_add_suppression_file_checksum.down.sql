@@ -0,0 +1,7 @@
+-- Remove the bene-prefs file checksum
+
+BEGIN;
+
+ALTER TABLE public.suppression_files DROP COLUMN IF EXISTS checksum;
+
+COMMIT;
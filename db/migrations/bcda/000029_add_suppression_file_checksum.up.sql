@@ -0,0 +1,7 @@
+-- Record a checksum of each bene-prefs file so a corrected delivery can be told apart from a re-run of the same file
+
+BEGIN;
+
+ALTER TABLE public.suppression_files ADD COLUMN IF NOT EXISTS checksum text;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Remove the MBI crosswalk
+
+BEGIN;
+
+DROP TABLE IF EXISTS public.mbi_links;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Track every MBI seen for a beneficiary in bene-prefs files so lookups can follow an MBI that CMS has reissued
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.mbi_links (
+    beneficiary_link_key integer NOT NULL,
+    mbi character varying(11) NOT NULL,
+    last_seen timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (beneficiary_link_key, mbi)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mbi_links_mbi ON public.mbi_links USING btree (mbi);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Remove the BFD patient ID cache
+
+BEGIN;
+
+DROP TABLE IF EXISTS public.bfd_patient_ids;
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- Cache the BFD patient ID each MBI resolves to so exports do not look it up for every job
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.bfd_patient_ids (
+    mbi character varying(11) NOT NULL,
+    bfd_base_path text NOT NULL,
+    patient_id text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (mbi, bfd_base_path)
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Remove the worker BFD request stats
+
+BEGIN;
+
+DROP TABLE IF EXISTS public.bfd_health_stats;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Rolling BFD request stats reported by each worker so the API health check can show them
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.bfd_health_stats (
+    instance text NOT NULL,
+    base_path text NOT NULL,
+    requests integer NOT NULL,
+    error_rate double precision NOT NULL,
+    p50_ms double precision NOT NULL,
+    p95_ms double precision NOT NULL,
+    last_success timestamp with time zone,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (instance, base_path)
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Remove the download sizes
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_job_key_downloads_created_at;
+ALTER TABLE public.job_key_downloads DROP COLUMN IF EXISTS bytes;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Record the size of each download so usage reports can show how much data ACOs retrieve
+
+BEGIN;
+
+ALTER TABLE public.job_key_downloads ADD COLUMN bytes bigint;
+
+CREATE INDEX IF NOT EXISTS idx_job_key_downloads_created_at ON public.job_key_downloads USING btree (created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Remove the sent alert tracking
+
+BEGIN;
+
+DROP TABLE IF EXISTS public.alert_notifications;
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- When each alert was last sent, so workers don't repeat an alert that is still firing on every evaluation
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS public.alert_notifications (
+    alert_id text PRIMARY KEY,
+    notified_at timestamp with time zone NOT NULL
+);
+
+COMMIT;
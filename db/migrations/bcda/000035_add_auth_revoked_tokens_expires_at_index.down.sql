@@ -0,0 +1,7 @@
+-- Remove the revoked token expiry index
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_auth_revoked_tokens_expires_at;
+
+COMMIT;
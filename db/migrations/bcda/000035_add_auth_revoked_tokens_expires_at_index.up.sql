@@ -0,0 +1,7 @@
+-- Index the revoked token expiry so expired revocations can be pruned
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_auth_revoked_tokens_expires_at ON public.auth_revoked_tokens USING btree (expires_at);
+
+COMMIT;
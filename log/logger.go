@@ -54,6 +54,7 @@ func newLogger() *logrus.Logger {
 		TimestampFormat:   time.RFC3339Nano,
 	})
 	logger.SetReportCaller(true)
+	logger.AddHook(newRedactHook())
 	return logger
 }
 
@@ -65,6 +66,7 @@ func defaultFieldLogger(logType string) logrus.FieldLogger {
 		TimestampFormat:   time.RFC3339Nano,
 	})
 	logger.SetReportCaller(true)
+	logger.AddHook(newRedactHook())
 	fields := defaultFields("default")
 	fields["log_type"] = logType
 	return logger.WithFields(fields)
@@ -83,7 +85,7 @@ func defaultFields(application string) logrus.Fields {
 func NewSlogLogger(application string) *slog.Logger {
 	logrusLogger := newLogger()
 	handler := sloglogrus.Option{Logger: logrusLogger}.NewLogrusHandler()
-	return slogLoggerFromHandler(handler, application)
+	return slogLoggerFromHandler(newRedactHandler(handler), application)
 }
 
 func slogLoggerFromHandler(handler slog.Handler, application string) *slog.Logger {
@@ -0,0 +1,257 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/CMSgov/bcda-app/conf"
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "<redacted>"
+
+var phiPatterns = []*regexp.Regexp{
+	// MBI, matching the format checked when importing attribution files
+	regexp.MustCompile(`\b[1-9][AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9][0-9][AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9][0-9][AC-HJKMNP-RT-Y]{2}[0-9]{2}\b`),
+	// HICN: a nine digit SSN followed by a one or two character beneficiary identification code
+	regexp.MustCompile(`\b[0-9]{9}[A-Z][A-Z0-9]?\b`),
+	// Bearer tokens, in headers or URL encoded in query strings
+	regexp.MustCompile(`(?i)\bBearer(?:\s+|%20)[\w\-.~+/=]+`),
+	// JWTs
+	regexp.MustCompile(`\beyJ[\w-]+\.[\w-]+\.[\w-]*`),
+}
+
+// RedactPHI masks every MBI, HICN, and bearer token or JWT in s.
+func RedactPHI(s string) string {
+	for _, p := range phiPatterns {
+		s = p.ReplaceAllString(s, redacted)
+	}
+	return s
+}
+
+func containsPHI(s string) bool {
+	for _, p := range phiPatterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue returns v with any PHI masked, and whether it contained PHI. String slices keep their type;
+// any other value that isn't a number or bool, such as a struct, map or fmt.Stringer, is checked as it would
+// be formatted and replaced by its masked formatted value.
+func redactValue(v any) (any, bool) {
+	switch val := v.(type) {
+	case string:
+		if containsPHI(val) {
+			return RedactPHI(val), true
+		}
+	case error:
+		if msg := val.Error(); containsPHI(msg) {
+			return RedactPHI(msg), true
+		}
+	case []string:
+		var found bool
+		masked := make([]string, len(val))
+		for i, s := range val {
+			masked[i] = s
+			if containsPHI(s) {
+				masked[i], found = RedactPHI(s), true
+			}
+		}
+		if found {
+			return masked, true
+		}
+	case fmt.Stringer:
+		if s := val.String(); containsPHI(s) {
+			return RedactPHI(s), true
+		}
+	default:
+		if v == nil || isScalar(v) {
+			break
+		}
+		if s := fmt.Sprint(v); containsPHI(s) {
+			return RedactPHI(s), true
+		}
+	}
+	return v, false
+}
+
+func isScalar(v any) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// strictRedaction reports whether loggers should record, as well as mask, each entry that contains PHI. It is
+// meant for test runs, which fail through RunTests when code logs identifiers.
+func strictRedaction() bool {
+	return strings.EqualFold(conf.GetEnv("LOG_STRICT_REDACTION"), "true")
+}
+
+// maxUnmaskedPHI bounds how many findings are kept between calls to UnmaskedPHI; the rest are only counted.
+const maxUnmaskedPHI = 100
+
+// unmasked collects where PHI was found by loggers in strict mode. Panicking instead would crash whichever
+// goroutine happened to log, often outside of any test, and lose the rest of the run.
+var unmasked struct {
+	sync.Mutex
+	found   []string
+	dropped int
+}
+
+func unmaskedPHI(where string) {
+	unmasked.Lock()
+	defer unmasked.Unlock()
+	if len(unmasked.found) == maxUnmaskedPHI {
+		unmasked.dropped++
+		return
+	}
+	unmasked.found = append(unmasked.found, fmt.Sprintf("unmasked PHI logged in %s", where))
+}
+
+// UnmaskedPHI returns, and clears, the places PHI was found in log entries written by loggers created with
+// LOG_STRICT_REDACTION set.
+func UnmaskedPHI() []string {
+	unmasked.Lock()
+	defer unmasked.Unlock()
+	found := unmasked.found
+	if unmasked.dropped > 0 {
+		found = append(found, fmt.Sprintf("and %d more", unmasked.dropped))
+	}
+	unmasked.found, unmasked.dropped = nil, 0
+	return found
+}
+
+// RunTests runs a package's tests and fails the run if any of them logged unmasked PHI while
+// LOG_STRICT_REDACTION was set. Call it from the package's TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(log.RunTests(m))
+//	}
+func RunTests(m interface{ Run() int }) int {
+	code := m.Run()
+	if found := UnmaskedPHI(); len(found) > 0 {
+		fmt.Fprintln(os.Stderr, "FAIL: tests logged PHI without masking it at the source:")
+		for _, f := range found {
+			fmt.Fprintf(os.Stderr, "\t%s\n", f)
+		}
+		if code == 0 {
+			code = 1
+		}
+	}
+	return code
+}
+
+// redactHook masks PHI in the message and fields of every logrus entry before it is written.
+type redactHook struct {
+	strict bool
+}
+
+func newRedactHook() *redactHook {
+	return &redactHook{strict: strictRedaction()}
+}
+
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire runs on a copy of the entry, so masking fields here doesn't change the logger they came from.
+func (h *redactHook) Fire(entry *logrus.Entry) error {
+	if containsPHI(entry.Message) {
+		if h.strict {
+			unmaskedPHI("message")
+		}
+		entry.Message = RedactPHI(entry.Message)
+	}
+	for k, v := range entry.Data {
+		if masked, found := redactValue(v); found {
+			if h.strict {
+				unmaskedPHI(fmt.Sprintf("field %q", k))
+			}
+			entry.Data[k] = masked
+		}
+	}
+	return nil
+}
+
+// redactHandler masks PHI in the message and attributes of slog records before passing them to the
+// wrapped handler.
+type redactHandler struct {
+	slog.Handler
+	strict bool
+}
+
+func newRedactHandler(h slog.Handler) slog.Handler {
+	return &redactHandler{Handler: h, strict: strictRedaction()}
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	msg := r.Message
+	if containsPHI(msg) {
+		if h.strict {
+			unmaskedPHI("message")
+		}
+		msg = RedactPHI(msg)
+	}
+
+	masked := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		masked.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, masked)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		masked[i] = h.redactAttr(a)
+	}
+	return &redactHandler{Handler: h.Handler.WithAttrs(masked), strict: h.strict}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{Handler: h.Handler.WithGroup(name), strict: h.strict}
+}
+
+func (h *redactHandler) redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		group := v.Group()
+		masked := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			masked[i] = h.redactAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(masked...)}
+	}
+
+	var raw any
+	switch v.Kind() {
+	case slog.KindString:
+		raw = v.String()
+	case slog.KindAny:
+		raw = v.Any()
+	default:
+		return a
+	}
+	masked, found := redactValue(raw)
+	if !found {
+		return a
+	}
+	if h.strict {
+		unmaskedPHI(fmt.Sprintf("attribute %q", a.Key))
+	}
+	return slog.Any(a.Key, masked)
+}
@@ -0,0 +1,10 @@
+package log
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(RunTests(m))
+}
@@ -0,0 +1,242 @@
+/* #nosec G404 */
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+)
+
+const (
+	beneIDSystem     = "https://bluebutton.cms.gov/resources/variables/bene_id"
+	icd10System      = "http://hl7.org/fhir/sid/icd-10-cm"
+	actCodeSystem    = "http://terminology.hl7.org/CodeSystem/v3-ActCode"
+	eobTypeSystem    = "https://bluebutton.cms.gov/resources/codesystem/eob-type"
+	samhsaLabel      = "42CFRPart2"
+	mbiLength        = 11
+	claimHistoryDays = 3 * 365
+)
+
+var (
+	eobTypes     = []string{"carrier", "dme", "hha", "hospice", "inpatient", "outpatient", "snf", "pde"}
+	diagnoses    = []string{"E11.9", "I10", "J44.9", "M54.5", "N18.3", "Z00.00"}
+	sudDiagnoses = []string{"F10.20", "F11.20"}
+	firstNames   = []string{"Alex", "Casey", "Jordan", "Morgan", "Riley", "Taylor"}
+	lastNames    = []string{"Doe", "Garcia", "Nguyen", "Patel", "Smith", "Walker"}
+)
+
+// record is a generated resource along with the fields searches filter on.
+type record struct {
+	resource    map[string]any
+	lastUpdated time.Time
+	serviceDate time.Time
+	// tags are the resource's meta.tag codings as system|code
+	tags   []string
+	samhsa bool
+}
+
+// generator deterministically creates a beneficiary's synthetic data from the seed and their MBI, so the
+// same seed always serves the same data without needing to store anything.
+type generator struct {
+	seed int64
+	// anchor is the latest date generated data is updated, so it is never newer than a request's _lastUpdated
+	anchor    time.Time
+	maxClaims int
+	// missingPct is the percentage of MBIs that BFD reports as not found
+	missingPct int
+}
+
+// patientIDForMBI returns the negative (synthetic) BFD patient ID for mbi. IDs encode the MBI so the
+// beneficiary can be recovered from the patient ID used by later searches.
+func patientIDForMBI(mbi string) (string, bool) {
+	if len(mbi) != mbiLength {
+		return "", false
+	}
+	n, err := strconv.ParseInt(mbi, 36, 64)
+	if err != nil || n <= 0 {
+		return "", false
+	}
+	return strconv.FormatInt(-n, 10), true
+}
+
+// mbiForPatientID reverses patientIDForMBI.
+func mbiForPatientID(id string) (string, bool) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n >= 0 {
+		return "", false
+	}
+	mbi := strings.ToUpper(strconv.FormatInt(-n, 36))
+	if len(mbi) > mbiLength {
+		return "", false
+	}
+	return strings.Repeat("0", mbiLength-len(mbi)) + mbi, true
+}
+
+// rand returns a source that always produces the same values for key.
+func (g generator) rand(key string) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%s", g.seed, key)
+	sum := h.Sum64()
+	return rand.New(rand.NewPCG(sum, sum>>1))
+}
+
+// found reports whether BFD knows the beneficiary.
+func (g generator) found(mbi string) bool {
+	return g.rand("found|"+mbi).IntN(100) >= g.missingPct
+}
+
+func (g generator) patient(mbi string) record {
+	id, _ := patientIDForMBI(mbi)
+	r := g.rand("patient|" + mbi)
+	lastUpdated := g.anchor.AddDate(0, 0, -r.IntN(claimHistoryDays))
+	birthDate := g.anchor.AddDate(-65-r.IntN(30), 0, -r.IntN(365))
+
+	return record{
+		lastUpdated: lastUpdated,
+		resource: map[string]any{
+			"resourceType": "Patient",
+			"id":           id,
+			"meta":         map[string]any{"lastUpdated": lastUpdated.Format(time.RFC3339Nano)},
+			"identifier": []any{
+				map[string]any{"system": beneIDSystem, "value": id},
+				map[string]any{"system": constants.MBISystem, "value": mbi},
+			},
+			"name": []any{map[string]any{
+				"use":    "usual",
+				"family": lastNames[r.IntN(len(lastNames))],
+				"given":  []any{firstNames[r.IntN(len(firstNames))]},
+			}},
+			"gender":    []string{"male", "female"}[r.IntN(2)],
+			"birthDate": birthDate.Format("2006-01-02"),
+			"address": []any{map[string]any{
+				"state":      "MD",
+				"postalCode": fmt.Sprintf("21%03d", r.IntN(1000)),
+			}},
+		},
+	}
+}
+
+func (g generator) coverage(mbi string) []record {
+	id, _ := patientIDForMBI(mbi)
+	r := g.rand("coverage|" + mbi)
+
+	var records []record
+	for _, part := range []string{"part-a", "part-b", "part-c", "part-d"} {
+		// Every beneficiary has Part A, the other parts are optional
+		if part != "part-a" && r.IntN(4) == 0 {
+			continue
+		}
+		lastUpdated := g.anchor.AddDate(0, 0, -r.IntN(claimHistoryDays))
+		records = append(records, record{
+			lastUpdated: lastUpdated,
+			resource: map[string]any{
+				"resourceType": "Coverage",
+				"id":           fmt.Sprintf("%s-%s", part, id),
+				"meta":         map[string]any{"lastUpdated": lastUpdated.Format(time.RFC3339Nano)},
+				"status":       "active",
+				"beneficiary":  map[string]any{"reference": "Patient/" + id},
+				"type": map[string]any{"coding": []any{
+					map[string]any{"system": actCodeSystem, "code": "SUBSIDIZ"},
+				}},
+			},
+		})
+	}
+	return records
+}
+
+func (g generator) explanationOfBenefits(mbi string) []record {
+	id, _ := patientIDForMBI(mbi)
+	r := g.rand("eob|" + mbi)
+
+	records := make([]record, 0, g.maxClaims)
+	for i := range r.IntN(g.maxClaims + 1) {
+		eobType := eobTypes[r.IntN(len(eobTypes))]
+		systemType := "NationalClaimsHistory"
+		if eobType == "pde" {
+			systemType = "DDPS"
+		}
+		rec := g.claim(r, fmt.Sprintf("%s-%s%03d", eobType, strings.TrimPrefix(id, "-"), i), "ExplanationOfBenefit", systemType, "FinalAction")
+		rec.resource["patient"] = map[string]any{"reference": "Patient/" + id}
+		rec.resource["type"] = map[string]any{"coding": []any{
+			map[string]any{"system": eobTypeSystem, "code": strings.ToUpper(eobType)},
+		}}
+		rec.resource["status"] = "active"
+		records = append(records, rec)
+	}
+	return records
+}
+
+// partiallyAdjudicated generates the Claim or ClaimResponse resources BFD holds for mbi from the shared systems.
+func (g generator) partiallyAdjudicated(mbi, resourceType string) []record {
+	id, _ := patientIDForMBI(mbi)
+	r := g.rand(resourceType + "|" + mbi)
+
+	records := make([]record, 0, g.maxClaims)
+	for i := range r.IntN(g.maxClaims + 1) {
+		finalAction := "NotFinalAction"
+		if r.IntN(2) == 0 {
+			finalAction = "FinalAction"
+		}
+		rec := g.claim(r, fmt.Sprintf("f-%s%03d", strings.TrimPrefix(id, "-"), i), resourceType, "SharedSystem", finalAction)
+		rec.resource["patient"] = map[string]any{"reference": "Patient/" + id}
+		if resourceType == "ClaimResponse" {
+			rec.resource["outcome"] = []string{"queued", "complete", "partial"}[r.IntN(3)]
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// claim generates the fields shared by all claim resources. About one in ten claims contains substance use
+// disorder data and is labelled as protected under 42 CFR Part 2.
+func (g generator) claim(r *rand.Rand, id, resourceType, systemType, finalAction string) record {
+	serviceDate := g.anchor.AddDate(0, 0, -r.IntN(claimHistoryDays))
+	lastUpdated := serviceDate.AddDate(0, 0, 1+r.IntN(60))
+	if lastUpdated.After(g.anchor) {
+		lastUpdated = g.anchor
+	}
+	samhsa := r.IntN(10) == 0
+
+	diagnosis := diagnoses[r.IntN(len(diagnoses))]
+	meta := map[string]any{
+		"lastUpdated": lastUpdated.Format(time.RFC3339Nano),
+		"tag": []any{
+			map[string]any{"system": constants.BFDSystemTypeURL, "code": systemType},
+			map[string]any{"system": constants.BFDFinalActionURL, "code": finalAction},
+		},
+	}
+	if samhsa {
+		diagnosis = sudDiagnoses[r.IntN(len(sudDiagnoses))]
+		meta["security"] = []any{map[string]any{"system": actCodeSystem, "code": samhsaLabel}}
+	}
+
+	return record{
+		lastUpdated: lastUpdated,
+		serviceDate: serviceDate,
+		tags:        []string{constants.BFDSystemTypeURL + "|" + systemType, constants.BFDFinalActionURL + "|" + finalAction},
+		samhsa:      samhsa,
+		resource: map[string]any{
+			"resourceType": resourceType,
+			"id":           id,
+			"meta":         meta,
+			"billablePeriod": map[string]any{
+				"start": serviceDate.Format("2006-01-02"),
+				"end":   serviceDate.AddDate(0, 0, r.IntN(5)).Format("2006-01-02"),
+			},
+			"diagnosis": []any{map[string]any{
+				"sequence": 1,
+				"diagnosisCodeableConcept": map[string]any{"coding": []any{
+					map[string]any{"system": icd10System, "code": diagnosis},
+				}},
+			}},
+			"total": []any{map[string]any{
+				"amount": map[string]any{"value": float64(r.IntN(500000)) / 100, "currency": "USD"},
+			}},
+		},
+	}
+}
@@ -0,0 +1,59 @@
+// mockbfd serves synthetic beneficiary data from the BFD endpoints BCDA calls so exports can be tested
+// end-to-end without access to a BFD environment.
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	var (
+		addr, certFile, keyFile string
+		seed                    int64
+		maxClaims, missingPct   int
+		f                       faults
+	)
+	flag.StringVar(&addr, "addr", ":8080", "address to listen on")
+	flag.StringVar(&certFile, "cert", "", "TLS certificate file; the server uses plain HTTP when not set")
+	flag.StringVar(&keyFile, "key", "", "TLS key file")
+	flag.Int64Var(&seed, "seed", 1, "seed for the generated data; the same seed always serves the same data")
+	flag.IntVar(&maxClaims, "max-claims", 12, "maximum number of claims generated for each beneficiary and resource type")
+	flag.IntVar(&missingPct, "missing-pct", 0, "percentage of MBIs reported as not found")
+	flag.DurationVar(&f.latency, "latency", 0, "average delay added to every response")
+	flag.IntVar(&f.errorPct, "error-pct", 0, "percentage of requests failed with a 500")
+	flag.IntVar(&f.throttlePct, "throttle-pct", 0, "percentage of requests rejected with a 429")
+	flag.DurationVar(&f.retryAfter, "retry-after", time.Second, "Retry-After sent with 429 responses")
+	flag.Parse()
+
+	log := logrus.New()
+	log.SetFormatter(&logrus.JSONFormatter{})
+
+	gen := generator{
+		seed:       seed,
+		anchor:     time.Now().UTC().Truncate(24 * time.Hour),
+		maxClaims:  maxClaims,
+		missingPct: missingPct,
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           newServer(gen, f, log).routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Infof("Starting mock BFD on %s (seed %d)", addr, seed)
+	var err error
+	if certFile != "" {
+		err = srv.ListenAndServeTLS(certFile, keyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,358 @@
+/* #nosec G404 */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	"github.com/sirupsen/logrus"
+)
+
+// faults configures the failures the server injects to exercise BCDA's retry and throttling behavior.
+type faults struct {
+	latency time.Duration
+	// errorPct and throttlePct are the percentages of requests answered with a 500 or a 429
+	errorPct    int
+	throttlePct int
+	retryAfter  time.Duration
+}
+
+type server struct {
+	gen    generator
+	faults faults
+	log    logrus.FieldLogger
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newServer(gen generator, f faults, log logrus.FieldLogger) *server {
+	return &server{gen: gen, faults: f, log: log, rng: gen.rand("faults")}
+}
+
+// routes registers the endpoints BCDA calls under each BFD version. The client adds a trailing slash to
+// every path, so both forms are accepted.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, h http.HandlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		for _, base := range []string{constants.BFDV1Path, constants.BFDV2Path, constants.BFDV3Path} {
+			mux.HandleFunc(fmt.Sprintf("%s %s%s", method, base, path), h)
+			mux.HandleFunc(fmt.Sprintf("%s %s%s/{$}", method, base, path), h)
+		}
+	}
+
+	handle("GET /metadata", s.metadata)
+	handle("GET /Patient", s.patient)
+	handle("POST /Patient/_search", s.patientSearch)
+	handle("GET /Coverage", s.coverage)
+	handle("GET /ExplanationOfBenefit", s.explanationOfBenefit)
+	handle("POST /Claim/_search", s.partiallyAdjudicated("Claim"))
+	handle("POST /ClaimResponse/_search", s.partiallyAdjudicated("ClaimResponse"))
+
+	return s.injectFaults(mux)
+}
+
+// injectFaults delays every request and fails a configured share of them.
+func (s *server) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		roll := s.rng.IntN(100)
+		var delay time.Duration
+		if s.faults.latency > 0 {
+			// Jitter the latency by up to 50% either way so concurrent requests don't arrive in lockstep
+			delay = s.faults.latency/2 + time.Duration(s.rng.Int64N(int64(s.faults.latency)))
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+
+		switch {
+		case roll < s.faults.throttlePct:
+			s.log.Infof("Throttling %s %s", r.Method, r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(int(s.faults.retryAfter.Seconds())))
+			writeOperationOutcome(w, http.StatusTooManyRequests, "throttling", "Too many requests")
+		case roll < s.faults.throttlePct+s.faults.errorPct:
+			s.log.Infof("Failing %s %s", r.Method, r.URL.Path)
+			writeOperationOutcome(w, http.StatusInternalServerError, "exception", "Injected server error")
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (s *server) metadata(w http.ResponseWriter, r *http.Request) {
+	var resources []any
+	for _, t := range []string{"Patient", "Coverage", "ExplanationOfBenefit", "Claim", "ClaimResponse"} {
+		resources = append(resources, map[string]any{"type": t, "interaction": []any{map[string]any{"code": "search-type"}}})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"resourceType": "CapabilityStatement",
+		"status":       "active",
+		"date":         s.gen.anchor.Format("2006-01-02"),
+		"kind":         "instance",
+		"fhirVersion":  "4.0.1",
+		"format":       []string{"application/fhir+json"},
+		"software":     map[string]any{"name": "mockbfd"},
+		"rest":         []any{map[string]any{"mode": "server", "resource": resources}},
+	})
+}
+
+func (s *server) patient(w http.ResponseWriter, r *http.Request) {
+	s.search(w, r, "_id", func(mbi string) []record {
+		return []record{s.gen.patient(mbi)}
+	})
+}
+
+func (s *server) patientSearch(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOperationOutcome(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+	system, mbi, _ := strings.Cut(r.Form.Get("identifier"), "|")
+	if system != constants.MBISystem {
+		writeOperationOutcome(w, http.StatusBadRequest, "invalid", "identifier must be an MBI")
+		return
+	}
+
+	var records []record
+	if _, ok := patientIDForMBI(mbi); ok && s.gen.found(mbi) {
+		records = append(records, s.gen.patient(mbi))
+	}
+	s.writeBundle(w, r, records)
+}
+
+func (s *server) coverage(w http.ResponseWriter, r *http.Request) {
+	s.search(w, r, "beneficiary", s.gen.coverage)
+}
+
+func (s *server) explanationOfBenefit(w http.ResponseWriter, r *http.Request) {
+	s.search(w, r, "patient", s.gen.explanationOfBenefits)
+}
+
+func (s *server) partiallyAdjudicated(resourceType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.search(w, r, "mbi", func(mbi string) []record {
+			return s.gen.partiallyAdjudicated(mbi, resourceType)
+		})
+	}
+}
+
+// search returns the beneficiary's resources matching the request's search parameters. The beneficiary is
+// identified by param, which holds either their patient ID or, for the partially adjudicated searches, MBI.
+func (s *server) search(w http.ResponseWriter, r *http.Request, param string, resources func(mbi string) []record) {
+	if err := r.ParseForm(); err != nil {
+		writeOperationOutcome(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+
+	id := r.Form.Get(param)
+	mbi, ok := id, param == "mbi"
+	if !ok {
+		mbi, ok = mbiForPatientID(id)
+	} else {
+		_, ok = patientIDForMBI(mbi)
+	}
+	if !ok {
+		writeOperationOutcome(w, http.StatusBadRequest, "invalid", fmt.Sprintf("invalid %s %q", param, id))
+		return
+	}
+
+	var records []record
+	if s.gen.found(mbi) {
+		f, err := parseFilter(r.Form)
+		if err != nil {
+			writeOperationOutcome(w, http.StatusBadRequest, "invalid", err.Error())
+			return
+		}
+		for _, rec := range resources(mbi) {
+			if f.matches(rec) {
+				records = append(records, rec)
+			}
+		}
+	}
+	s.writeBundle(w, r, records)
+}
+
+// writeBundle writes the page of records selected by the request's _count and startIndex parameters. Every
+// search parameter is carried in the next link so it can be followed without the original request body.
+func (s *server) writeBundle(w http.ResponseWriter, r *http.Request, records []record) {
+	start, err := nonNegativeParam(r.Form, "startIndex", 0)
+	if err != nil {
+		writeOperationOutcome(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+	count, err := nonNegativeParam(r.Form, "_count", max(len(records), 1))
+	if err == nil && count == 0 {
+		err = fmt.Errorf("invalid _count %q", r.Form.Get("_count"))
+	}
+	if err != nil {
+		writeOperationOutcome(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+	end := min(start+count, len(records))
+	start = min(start, end)
+
+	self := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path, RawQuery: r.Form.Encode()}
+	if r.TLS != nil {
+		self.Scheme = "https"
+	}
+	links := []any{map[string]any{"relation": "self", "url": self.String()}}
+	if end < len(records) {
+		next := self
+		q := r.Form
+		q.Set("startIndex", strconv.Itoa(end))
+		q.Set("_count", strconv.Itoa(count))
+		next.RawQuery = q.Encode()
+		links = append(links, map[string]any{"relation": "next", "url": next.String()})
+	}
+
+	entries := make([]any, 0, end-start)
+	for _, rec := range records[start:end] {
+		entries = append(entries, map[string]any{"resource": rec.resource})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"resourceType": "Bundle",
+		"type":         "searchset",
+		"meta":         map[string]any{"lastUpdated": s.gen.anchor.Format(time.RFC3339Nano)},
+		"total":        len(records),
+		"link":         links,
+		"entry":        entries,
+	})
+}
+
+func nonNegativeParam(form url.Values, name string, def int) (int, error) {
+	v := form.Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, v)
+	}
+	return n, nil
+}
+
+// dateBound is one side of a FHIR date search, e.g. ge2024-01-01.
+type dateBound struct {
+	prefix string
+	t      time.Time
+}
+
+func (b dateBound) matches(t time.Time) bool {
+	switch b.prefix {
+	case "gt":
+		return t.After(b.t)
+	case "ge":
+		return !t.Before(b.t)
+	case "lt":
+		return t.Before(b.t)
+	case "le":
+		return !t.After(b.t)
+	default:
+		return t.Equal(b.t)
+	}
+}
+
+// filter holds the search parameters BCDA uses to narrow results.
+type filter struct {
+	lastUpdated   []dateBound
+	serviceDate   []dateBound
+	tags          [][]string
+	excludeSAMHSA bool
+}
+
+func parseFilter(form url.Values) (filter, error) {
+	var (
+		f   filter
+		err error
+	)
+	if f.lastUpdated, err = parseDateBounds(form["_lastUpdated"]); err != nil {
+		return f, fmt.Errorf("invalid _lastUpdated: %w", err)
+	}
+	if f.serviceDate, err = parseDateBounds(form["service-date"]); err != nil {
+		return f, fmt.Errorf("invalid service-date: %w", err)
+	}
+	// Each _tag parameter must match, and matches when any of its comma-separated values do
+	for _, tag := range form["_tag"] {
+		f.tags = append(f.tags, strings.Split(tag, ","))
+	}
+	f.excludeSAMHSA = form.Get("excludeSAMHSA") == "true" || form.Get("_security:not") == samhsaLabel
+	return f, nil
+}
+
+func parseDateBounds(values []string) ([]dateBound, error) {
+	bounds := make([]dateBound, 0, len(values))
+	for _, v := range values {
+		b := dateBound{prefix: "eq"}
+		if len(v) > 2 && v[0] >= 'a' && v[0] <= 'z' {
+			b.prefix, v = v[:2], v[2:]
+		}
+		if !slices.Contains([]string{"eq", "gt", "ge", "lt", "le"}, b.prefix) {
+			return nil, fmt.Errorf("unsupported prefix %q", b.prefix)
+		}
+
+		var err error
+		if b.t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			if b.t, err = time.Parse("2006-01-02", v); err != nil {
+				return nil, fmt.Errorf("unsupported date %q", v)
+			}
+		}
+		bounds = append(bounds, b)
+	}
+	return bounds, nil
+}
+
+func (f filter) matches(rec record) bool {
+	if f.excludeSAMHSA && rec.samhsa {
+		return false
+	}
+	for _, b := range f.lastUpdated {
+		if !b.matches(rec.lastUpdated) {
+			return false
+		}
+	}
+	for _, b := range f.serviceDate {
+		if rec.serviceDate.IsZero() || !b.matches(rec.serviceDate) {
+			return false
+		}
+	}
+	for _, options := range f.tags {
+		if !slices.ContainsFunc(options, func(tag string) bool { return slices.Contains(rec.tags, tag) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeOperationOutcome(w http.ResponseWriter, status int, code, diagnostics string) {
+	writeJSON(w, status, map[string]any{
+		"resourceType": "OperationOutcome",
+		"issue": []any{map[string]any{
+			"severity":    "error",
+			"code":        code,
+			"diagnostics": diagnostics,
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/fhir+json")
+	w.WriteHeader(status)
+	// The status has already been written, so there is nothing more to do if encoding fails
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CMSgov/bcda-app/bcda/client"
+	"github.com/CMSgov/bcda-app/bcda/constants"
+	fhirModels "github.com/CMSgov/bcda-app/bcda/models/fhir"
+	"github.com/CMSgov/bcda-app/bcdaworker/queueing/worker_types"
+	"github.com/CMSgov/bcda-app/conf"
+)
+
+const testMBI = "1S00E00AA00"
+
+func newTestServer(t *testing.T, f faults) *httptest.Server {
+	gen := generator{seed: 1, anchor: time.Now().UTC().Truncate(24 * time.Hour), maxClaims: 20}
+	ts := httptest.NewServer(newServer(gen, f, logrus.New()).routes())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// setClientEnv points the Blue Button client at a throwaway keypair, since the mock server does not check
+// client certificates. The environment is restored when the test finishes.
+func setClientEnv(t *testing.T, pageSize string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "mockbfd"}, NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	env := map[string]string{
+		"BB_CLIENT_CERT_FILE":          certFile,
+		"BB_CLIENT_KEY_FILE":           keyFile,
+		"BB_CHECK_CERT":                "false",
+		"BB_CLIENT_PAGE_SIZE":          pageSize,
+		"BB_REQUEST_MAX_TRIES":         "1",
+		"BB_REQUEST_RETRY_INTERVAL_MS": "10",
+		"BB_TIMEOUT_MS":                "2000",
+	}
+	for k, v := range env {
+		orig := conf.GetEnv(k)
+		t.Cleanup(func() { conf.SetEnv(t, k, orig) })
+		conf.SetEnv(t, k, v)
+	}
+	client.SetLogger(logrus.New())
+}
+
+func TestPatientIDForMBI(t *testing.T) {
+	id, ok := patientIDForMBI(testMBI)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(id, "-"))
+	mbi, ok := mbiForPatientID(id)
+	assert.True(t, ok)
+	assert.Equal(t, testMBI, mbi)
+
+	for _, invalid := range []string{"", "1S00E00AA0", "1S00E00AA0!"} {
+		_, ok = patientIDForMBI(invalid)
+		assert.False(t, ok, invalid)
+	}
+	for _, invalid := range []string{"", "12345", "abc"} {
+		_, ok = mbiForPatientID(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestBlueButtonClient(t *testing.T) {
+	ts := newTestServer(t, faults{})
+	jobData := worker_types.JobEnqueueArgs{ID: 1, CMSID: "A0000", TransactionTime: time.Now()}
+
+	for _, basePath := range []string{constants.BFDV2Path, constants.BFDV3Path} {
+		t.Run(basePath, func(t *testing.T) {
+			// Paging with a small page size returns the same resources as a single request
+			resources := make(map[string][]string)
+			for _, pageSize := range []string{"0", "3"} {
+				setClientEnv(t, pageSize)
+				bbc, err := client.NewBlueButtonClient(client.BlueButtonConfig{BBServer: ts.URL, BBBasePath: basePath})
+				require.NoError(t, err)
+
+				raw, err := bbc.GetPatientByMbi(context.Background(), jobData, testMBI)
+				require.NoError(t, err)
+				var patients fhirModels.Bundle
+				require.NoError(t, json.Unmarshal([]byte(raw), &patients))
+				require.Len(t, patients.Entries, 1)
+				patientID := patients.Entries[0]["resource"].(map[string]any)["id"].(string)
+
+				b, err := bbc.GetPatient(context.Background(), jobData, patientID)
+				require.NoError(t, err)
+				resources["Patient"+pageSize] = ids(b)
+
+				b, err = bbc.GetCoverage(context.Background(), jobData, patientID)
+				require.NoError(t, err)
+				resources["Coverage"+pageSize] = ids(b)
+
+				b, err = bbc.GetExplanationOfBenefit(context.Background(), jobData, patientID, client.ClaimsWindow{})
+				require.NoError(t, err)
+				resources["ExplanationOfBenefit"+pageSize] = ids(b)
+				for _, entry := range b.Entries {
+					assert.NotContains(t, entry["resource"].(map[string]any)["meta"], "security", "SAMHSA claims are excluded")
+				}
+
+				b, err = bbc.GetClaim(context.Background(), jobData, testMBI, client.ClaimsWindow{})
+				require.NoError(t, err)
+				resources["Claim"+pageSize] = ids(b)
+
+				b, err = bbc.GetClaimResponse(context.Background(), jobData, testMBI, client.ClaimsWindow{})
+				require.NoError(t, err)
+				resources["ClaimResponse"+pageSize] = ids(b)
+
+				metadata, err := bbc.GetMetadata()
+				require.NoError(t, err)
+				assert.Contains(t, metadata, "CapabilityStatement")
+			}
+
+			for _, resourceType := range []string{"Patient", "Coverage", "ExplanationOfBenefit", "Claim", "ClaimResponse"} {
+				assert.NotEmpty(t, resources[resourceType+"0"], resourceType)
+				assert.Equal(t, resources[resourceType+"0"], resources[resourceType+"3"], resourceType)
+			}
+		})
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	ts := newTestServer(t, faults{})
+	patientID, _ := patientIDForMBI(testMBI)
+	search := func(query string) *fhirModels.Bundle {
+		resp, err := http.Get(ts.URL + constants.BFDV2Path + "/ExplanationOfBenefit/?patient=" + patientID + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var b fhirModels.Bundle
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&b))
+		return &b
+	}
+
+	all := search("")
+	require.NotEmpty(t, all.Entries)
+	assert.Equal(t, len(all.Entries), int(all.Total))
+
+	// The same seed always generates the same data
+	assert.Equal(t, ids(all), ids(search("")))
+
+	since := time.Now().UTC().AddDate(-1, 0, 0).Format("2006-01-02")
+	for _, entry := range search("&service-date=ge" + since).Entries {
+		start := entry["resource"].(map[string]any)["billablePeriod"].(map[string]any)["start"].(string)
+		assert.GreaterOrEqual(t, start, since)
+	}
+
+	for _, entry := range search("&_tag=" + constants.BFDSystemTypeURL + "|DDPS").Entries {
+		assert.True(t, strings.HasPrefix(entry["resource"].(map[string]any)["id"].(string), "pde-"))
+	}
+
+	assert.Empty(t, search("&_lastUpdated=gt"+time.Now().Format(time.RFC3339Nano)).Entries)
+
+	for _, query := range []string{"&service-date=xx2024-01-01", "&_lastUpdated=yesterday", "&_count=0", "&startIndex=-1"} {
+		resp, err := http.Get(ts.URL + constants.BFDV2Path + "/ExplanationOfBenefit/?patient=" + patientID + query)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+func TestInjectFaults(t *testing.T) {
+	ts := newTestServer(t, faults{throttlePct: 100, retryAfter: 2 * time.Second})
+	resp, err := http.Get(ts.URL + constants.BFDV2Path + "/metadata/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+
+	ts = newTestServer(t, faults{errorPct: 100, latency: 20 * time.Millisecond})
+	start := time.Now()
+	resp, err = http.Get(ts.URL + constants.BFDV2Path + "/metadata/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+}
+
+func ids(b *fhirModels.Bundle) []string {
+	var ids []string
+	for _, entry := range b.Entries {
+		ids = append(ids, entry["resource"].(map[string]any)["id"].(string))
+	}
+	return ids
+}